@@ -1,6 +1,7 @@
 package application
 
 import (
+	"context"
 	"version-list/internal/domain/model"
 	"version-list/internal/domain/service"
 	"version-list/internal/infrastructure/persistence"
@@ -45,11 +46,15 @@ func (s *VersionAppService) List() ([]*service.VersionInfo, error) {
 		return nil, err
 	}
 
+	// 按发布版本排序，同一发布版本的自定义名称工具链排在原版之后
+	versions = model.SortVersions(versions, &model.VersionSorter{Field: "version", Direction: "asc"})
+
 	// 转换为视图模型
 	var result []*service.VersionInfo
 	for _, v := range versions {
 		result = append(result, &service.VersionInfo{
 			Version:  v.Version,
+			Release:  v.ReleaseVersion(),
 			Path:     v.Path,
 			IsActive: v.IsActive,
 		})
@@ -58,9 +63,54 @@ func (s *VersionAppService) List() ([]*service.VersionInfo, error) {
 	return result, nil
 }
 
-// Use 切换到指定版本的Go
-func (s *VersionAppService) Use(version string) error {
-	return s.versionService.Use(version)
+// Use 切换到指定版本的Go，运行 pre-use/post-use 钩子，返回非必需钩子失败的警告
+func (s *VersionAppService) Use(version string) ([]string, error) {
+	return s.versionService.UseWithHooks(version)
+}
+
+// SetIsolation 设置 GOBIN、GOCACHE 的隔离方式，返回更新后的环境变量配置
+func (s *VersionAppService) SetIsolation(mode model.IsolationMode, isolateModCache bool) (*model.Environment, error) {
+	return s.versionService.SetIsolation(mode, isolateModCache)
+}
+
+// Environment 获取当前的环境变量配置
+func (s *VersionAppService) Environment() (*model.Environment, error) {
+	return s.versionService.Environment()
+}
+
+// CollectGarbage 清理已移除版本的隔离目录
+func (s *VersionAppService) CollectGarbage(dryRun bool) ([]service.IsolatedEnvironment, error) {
+	return s.versionService.CollectGarbage(dryRun)
+}
+
+// SetEnvVar 设置指定范围的自定义环境变量
+func (s *VersionAppService) SetEnvVar(scope model.EnvScope, target, name, value string) error {
+	return s.versionService.SetEnvVar(scope, target, name, value)
+}
+
+// UnsetEnvVar 删除指定范围的自定义环境变量
+func (s *VersionAppService) UnsetEnvVar(scope model.EnvScope, target, name string) error {
+	return s.versionService.UnsetEnvVar(scope, target, name)
+}
+
+// ResolveEnvVars 获取指定版本在项目目录中生效的自定义环境变量
+func (s *VersionAppService) ResolveEnvVars(version, dir string) ([]model.EnvVar, error) {
+	return s.versionService.ResolveEnvVars(version, dir)
+}
+
+// EffectiveEnvironment 获取指定版本在项目目录中生效的完整环境变量
+func (s *VersionAppService) EffectiveEnvironment(version, dir string) (*model.EffectiveEnvironment, error) {
+	return s.versionService.EffectiveEnvironment(version, dir)
+}
+
+// InstalledTools 获取已安装版本记录中的默认工具
+func (s *VersionAppService) InstalledTools(version string) ([]model.InstalledTool, error) {
+	return s.versionService.InstalledTools(version)
+}
+
+// InstallDefaultTools 为已安装的版本安装默认工具，返回安装成功的工具和失败的警告
+func (s *VersionAppService) InstallDefaultTools(ctx context.Context, version string) ([]model.InstalledTool, []string, error) {
+	return s.versionService.InstallDefaultTools(ctx, version)
 }
 
 // Current 获取当前使用的Go版本
@@ -82,13 +132,13 @@ func (s *VersionAppService) Remove(version string) error {
 	return s.versionService.Remove(version)
 }
 
-// ImportLocal 导入本地已安装的Go版本
-func (s *VersionAppService) ImportLocal(path string) (string, error) {
-	return s.versionService.ImportLocal(path)
+// ImportLocal 导入本地已安装的Go版本，name 为自定义工具链名称（为空时使用检测到的版本号）
+func (s *VersionAppService) ImportLocal(path, name string) (string, error) {
+	return s.versionService.ImportLocal(path, name)
 }
 
-// InstallOnline 在线安装指定版本的Go
-func (s *VersionAppService) InstallOnline(version string, options *model.InstallOptions, progressUI *ui.InstallProgressUI) (*model.InstallationResult, error) {
+// InstallOnline 在线安装指定版本的Go，ctx 取消时中断安装并清理
+func (s *VersionAppService) InstallOnline(ctx context.Context, version string, options *model.InstallOptions, progressUI *ui.InstallProgressUI) (*model.InstallationResult, error) {
 	// 设置进度回调
 	if progressUI != nil {
 		// 开始系统检测阶段
@@ -97,5 +147,103 @@ func (s *VersionAppService) InstallOnline(version string, options *model.Install
 		progressUI.SetMessage("正在检测操作系统和CPU架构...")
 	}
 
-	return s.versionService.InstallOnlineWithProgress(version, options, progressUI)
+	// 避免将 nil 指针包装为非 nil 接口
+	if progressUI == nil {
+		return s.versionService.InstallOnlineWithContext(ctx, version, options, nil)
+	}
+	return s.versionService.InstallOnlineWithContext(ctx, version, options, progressUI)
+}
+
+// InstallOnlineBatch 并行在线安装多个Go版本
+func (s *VersionAppService) InstallOnlineBatch(ctx context.Context, versions []string, options *model.InstallOptions, parallel int, progressUI *ui.MultiInstallProgressUI) []*model.InstallationResult {
+	// 避免将 nil 指针包装为非 nil 接口
+	if progressUI == nil {
+		return s.versionService.InstallOnlineBatch(ctx, versions, options, parallel, nil)
+	}
+	return s.versionService.InstallOnlineBatch(ctx, versions, options, parallel, progressUI)
+}
+
+// InstallFromArchive 从本地压缩包安装Go
+func (s *VersionAppService) InstallFromArchive(ctx context.Context, archivePath string, options *model.InstallOptions, progressUI *ui.InstallProgressUI) (*model.InstallationResult, error) {
+	// 避免将 nil 指针包装为非 nil 接口
+	if progressUI == nil {
+		return s.versionService.InstallFromArchive(ctx, archivePath, options, nil)
+	}
+	return s.versionService.InstallFromArchive(ctx, archivePath, options, progressUI)
+}
+
+// InstallFromSource 从源码编译安装Go，source 为版本号或本地Go源码目录
+func (s *VersionAppService) InstallFromSource(ctx context.Context, source string, options *model.InstallOptions, progressUI *ui.InstallProgressUI) (*model.InstallationResult, error) {
+	// 避免将 nil 指针包装为非 nil 接口
+	if progressUI == nil {
+		return s.versionService.InstallFromSource(ctx, source, options, nil)
+	}
+	return s.versionService.InstallFromSource(ctx, source, options, progressUI)
+}
+
+// GenerateLock 根据发布目录和下载缓存生成锁文件
+func (s *VersionAppService) GenerateLock(ctx context.Context, options *service.LockOptions) (*model.LockFile, error) {
+	return s.versionService.GenerateLock(ctx, options)
+}
+
+// InstallLocked 按锁文件安装指定版本
+func (s *VersionAppService) InstallLocked(ctx context.Context, version string, lock *model.LockFile, options *model.InstallOptions, progressUI *ui.InstallProgressUI) (*model.InstallationResult, error) {
+	// 避免将 nil 指针包装为非 nil 接口
+	if progressUI == nil {
+		return s.versionService.InstallLocked(ctx, version, lock, options, nil)
+	}
+	return s.versionService.InstallLocked(ctx, version, lock, options, progressUI)
+}
+
+// CheckLockDrift 对比锁文件与已安装版本
+func (s *VersionAppService) CheckLockDrift(lock *model.LockFile) ([]model.LockDrift, error) {
+	return s.versionService.CheckLockDrift(lock)
+}
+
+// PlanSync 对比工具链清单与已安装的版本，生成同步计划
+func (s *VersionAppService) PlanSync(manifest *model.SyncManifest) (*model.SyncPlan, error) {
+	return s.versionService.PlanSync(manifest)
+}
+
+// ApplySync 执行同步计划
+func (s *VersionAppService) ApplySync(ctx context.Context, plan *model.SyncPlan, options *model.InstallOptions, progressUI service.SyncProgressReporter) []*model.SyncResult {
+	return s.versionService.ApplySync(ctx, plan, options, progressUI)
+}
+
+// PendingInstallJournals 获取进程异常退出后留下的未完成安装
+func (s *VersionAppService) PendingInstallJournals() ([]*model.InstallJournal, error) {
+	return s.versionService.PendingInstallJournals()
+}
+
+// RollbackInstallJournal 回滚未完成的安装
+func (s *VersionAppService) RollbackInstallJournal(journal *model.InstallJournal) error {
+	return s.versionService.RollbackInstallJournal(journal)
+}
+
+// ResumeInstallJournal 继续未完成的安装
+func (s *VersionAppService) ResumeInstallJournal(ctx context.Context, journal *model.InstallJournal, progressUI *ui.InstallProgressUI) (*model.InstallationResult, error) {
+	// 避免将 nil 指针包装为非 nil 接口
+	if progressUI == nil {
+		return s.versionService.ResumeInstallJournal(ctx, journal, nil)
+	}
+	return s.versionService.ResumeInstallJournal(ctx, journal, progressUI)
+}
+
+// CreateBundle 创建离线安装包
+func (s *VersionAppService) CreateBundle(ctx context.Context, outputPath string, options *service.BundleCreateOptions, progress service.BundleProgressCallback) (*model.BundleManifest, error) {
+	return s.versionService.CreateBundle(ctx, outputPath, options, progress)
+}
+
+// InstallBundle 从离线安装包安装Go版本
+func (s *VersionAppService) InstallBundle(ctx context.Context, bundlePath, version string, options *model.InstallOptions, progress service.BundleProgressCallback) ([]*model.InstallationResult, error) {
+	return s.versionService.InstallBundle(ctx, bundlePath, version, options, progress)
+}
+
+// Fetch 下载并验证任意平台的Go压缩包，但不安装
+func (s *VersionAppService) Fetch(ctx context.Context, version string, options *service.FetchOptions, progressUI *ui.InstallProgressUI) (*service.FetchResult, error) {
+	// 避免将 nil 指针包装为非 nil 接口
+	if progressUI == nil {
+		return s.versionService.Fetch(ctx, version, options, nil)
+	}
+	return s.versionService.Fetch(ctx, version, options, progressUI)
 }
@@ -0,0 +1,46 @@
+package model
+
+import (
+	"time"
+)
+
+// BundleFormatVersion 离线安装包清单格式版本
+const BundleFormatVersion = 1
+
+// BundleManifest 离线安装包清单
+type BundleManifest struct {
+	FormatVersion int           `json:"format_version"` // 清单格式版本
+	CreatedAt     time.Time     `json:"created_at"`     // 创建时间
+	Entries       []BundleEntry `json:"entries"`        // 包含的压缩包
+}
+
+// BundleEntry 离线安装包中的单个Go压缩包
+type BundleEntry struct {
+	Version      string        `json:"version"`       // Go版本号
+	OS           string        `json:"os"`            // 操作系统
+	Arch         string        `json:"arch"`          // CPU架构
+	Filename     string        `json:"filename"`      // 压缩包文件名
+	SHA256       string        `json:"sha256"`        // 压缩包SHA-256
+	Size         int64         `json:"size"`          // 压缩包大小
+	DownloadInfo *DownloadInfo `json:"download_info"` // 原始下载信息
+}
+
+// Platform 返回条目的平台标识，如 linux/amd64
+func (e *BundleEntry) Platform() string {
+	return e.OS + "/" + e.Arch
+}
+
+// FindEntries 查找匹配指定平台和版本的条目，version 为空时匹配所有版本
+func (m *BundleManifest) FindEntries(os, arch, version string) []BundleEntry {
+	var entries []BundleEntry
+	for _, entry := range m.Entries {
+		if entry.OS != os || entry.Arch != arch {
+			continue
+		}
+		if version != "" && entry.Version != version {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
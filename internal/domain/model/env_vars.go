@@ -0,0 +1,215 @@
+package model
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// EnvScope 自定义环境变量的作用范围
+type EnvScope string
+
+const (
+	EnvScopeGlobal  EnvScope = "global"  // 所有版本
+	EnvScopeVersion EnvScope = "version" // 指定版本
+	EnvScopeProject EnvScope = "project" // 项目目录（go-version.env.yaml）
+	EnvScopeManaged EnvScope = "managed" // 由 go-version 根据版本和隔离方式计算
+)
+
+// envVarNamePattern 环境变量名格式
+var envVarNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// deniedEnvVarNames 控制 shell 行为的变量，设置后会在 shell 钩子或子进程中执行任意命令
+var deniedEnvVarNames = map[string]bool{
+	"PROMPT_COMMAND": true, "BASH_ENV": true, "ENV": true, "SHELLOPTS": true, "BASHOPTS": true,
+	"PS0": true, "PS1": true, "PS2": true, "PS3": true, "PS4": true, "IFS": true, "CDPATH": true,
+	"ZDOTDIR": true, "FPATH": true, "PERL5OPT": true, "PYTHONSTARTUP": true, "NODE_OPTIONS": true,
+}
+
+// deniedEnvVarPrefixes 动态链接器和 shell 函数导出使用的变量前缀
+var deniedEnvVarPrefixes = []string{"LD_", "DYLD_", "BASH_FUNC_"}
+
+// ValidateEnvVarName 验证自定义环境变量名：GOROOT 和 PATH 由工具管理，
+// 控制 shell 和动态链接器的变量（如 PROMPT_COMMAND、BASH_ENV、LD_PRELOAD）可用于执行任意代码，均不能自定义
+func ValidateEnvVarName(name string) error {
+	if !envVarNamePattern.MatchString(name) {
+		return fmt.Errorf("无效的环境变量名 '%s'", name)
+	}
+	upper := strings.ToUpper(name)
+	switch upper {
+	case "GOROOT", "PATH":
+		return fmt.Errorf("%s 由 go-version 管理，不能自定义", name)
+	}
+	if deniedEnvVarNames[upper] {
+		return fmt.Errorf("%s 控制 shell 的行为，不能自定义", name)
+	}
+	for _, prefix := range deniedEnvVarPrefixes {
+		if strings.HasPrefix(upper, prefix) {
+			return fmt.Errorf("%s 控制动态链接器或 shell 函数，不能自定义", name)
+		}
+	}
+	return nil
+}
+
+// ProjectEnvironment 项目目录中 go-version.env.yaml 的内容
+type ProjectEnvironment struct {
+	Env map[string]string `json:"env,omitempty" yaml:"env,omitempty"` // 在此项目中使用的环境变量
+}
+
+// Validate 检查环境变量名是否有效
+func (p *ProjectEnvironment) Validate() error {
+	for name := range p.Env {
+		if err := ValidateEnvVarName(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AllowedProjectEnv 经用户检查后允许使用的项目环境变量文件，内容变化后需要重新允许
+type AllowedProjectEnv struct {
+	Path      string    `json:"path"`       // 文件的绝对路径
+	SHA256    string    `json:"sha256"`     // 允许时文件内容的SHA-256
+	AllowedAt time.Time `json:"allowed_at"` // 允许的时间
+}
+
+// ProjectEnvAllowList 允许使用的项目环境变量文件列表，保存在 ~/.go-version/env-allowed.json
+type ProjectEnvAllowList struct {
+	Files []AllowedProjectEnv `json:"files"`
+}
+
+// Allowed 检查指定内容的文件是否已被允许
+func (l *ProjectEnvAllowList) Allowed(path, sha256 string) bool {
+	for _, file := range l.Files {
+		if file.Path == path {
+			return strings.EqualFold(file.SHA256, sha256)
+		}
+	}
+	return false
+}
+
+// Allow 允许文件的当前内容，替换之前的记录
+func (l *ProjectEnvAllowList) Allow(path, sha256 string, now time.Time) {
+	l.Revoke(path)
+	l.Files = append(l.Files, AllowedProjectEnv{Path: path, SHA256: sha256, AllowedAt: now})
+}
+
+// Revoke 撤销文件的允许记录，返回是否存在记录
+func (l *ProjectEnvAllowList) Revoke(path string) bool {
+	for i, file := range l.Files {
+		if file.Path == path {
+			l.Files = append(l.Files[:i], l.Files[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// EnvVar 合并后生效的自定义环境变量
+type EnvVar struct {
+	Name   string   // 变量名
+	Value  string   // 值
+	Source EnvScope // 生效值的来源
+}
+
+// String 获取 NAME=value 形式
+func (v EnvVar) String() string {
+	return v.Name + "=" + v.Value
+}
+
+// ResolveEnvVars 合并自定义环境变量，优先级从低到高为：全局、指定版本、项目，按变量名排序
+func ResolveEnvVars(env *Environment, version string, project *ProjectEnvironment) []EnvVar {
+	merged := make(map[string]EnvVar)
+	apply := func(vars map[string]string, source EnvScope) {
+		for name, value := range vars {
+			// 忽略旧版本写入、现在不允许自定义的变量
+			if ValidateEnvVarName(name) != nil {
+				continue
+			}
+			merged[name] = EnvVar{Name: name, Value: value, Source: source}
+		}
+	}
+	if env != nil {
+		apply(env.Vars, EnvScopeGlobal)
+		if version != "" {
+			apply(env.VersionVars[version], EnvScopeVersion)
+		}
+	}
+	if project != nil {
+		apply(project.Env, EnvScopeProject)
+	}
+
+	vars := make([]EnvVar, 0, len(merged))
+	for _, v := range merged {
+		vars = append(vars, v)
+	}
+	sort.Slice(vars, func(i, j int) bool { return vars[i].Name < vars[j].Name })
+	return vars
+}
+
+// EffectiveEnvironment 指定版本生效的完整环境变量
+type EffectiveEnvironment struct {
+	Version string   // 版本名称
+	Vars    []EnvVar // GOROOT、GOBIN 等由 go-version 管理的变量和自定义环境变量，不包括 PATH
+	Path    []string // 需要加在 PATH 前面的目录
+}
+
+// env 输出在 shell 中留下的记录，再次输出（shell 钩子、exec）时据此撤销不再生效的设置，
+// 切换版本、隔离方式或离开项目目录后不会留下旧的值
+const (
+	ShellPathVar = "GO_VERSION_SHELL_PATH" // 加在 PATH 前面的目录
+	ShellVarsVar = "GO_VERSION_SHELL_VARS" // 设置的变量名，以逗号分隔
+)
+
+// ShellState 之前在 shell 中应用 env 输出后留下的状态
+type ShellState struct {
+	Path      string // 当前的 PATH
+	AddedPath string // 之前加在 PATH 前面的目录（ShellPathVar 的值）
+	Vars      string // 之前设置的变量名（ShellVarsVar 的值）
+}
+
+// VarNames 获取 Vars 中的变量名，用于 ShellVarsVar
+func (e *EffectiveEnvironment) VarNames() string {
+	names := make([]string, len(e.Vars))
+	for i, v := range e.Vars {
+		names[i] = v.Name
+	}
+	return strings.Join(names, ",")
+}
+
+// StaleVars 获取之前设置、现在不再生效的变量名，需要在 shell 中删除
+func (e *EffectiveEnvironment) StaleVars(state ShellState) []string {
+	current := make(map[string]bool, len(e.Vars))
+	for _, v := range e.Vars {
+		current[v.Name] = true
+	}
+	var stale []string
+	for _, name := range strings.Split(state.Vars, ",") {
+		if !current[name] && envVarNamePattern.MatchString(name) && name != "PATH" {
+			stale = append(stale, name)
+		}
+	}
+	return stale
+}
+
+// PathValue 获取把 Path 中的目录加在当前 PATH 前面后的 PATH，并去掉当前 PATH 中与 Path 重复的目录和之前加入的目录，
+// 重复执行时 PATH 不会变长
+func (e *EffectiveEnvironment) PathValue(state ShellState, separator string) string {
+	entries := append([]string(nil), e.Path...)
+	seen := make(map[string]bool, len(e.Path))
+	for _, dir := range e.Path {
+		seen[dir] = true
+	}
+	for _, dir := range strings.Split(state.AddedPath, separator) {
+		seen[dir] = true
+	}
+	for _, dir := range strings.Split(state.Path, separator) {
+		if dir == "" || seen[dir] {
+			continue
+		}
+		entries = append(entries, dir)
+	}
+	return strings.Join(entries, separator)
+}
@@ -0,0 +1,109 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// HookEvent 钩子触发的时机
+type HookEvent string
+
+const (
+	HookPreInstall  HookEvent = "pre-install"  // 安装开始前
+	HookPostInstall HookEvent = "post-install" // 保存版本记录后、安装完成前
+	HookPreUse      HookEvent = "pre-use"      // 切换版本前
+	HookPostUse     HookEvent = "post-use"     // 切换版本后
+)
+
+// HookEvents 所有支持的钩子时机，按执行顺序排列
+var HookEvents = []HookEvent{HookPreInstall, HookPostInstall, HookPreUse, HookPostUse}
+
+// Hook 在指定时机运行的外部命令
+type Hook struct {
+	Name     string `json:"name,omitempty" yaml:"name,omitempty"`         // 显示名称，为空时使用命令
+	Command  string `json:"command" yaml:"command"`                       // 通过 shell 执行的命令
+	Timeout  int    `json:"timeout,omitempty" yaml:"timeout,omitempty"`   // 超时时间（秒），0 表示不限制
+	Required bool   `json:"required,omitempty" yaml:"required,omitempty"` // 失败时中止操作，post-install 钩子失败时回滚安装
+}
+
+// DisplayName 获取钩子的显示名称
+func (h *Hook) DisplayName() string {
+	if h.Name != "" {
+		return h.Name
+	}
+	return h.Command
+}
+
+// HooksConfig 钩子和默认工具配置
+type HooksConfig struct {
+	Hooks        map[HookEvent][]Hook `json:"hooks,omitempty" yaml:"hooks,omitempty"`                 // 按时机配置的钩子
+	DefaultTools []string             `json:"default_tools,omitempty" yaml:"default_tools,omitempty"` // 安装后使用新工具链 go install 的工具
+}
+
+// Validate 检查钩子时机和命令是否有效
+func (c *HooksConfig) Validate() error {
+	for event, hooks := range c.Hooks {
+		if !isHookEvent(event) {
+			return fmt.Errorf("未知的钩子时机 '%s'，支持: %s", event, joinHookEvents())
+		}
+		for i, hook := range hooks {
+			if strings.TrimSpace(hook.Command) == "" {
+				return fmt.Errorf("%s 的第 %d 个钩子缺少 command", event, i+1)
+			}
+			if hook.Timeout < 0 {
+				return fmt.Errorf("%s 钩子 %s 的超时时间不能为负数", event, hook.DisplayName())
+			}
+		}
+	}
+	for _, tool := range c.DefaultTools {
+		if _, err := ParseToolSpec(tool); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isHookEvent 检查是否为支持的钩子时机
+func isHookEvent(event HookEvent) bool {
+	for _, known := range HookEvents {
+		if event == known {
+			return true
+		}
+	}
+	return false
+}
+
+// joinHookEvents 获取所有钩子时机的列表说明
+func joinHookEvents() string {
+	names := make([]string, len(HookEvents))
+	for i, event := range HookEvents {
+		names[i] = string(event)
+	}
+	return strings.Join(names, ", ")
+}
+
+// InstalledTool 使用工具链安装的工具
+type InstalledTool struct {
+	Package     string    // 包路径，如 golang.org/x/tools/gopls
+	Version     string    // 安装时指定的版本，如 latest、v0.16.2
+	InstalledAt time.Time // 安装时间
+}
+
+// String 获取 go install 使用的参数，如 golang.org/x/tools/gopls@latest
+func (t InstalledTool) String() string {
+	return t.Package + "@" + t.Version
+}
+
+// ParseToolSpec 解析默认工具配置，未指定版本时使用 latest
+func ParseToolSpec(spec string) (InstalledTool, error) {
+	spec = strings.TrimSpace(spec)
+	pkg, version := spec, "latest"
+	if index := strings.LastIndex(spec, "@"); index >= 0 {
+		pkg, version = spec[:index], spec[index+1:]
+	}
+	if pkg == "" || version == "" || strings.ContainsAny(spec, " \t") || strings.HasPrefix(pkg, "-") {
+		return InstalledTool{}, fmt.Errorf("无效的工具 '%s'，格式应为 <包路径>[@版本]", spec)
+	}
+	return InstalledTool{Package: pkg, Version: version}, nil
+}
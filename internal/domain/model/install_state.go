@@ -0,0 +1,46 @@
+package model
+
+import "time"
+
+// InstallStage 安装已完成的阶段
+type InstallStage string
+
+const (
+	StageNone       InstallStage = ""           // 尚未完成任何阶段
+	StageDownloaded InstallStage = "downloaded" // 压缩包已下载完成
+	StageExtracted  InstallStage = "extracted"  // 压缩包已完整解压到临时目录
+)
+
+// InstallState 保存在临时目录中的安装状态，进程重启后用于继续下载并跳过已完成的阶段
+type InstallState struct {
+	Version      string       `json:"version"`                 // Go发布版本号
+	Name         string       `json:"name,omitempty"`          // 工具链名称
+	OS           string       `json:"os"`                      // 目标操作系统
+	Arch         string       `json:"arch"`                    // 目标CPU架构
+	Filename     string       `json:"filename"`                // 压缩包文件名
+	URL          string       `json:"url,omitempty"`           // 下载地址
+	Mirror       string       `json:"mirror,omitempty"`        // 下载使用的镜像
+	Size         int64        `json:"size,omitempty"`          // 服务器报告的文件大小
+	ETag         string       `json:"etag,omitempty"`          // 服务器返回的ETag
+	LastModified string       `json:"last_modified,omitempty"` // 服务器返回的Last-Modified
+	Stage        InstallStage `json:"stage,omitempty"`         // 已完成的阶段
+	SHA256       string       `json:"sha256,omitempty"`        // 解压时压缩包的SHA-256，与本次校验后的压缩包一致时才复用解压结果
+	UpdatedAt    time.Time    `json:"updated_at"`              // 更新时间
+}
+
+// Validator 获取用于 If-Range 请求的校验值，优先使用ETag
+func (s *InstallState) Validator() string {
+	if s.ETag != "" {
+		return s.ETag
+	}
+	return s.LastModified
+}
+
+// Matches 检查状态是否属于指定的安装（版本、名称和压缩包均一致）
+func (s *InstallState) Matches(context *InstallationContext) bool {
+	return s.Version == context.Version &&
+		s.Name == context.Name &&
+		s.OS == context.SystemInfo.OS &&
+		s.Arch == context.SystemInfo.Arch &&
+		s.Filename == context.SystemInfo.Filename
+}
@@ -0,0 +1,57 @@
+package model
+
+import "time"
+
+// JournalStepType 安装事务日志中的步骤类型
+type JournalStepType string
+
+const (
+	JournalTempDir       JournalStepType = "temp_dir"       // 创建的临时目录
+	JournalArchive       JournalStepType = "archive"        // 下载的压缩包
+	JournalExtractDir    JournalStepType = "extract_dir"    // 解压使用的目录
+	JournalVersionDir    JournalStepType = "version_dir"    // 创建的版本目录
+	JournalMove          JournalStepType = "move"           // 移动到版本目录的文件
+	JournalVersionRecord JournalStepType = "version_record" // 保存的版本记录
+)
+
+// JournalStep 安装事务中的单个步骤，在执行前写入日志，回滚时按相反顺序撤销
+type JournalStep struct {
+	Type    JournalStepType `json:"type"`              // 步骤类型
+	Path    string          `json:"path,omitempty"`    // 创建的文件或目录，移动操作的目标目录
+	Files   []string        `json:"files,omitempty"`   // 移动到 Path 中的顶层文件和目录
+	Version string          `json:"version,omitempty"` // 版本记录的名称
+}
+
+// InstallJournal 持久化的安装事务日志，进程异常退出后用于回滚或继续安装
+type InstallJournal struct {
+	ID        string          `json:"id"`                // 日志标识
+	Version   string          `json:"version"`           // Go发布版本号
+	Name      string          `json:"name,omitempty"`    // 工具链名称，为空时与 Version 相同
+	Source    InstallSource   `json:"source"`            // 安装来源
+	Archive   string          `json:"archive,omitempty"` // 本地压缩包路径（仅压缩包安装）
+	Options   *InstallOptions `json:"options,omitempty"` // 安装选项，继续安装时使用
+	PID       int             `json:"pid"`               // 执行安装的进程ID
+	StartedAt time.Time       `json:"started_at"`        // 开始时间
+	Status    InstallStatus   `json:"status"`            // 中断时所处的阶段
+	Steps     []JournalStep   `json:"steps"`             // 已记录的步骤
+}
+
+// ToolchainName 获取安装的工具链名称
+func (j *InstallJournal) ToolchainName() string {
+	if j.Name == "" {
+		return j.Version
+	}
+	return j.Name
+}
+
+// Resumable 检查中断的安装是否可以继续：在线安装可以重新下载，压缩包安装需要知道压缩包路径
+func (j *InstallJournal) Resumable() bool {
+	switch j.Source {
+	case SourceOnline:
+		return j.Version != ""
+	case SourceArchive:
+		return j.Archive != ""
+	default:
+		return false
+	}
+}
@@ -0,0 +1,93 @@
+package model
+
+import (
+	"sort"
+	"time"
+)
+
+// LockFormatVersion 锁文件格式版本
+const LockFormatVersion = 1
+
+// LockFile 团队锁文件，锁定Go版本及各平台压缩包的来源和校验和
+type LockFile struct {
+	FormatVersion int         `json:"format_version"` // 锁文件格式版本
+	GeneratedAt   time.Time   `json:"generated_at"`   // 生成时间
+	Entries       []LockEntry `json:"entries"`        // 锁定的压缩包
+}
+
+// LockEntry 锁定的单个Go版本在某个平台的压缩包
+type LockEntry struct {
+	Version    string `json:"version"`              // Go发布版本号
+	OS         string `json:"os"`                   // 操作系统
+	Arch       string `json:"arch"`                 // CPU架构
+	Filename   string `json:"filename"`             // 压缩包文件名
+	SHA256     string `json:"sha256"`               // 压缩包SHA-256
+	Size       int64  `json:"size,omitempty"`       // 压缩包大小
+	URL        string `json:"url"`                  // 压缩包来源地址
+	Unverified bool   `json:"unverified,omitempty"` // 校验和取自下载缓存，未经官方发布目录确认
+}
+
+// LockDrift 已安装版本与锁文件之间的差异
+type LockDrift struct {
+	Version  string // 版本号
+	Platform string // 平台，如 linux/amd64
+	Expected string // 锁文件中的SHA-256
+	Actual   string // 安装记录中的SHA-256（未记录时为空）
+	Reason   string // 差异说明
+}
+
+// Platform 返回条目的平台标识，如 linux/amd64
+func (e *LockEntry) Platform() string {
+	return e.OS + "/" + e.Arch
+}
+
+// Find 查找指定版本和平台的条目
+func (l *LockFile) Find(version, os, arch string) (*LockEntry, bool) {
+	for i := range l.Entries {
+		entry := &l.Entries[i]
+		if entry.Version == version && entry.OS == os && entry.Arch == arch {
+			return entry, true
+		}
+	}
+	return nil, false
+}
+
+// Versions 获取锁定的所有版本，按版本号从旧到新排列
+func (l *LockFile) Versions() []string {
+	return l.uniqueSorted(func(entry LockEntry) string { return entry.Version }, func(a, b string) bool {
+		return CompareVersionStrings(a, b).Result < 0
+	})
+}
+
+// Platforms 获取锁定的所有平台
+func (l *LockFile) Platforms() []string {
+	return l.uniqueSorted(func(entry LockEntry) string { return entry.Platform() }, func(a, b string) bool {
+		return a < b
+	})
+}
+
+// Sort 按版本号和平台排序条目，使生成的锁文件内容稳定
+func (l *LockFile) Sort() {
+	sort.SliceStable(l.Entries, func(i, j int) bool {
+		a, b := l.Entries[i], l.Entries[j]
+		if a.Version != b.Version {
+			return CompareVersionStrings(a.Version, b.Version).Result < 0
+		}
+		return a.Platform() < b.Platform()
+	})
+}
+
+// uniqueSorted 获取条目中去重并排序后的字段值
+func (l *LockFile) uniqueSorted(key func(LockEntry) string, less func(a, b string) bool) []string {
+	seen := make(map[string]bool)
+	var values []string
+	for _, entry := range l.Entries {
+		value := key(entry)
+		if !seen[value] {
+			seen[value] = true
+			values = append(values, value)
+		}
+	}
+	sort.SliceStable(values, func(i, j int) bool { return less(values[i], values[j]) })
+	return values
+}
@@ -28,6 +28,7 @@ func GoVersionFromJSON(jsonStr string) (*GoVersion, error) {
 func (v *GoVersion) ToMap() map[string]interface{} {
 	result := map[string]interface{}{
 		"version":    v.Version,
+		"release":    v.ReleaseVersion(),
 		"path":       v.Path,
 		"is_active":  v.IsActive,
 		"source":     v.Source,
@@ -45,8 +46,16 @@ func (v *GoVersion) ToMap() map[string]interface{} {
 		result["install_duration"] = v.InstallDuration.String()
 	}
 
+	if len(v.Tools) > 0 {
+		tools := make([]string, len(v.Tools))
+		for i, tool := range v.Tools {
+			tools[i] = tool.String()
+		}
+		result["tools"] = tools
+	}
+
 	if v.DownloadInfo != nil {
-		result["download_info"] = map[string]interface{}{
+		downloadInfo := map[string]interface{}{
 			"url":           v.DownloadInfo.URL,
 			"filename":      v.DownloadInfo.Filename,
 			"size":          v.DownloadInfo.Size,
@@ -56,6 +65,13 @@ func (v *GoVersion) ToMap() map[string]interface{} {
 			"duration":      v.DownloadInfo.Duration,
 			"speed":         v.DownloadInfo.Speed,
 		}
+		if v.DownloadInfo.Mirror != "" {
+			downloadInfo["mirror"] = v.DownloadInfo.Mirror
+		}
+		if len(v.DownloadInfo.AttemptedMirrors) > 0 {
+			downloadInfo["attempted_mirrors"] = v.DownloadInfo.AttemptedMirrors
+		}
+		result["download_info"] = downloadInfo
 	}
 
 	if v.ExtractInfo != nil {
@@ -69,12 +85,16 @@ func (v *GoVersion) ToMap() map[string]interface{} {
 	}
 
 	if v.ValidationInfo != nil {
-		result["validation_info"] = map[string]interface{}{
+		validationInfo := map[string]interface{}{
 			"checksum_valid":   v.ValidationInfo.ChecksumValid,
 			"executable_valid": v.ValidationInfo.ExecutableValid,
 			"version_valid":    v.ValidationInfo.VersionValid,
 			"error":            v.ValidationInfo.Error,
 		}
+		if v.ValidationInfo.GoVersionOutput != "" {
+			validationInfo["go_version_output"] = v.ValidationInfo.GoVersionOutput
+		}
+		result["validation_info"] = validationInfo
 	}
 
 	if v.SystemInfo != nil {
@@ -94,6 +114,7 @@ func (v *GoVersion) ToMap() map[string]interface{} {
 func (v *GoVersion) Clone() *GoVersion {
 	clone := &GoVersion{
 		Version:         v.Version,
+		Release:         v.Release,
 		Path:            v.Path,
 		IsActive:        v.IsActive,
 		Source:          v.Source,
@@ -115,6 +136,12 @@ func (v *GoVersion) Clone() *GoVersion {
 		copy(clone.Tags, v.Tags)
 	}
 
+	// 复制Tools
+	if v.Tools != nil {
+		clone.Tools = make([]InstalledTool, len(v.Tools))
+		copy(clone.Tools, v.Tools)
+	}
+
 	// 复制DownloadInfo
 	if v.DownloadInfo != nil {
 		clone.DownloadInfo = &DownloadInfo{
@@ -126,6 +153,11 @@ func (v *GoVersion) Clone() *GoVersion {
 			DownloadedAt: v.DownloadInfo.DownloadedAt,
 			Duration:     v.DownloadInfo.Duration,
 			Speed:        v.DownloadInfo.Speed,
+			Mirror:       v.DownloadInfo.Mirror,
+		}
+		if v.DownloadInfo.AttemptedMirrors != nil {
+			clone.DownloadInfo.AttemptedMirrors = make([]string, len(v.DownloadInfo.AttemptedMirrors))
+			copy(clone.DownloadInfo.AttemptedMirrors, v.DownloadInfo.AttemptedMirrors)
 		}
 	}
 
@@ -147,6 +179,7 @@ func (v *GoVersion) Clone() *GoVersion {
 			ExecutableValid: v.ValidationInfo.ExecutableValid,
 			VersionValid:    v.ValidationInfo.VersionValid,
 			Error:           v.ValidationInfo.Error,
+			GoVersionOutput: v.ValidationInfo.GoVersionOutput,
 		}
 	}
 
@@ -174,7 +207,14 @@ func (v *GoVersion) Validate() error {
 		return fmt.Errorf("安装路径不能为空")
 	}
 
-	if v.Source != SourceLocal && v.Source != SourceOnline {
+	// 自定义名称的工具链，名称用作安装目录名
+	if v.IsNamed() {
+		if err := ValidateToolchainName(v.Version); err != nil {
+			return err
+		}
+	}
+
+	if v.Source != SourceLocal && v.Source != SourceOnline && v.Source != SourceArchive && v.Source != SourceBuild {
 		return fmt.Errorf("无效的安装来源: %d", v.Source)
 	}
 
@@ -212,8 +252,13 @@ func (v *GoVersion) Validate() error {
 // Summary 返回版本的摘要信息
 func (v *GoVersion) Summary() string {
 	status := "本地"
-	if v.Source == SourceOnline {
+	switch v.Source {
+	case SourceOnline:
 		status = "在线"
+	case SourceArchive:
+		status = "压缩包"
+	case SourceBuild:
+		status = "源码编译"
 	}
 
 	active := ""
@@ -226,5 +271,10 @@ func (v *GoVersion) Summary() string {
 		tags = fmt.Sprintf(" [%s]", v.Tags[0])
 	}
 
-	return fmt.Sprintf("Go %s%s - %s安装%s", v.Version, active, status, tags)
+	name := v.Version
+	if v.IsNamed() {
+		name = fmt.Sprintf("%s (go%s)", v.Version, v.Release)
+	}
+
+	return fmt.Sprintf("Go %s%s - %s安装%s", name, active, status, tags)
 }
@@ -0,0 +1,100 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SyncManifest 声明式的工具链清单，描述机器上应安装的版本、默认版本和需要移除的版本
+type SyncManifest struct {
+	Default    string          `json:"default,omitempty" yaml:"default,omitempty"` // 默认（全局）使用的版本
+	Toolchains []SyncToolchain `json:"toolchains" yaml:"toolchains"`               // 必须安装的工具链
+	Remove     []string        `json:"remove,omitempty" yaml:"remove,omitempty"`   // 需要移除的版本
+}
+
+// SyncToolchain 清单中必须安装的工具链
+type SyncToolchain struct {
+	Version string   `json:"version" yaml:"version"`               // Go发布版本号
+	Name    string   `json:"name,omitempty" yaml:"name,omitempty"` // 自定义工具链名称，为空时使用版本号
+	Tags    []string `json:"tags,omitempty" yaml:"tags,omitempty"` // 必须具有的标签
+}
+
+// ToolchainName 获取工具链在版本列表中的名称
+func (t *SyncToolchain) ToolchainName() string {
+	if t.Name != "" {
+		return t.Name
+	}
+	return t.Version
+}
+
+// SyncActionType 同步操作类型
+type SyncActionType string
+
+const (
+	SyncInstall   SyncActionType = "install"   // 安装缺少的工具链
+	SyncReinstall SyncActionType = "reinstall" // 重新安装发布版本与清单不一致的工具链
+	SyncTag       SyncActionType = "tag"       // 为已安装的工具链添加缺少的标签
+	SyncUse       SyncActionType = "use"       // 切换默认版本
+	SyncRemove    SyncActionType = "remove"    // 移除版本
+)
+
+// SyncAction 同步计划中的单个操作
+type SyncAction struct {
+	Type      SyncActionType // 操作类型
+	Version   string         // 版本列表中的名称
+	Release   string         // Go发布版本号（仅安装和重新安装操作）
+	Installed string         // 已安装的发布版本号（仅重新安装操作）
+	Tags      []string       // 需要添加的标签
+}
+
+// String 获取操作的说明
+func (a *SyncAction) String() string {
+	switch a.Type {
+	case SyncInstall:
+		description := "安装 Go " + a.Version
+		if a.Release != "" && a.Release != a.Version {
+			description += fmt.Sprintf("（go%s）", a.Release)
+		}
+		if len(a.Tags) > 0 {
+			description += "，标签: " + strings.Join(a.Tags, ", ")
+		}
+		return description
+	case SyncReinstall:
+		description := fmt.Sprintf("重新安装 Go %s（已安装 go%s，清单要求 go%s）", a.Version, a.Installed, a.Release)
+		if len(a.Tags) > 0 {
+			description += "，标签: " + strings.Join(a.Tags, ", ")
+		}
+		return description
+	case SyncTag:
+		return fmt.Sprintf("为 Go %s 添加标签: %s", a.Version, strings.Join(a.Tags, ", "))
+	case SyncUse:
+		return fmt.Sprintf("将默认版本切换到 Go %s", a.Version)
+	case SyncRemove:
+		return "移除 Go " + a.Version
+	default:
+		return string(a.Type) + " " + a.Version
+	}
+}
+
+// SyncPlan 清单与已安装版本的差异，按安装、重新安装、添加标签、切换默认版本、移除的顺序执行
+type SyncPlan struct {
+	Actions   []SyncAction // 需要执行的操作
+	Unchanged []string     // 已与清单一致的工具链
+}
+
+// Empty 检查是否已与清单一致
+func (p *SyncPlan) Empty() bool {
+	return len(p.Actions) == 0
+}
+
+// SyncResult 同步操作的执行结果
+type SyncResult struct {
+	Action SyncAction          // 执行的操作
+	Result *InstallationResult // 安装结果（仅安装操作）
+	Error  string              // 失败原因，成功时为空
+}
+
+// Success 检查操作是否成功
+func (r *SyncResult) Success() bool {
+	return r.Error == ""
+}
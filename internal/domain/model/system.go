@@ -6,43 +6,84 @@ import (
 
 // SystemInfo 系统信息
 type SystemInfo struct {
-	OS       string // 操作系统: windows, linux, darwin
-	Arch     string // CPU架构: amd64, arm64, 386
-	Version  string // Go版本号
-	Filename string // 下载文件名
-	URL      string // 下载URL
-	Mirror   string // 使用的镜像源名称
+	OS          string // 操作系统: windows, linux, darwin
+	Arch        string // CPU架构: amd64, arm64, 386
+	Version     string // Go版本号
+	Filename    string // 下载文件名
+	URL         string // 下载URL
+	Mirror      string // 使用的镜像源名称
+	ChecksumURL string // SHA-256校验和文件地址（镜像未提供时为空）
 }
 
 // InstallSource 安装来源
 type InstallSource int
 
 const (
-	SourceLocal  InstallSource = iota // 本地导入
-	SourceOnline                      // 在线下载
+	SourceLocal   InstallSource = iota // 本地导入
+	SourceOnline                       // 在线下载
+	SourceArchive                      // 本地压缩包安装
+	SourceBuild                        // 从源码编译安装
 )
 
+// String 返回安装来源的字符串表示
+func (s InstallSource) String() string {
+	switch s {
+	case SourceLocal:
+		return "local"
+	case SourceOnline:
+		return "online"
+	case SourceArchive:
+		return "archive"
+	case SourceBuild:
+		return "source-build"
+	default:
+		return "unknown"
+	}
+}
+
 // DownloadInfo 下载信息
 type DownloadInfo struct {
-	URL          string    // 下载URL
-	Filename     string    // 文件名
-	Size         int64     // 文件大小
-	Checksum     string    // 校验和
-	ChecksumType string    // 校验和类型 (sha256, md5等)
-	DownloadedAt time.Time // 下载时间
-	Duration     int64     // 下载耗时（毫秒）
-	Speed        float64   // 平均下载速度（字节/秒）
+	URL              string    // 下载URL
+	Filename         string    // 文件名
+	Size             int64     // 文件大小
+	Checksum         string    // 校验和
+	ChecksumType     string    // 校验和类型 (sha256, md5等)
+	DownloadedAt     time.Time // 下载时间
+	Duration         int64     // 下载耗时（毫秒）
+	Speed            float64   // 平均下载速度（字节/秒）
+	Mirror           string    // 实际完成下载的镜像源
+	AttemptedMirrors []string  // 依次尝试过的镜像源（包括失败的镜像）
 }
 
 // InstallationContext 安装上下文
 type InstallationContext struct {
-	Version    string          // 目标版本
-	SystemInfo *SystemInfo     // 系统信息
-	Paths      *InstallPaths   // 安装路径
-	TempDir    string          // 临时目录
-	Options    *InstallOptions // 安装选项
-	StartTime  time.Time       // 安装开始时间
-	Status     InstallStatus   // 安装状态
+	Version       string            // 目标版本
+	Name          string            // 工具链名称（版本列表中的唯一标识），为空时与 Version 相同
+	Source        InstallSource     // 安装来源
+	SystemInfo    *SystemInfo       // 系统信息
+	Paths         *InstallPaths     // 安装路径
+	TempDir       string            // 临时目录
+	Options       *InstallOptions   // 安装选项
+	StartTime     time.Time         // 安装开始时间
+	Status        InstallStatus     // 安装状态
+	Mirrors       []MirrorCandidate // 下载失败时依次尝试的镜像（第一个为选定的镜像）
+	State         *InstallState     // 临时目录中保存的安装状态，用于进程重启后继续安装
+	ArchiveSHA256 string            // 本次下载（并校验）的压缩包的SHA-256
+}
+
+// ToolchainName 获取安装后在版本列表中使用的名称，未指定名称时为版本号
+func (c *InstallationContext) ToolchainName() string {
+	if c.Name == "" {
+		return c.Version
+	}
+	return c.Name
+}
+
+// MirrorCandidate 下载时可切换的候选镜像
+type MirrorCandidate struct {
+	Name        string // 镜像源名称
+	URL         string // 压缩包下载地址
+	ChecksumURL string // SHA-256校验和文件地址（未提供时为空）
 }
 
 // InstallPaths 安装路径配置
@@ -58,10 +99,33 @@ type InstallOptions struct {
 	Force            bool   // 强制重新安装
 	CustomPath       string // 自定义安装路径
 	SkipVerification bool   // 跳过文件验证
-	Timeout          int    // 超时时间（秒）
+	Timeout          int    // 下载停滞超时（秒），超过该时间没有下载进度时中断当前镜像，0 表示不限制
 	MaxRetries       int    // 最大重试次数
 	Mirror           string // 指定镜像源名称
 	AutoMirror       bool   // 自动选择最快镜像
+	SHA256           string // 期望的压缩包SHA-256校验和（为空时不校验）
+	NoFailover       bool   // 下载失败时不切换到其他镜像
+	// TargetSystem 目标平台（只使用 OS 和 Arch），为空时使用当前系统
+	// 与当前系统不同时必须指定 CustomPath，安装结果不加入版本列表
+	TargetSystem *SystemInfo
+	// BootstrapVersion 从源码编译时使用的引导工具链版本，为空时自动选择已安装的版本
+	BootstrapVersion string
+	// Locked 按锁文件安装，SHA256 为锁文件中的校验和，压缩包不一致时拒绝安装
+	Locked bool
+	// Name 自定义工具链名称（如 1.22.7-fips），用于在版本列表中区分同一发布版本的不同构建，为空时使用版本号
+	Name string
+	// NoHooks 不运行 pre-install/post-install 钩子
+	NoHooks bool
+	// NoTools 不安装默认工具
+	NoTools bool
+}
+
+// ToolchainName 获取安装后在版本列表中使用的名称，未指定名称时为版本号
+func (o *InstallOptions) ToolchainName(version string) string {
+	if o == nil || o.Name == "" {
+		return version
+	}
+	return o.Name
 }
 
 // InstallStatus 安装状态
@@ -101,13 +165,15 @@ func (s InstallStatus) String() string {
 
 // InstallationResult 安装结果
 type InstallationResult struct {
-	Success      bool          // 是否成功
-	Version      string        // 安装的版本
-	Path         string        // 安装路径
-	Duration     time.Duration // 安装耗时
-	Error        string        // 错误信息（如果失败）
-	DownloadInfo *DownloadInfo // 下载信息
-	ExtractInfo  *ExtractInfo  // 解压信息
+	Success      bool            // 是否成功
+	Version      string          // 安装的版本
+	Path         string          // 安装路径
+	Duration     time.Duration   // 安装耗时
+	Error        string          // 错误信息（如果失败）
+	DownloadInfo *DownloadInfo   // 下载信息
+	ExtractInfo  *ExtractInfo    // 解压信息
+	Tools        []InstalledTool // 安装的默认工具
+	Warnings     []string        // 钩子和默认工具失败的警告（不影响安装结果）
 }
 
 // ExtractInfo 解压信息
@@ -125,4 +191,5 @@ type ValidationInfo struct {
 	ExecutableValid bool   // 可执行文件是否有效
 	VersionValid    bool   // 版本信息是否正确
 	Error           string // 验证错误信息
+	GoVersionOutput string // go version 命令的输出
 }
@@ -267,3 +267,21 @@ func TestInstallOptions_DefaultValues(t *testing.T) {
 		t.Errorf("MaxRetries = %d, 期望 %d", options.MaxRetries, 3)
 	}
 }
+
+func TestInstallSource_String(t *testing.T) {
+	tests := []struct {
+		source   InstallSource
+		expected string
+	}{
+		{SourceLocal, "local"},
+		{SourceOnline, "online"},
+		{SourceArchive, "archive"},
+		{InstallSource(99), "unknown"},
+	}
+
+	for _, tt := range tests {
+		if result := tt.source.String(); result != tt.expected {
+			t.Errorf("InstallSource(%d).String() = %s, 期望 %s", int(tt.source), result, tt.expected)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package model
 
 import (
 	"fmt"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
@@ -9,7 +10,8 @@ import (
 
 // GoVersion 表示一个Go版本
 type GoVersion struct {
-	Version         string          // 版本号，如 "1.21.0"
+	Version         string          // 版本号或工具链名称，如 "1.21.0"、"1.22.7-fips"，在版本列表中唯一
+	Release         string          // 检测到的Go发布版本号，如 "1.22.7"（为空时与 Version 相同）
 	Path            string          // 安装路径
 	IsActive        bool            // 是否为当前激活版本
 	Source          InstallSource   // 安装来源
@@ -23,13 +25,77 @@ type GoVersion struct {
 	InstallDuration time.Duration   // 安装耗时
 	Tags            []string        // 标签（如 "stable", "beta", "rc"）
 	Notes           string          // 备注信息
+	Tools           []InstalledTool // 安装后使用此工具链 go install 的默认工具
 }
 
 // Environment 表示环境变量配置
 type Environment struct {
-	GOROOT string // Go安装目录
-	GOPATH string // 工作目录
-	GOBIN  string // 二进制文件目录
+	GOROOT          string                       // Go安装目录
+	GOPATH          string                       // 工作目录
+	GOBIN           string                       // 二进制文件目录
+	GOCACHE         string                       // 构建缓存目录，为空时使用 go 的默认值
+	GOMODCACHE      string                       // 模块缓存目录，为空时使用 go 的默认值
+	Isolation       IsolationMode                // GOBIN、GOCACHE 的隔离方式，为空时与 global 相同
+	IsolateModCache bool                         // 按版本隔离时是否同时隔离 GOMODCACHE
+	Vars            map[string]string            // 所有版本使用的自定义环境变量
+	VersionVars     map[string]map[string]string // 按版本名称设置的自定义环境变量
+}
+
+// IsolationMode GOBIN、GOCACHE 等目录的隔离方式
+type IsolationMode string
+
+const (
+	IsolationGlobal  IsolationMode = "global"  // 所有版本共用
+	IsolationVersion IsolationMode = "version" // 每个版本使用独立的目录
+)
+
+// ParseIsolationMode 解析隔离方式
+func ParseIsolationMode(value string) (IsolationMode, error) {
+	switch mode := IsolationMode(strings.ToLower(strings.TrimSpace(value))); mode {
+	case IsolationGlobal, IsolationVersion:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("无效的隔离方式 '%s'，支持: global, version", value)
+	}
+}
+
+// IsVersionIsolated 检查是否按版本隔离
+func (e *Environment) IsVersionIsolated() bool {
+	return e.Isolation == IsolationVersion
+}
+
+// toolchainNamePattern 工具链名称格式：字母或数字开头，只包含字母、数字和 . _ + -
+var toolchainNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._+-]*$`)
+
+// releaseNamePattern Go发布版本号格式（如 1.23.0、go1.23rc1），不能用作其他发布版本的工具链名称
+var releaseNamePattern = regexp.MustCompile(`^(?:go)?\d+(?:\.\d+)+(?:(?:rc|beta)\d+)?$`)
+
+// ValidateToolchainName 验证自定义工具链名称，名称同时用作安装目录名。
+// 自定义名称与实际发布版本号不同，因此不能是Go发布版本号，否则会与该发布版本混淆
+func ValidateToolchainName(name string) error {
+	if len(name) > 64 {
+		return fmt.Errorf("工具链名称不能超过64个字符: %s", name)
+	}
+	if !toolchainNamePattern.MatchString(name) {
+		return fmt.Errorf("无效的工具链名称 '%s'：必须以字母或数字开头，只能包含字母、数字和 . _ + -", name)
+	}
+	if releaseNamePattern.MatchString(name) {
+		return fmt.Errorf("工具链名称 '%s' 是Go发布版本号，只能使用实际的发布版本号作为名称，或使用其他名称（如 %s-custom）", name, name)
+	}
+	return nil
+}
+
+// ReleaseVersion 获取Go发布版本号，未记录时使用版本号
+func (v *GoVersion) ReleaseVersion() string {
+	if v.Release != "" {
+		return v.Release
+	}
+	return v.Version
+}
+
+// IsNamed 检查是否为自定义名称的工具链（名称与发布版本号不同）
+func (v *GoVersion) IsNamed() bool {
+	return v.Version != v.ReleaseVersion()
 }
 
 // IsOnlineInstalled 检查是否为在线安装
@@ -172,9 +238,26 @@ func (v *GoVersion) GetLastUsedDate() string {
 	return v.LastUsedAt.Format("2006-01-02 15:04:05")
 }
 
-// CompareVersion 比较两个版本号
+// CompareVersion 比较两个版本：先比较Go发布版本号，相同时比较工具链名称（未命名的排在前面）
 func (v *GoVersion) CompareVersion(other *GoVersion) *VersionComparison {
-	return CompareVersionStrings(v.Version, other.Version)
+	result := CompareVersionStrings(v.ReleaseVersion(), other.ReleaseVersion())
+	result.Version1 = v.Version
+	result.Version2 = other.Version
+	if result.Error != "" || result.Result != 0 {
+		return result
+	}
+
+	switch {
+	case v.Version == other.Version:
+		result.Result = 0
+	case !v.IsNamed():
+		result.Result = -1
+	case !other.IsNamed():
+		result.Result = 1
+	default:
+		result.Result = strings.Compare(v.Version, other.Version)
+	}
+	return result
 }
 
 // Matches 检查版本是否匹配过滤器
@@ -279,59 +362,115 @@ func matchPattern(version, pattern string) (bool, error) {
 	return version == pattern, nil
 }
 
+// versionStringPattern 版本字符串格式：发布版本号，可选的预发布标识（beta/rc）和名称后缀，如 1.22rc1、1.22.7-fips
+var versionStringPattern = regexp.MustCompile(`^(\d+(?:\.\d+)*)(?:(beta|rc)(\d+))?(?:[-+._]?(.+))?$`)
+
+// parsedVersion 解析后的版本字符串
+type parsedVersion struct {
+	numbers    []int  // 发布版本号各部分
+	prerelease int    // 预发布阶段: 0(beta), 1(rc), 2(正式版)
+	preNumber  int    // 预发布序号
+	suffix     string // 名称后缀
+}
+
+// parseVersionString 解析版本字符串
+func parseVersionString(version string) (*parsedVersion, bool) {
+	match := versionStringPattern.FindStringSubmatch(version)
+	if match == nil {
+		return nil, false
+	}
+
+	parsed := &parsedVersion{prerelease: 2, suffix: match[4]}
+	for _, part := range strings.Split(match[1], ".") {
+		number, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, false
+		}
+		parsed.numbers = append(parsed.numbers, number)
+	}
+	if match[2] != "" {
+		parsed.prerelease = 0
+		if match[2] == "rc" {
+			parsed.prerelease = 1
+		}
+		parsed.preNumber, _ = strconv.Atoi(match[3])
+	}
+	return parsed, true
+}
+
+// compareInts 比较两个整数
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// compare 先按发布版本号和预发布阶段比较，相同时比较名称后缀（无后缀的排在前面）
+func (p *parsedVersion) compare(other *parsedVersion) int {
+	maxLen := len(p.numbers)
+	if len(other.numbers) > maxLen {
+		maxLen = len(other.numbers)
+	}
+	for i := 0; i < maxLen; i++ {
+		var n1, n2 int
+		if i < len(p.numbers) {
+			n1 = p.numbers[i]
+		}
+		if i < len(other.numbers) {
+			n2 = other.numbers[i]
+		}
+		if result := compareInts(n1, n2); result != 0 {
+			return result
+		}
+	}
+
+	if result := compareInts(p.prerelease, other.prerelease); result != 0 {
+		return result
+	}
+	if result := compareInts(p.preNumber, other.preNumber); result != 0 {
+		return result
+	}
+
+	switch {
+	case p.suffix == other.suffix:
+		return 0
+	case p.suffix == "":
+		return -1
+	case other.suffix == "":
+		return 1
+	}
+	return strings.Compare(p.suffix, other.suffix)
+}
+
 // CompareVersionStrings 比较两个版本字符串
+// 先比较发布版本号（rc、beta 等预发布版本排在正式版之前），发布版本相同时比较名称后缀，如 1.22.7 < 1.22.7-fips
 func CompareVersionStrings(v1, v2 string) *VersionComparison {
 	result := &VersionComparison{
 		Version1: v1,
 		Version2: v2,
 	}
 
-	// 简单的版本比较实现
-	// 实际项目中可能需要更复杂的语义版本比较
 	if v1 == v2 {
 		result.Result = 0
 		return result
 	}
 
-	// 按点分割版本号
-	parts1 := strings.Split(v1, ".")
-	parts2 := strings.Split(v2, ".")
-
-	maxLen := len(parts1)
-	if len(parts2) > maxLen {
-		maxLen = len(parts2)
+	parsed1, ok := parseVersionString(v1)
+	if !ok {
+		result.Error = fmt.Sprintf("无效的版本号格式: %s", v1)
+		return result
 	}
-
-	for i := 0; i < maxLen; i++ {
-		var p1, p2 int
-		var err error
-
-		if i < len(parts1) {
-			p1, err = strconv.Atoi(parts1[i])
-			if err != nil {
-				result.Error = fmt.Sprintf("无效的版本号格式: %s", v1)
-				return result
-			}
-		}
-
-		if i < len(parts2) {
-			p2, err = strconv.Atoi(parts2[i])
-			if err != nil {
-				result.Error = fmt.Sprintf("无效的版本号格式: %s", v2)
-				return result
-			}
-		}
-
-		if p1 < p2 {
-			result.Result = -1
-			return result
-		} else if p1 > p2 {
-			result.Result = 1
-			return result
-		}
+	parsed2, ok := parseVersionString(v2)
+	if !ok {
+		result.Error = fmt.Sprintf("无效的版本号格式: %s", v2)
+		return result
 	}
 
-	result.Result = 0
+	result.Result = parsed1.compare(parsed2)
 	return result
 }
 
@@ -368,7 +507,7 @@ func SortVersions(versions []*GoVersion, sorter *VersionSorter) []*GoVersion {
 			// 降序排列
 			for i := 0; i < len(sorted)-1; i++ {
 				for j := i + 1; j < len(sorted); j++ {
-					comp := CompareVersionStrings(sorted[i].Version, sorted[j].Version)
+					comp := sorted[i].CompareVersion(sorted[j])
 					if comp.Result < 0 {
 						sorted[i], sorted[j] = sorted[j], sorted[i]
 					}
@@ -378,7 +517,7 @@ func SortVersions(versions []*GoVersion, sorter *VersionSorter) []*GoVersion {
 			// 升序排列
 			for i := 0; i < len(sorted)-1; i++ {
 				for j := i + 1; j < len(sorted); j++ {
-					comp := CompareVersionStrings(sorted[i].Version, sorted[j].Version)
+					comp := sorted[i].CompareVersion(sorted[j])
 					if comp.Result > 0 {
 						sorted[i], sorted[j] = sorted[j], sorted[i]
 					}
@@ -448,9 +587,14 @@ func CalculateStatistics(versions []*GoVersion) *VersionStatistics {
 
 	for _, v := range versions {
 		// 统计安装来源
-		if v.Source == SourceOnline {
+		switch v.Source {
+		case SourceOnline:
 			stats.OnlineVersions++
-		} else {
+		case SourceArchive:
+			stats.ArchiveVersions++
+		case SourceBuild:
+			stats.BuildVersions++
+		default:
 			stats.LocalVersions++
 		}
 
@@ -530,6 +674,8 @@ type VersionStatistics struct {
 	TotalVersions      int            // 总版本数
 	OnlineVersions     int            // 在线安装版本数
 	LocalVersions      int            // 本地导入版本数
+	ArchiveVersions    int            // 压缩包安装版本数
+	BuildVersions      int            // 源码编译安装版本数
 	ActiveVersion      string         // 当前激活版本
 	TotalDiskUsage     int64          // 总磁盘使用量
 	MostRecentlyUsed   string         // 最近使用的版本
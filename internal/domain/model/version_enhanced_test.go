@@ -1,6 +1,7 @@
 package model
 
 import (
+	"strings"
 	"testing"
 	"time"
 )
@@ -197,6 +198,51 @@ func TestGoVersion_CompareVersion(t *testing.T) {
 	}
 }
 
+func TestGoVersion_CompareVersion_Named(t *testing.T) {
+	upstream := &GoVersion{Version: "1.22.7"}
+	fips := &GoVersion{Version: "corp-fips", Release: "1.22.7"}
+	boring := &GoVersion{Version: "boring", Release: "1.22.7"}
+	newer := &GoVersion{Version: "1.22.8"}
+
+	tests := []struct {
+		v1, v2   *GoVersion
+		expected int
+	}{
+		{upstream, fips, -1},
+		{fips, upstream, 1},
+		{boring, fips, -1},
+		{fips, newer, -1},
+		{fips, fips, 0},
+	}
+	for _, tt := range tests {
+		if comp := tt.v1.CompareVersion(tt.v2); comp.Result != tt.expected || comp.Error != "" {
+			t.Errorf("%s vs %s = %d (%s), 期望 %d", tt.v1.Version, tt.v2.Version, comp.Result, comp.Error, tt.expected)
+		}
+	}
+
+	sorted := SortVersions([]*GoVersion{newer, fips, upstream, boring}, &VersionSorter{Field: "version", Direction: "asc"})
+	var names []string
+	for _, v := range sorted {
+		names = append(names, v.Version)
+	}
+	if strings.Join(names, ",") != "1.22.7,boring,corp-fips,1.22.8" {
+		t.Errorf("排序结果 = %v, 期望先按发布版本再按名称排序", names)
+	}
+}
+
+func TestValidateToolchainName(t *testing.T) {
+	for _, name := range []string{"1.22.7-fips", "corp_go+boring", "go1.22.7.custom"} {
+		if err := ValidateToolchainName(name); err != nil {
+			t.Errorf("ValidateToolchainName(%s) 返回错误: %v", name, err)
+		}
+	}
+	for _, name := range []string{"", "-fips", "../1.22.7", "fips build", "a/b", strings.Repeat("a", 65), "1.23.0", "1.23", "go1.23.0", "1.24rc1"} {
+		if err := ValidateToolchainName(name); err == nil {
+			t.Errorf("ValidateToolchainName(%q) 期望返回错误", name)
+		}
+	}
+}
+
 func TestGoVersion_Matches(t *testing.T) {
 	now := time.Now()
 	version := &GoVersion{
@@ -308,6 +354,13 @@ func TestCompareVersionStrings(t *testing.T) {
 		{"1.21.1", "1.21.0", 1},
 		{"2.0.0", "1.21.0", 1},
 		{"1.19.0", "1.21.0", -1},
+		{"1.21", "1.21.0", 0},
+		{"1.22rc1", "1.22.0", -1},
+		{"1.22rc2", "1.22rc1", 1},
+		{"1.22beta1", "1.22rc1", -1},
+		{"1.22.7-fips", "1.22.7", 1},
+		{"1.22.7-fips", "1.22.8", -1},
+		{"1.22.7-boring", "1.22.7-fips", -1},
 	}
 
 	for _, tt := range tests {
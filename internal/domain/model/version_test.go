@@ -1,6 +1,7 @@
 package model
 
 import (
+	"strings"
 	"testing"
 	"time"
 )
@@ -221,3 +222,115 @@ func TestInstallStatus_String(t *testing.T) {
 		})
 	}
 }
+
+func TestParseIsolationMode(t *testing.T) {
+	testCases := []struct {
+		value    string
+		expected IsolationMode
+		wantErr  bool
+	}{
+		{"global", IsolationGlobal, false},
+		{"Version", IsolationVersion, false},
+		{"project", "", true},
+		{"", "", true},
+	}
+
+	for _, tc := range testCases {
+		mode, err := ParseIsolationMode(tc.value)
+		if (err != nil) != tc.wantErr || mode != tc.expected {
+			t.Errorf("ParseIsolationMode(%q) = %s, %v, 期望 %s", tc.value, mode, err, tc.expected)
+		}
+	}
+}
+
+func TestResolveEnvVars(t *testing.T) {
+	env := &Environment{
+		Vars:        map[string]string{"GOFLAGS": "-mod=mod", "CGO_ENABLED": "1"},
+		VersionVars: map[string]map[string]string{"1.21.13": {"CGO_ENABLED": "0"}},
+	}
+	project := &ProjectEnvironment{Env: map[string]string{"GOFLAGS": "-mod=vendor"}}
+
+	vars := ResolveEnvVars(env, "1.21.13", project)
+	if len(vars) != 2 {
+		t.Fatalf("期望 2 个变量，实际 %d 个", len(vars))
+	}
+	if vars[0] != (EnvVar{Name: "CGO_ENABLED", Value: "0", Source: EnvScopeVersion}) {
+		t.Errorf("CGO_ENABLED = %+v, 期望使用版本的值", vars[0])
+	}
+	if vars[1] != (EnvVar{Name: "GOFLAGS", Value: "-mod=vendor", Source: EnvScopeProject}) {
+		t.Errorf("GOFLAGS = %+v, 期望使用项目的值", vars[1])
+	}
+}
+
+func TestEffectiveEnvironment_PathValue(t *testing.T) {
+	env := &EffectiveEnvironment{Path: []string{"/go/current/bin", "/go/isolated/1.22.7/bin"}}
+
+	path := env.PathValue(ShellState{Path: "/usr/bin:/go/current/bin::/bin"}, ":")
+	if path != "/go/current/bin:/go/isolated/1.22.7/bin:/usr/bin:/bin" {
+		t.Errorf("PATH = %s", path)
+	}
+	// 重复执行时 PATH 不变
+	if again := env.PathValue(ShellState{Path: path, AddedPath: strings.Join(env.Path, ":")}, ":"); again != path {
+		t.Errorf("重复执行后 PATH = %s, 期望 %s", again, path)
+	}
+
+	// 切换版本后移除之前加入的隔离目录
+	other := &EffectiveEnvironment{Path: []string{"/go/current/bin", "/go/isolated/1.21.13/bin"}}
+	switched := other.PathValue(ShellState{Path: path, AddedPath: strings.Join(env.Path, ":")}, ":")
+	if switched != "/go/current/bin:/go/isolated/1.21.13/bin:/usr/bin:/bin" {
+		t.Errorf("切换版本后 PATH = %s", switched)
+	}
+}
+
+func TestEffectiveEnvironment_StaleVars(t *testing.T) {
+	env := &EffectiveEnvironment{Vars: []EnvVar{{Name: "GOROOT"}, {Name: "GOBIN"}}}
+	if names := env.VarNames(); names != "GOROOT,GOBIN" {
+		t.Errorf("VarNames() = %s", names)
+	}
+
+	// 切换为全局隔离后不再设置 GOCACHE，离开项目后不再设置 GOFLAGS；无效的名称被忽略
+	stale := env.StaleVars(ShellState{Vars: "GOROOT,GOBIN,GOCACHE,GOFLAGS,,PATH,$(rm)"})
+	if strings.Join(stale, ",") != "GOCACHE,GOFLAGS" {
+		t.Errorf("StaleVars() = %v", stale)
+	}
+}
+
+func TestValidateEnvVarName(t *testing.T) {
+	for _, name := range []string{"GOFLAGS", "CGO_ENABLED", "GOTOOLCHAIN", "GOEXPERIMENT", "HTTP_PROXY"} {
+		if err := ValidateEnvVarName(name); err != nil {
+			t.Errorf("%s 应允许自定义: %v", name, err)
+		}
+	}
+	for _, name := range []string{"GOROOT", "PATH", "PROMPT_COMMAND", "BASH_ENV", "ENV", "PS1", "ld_preload", "LD_LIBRARY_PATH", "DYLD_INSERT_LIBRARIES", "BASH_FUNC_ls", "1GO"} {
+		if err := ValidateEnvVarName(name); err == nil {
+			t.Errorf("%s 不应允许自定义", name)
+		}
+	}
+
+	// 不允许的变量即使已保存在配置中也不生效
+	vars := ResolveEnvVars(nil, "", &ProjectEnvironment{Env: map[string]string{"LD_PRELOAD": "/tmp/x.so", "GOFLAGS": "-mod=mod"}})
+	if len(vars) != 1 || vars[0].Name != "GOFLAGS" {
+		t.Errorf("合并结果 = %v", vars)
+	}
+}
+
+func TestProjectEnvAllowList(t *testing.T) {
+	var list ProjectEnvAllowList
+	if list.Allowed("/src/app/go-version.env.yaml", "aa") {
+		t.Error("未允许的文件不应通过")
+	}
+	list.Allow("/src/app/go-version.env.yaml", "aa", time.Now())
+	if !list.Allowed("/src/app/go-version.env.yaml", "AA") {
+		t.Error("已允许的文件应通过")
+	}
+	if list.Allowed("/src/app/go-version.env.yaml", "bb") {
+		t.Error("内容变化后应重新允许")
+	}
+	list.Allow("/src/app/go-version.env.yaml", "bb", time.Now())
+	if len(list.Files) != 1 || !list.Allowed("/src/app/go-version.env.yaml", "bb") {
+		t.Errorf("重新允许后应替换之前的记录: %+v", list.Files)
+	}
+	if !list.Revoke("/src/app/go-version.env.yaml") || list.Revoke("/src/app/go-version.env.yaml") {
+		t.Error("撤销应只对已允许的文件返回 true")
+	}
+}
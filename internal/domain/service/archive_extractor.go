@@ -4,6 +4,7 @@ import (
 	"archive/tar"
 	"archive/zip"
 	"compress/gzip"
+	"context"
 	"fmt"
 	"io"
 	"os"
@@ -43,6 +44,11 @@ type ArchiveExtractor interface {
 	GetArchiveInfo(archivePath string) (*ArchiveInfo, error)
 }
 
+// ContextArchiveExtractor 支持通过上下文取消的解压服务
+type ContextArchiveExtractor interface {
+	ExtractWithContext(ctx context.Context, archivePath, destPath string, progress ExtractionProgressCallback) error
+}
+
 // ArchiveInfo 压缩包信息
 type ArchiveInfo struct {
 	Type      ArchiveType // 压缩包类型
@@ -145,6 +151,11 @@ func (e *ArchiveExtractorImpl) GetArchiveInfo(archivePath string) (*ArchiveInfo,
 
 // Extract 解压压缩包
 func (e *ArchiveExtractorImpl) Extract(archivePath, destPath string, progress ExtractionProgressCallback) error {
+	return e.ExtractWithContext(context.Background(), archivePath, destPath, progress)
+}
+
+// ExtractWithContext 解压压缩包，ctx 取消后不再解压新的文件并返回上下文错误
+func (e *ArchiveExtractorImpl) ExtractWithContext(ctx context.Context, archivePath, destPath string, progress ExtractionProgressCallback) error {
 	// 验证压缩包
 	if err := e.ValidateArchive(archivePath); err != nil {
 		return fmt.Errorf("压缩包验证失败: %v", err)
@@ -159,9 +170,9 @@ func (e *ArchiveExtractorImpl) Extract(archivePath, destPath string, progress Ex
 
 	switch archiveType {
 	case ArchiveTypeZip:
-		return e.extractZip(archivePath, destPath, progress)
+		return e.extractZip(ctx, archivePath, destPath, progress)
 	case ArchiveTypeTarGz:
-		return e.extractTarGz(archivePath, destPath, progress)
+		return e.extractTarGz(ctx, archivePath, destPath, progress)
 	default:
 		return fmt.Errorf("不支持的压缩包类型: %s", archivePath)
 	}
@@ -295,7 +306,7 @@ func (e *ArchiveExtractorImpl) getTarGzInfo(archivePath string) (*ArchiveInfo, e
 }
 
 // extractZip 解压ZIP文件（支持并行处理）
-func (e *ArchiveExtractorImpl) extractZip(archivePath, destPath string, progress ExtractionProgressCallback) error {
+func (e *ArchiveExtractorImpl) extractZip(ctx context.Context, archivePath, destPath string, progress ExtractionProgressCallback) error {
 	reader, err := zip.OpenReader(archivePath)
 	if err != nil {
 		return fmt.Errorf("打开ZIP文件失败: %v", err)
@@ -312,19 +323,22 @@ func (e *ArchiveExtractorImpl) extractZip(archivePath, destPath string, progress
 
 	// 使用并行处理优化解压性能
 	if e.parallelWorkers > 1 && totalFiles > 10 {
-		return e.extractZipParallel(reader.File, destPath, totalFiles, totalBytes, progress)
+		return e.extractZipParallel(ctx, reader.File, destPath, totalFiles, totalBytes, progress)
 	}
 
 	// 串行处理（小文件或单线程模式）
-	return e.extractZipSequential(reader.File, destPath, totalFiles, totalBytes, progress)
+	return e.extractZipSequential(ctx, reader.File, destPath, totalFiles, totalBytes, progress)
 }
 
 // extractZipSequential 串行解压ZIP文件
-func (e *ArchiveExtractorImpl) extractZipSequential(files []*zip.File, destPath string, totalFiles int, totalBytes int64, progress ExtractionProgressCallback) error {
+func (e *ArchiveExtractorImpl) extractZipSequential(ctx context.Context, files []*zip.File, destPath string, totalFiles int, totalBytes int64, progress ExtractionProgressCallback) error {
 	var processedFiles int
 	var processedBytes int64
 
 	for _, file := range files {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		if err := e.extractZipFile(file, destPath); err != nil {
 			return fmt.Errorf("解压文件 %s 失败: %v", file.Name, err)
 		}
@@ -348,7 +362,7 @@ func (e *ArchiveExtractorImpl) extractZipSequential(files []*zip.File, destPath
 }
 
 // extractZipParallel 并行解压ZIP文件
-func (e *ArchiveExtractorImpl) extractZipParallel(files []*zip.File, destPath string, totalFiles int, totalBytes int64, progress ExtractionProgressCallback) error {
+func (e *ArchiveExtractorImpl) extractZipParallel(ctx context.Context, files []*zip.File, destPath string, totalFiles int, totalBytes int64, progress ExtractionProgressCallback) error {
 	// 创建工作队列
 	fileChan := make(chan *zip.File, len(files))
 	errorChan := make(chan error, e.parallelWorkers)
@@ -364,6 +378,10 @@ func (e *ArchiveExtractorImpl) extractZipParallel(files []*zip.File, destPath st
 	for i := 0; i < e.parallelWorkers; i++ {
 		go func() {
 			for file := range fileChan {
+				if err := ctx.Err(); err != nil {
+					errorChan <- err
+					return
+				}
 				if err := e.extractZipFile(file, destPath); err != nil {
 					errorChan <- fmt.Errorf("解压文件 %s 失败: %v", file.Name, err)
 					return
@@ -475,7 +493,7 @@ func (e *ArchiveExtractorImpl) extractZipFile(file *zip.File, destPath string) e
 }
 
 // extractTarGz 解压tar.gz文件
-func (e *ArchiveExtractorImpl) extractTarGz(archivePath, destPath string, progress ExtractionProgressCallback) error {
+func (e *ArchiveExtractorImpl) extractTarGz(ctx context.Context, archivePath, destPath string, progress ExtractionProgressCallback) error {
 	file, err := os.Open(archivePath)
 	if err != nil {
 		return fmt.Errorf("打开tar.gz文件失败: %v", err)
@@ -500,6 +518,10 @@ func (e *ArchiveExtractorImpl) extractTarGz(archivePath, destPath string, progre
 	var processedBytes int64
 
 	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
 		header, err := tarReader.Next()
 		if err == io.EOF {
 			break
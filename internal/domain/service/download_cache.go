@@ -0,0 +1,571 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// cacheIndexFile 缓存索引文件名
+	cacheIndexFile = "index.json"
+	// cacheLockFile 多个进程读写缓存索引时使用的锁文件名
+	cacheLockFile = "index.lock"
+	// cacheIncomingPrefix 正在写入的缓存临时文件的前缀
+	cacheIncomingPrefix = "incoming-"
+	// cacheIncomingMaxAge 临时文件超过该时长未修改时视为其他进程异常退出的残留，清理缓存时删除
+	cacheIncomingMaxAge = time.Hour
+	// DefaultMaxCacheSize 默认缓存容量上限（2GB）
+	DefaultMaxCacheSize int64 = 2 * 1024 * 1024 * 1024
+)
+
+// CacheEntry 缓存条目，以文件内容的SHA-256作为键
+type CacheEntry struct {
+	SHA256       string    `json:"sha256"`        // 文件内容的SHA-256
+	Filename     string    `json:"filename"`      // 原始文件名
+	URLs         []string  `json:"urls"`          // 下载过该内容的URL列表
+	Size         int64     `json:"size"`          // 文件大小
+	CreatedAt    time.Time `json:"created_at"`    // 缓存时间
+	LastAccessed time.Time `json:"last_accessed"` // 最近访问时间
+}
+
+// CacheVerifyResult 缓存校验结果
+type CacheVerifyResult struct {
+	Entry CacheEntry // 缓存条目
+	Valid bool       // 是否有效
+	Error string     // 无效原因
+}
+
+// cacheIndex 缓存元数据索引
+type cacheIndex struct {
+	Entries map[string]*CacheEntry `json:"entries"`
+}
+
+// DownloadCache 内容寻址的下载缓存
+// 相同内容的文件无论来自哪个镜像都只保存一份，超出容量上限时按LRU淘汰。
+// 索引的读写在进程内由互斥锁保护，进程间由缓存目录中的文件锁保护
+type DownloadCache struct {
+	mu      sync.Mutex
+	dir     string
+	maxSize int64
+}
+
+// NewDownloadCache 创建下载缓存，maxSize 小于等于0时不限制容量
+func NewDownloadCache(dir string, maxSize int64) *DownloadCache {
+	return &DownloadCache{
+		dir:     dir,
+		maxSize: maxSize,
+	}
+}
+
+// DefaultCacheDir 获取默认缓存目录
+func DefaultCacheDir() string {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(os.TempDir(), "go-version-cache")
+	}
+	return filepath.Join(homeDir, ".go-version", "cache")
+}
+
+// Dir 获取缓存目录
+func (c *DownloadCache) Dir() string {
+	return c.dir
+}
+
+// Lookup 查找URL对应的缓存文件，读取前校验文件内容，校验失败的条目会被移除
+func (c *DownloadCache) Lookup(url string) (*CacheEntry, string, error) {
+	unlock, err := c.lock()
+	if err != nil {
+		return nil, "", err
+	}
+	defer unlock()
+
+	index, err := c.loadIndex()
+	if err != nil {
+		return nil, "", err
+	}
+
+	entry := index.findByURL(url)
+	if entry == nil {
+		return nil, "", fmt.Errorf("缓存文件不存在")
+	}
+
+	path := c.entryPath(entry.SHA256)
+	if err := verifyCacheFile(path, entry); err != nil {
+		c.removeEntry(index, entry.SHA256)
+		c.saveIndex(index)
+		return nil, "", fmt.Errorf("缓存文件校验失败: %v", err)
+	}
+
+	entry.LastAccessed = time.Now()
+	if err := c.saveIndex(index); err != nil {
+		return nil, "", err
+	}
+
+	result := *entry
+	return &result, path, nil
+}
+
+// LookupFilename 按原始文件名查找缓存文件，用于复用其他镜像下载的相同压缩包
+func (c *DownloadCache) LookupFilename(filename string) (*CacheEntry, string, error) {
+	unlock, err := c.lock()
+	if err != nil {
+		return nil, "", err
+	}
+	defer unlock()
+
+	index, err := c.loadIndex()
+	if err != nil {
+		return nil, "", err
+	}
+
+	// 同名条目可能有多个，优先使用最近访问的
+	var entry *CacheEntry
+	for _, candidate := range index.Entries {
+		if candidate.Filename == filename && (entry == nil || candidate.LastAccessed.After(entry.LastAccessed)) {
+			entry = candidate
+		}
+	}
+	if entry == nil {
+		return nil, "", fmt.Errorf("缓存文件不存在")
+	}
+
+	path := c.entryPath(entry.SHA256)
+	if err := verifyCacheFile(path, entry); err != nil {
+		c.removeEntry(index, entry.SHA256)
+		c.saveIndex(index)
+		return nil, "", fmt.Errorf("缓存文件校验失败: %v", err)
+	}
+
+	entry.LastAccessed = time.Now()
+	if err := c.saveIndex(index); err != nil {
+		return nil, "", err
+	}
+
+	result := *entry
+	return &result, path, nil
+}
+
+// Store 将下载完成的文件加入缓存
+func (c *DownloadCache) Store(url, filePath string) (*CacheEntry, error) {
+	return c.store(url, filePath, "")
+}
+
+// StoreVerified 将已通过验证的文件加入缓存，复制到缓存的内容与验证时的SHA-256不一致时不保存
+func (c *DownloadCache) StoreVerified(url, filePath, sha256 string) (*CacheEntry, error) {
+	if sha256 == "" {
+		return nil, fmt.Errorf("缺少已验证的SHA-256")
+	}
+	return c.store(url, filePath, strings.ToLower(sha256))
+}
+
+// Remove 移除指定SHA-256的缓存条目，用于丢弃未通过验证的缓存文件
+func (c *DownloadCache) Remove(sha256 string) error {
+	unlock, err := c.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	index, err := c.loadIndex()
+	if err != nil {
+		return err
+	}
+
+	sum := strings.ToLower(sha256)
+	if _, exists := index.Entries[sum]; !exists {
+		return nil
+	}
+	c.removeEntry(index, sum)
+	return c.saveIndex(index)
+}
+
+// store 将文件加入缓存，expected 不为空时要求文件内容的SHA-256与之一致
+func (c *DownloadCache) store(url, filePath, expected string) (*CacheEntry, error) {
+	if err := os.MkdirAll(c.dir, 0755); err != nil {
+		return nil, fmt.Errorf("创建缓存目录失败: %v", err)
+	}
+
+	// 边复制边计算哈希，写入临时文件后再按哈希重命名；复制耗时较长，不持有锁
+	tmpPath, checksum, size, err := c.copyToTemp(filePath)
+	if err != nil {
+		return nil, err
+	}
+	if expected != "" && checksum != expected {
+		os.Remove(tmpPath)
+		return nil, fmt.Errorf("文件在验证后发生变化: 期望SHA-256 %s, 实际 %s", expected, checksum)
+	}
+
+	unlock, err := c.lock()
+	if err != nil {
+		os.Remove(tmpPath)
+		return nil, err
+	}
+	defer unlock()
+
+	index, err := c.loadIndex()
+	if err != nil {
+		os.Remove(tmpPath)
+		return nil, err
+	}
+
+	now := time.Now()
+	entry, exists := index.Entries[checksum]
+	if exists {
+		os.Remove(tmpPath)
+	} else {
+		if err := os.Rename(tmpPath, c.entryPath(checksum)); err != nil {
+			os.Remove(tmpPath)
+			return nil, fmt.Errorf("保存缓存文件失败: %v", err)
+		}
+		entry = &CacheEntry{
+			SHA256:    checksum,
+			Filename:  filepath.Base(filePath),
+			Size:      size,
+			CreatedAt: now,
+		}
+		index.Entries[checksum] = entry
+	}
+
+	// 同一URL只对应最新的内容
+	for sum, other := range index.Entries {
+		if sum != checksum {
+			other.URLs = removeString(other.URLs, url)
+		}
+	}
+	if !hasString(entry.URLs, url) {
+		entry.URLs = append(entry.URLs, url)
+	}
+	entry.LastAccessed = now
+
+	c.evict(index, checksum)
+
+	if err := c.saveIndex(index); err != nil {
+		return nil, err
+	}
+
+	result := *entry
+	return &result, nil
+}
+
+// List 列出所有缓存条目，按最近访问时间倒序排列
+func (c *DownloadCache) List() ([]CacheEntry, error) {
+	unlock, err := c.lock()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	index, err := c.loadIndex()
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]CacheEntry, 0, len(index.Entries))
+	for _, entry := range index.Entries {
+		entries = append(entries, *entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].LastAccessed.After(entries[j].LastAccessed)
+	})
+	return entries, nil
+}
+
+// Size 获取缓存总大小及条目数量
+func (c *DownloadCache) Size() (int64, int, error) {
+	entries, err := c.List()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var total int64
+	for _, entry := range entries {
+		total += entry.Size
+	}
+	return total, len(entries), nil
+}
+
+// MaxSize 获取缓存容量上限
+func (c *DownloadCache) MaxSize() int64 {
+	return c.maxSize
+}
+
+// Verify 校验所有缓存条目，移除已损坏的条目
+func (c *DownloadCache) Verify() ([]CacheVerifyResult, error) {
+	unlock, err := c.lock()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	index, err := c.loadIndex()
+	if err != nil {
+		return nil, err
+	}
+
+	sums := make([]string, 0, len(index.Entries))
+	for sum := range index.Entries {
+		sums = append(sums, sum)
+	}
+	sort.Strings(sums)
+
+	results := make([]CacheVerifyResult, 0, len(sums))
+	for _, sum := range sums {
+		entry := index.Entries[sum]
+		result := CacheVerifyResult{Entry: *entry, Valid: true}
+		if err := verifyCacheFile(c.entryPath(sum), entry); err != nil {
+			result.Valid = false
+			result.Error = err.Error()
+			c.removeEntry(index, sum)
+		}
+		results = append(results, result)
+	}
+
+	if err := c.saveIndex(index); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// Clean 清理缓存，olderThan 大于0时只清理超过该时长未访问的条目
+// 返回清理的条目数量及释放的空间
+func (c *DownloadCache) Clean(olderThan time.Duration) (int, int64, error) {
+	unlock, err := c.lock()
+	if err != nil {
+		return 0, 0, err
+	}
+	defer unlock()
+
+	index, err := c.loadIndex()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	cutoff := time.Now().Add(-olderThan)
+	removed := 0
+	var freed int64
+	for sum, entry := range index.Entries {
+		if olderThan > 0 && entry.LastAccessed.After(cutoff) {
+			continue
+		}
+		freed += entry.Size
+		removed++
+		c.removeEntry(index, sum)
+	}
+
+	// 完全清理时同时删除索引中不存在的残留文件，其他进程可能正在写入的临时文件保留
+	if olderThan <= 0 {
+		if files, err := os.ReadDir(c.dir); err == nil {
+			for _, file := range files {
+				if file.Name() == cacheIndexFile || file.Name() == cacheLockFile || c.incomingInUse(file) {
+					continue
+				}
+				os.RemoveAll(filepath.Join(c.dir, file.Name()))
+			}
+		}
+	}
+
+	if err := c.saveIndex(index); err != nil {
+		return removed, freed, err
+	}
+	return removed, freed, nil
+}
+
+// lock 获取缓存索引的进程内互斥锁和进程间文件锁，返回释放函数
+func (c *DownloadCache) lock() (func(), error) {
+	c.mu.Lock()
+	if err := os.MkdirAll(c.dir, 0755); err != nil {
+		c.mu.Unlock()
+		return nil, fmt.Errorf("创建缓存目录失败: %v", err)
+	}
+	fl, err := lockFile(filepath.Join(c.dir, cacheLockFile))
+	if err != nil {
+		c.mu.Unlock()
+		return nil, err
+	}
+	return func() {
+		fl.Unlock()
+		c.mu.Unlock()
+	}, nil
+}
+
+// incomingInUse 检查是否为最近仍在修改的临时文件（可能正由其他进程写入）
+func (c *DownloadCache) incomingInUse(file os.DirEntry) bool {
+	if !strings.HasPrefix(file.Name(), cacheIncomingPrefix) {
+		return false
+	}
+	info, err := file.Info()
+	if err != nil {
+		return false
+	}
+	return time.Since(info.ModTime()) < cacheIncomingMaxAge
+}
+
+// evict 按最近访问时间淘汰条目直到总大小不超过上限（调用方需持有锁）
+func (c *DownloadCache) evict(index *cacheIndex, keep string) {
+	if c.maxSize <= 0 {
+		return
+	}
+
+	var total int64
+	entries := make([]*CacheEntry, 0, len(index.Entries))
+	for _, entry := range index.Entries {
+		total += entry.Size
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].LastAccessed.Before(entries[j].LastAccessed)
+	})
+
+	for _, entry := range entries {
+		if total <= c.maxSize {
+			break
+		}
+		if entry.SHA256 == keep {
+			continue
+		}
+		total -= entry.Size
+		c.removeEntry(index, entry.SHA256)
+	}
+}
+
+// copyToTemp 将文件复制到缓存目录下的临时文件，同时计算SHA-256
+func (c *DownloadCache) copyToTemp(filePath string) (string, string, int64, error) {
+	src, err := os.Open(filePath)
+	if err != nil {
+		return "", "", 0, fmt.Errorf("打开文件失败: %v", err)
+	}
+	defer src.Close()
+
+	tmp, err := os.CreateTemp(c.dir, cacheIncomingPrefix+"*")
+	if err != nil {
+		return "", "", 0, fmt.Errorf("创建缓存临时文件失败: %v", err)
+	}
+	defer tmp.Close()
+
+	hasher := sha256.New()
+	size, err := io.Copy(io.MultiWriter(tmp, hasher), src)
+	if err != nil {
+		os.Remove(tmp.Name())
+		return "", "", 0, fmt.Errorf("复制文件到缓存失败: %v", err)
+	}
+
+	return tmp.Name(), hex.EncodeToString(hasher.Sum(nil)), size, nil
+}
+
+// removeEntry 从索引中移除条目并删除对应文件（调用方需持有锁）
+func (c *DownloadCache) removeEntry(index *cacheIndex, sum string) {
+	delete(index.Entries, sum)
+	os.Remove(c.entryPath(sum))
+}
+
+// entryPath 获取条目对应的缓存文件路径
+func (c *DownloadCache) entryPath(sum string) string {
+	return filepath.Join(c.dir, sum)
+}
+
+// loadIndex 加载缓存索引（调用方需持有锁）
+func (c *DownloadCache) loadIndex() (*cacheIndex, error) {
+	index := &cacheIndex{Entries: make(map[string]*CacheEntry)}
+
+	data, err := os.ReadFile(filepath.Join(c.dir, cacheIndexFile))
+	if os.IsNotExist(err) {
+		return index, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取缓存索引失败: %v", err)
+	}
+
+	if err := json.Unmarshal(data, index); err != nil {
+		// 索引损坏时重建空索引，残留文件由 Clean 清理
+		return &cacheIndex{Entries: make(map[string]*CacheEntry)}, nil
+	}
+	if index.Entries == nil {
+		index.Entries = make(map[string]*CacheEntry)
+	}
+	return index, nil
+}
+
+// saveIndex 保存缓存索引（调用方需持有锁）
+func (c *DownloadCache) saveIndex(index *cacheIndex) error {
+	if err := os.MkdirAll(c.dir, 0755); err != nil {
+		return fmt.Errorf("创建缓存目录失败: %v", err)
+	}
+
+	data, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return fmt.Errorf("序列化缓存索引失败: %v", err)
+	}
+
+	// 先写临时文件再重命名，避免中断时索引损坏
+	indexPath := filepath.Join(c.dir, cacheIndexFile)
+	tmpPath := indexPath + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("写入缓存索引失败: %v", err)
+	}
+	if err := os.Rename(tmpPath, indexPath); err != nil {
+		return fmt.Errorf("保存缓存索引失败: %v", err)
+	}
+	return nil
+}
+
+// findByURL 查找包含指定URL的条目
+func (index *cacheIndex) findByURL(url string) *CacheEntry {
+	for _, entry := range index.Entries {
+		if hasString(entry.URLs, url) {
+			return entry
+		}
+	}
+	return nil
+}
+
+// verifyCacheFile 校验缓存文件的大小和SHA-256
+func verifyCacheFile(path string, entry *CacheEntry) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("打开缓存文件失败: %v", err)
+	}
+	defer file.Close()
+
+	hasher := sha256.New()
+	size, err := io.Copy(hasher, file)
+	if err != nil {
+		return fmt.Errorf("读取缓存文件失败: %v", err)
+	}
+	if size != entry.Size {
+		return fmt.Errorf("文件大小不匹配: 期望 %d, 实际 %d", entry.Size, size)
+	}
+	if actual := hex.EncodeToString(hasher.Sum(nil)); actual != entry.SHA256 {
+		return fmt.Errorf("SHA-256不匹配: 期望 %s, 实际 %s", entry.SHA256, actual)
+	}
+	return nil
+}
+
+// hasString 检查切片中是否包含指定字符串
+func hasString(values []string, target string) bool {
+	for _, value := range values {
+		if value == target {
+			return true
+		}
+	}
+	return false
+}
+
+// removeString 从切片中移除指定字符串
+func removeString(values []string, target string) []string {
+	result := values[:0]
+	for _, value := range values {
+		if value != target {
+			result = append(result, value)
+		}
+	}
+	return result
+}
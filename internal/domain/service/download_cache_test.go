@@ -0,0 +1,263 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// writeTestFile 写入测试文件并返回路径
+func writeTestFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("写入测试文件失败: %v", err)
+	}
+	return path
+}
+
+func TestDownloadCache_StoreAndLookup(t *testing.T) {
+	cache := NewDownloadCache(t.TempDir(), 0)
+	srcDir := t.TempDir()
+
+	// 不同镜像的相同安装包只保存一份
+	file := writeTestFile(t, srcDir, "go1.21.0.linux-amd64.tar.gz", "archive content")
+	first, err := cache.Store("https://golang.org/dl/go1.21.0.linux-amd64.tar.gz", file)
+	if err != nil {
+		t.Fatalf("缓存文件失败: %v", err)
+	}
+	second, err := cache.Store("https://mirrors.aliyun.com/golang/go1.21.0.linux-amd64.tar.gz", file)
+	if err != nil {
+		t.Fatalf("缓存文件失败: %v", err)
+	}
+
+	if first.SHA256 != second.SHA256 {
+		t.Errorf("相同内容的哈希不一致: %s != %s", first.SHA256, second.SHA256)
+	}
+	if len(second.URLs) != 2 {
+		t.Errorf("URL数量 = %d, 期望 2", len(second.URLs))
+	}
+
+	entries, err := cache.List()
+	if err != nil {
+		t.Fatalf("列出缓存失败: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("缓存条目数量 = %d, 期望 1", len(entries))
+	}
+
+	entry, path, err := cache.Lookup("https://mirrors.aliyun.com/golang/go1.21.0.linux-amd64.tar.gz")
+	if err != nil {
+		t.Fatalf("查找缓存失败: %v", err)
+	}
+	if filepath.Base(path) != entry.SHA256 {
+		t.Errorf("缓存文件名 = %s, 期望以SHA-256命名", filepath.Base(path))
+	}
+	if entry.Filename != "go1.21.0.linux-amd64.tar.gz" {
+		t.Errorf("文件名 = %s, 期望 go1.21.0.linux-amd64.tar.gz", entry.Filename)
+	}
+
+	if _, _, err := cache.Lookup("https://example.com/unknown.tar.gz"); err == nil {
+		t.Error("未缓存的URL应该返回错误")
+	}
+}
+
+func TestDownloadCache_LookupVerifiesContent(t *testing.T) {
+	cache := NewDownloadCache(t.TempDir(), 0)
+	file := writeTestFile(t, t.TempDir(), "go.tar.gz", "archive content")
+
+	entry, err := cache.Store("https://example.com/go.tar.gz", file)
+	if err != nil {
+		t.Fatalf("缓存文件失败: %v", err)
+	}
+
+	// 篡改缓存文件
+	if err := os.WriteFile(filepath.Join(cache.Dir(), entry.SHA256), []byte("corrupted data!"), 0644); err != nil {
+		t.Fatalf("篡改缓存文件失败: %v", err)
+	}
+
+	if _, _, err := cache.Lookup("https://example.com/go.tar.gz"); err == nil {
+		t.Fatal("损坏的缓存文件应该校验失败")
+	}
+
+	// 校验失败的条目应被移除
+	if _, count, _ := cache.Size(); count != 0 {
+		t.Errorf("缓存条目数量 = %d, 期望 0", count)
+	}
+}
+
+func TestDownloadCache_StoreVerifiedAndRemove(t *testing.T) {
+	cache := NewDownloadCache(t.TempDir(), 0)
+	file := writeTestFile(t, t.TempDir(), "go.tar.gz", "archive content")
+	sum := sha256.Sum256([]byte("archive content"))
+	checksum := hex.EncodeToString(sum[:])
+
+	// 内容与验证时的SHA-256不一致时不保存
+	if _, err := cache.StoreVerified("https://example.com/go.tar.gz", file, strings.Repeat("0", 64)); err == nil {
+		t.Error("SHA-256不一致时应返回错误")
+	}
+	if _, count, _ := cache.Size(); count != 0 {
+		t.Errorf("缓存条目数量 = %d, 期望 0", count)
+	}
+
+	entry, err := cache.StoreVerified("https://example.com/go.tar.gz", file, strings.ToUpper(checksum))
+	if err != nil {
+		t.Fatalf("缓存文件失败: %v", err)
+	}
+	if entry.SHA256 != checksum {
+		t.Errorf("缓存键 = %s, 期望 %s", entry.SHA256, checksum)
+	}
+
+	if err := cache.Remove(checksum); err != nil {
+		t.Fatalf("移除缓存条目失败: %v", err)
+	}
+	if _, _, err := cache.Lookup("https://example.com/go.tar.gz"); err == nil {
+		t.Error("移除后不应再找到缓存条目")
+	}
+	if _, err := os.Stat(filepath.Join(cache.Dir(), checksum)); !os.IsNotExist(err) {
+		t.Error("移除后应删除缓存文件")
+	}
+	if err := cache.Remove(checksum); err != nil {
+		t.Errorf("移除不存在的条目不应返回错误: %v", err)
+	}
+}
+
+func TestDownloadCache_LRUEviction(t *testing.T) {
+	cache := NewDownloadCache(t.TempDir(), 25)
+	srcDir := t.TempDir()
+
+	first := writeTestFile(t, srcDir, "a.tar.gz", "0123456789")
+	second := writeTestFile(t, srcDir, "b.tar.gz", "abcdefghij")
+	third := writeTestFile(t, srcDir, "c.tar.gz", "ABCDEFGHIJ")
+
+	if _, err := cache.Store("https://example.com/a.tar.gz", first); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(5 * time.Millisecond)
+	if _, err := cache.Store("https://example.com/b.tar.gz", second); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(5 * time.Millisecond)
+
+	// 访问 a 使 b 成为最久未使用的条目
+	if _, _, err := cache.Lookup("https://example.com/a.tar.gz"); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(5 * time.Millisecond)
+
+	if _, err := cache.Store("https://example.com/c.tar.gz", third); err != nil {
+		t.Fatal(err)
+	}
+
+	total, count, err := cache.Size()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 2 || total != 20 {
+		t.Errorf("缓存 = %d 个条目 %d 字节, 期望 2 个条目 20 字节", count, total)
+	}
+	if _, _, err := cache.Lookup("https://example.com/b.tar.gz"); err == nil {
+		t.Error("最久未使用的条目应该被淘汰")
+	}
+	if _, _, err := cache.Lookup("https://example.com/a.tar.gz"); err != nil {
+		t.Errorf("最近使用的条目不应被淘汰: %v", err)
+	}
+}
+
+func TestDownloadCache_VerifyAndClean(t *testing.T) {
+	cache := NewDownloadCache(t.TempDir(), 0)
+	srcDir := t.TempDir()
+
+	good, err := cache.Store("https://example.com/good.tar.gz", writeTestFile(t, srcDir, "good.tar.gz", "good"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	bad, err := cache.Store("https://example.com/bad.tar.gz", writeTestFile(t, srcDir, "bad.tar.gz", "bad"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(filepath.Join(cache.Dir(), bad.SHA256), []byte("BAD"), 0644)
+
+	results, err := cache.Verify()
+	if err != nil {
+		t.Fatalf("校验缓存失败: %v", err)
+	}
+	for _, result := range results {
+		if expected := result.Entry.SHA256 == good.SHA256; result.Valid != expected {
+			t.Errorf("%s 校验结果 = %v, 期望 %v", result.Entry.Filename, result.Valid, expected)
+		}
+	}
+
+	// 最近使用的条目不会被按时长清理
+	removed, _, err := cache.Clean(time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if removed != 0 {
+		t.Errorf("清理数量 = %d, 期望 0", removed)
+	}
+
+	removed, freed, err := cache.Clean(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if removed != 1 || freed != good.Size {
+		t.Errorf("清理结果 = %d 个条目 %d 字节, 期望 1 个条目 %d 字节", removed, freed, good.Size)
+	}
+	if _, err := os.Stat(filepath.Join(cache.Dir(), good.SHA256)); !os.IsNotExist(err) {
+		t.Error("清理后缓存文件应该被删除")
+	}
+}
+
+func TestDownloadCache_ConcurrentStoreAcrossInstances(t *testing.T) {
+	dir := t.TempDir()
+	srcDir := t.TempDir()
+
+	// 每个实例有独立的进程内互斥锁，模拟多个进程同时写入同一缓存目录
+	const count = 8
+	var wg sync.WaitGroup
+	for i := 0; i < count; i++ {
+		file := writeTestFile(t, srcDir, fmt.Sprintf("go1.21.%d.tar.gz", i), fmt.Sprintf("archive %d", i))
+		wg.Add(1)
+		go func(i int, file string) {
+			defer wg.Done()
+			cache := NewDownloadCache(dir, 0)
+			if _, err := cache.Store(fmt.Sprintf("https://example.com/go1.21.%d.tar.gz", i), file); err != nil {
+				t.Errorf("缓存文件失败: %v", err)
+			}
+		}(i, file)
+	}
+	wg.Wait()
+
+	entries, err := NewDownloadCache(dir, 0).List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != count {
+		t.Errorf("缓存条目数量 = %d, 期望 %d（并发写入丢失了索引条目）", len(entries), count)
+	}
+}
+
+func TestDownloadCache_CleanKeepsIncomingFilesInUse(t *testing.T) {
+	cache := NewDownloadCache(t.TempDir(), 0)
+	fresh := writeTestFile(t, cache.Dir(), cacheIncomingPrefix+"fresh", "writing")
+	stale := writeTestFile(t, cache.Dir(), cacheIncomingPrefix+"stale", "abandoned")
+	old := time.Now().Add(-2 * cacheIncomingMaxAge)
+	os.Chtimes(stale, old, old)
+
+	if _, _, err := cache.Clean(0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(fresh); err != nil {
+		t.Error("不应删除其他进程正在写入的临时文件")
+	}
+	if _, err := os.Stat(stale); !os.IsNotExist(err) {
+		t.Error("应删除残留的临时文件")
+	}
+}
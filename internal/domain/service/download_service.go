@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -14,6 +15,22 @@ import (
 // ProgressCallback 进度回调函数
 type ProgressCallback func(downloaded, total int64, speed float64)
 
+// HTTPStatusError 服务器返回非预期状态码时的错误
+type HTTPStatusError struct {
+	StatusCode int
+	Status     string
+}
+
+// Error 实现error接口
+func (e *HTTPStatusError) Error() string {
+	return fmt.Sprintf("%d %s", e.StatusCode, e.Status)
+}
+
+// newHTTPStatusError 根据响应创建状态码错误
+func newHTTPStatusError(resp *http.Response) *HTTPStatusError {
+	return &HTTPStatusError{StatusCode: resp.StatusCode, Status: resp.Status}
+}
+
 // DownloadProgress 下载进度信息
 type DownloadProgress struct {
 	Downloaded int64         // 已下载字节数
@@ -84,56 +101,166 @@ type DownloadServiceImpl struct {
 	chunkSize          int64
 	userAgent          string
 	enableCache        bool
-	cacheDir           string
+	cache              *DownloadCache
 	progressUpdateRate time.Duration
+	segments           int
+	minSegmentSize     int64
 }
 
 // DownloadOptions 下载选项
 type DownloadOptions struct {
-	MaxRetries         int           // 最大重试次数
-	RetryDelay         time.Duration // 重试延迟
-	Timeout            time.Duration // 超时时间
-	ChunkSize          int64         // 分块大小
-	UserAgent          string        // User-Agent
-	EnableCache        bool          // 启用缓存
-	CacheDir           string        // 缓存目录
-	ProgressUpdateRate time.Duration // 进度更新频率
+	MaxRetries         int                // 最大重试次数
+	RetryDelay         time.Duration      // 重试延迟
+	Timeout            time.Duration      // 超时时间
+	ChunkSize          int64              // 分块大小
+	UserAgent          string             // User-Agent
+	EnableCache        bool               // 启用缓存
+	CacheDir           string             // 缓存目录
+	MaxCacheSize       int64              // 缓存容量上限（小于等于0时不限制）
+	ProgressUpdateRate time.Duration      // 进度更新频率
+	Segments           int                // 并行分段下载的连接数（小于等于1时使用单连接）
+	MinSegmentSize     int64              // 每个分段的最小字节数
+	HTTPClientFactory  *HTTPClientFactory // 共享代理、TLS和认证配置的HTTP客户端工厂，为空时使用默认配置
+}
+
+// DefaultDownloadOptions 获取默认下载选项
+func DefaultDownloadOptions() *DownloadOptions {
+	return &DownloadOptions{
+		MaxRetries:         3,
+		RetryDelay:         2 * time.Second,
+		Timeout:            30 * time.Minute,
+		ChunkSize:          optimizeChunkSize(), // 动态优化分块大小
+		UserAgent:          "go-version-manager/1.0",
+		EnableCache:        true,
+		CacheDir:           DefaultCacheDir(),
+		MaxCacheSize:       DefaultMaxCacheSize,
+		ProgressUpdateRate: 50 * time.Millisecond, // 优化进度更新频率
+		Segments:           4,
+		MinSegmentSize:     1024 * 1024, // 1MB
+	}
 }
 
 // NewDownloadService 创建下载服务实例
 func NewDownloadService(options *DownloadOptions) DownloadService {
 	if options == nil {
-		options = &DownloadOptions{
-			MaxRetries:         3,
-			RetryDelay:         2 * time.Second,
-			Timeout:            30 * time.Minute,
-			ChunkSize:          optimizeChunkSize(), // 动态优化分块大小
-			UserAgent:          "go-version-manager/1.0",
-			EnableCache:        true,
-			CacheDir:           filepath.Join(os.TempDir(), "go-version-cache"),
-			ProgressUpdateRate: 50 * time.Millisecond, // 优化进度更新频率
-		}
+		options = DefaultDownloadOptions()
+	}
+
+	// 未指定分块大小时使用默认值，避免零长度缓冲区导致读取死循环
+	chunkSize := options.ChunkSize
+	if chunkSize <= 0 {
+		chunkSize = optimizeChunkSize()
 	}
 
 	// 确保缓存目录存在
+	var cache *DownloadCache
 	if options.EnableCache && options.CacheDir != "" {
 		os.MkdirAll(options.CacheDir, 0755)
+		cache = NewDownloadCache(options.CacheDir, options.MaxCacheSize)
 	}
 
-	client := &http.Client{
-		Timeout: options.Timeout,
+	factory := options.HTTPClientFactory
+	if factory == nil {
+		factory = DefaultHTTPClientFactory()
 	}
+	client := factory.Client(options.Timeout)
 
 	return &DownloadServiceImpl{
 		client:             client,
 		maxRetries:         options.MaxRetries,
 		retryDelay:         options.RetryDelay,
-		chunkSize:          options.ChunkSize,
+		chunkSize:          chunkSize,
 		userAgent:          options.UserAgent,
-		enableCache:        options.EnableCache,
-		cacheDir:           options.CacheDir,
+		enableCache:        cache != nil,
+		cache:              cache,
 		progressUpdateRate: options.ProgressUpdateRate,
+		segments:           options.Segments,
+		minSegmentSize:     options.MinSegmentSize,
+	}
+}
+
+// maxRetriesKey 上下文中单次下载最大重试次数的键
+type maxRetriesKey struct{}
+
+// WithMaxRetries 返回指定最大重试次数的上下文，用该上下文下载时覆盖下载服务的默认重试次数
+func WithMaxRetries(ctx context.Context, maxRetries int) context.Context {
+	if maxRetries < 0 {
+		maxRetries = 0
+	}
+	return context.WithValue(ctx, maxRetriesKey{}, maxRetries)
+}
+
+// maxRetriesFromContext 获取上下文中指定的最大重试次数，未指定时返回 fallback
+func maxRetriesFromContext(ctx context.Context, fallback int) int {
+	if maxRetries, ok := ctx.Value(maxRetriesKey{}).(int); ok {
+		return maxRetries
+	}
+	return fallback
+}
+
+// resumeValidatorKey 上下文中断点续传校验值的键
+type resumeValidatorKey struct{}
+
+// WithResumeValidator 返回携带断点续传校验值（ETag 或 Last-Modified）的上下文
+// 用该上下文继续下载已有的部分文件时会发送 If-Range，服务器上的文件已变化时重新下载完整文件
+func WithResumeValidator(ctx context.Context, validator string) context.Context {
+	return context.WithValue(ctx, resumeValidatorKey{}, validator)
+}
+
+// resumeValidatorFromContext 获取上下文中的断点续传校验值
+func resumeValidatorFromContext(ctx context.Context) string {
+	validator, _ := ctx.Value(resumeValidatorKey{}).(string)
+	return validator
+}
+
+// RemoteFileInfo 远程文件信息
+type RemoteFileInfo struct {
+	Size         int64  // 文件大小
+	ETag         string // ETag响应头
+	LastModified string // Last-Modified响应头
+	AcceptRanges bool   // 是否支持Range请求
+}
+
+// Validator 获取用于 If-Range 请求的校验值，优先使用ETag
+func (i *RemoteFileInfo) Validator() string {
+	if i.ETag != "" {
+		return i.ETag
+	}
+	return i.LastModified
+}
+
+// RemoteFileProber 可获取远程文件校验信息的下载服务
+type RemoteFileProber interface {
+	ProbeFile(ctx context.Context, url string) (*RemoteFileInfo, error)
+}
+
+// ProbeFile 通过HEAD请求获取远程文件的大小、ETag和Last-Modified
+func (d *DownloadServiceImpl) ProbeFile(ctx context.Context, url string) (*RemoteFileInfo, error) {
+	req, err := http.NewRequestWithContext(ctx, "HEAD", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("创建HEAD请求失败: %v", err)
+	}
+	req.Header.Set("User-Agent", d.userAgent)
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("HEAD请求失败: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("HEAD请求响应错误: %w", newHTTPStatusError(resp))
+	}
+	if resp.ContentLength < 0 {
+		return nil, fmt.Errorf("服务器未返回Content-Length")
 	}
+
+	return &RemoteFileInfo{
+		Size:         resp.ContentLength,
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		AcceptRanges: resp.Header.Get("Accept-Ranges") == "bytes",
+	}, nil
 }
 
 // Download 下载文件
@@ -142,26 +269,51 @@ func (d *DownloadServiceImpl) Download(url, destPath string, progress ProgressCa
 }
 
 // DownloadWithContext 带上下文的下载文件
+// 下载结果未经验证，不读写下载缓存；由调用方验证SHA-256后再加入缓存
 func (d *DownloadServiceImpl) DownloadWithContext(ctx context.Context, url, destPath string, progress ProgressCallback) error {
-	// 检查缓存
-	if d.enableCache {
-		if cachedFile, err := d.getCachedFile(url); err == nil {
-			return d.copyFromCache(cachedFile, destPath, progress)
-		}
-	}
-
 	// 确保目标目录存在
 	if err := os.MkdirAll(filepath.Dir(destPath), 0755); err != nil {
 		return fmt.Errorf("创建目标目录失败: %v", err)
 	}
 
+	// 继续之前（可能是其他进程）未完成的分段下载
+	if resumed, err := d.resumeSegmented(ctx, url, destPath, progress); resumed {
+		if err == nil {
+			return nil
+		}
+		if !errors.Is(err, errRangeNotSupported) {
+			return fmt.Errorf("分段下载失败: %w", err)
+		}
+		// 服务器上的文件已变化或不再支持分段，重新下载
+		if err := removeSegmentedDownload(destPath); err != nil {
+			return err
+		}
+	}
+
+	// 新下载且服务器支持Range请求时，使用多连接分段下载
+	if _, err := os.Stat(destPath); os.IsNotExist(err) && d.segments > 1 {
+		if info, err := d.ProbeFile(ctx, url); err == nil && d.shouldDownloadSegmented(info.Size, info.AcceptRanges) {
+			err := d.downloadSegmented(ctx, url, destPath, info.Size, info.Validator(), progress)
+			if err == nil {
+				return nil
+			}
+			if !errors.Is(err, errRangeNotSupported) {
+				return fmt.Errorf("分段下载失败: %w", err)
+			}
+			// 服务器实际不支持分段，回退到单连接下载
+			if err := removeSegmentedDownload(destPath); err != nil {
+				return err
+			}
+		}
+	}
+
 	// 检查是否支持断点续传
 	var startByte int64 = 0
 	if fileInfo, err := os.Stat(destPath); err == nil {
 		// 文件已存在，检查是否支持断点续传
 		supportsResume, err := d.SupportsResume(url)
 		if err != nil {
-			return fmt.Errorf("检查断点续传支持失败: %v", err)
+			return fmt.Errorf("检查断点续传支持失败: %w", err)
 		}
 
 		if supportsResume {
@@ -174,8 +326,9 @@ func (d *DownloadServiceImpl) DownloadWithContext(ctx context.Context, url, dest
 		}
 	}
 
+	maxRetries := maxRetriesFromContext(ctx, d.maxRetries)
 	var lastErr error
-	for attempt := 0; attempt <= d.maxRetries; attempt++ {
+	for attempt := 0; attempt <= maxRetries; attempt++ {
 		if attempt > 0 {
 			select {
 			case <-ctx.Done():
@@ -186,15 +339,14 @@ func (d *DownloadServiceImpl) DownloadWithContext(ctx context.Context, url, dest
 
 		err := d.downloadWithResume(ctx, url, destPath, startByte, progress)
 		if err == nil {
-			// 下载成功后缓存文件
-			if d.enableCache {
-				d.cacheFile(url, destPath)
-			}
 			return nil
 		}
 
 		lastErr = err
-		if attempt < d.maxRetries {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if attempt < maxRetries {
 			// 重试前检查文件大小，更新startByte
 			if fileInfo, statErr := os.Stat(destPath); statErr == nil {
 				startByte = fileInfo.Size()
@@ -202,7 +354,7 @@ func (d *DownloadServiceImpl) DownloadWithContext(ctx context.Context, url, dest
 		}
 	}
 
-	return fmt.Errorf("下载失败，已重试 %d 次: %v", d.maxRetries, lastErr)
+	return fmt.Errorf("下载失败，已重试 %d 次: %w", maxRetries, lastErr)
 }
 
 // downloadWithResume 支持断点续传的下载
@@ -216,9 +368,12 @@ func (d *DownloadServiceImpl) downloadWithResume(ctx context.Context, url, destP
 	// 设置User-Agent
 	req.Header.Set("User-Agent", d.userAgent)
 
-	// 设置Range头支持断点续传
+	// 设置Range头支持断点续传，已知校验值时服务器上的文件变化后返回完整文件
 	if startByte > 0 {
 		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", startByte))
+		if validator := resumeValidatorFromContext(ctx); validator != "" {
+			req.Header.Set("If-Range", validator)
+		}
 	}
 
 	// 发送请求
@@ -237,10 +392,10 @@ func (d *DownloadServiceImpl) downloadWithResume(ctx context.Context, url, destP
 				return fmt.Errorf("删除现有文件失败: %v", err)
 			}
 		} else {
-			return fmt.Errorf("HTTP响应错误: %d %s", resp.StatusCode, resp.Status)
+			return fmt.Errorf("HTTP响应错误: %w", newHTTPStatusError(resp))
 		}
 	} else if startByte == 0 && resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("HTTP响应错误: %d %s", resp.StatusCode, resp.Status)
+		return fmt.Errorf("HTTP响应错误: %w", newHTTPStatusError(resp))
 	}
 
 	// 获取文件总大小
@@ -331,7 +486,7 @@ func (d *DownloadServiceImpl) GetFileSize(url string) (int64, error) {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return 0, fmt.Errorf("HEAD请求响应错误: %d %s", resp.StatusCode, resp.Status)
+		return 0, fmt.Errorf("HEAD请求响应错误: %w", newHTTPStatusError(resp))
 	}
 
 	contentLength := resp.Header.Get("Content-Length")
@@ -363,7 +518,7 @@ func (d *DownloadServiceImpl) SupportsResume(url string) (bool, error) {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return false, fmt.Errorf("HEAD请求响应错误: %d %s", resp.StatusCode, resp.Status)
+		return false, fmt.Errorf("HEAD请求响应错误: %w", newHTTPStatusError(resp))
 	}
 
 	// 检查是否支持Range请求
@@ -526,47 +681,36 @@ func optimizeChunkSize() int64 {
 	return defaultChunk
 }
 
-// getCachedFile 获取缓存文件路径
+// Cache 获取下载缓存，未启用缓存时返回nil
+func (d *DownloadServiceImpl) Cache() *DownloadCache {
+	return d.cache
+}
+
+// HTTPClient 获取下载使用的HTTP客户端，包含代理、TLS和认证配置
+func (d *DownloadServiceImpl) HTTPClient() *http.Client {
+	return d.client
+}
+
+// getCachedFile 获取缓存文件路径，返回前会校验缓存内容
 func (d *DownloadServiceImpl) getCachedFile(url string) (string, error) {
-	if !d.enableCache || d.cacheDir == "" {
+	if !d.enableCache || d.cache == nil {
 		return "", fmt.Errorf("缓存未启用")
 	}
 
-	// 使用URL的哈希作为缓存文件名
-	hash := fmt.Sprintf("%x", url) // 简化的哈希，实际应用中可以使用更好的哈希算法
-	cacheFile := filepath.Join(d.cacheDir, hash)
-
-	// 检查缓存文件是否存在且有效
-	if _, err := os.Stat(cacheFile); err == nil {
-		return cacheFile, nil
+	_, cacheFile, err := d.cache.Lookup(url)
+	if err != nil {
+		return "", err
 	}
-
-	return "", fmt.Errorf("缓存文件不存在")
+	return cacheFile, nil
 }
 
 // cacheFile 缓存下载的文件
 func (d *DownloadServiceImpl) cacheFile(url, filePath string) error {
-	if !d.enableCache || d.cacheDir == "" {
+	if !d.enableCache || d.cache == nil {
 		return nil
 	}
 
-	hash := fmt.Sprintf("%x", url)
-	cacheFile := filepath.Join(d.cacheDir, hash)
-
-	// 复制文件到缓存目录
-	src, err := os.Open(filePath)
-	if err != nil {
-		return err
-	}
-	defer src.Close()
-
-	dst, err := os.Create(cacheFile)
-	if err != nil {
-		return err
-	}
-	defer dst.Close()
-
-	_, err = io.Copy(dst, src)
+	_, err := d.cache.Store(url, filePath)
 	return err
 }
 
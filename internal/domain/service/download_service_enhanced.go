@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -118,7 +119,8 @@ func (e *EnhancedDownloadServiceImpl) GetDownloadStats() *DownloadStats {
 
 // DownloadManager 下载管理器
 type DownloadManager struct {
-	service         EnhancedDownloadService
+	mu              sync.RWMutex
+	service         DownloadService
 	activeDownloads map[string]*DownloadContext
 }
 
@@ -135,20 +137,33 @@ type DownloadContext struct {
 
 // NewDownloadManager 创建下载管理器
 func NewDownloadManager(options *DownloadOptions) *DownloadManager {
+	return NewDownloadManagerWithService(NewEnhancedDownloadService(options))
+}
+
+// NewDownloadManagerWithService 使用指定的下载服务创建下载管理器
+func NewDownloadManagerWithService(service DownloadService) *DownloadManager {
 	return &DownloadManager{
-		service:         NewEnhancedDownloadService(options),
+		service:         service,
 		activeDownloads: make(map[string]*DownloadContext),
 	}
 }
 
 // StartDownload 开始下载
 func (dm *DownloadManager) StartDownload(url, destPath string, progress ProgressCallback) (*DownloadContext, error) {
+	return dm.StartDownloadWithContext(context.Background(), url, destPath, progress)
+}
+
+// StartDownloadWithContext 开始下载，parent 取消时同时取消下载
+func (dm *DownloadManager) StartDownloadWithContext(parent context.Context, url, destPath string, progress ProgressCallback) (*DownloadContext, error) {
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+
 	// 检查是否已经在下载
 	if _, exists := dm.activeDownloads[url]; exists {
 		return nil, fmt.Errorf("URL %s 正在下载中", url)
 	}
 
-	_, cancel := context.WithCancel(context.Background())
+	ctx, cancel := context.WithCancel(parent)
 	done := make(chan error, 1)
 
 	downloadCtx := &DownloadContext{
@@ -164,7 +179,10 @@ func (dm *DownloadManager) StartDownload(url, destPath string, progress Progress
 	// 启动下载协程
 	go func() {
 		defer func() {
+			dm.mu.Lock()
 			delete(dm.activeDownloads, url)
+			dm.mu.Unlock()
+			cancel()
 			close(done)
 		}()
 
@@ -176,9 +194,15 @@ func (dm *DownloadManager) StartDownload(url, destPath string, progress Progress
 			}
 		}
 
-		stats, err := dm.service.DownloadWithStats(url, destPath, progressCallback)
-		downloadCtx.Stats = stats
-		done <- err
+		// 增强服务附带统计信息，普通服务支持通过上下文取消
+		if enhanced, ok := dm.service.(EnhancedDownloadService); ok {
+			stats, err := enhanced.DownloadWithStats(url, destPath, progressCallback)
+			downloadCtx.Stats = stats
+			done <- err
+			return
+		}
+
+		done <- dm.service.DownloadWithContext(ctx, url, destPath, progressCallback)
 	}()
 
 	return downloadCtx, nil
@@ -186,7 +210,9 @@ func (dm *DownloadManager) StartDownload(url, destPath string, progress Progress
 
 // CancelDownload 取消下载
 func (dm *DownloadManager) CancelDownload(url string) error {
+	dm.mu.RLock()
 	downloadCtx, exists := dm.activeDownloads[url]
+	dm.mu.RUnlock()
 	if !exists {
 		return fmt.Errorf("URL %s 没有在下载中", url)
 	}
@@ -197,6 +223,9 @@ func (dm *DownloadManager) CancelDownload(url string) error {
 
 // GetActiveDownloads 获取活跃的下载
 func (dm *DownloadManager) GetActiveDownloads() map[string]*DownloadContext {
+	dm.mu.RLock()
+	defer dm.mu.RUnlock()
+
 	result := make(map[string]*DownloadContext)
 	for url, ctx := range dm.activeDownloads {
 		result[url] = ctx
@@ -206,7 +235,9 @@ func (dm *DownloadManager) GetActiveDownloads() map[string]*DownloadContext {
 
 // WaitForDownload 等待下载完成
 func (dm *DownloadManager) WaitForDownload(url string) error {
+	dm.mu.RLock()
 	downloadCtx, exists := dm.activeDownloads[url]
+	dm.mu.RUnlock()
 	if !exists {
 		return fmt.Errorf("URL %s 没有在下载中", url)
 	}
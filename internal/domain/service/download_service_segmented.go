@@ -0,0 +1,435 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// errRangeNotSupported 服务器未按预期返回分段内容，需要回退到单连接下载
+var errRangeNotSupported = errors.New("服务器不支持分段下载")
+
+// downloadSegment 下载分段
+type downloadSegment struct {
+	index int   // 分段序号
+	start int64 // 起始字节（包含）
+	end   int64 // 结束字节（包含）
+}
+
+// segmentProgress 汇总各分段的下载进度
+type segmentProgress struct {
+	mu         sync.Mutex
+	downloaded int64
+	total      int64
+	startTime  time.Time
+	lastReport time.Time
+	updateRate time.Duration
+	callback   ProgressCallback
+}
+
+// add 累加已下载字节数，并按更新频率回调进度
+func (p *segmentProgress) add(n int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.downloaded += n
+	if p.callback == nil || time.Since(p.lastReport) < p.updateRate {
+		return
+	}
+	p.report()
+}
+
+// report 回调当前进度（调用方需持有锁）
+func (p *segmentProgress) report() {
+	elapsed := time.Since(p.startTime).Seconds()
+	speed := 0.0
+	if elapsed > 0 {
+		speed = float64(p.downloaded) / elapsed
+	}
+	p.callback(p.downloaded, p.total, speed)
+	p.lastReport = time.Now()
+}
+
+// finish 输出最终进度
+func (p *segmentProgress) finish() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.callback != nil {
+		p.report()
+	}
+}
+
+// shouldDownloadSegmented 判断是否使用多连接分段下载
+func (d *DownloadServiceImpl) shouldDownloadSegmented(size int64, acceptRanges bool) bool {
+	return d.segments > 1 && acceptRanges && size > 0 && size >= 2*d.minSegmentSize
+}
+
+// splitSegments 将文件按字节范围切分为若干分段
+func (d *DownloadServiceImpl) splitSegments(size int64) []downloadSegment {
+	count := int64(d.segments)
+	if d.minSegmentSize > 0 && size/count < d.minSegmentSize {
+		count = size / d.minSegmentSize
+	}
+	if count < 1 {
+		count = 1
+	}
+
+	segmentSize := size / count
+	segments := make([]downloadSegment, 0, count)
+	for i := int64(0); i < count; i++ {
+		start := i * segmentSize
+		end := start + segmentSize - 1
+		if i == count-1 {
+			end = size - 1
+		}
+		segments = append(segments, downloadSegment{index: int(i), start: start, end: end})
+	}
+	return segments
+}
+
+// segmentRange 持久化的分段进度
+type segmentRange struct {
+	Offset int64 `json:"offset"` // 下一个要下载的字节
+	End    int64 `json:"end"`    // 结束字节（包含）
+}
+
+// segmentResumeState 分段下载进度文件的内容
+type segmentResumeState struct {
+	Size      int64          `json:"size"`                // 文件大小
+	Validator string         `json:"validator,omitempty"` // ETag 或 Last-Modified，续传时作为 If-Range 发送
+	Segments  []segmentRange `json:"segments"`            // 各分段的进度
+}
+
+// segmentJournal 记录分段下载进度，进程重启后从已完成的位置继续下载。
+// 只在数据写入文件后推进进度，因此记录的进度不会超过实际写入的位置
+type segmentJournal struct {
+	mu        sync.Mutex
+	path      string
+	state     segmentResumeState
+	lastSave  time.Time
+	saveEvery time.Duration
+}
+
+// segmentStatePath 获取分段下载进度文件的路径
+func segmentStatePath(destPath string) string {
+	return destPath + ".segments"
+}
+
+// removeSegmentedDownload 删除未完成的下载文件及其分段进度
+func removeSegmentedDownload(destPath string) error {
+	for _, path := range []string{destPath, segmentStatePath(destPath)} {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("删除现有文件失败: %v", err)
+		}
+	}
+	return nil
+}
+
+// loadSegmentJournal 读取分段下载进度，文件不存在时返回 nil
+func loadSegmentJournal(destPath string) (*segmentJournal, error) {
+	path := segmentStatePath(destPath)
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取分段下载进度失败: %v", err)
+	}
+	journal := &segmentJournal{path: path, saveEvery: time.Second}
+	if err := json.Unmarshal(data, &journal.state); err != nil {
+		return nil, fmt.Errorf("解析分段下载进度失败: %v", err)
+	}
+	return journal, nil
+}
+
+// advance 推进分段进度，距上次保存超过间隔时写入磁盘
+func (j *segmentJournal) advance(index int, n int64) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.state.Segments[index].Offset += n
+	if time.Since(j.lastSave) >= j.saveEvery {
+		j.saveLocked()
+	}
+}
+
+// save 写入分段进度
+func (j *segmentJournal) save() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.saveLocked()
+}
+
+// saveLocked 写入分段进度（调用方需持有锁），先写临时文件再重命名
+func (j *segmentJournal) saveLocked() error {
+	data, err := json.Marshal(j.state)
+	if err != nil {
+		return fmt.Errorf("序列化分段下载进度失败: %v", err)
+	}
+	tempPath := j.path + ".tmp"
+	if err := os.WriteFile(tempPath, data, 0644); err != nil {
+		return fmt.Errorf("写入分段下载进度失败: %v", err)
+	}
+	if err := os.Rename(tempPath, j.path); err != nil {
+		os.Remove(tempPath)
+		return fmt.Errorf("写入分段下载进度失败: %v", err)
+	}
+	j.lastSave = time.Now()
+	return nil
+}
+
+// remaining 获取尚未下载的字节数
+func (j *segmentJournal) remaining() int64 {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	var remaining int64
+	for _, segment := range j.state.Segments {
+		if segment.End >= segment.Offset {
+			remaining += segment.End - segment.Offset + 1
+		}
+	}
+	return remaining
+}
+
+// downloadSegmented 使用多个并行Range请求下载文件到预分配的目标文件
+func (d *DownloadServiceImpl) downloadSegmented(ctx context.Context, url, destPath string, size int64, validator string, progress ProgressCallback) error {
+	file, err := os.Create(destPath)
+	if err != nil {
+		return fmt.Errorf("创建目标文件失败: %v", err)
+	}
+	defer file.Close()
+
+	// 预分配文件空间
+	if err := file.Truncate(size); err != nil {
+		return fmt.Errorf("预分配文件空间失败: %v", err)
+	}
+
+	journal := &segmentJournal{
+		path:      segmentStatePath(destPath),
+		state:     segmentResumeState{Size: size, Validator: validator},
+		saveEvery: time.Second,
+	}
+	for _, segment := range d.splitSegments(size) {
+		journal.state.Segments = append(journal.state.Segments, segmentRange{Offset: segment.start, End: segment.end})
+	}
+	if err := journal.save(); err != nil {
+		return err
+	}
+
+	return d.runSegments(ctx, url, file, journal, progress)
+}
+
+// resumeSegmented 继续目标文件旁分段进度文件记录的下载，没有进度文件时返回 false。
+// 服务器上的文件大小或校验值已变化时返回 errRangeNotSupported，由调用方重新下载
+func (d *DownloadServiceImpl) resumeSegmented(ctx context.Context, url, destPath string, progress ProgressCallback) (bool, error) {
+	journal, err := loadSegmentJournal(destPath)
+	if err != nil || journal == nil {
+		return err != nil, err
+	}
+
+	fileInfo, err := os.Stat(destPath)
+	if err != nil || fileInfo.Size() != journal.state.Size {
+		return true, errRangeNotSupported
+	}
+	info, err := d.ProbeFile(ctx, url)
+	if err != nil {
+		return true, err
+	}
+	if !info.AcceptRanges || info.Size != journal.state.Size {
+		return true, errRangeNotSupported
+	}
+	if journal.state.Validator != "" && info.Validator() != "" && info.Validator() != journal.state.Validator {
+		return true, errRangeNotSupported
+	}
+
+	file, err := os.OpenFile(destPath, os.O_WRONLY, 0644)
+	if err != nil {
+		return true, fmt.Errorf("打开目标文件失败: %v", err)
+	}
+	defer file.Close()
+
+	return true, d.runSegments(ctx, url, file, journal, progress)
+}
+
+// runSegments 并行下载分段进度中未完成的部分，成功后删除进度文件，失败时保存当前进度
+func (d *DownloadServiceImpl) runSegments(ctx context.Context, url string, file *os.File, journal *segmentJournal, progress ProgressCallback) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	tracker := &segmentProgress{
+		downloaded: journal.state.Size - journal.remaining(),
+		total:      journal.state.Size,
+		startTime:  time.Now(),
+		updateRate: d.progressUpdateRate,
+		callback:   progress,
+	}
+
+	errChan := make(chan error, len(journal.state.Segments))
+	var wg sync.WaitGroup
+
+	for index, segment := range journal.state.Segments {
+		wg.Add(1)
+		go func(segment downloadSegment) {
+			defer wg.Done()
+			if err := d.downloadSegmentWithRetry(ctx, url, file, segment, journal, tracker); err != nil {
+				errChan <- err
+				cancel() // 任一分段失败时取消其余分段
+			}
+		}(downloadSegment{index: index, start: segment.Offset, end: segment.End})
+	}
+
+	wg.Wait()
+	close(errChan)
+
+	// 优先返回需要回退的错误，其次返回首个分段错误
+	var firstErr error
+	for err := range errChan {
+		if errors.Is(err, errRangeNotSupported) {
+			return err
+		}
+		if firstErr == nil && !errors.Is(err, context.Canceled) {
+			firstErr = err
+		}
+	}
+	if firstErr == nil {
+		firstErr = ctx.Err()
+	}
+	if firstErr != nil {
+		// 保存进度，下次运行时继续下载
+		if err := file.Sync(); err == nil {
+			journal.save()
+		}
+		return firstErr
+	}
+
+	tracker.finish()
+	if err := file.Sync(); err != nil {
+		return err
+	}
+	if err := os.Remove(journal.path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("删除分段下载进度失败: %v", err)
+	}
+	return nil
+}
+
+// downloadSegmentWithRetry 下载单个分段，失败时从已完成位置独立重试
+func (d *DownloadServiceImpl) downloadSegmentWithRetry(ctx context.Context, url string, file *os.File, segment downloadSegment, journal *segmentJournal, tracker *segmentProgress) error {
+	offset := segment.start
+	var lastErr error
+
+	for attempt := 0; attempt <= d.maxRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(d.retryDelay):
+			}
+		}
+
+		written, err := d.fetchRange(ctx, url, file, offset, segment.end, journal.state.Validator, func(n int64) {
+			journal.advance(segment.index, n)
+			tracker.add(n)
+		})
+		offset += written
+		if err == nil {
+			return nil
+		}
+		if errors.Is(err, errRangeNotSupported) || ctx.Err() != nil {
+			return err
+		}
+		lastErr = err
+	}
+
+	return fmt.Errorf("分段 %d 下载失败，已重试 %d 次: %w", segment.index, d.maxRetries, lastErr)
+}
+
+// fetchRange 下载指定字节范围并写入文件对应位置，每次写入后回调 written，返回实际写入的字节数。
+// validator 不为空时发送 If-Range，服务器上的文件已变化时返回 errRangeNotSupported
+func (d *DownloadServiceImpl) fetchRange(ctx context.Context, url string, file *os.File, start, end int64, validator string, written func(int64)) (int64, error) {
+	if start > end {
+		return 0, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return 0, fmt.Errorf("创建HTTP请求失败: %v", err)
+	}
+	req.Header.Set("User-Agent", d.userAgent)
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", start, end))
+	if validator != "" {
+		req.Header.Set("If-Range", validator)
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("HTTP请求失败: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK {
+		return 0, errRangeNotSupported
+	}
+	if resp.StatusCode != http.StatusPartialContent {
+		return 0, fmt.Errorf("HTTP响应错误: %w", newHTTPStatusError(resp))
+	}
+	if !contentRangeStartsAt(resp.Header.Get("Content-Range"), start) {
+		return 0, errRangeNotSupported
+	}
+
+	buffer := make([]byte, d.chunkSize)
+	offset := start
+	remaining := end - start + 1
+
+	for remaining > 0 {
+		n, readErr := resp.Body.Read(buffer)
+		if int64(n) > remaining {
+			n = int(remaining)
+		}
+		if n > 0 {
+			if _, err := file.WriteAt(buffer[:n], offset); err != nil {
+				return offset - start, fmt.Errorf("写入文件失败: %v", err)
+			}
+			offset += int64(n)
+			remaining -= int64(n)
+			written(int64(n))
+		}
+
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			return offset - start, fmt.Errorf("读取数据失败: %v", readErr)
+		}
+	}
+
+	if remaining > 0 {
+		return offset - start, fmt.Errorf("分段数据不完整: 缺少 %d 字节", remaining)
+	}
+
+	return offset - start, nil
+}
+
+// contentRangeStartsAt 检查Content-Range响应头的起始位置是否与请求一致
+func contentRangeStartsAt(contentRange string, start int64) bool {
+	// 格式: bytes 100-199/1000
+	if !strings.HasPrefix(contentRange, "bytes ") {
+		return false
+	}
+	rangePart := strings.TrimPrefix(contentRange, "bytes ")
+	dash := strings.Index(rangePart, "-")
+	if dash <= 0 {
+		return false
+	}
+	value, err := strconv.ParseInt(rangePart[:dash], 10, 64)
+	return err == nil && value == start
+}
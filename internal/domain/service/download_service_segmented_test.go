@@ -0,0 +1,346 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// newSegmentedTestService 创建启用分段下载的测试下载服务
+func newSegmentedTestService(segments int) *DownloadServiceImpl {
+	return NewDownloadService(&DownloadOptions{
+		MaxRetries:         3,
+		RetryDelay:         10 * time.Millisecond,
+		Timeout:            10 * time.Second,
+		ChunkSize:          4 * 1024,
+		ProgressUpdateRate: time.Millisecond,
+		Segments:           segments,
+		MinSegmentSize:     16 * 1024,
+	}).(*DownloadServiceImpl)
+}
+
+// createTestPayload 创建指定大小的测试数据
+func createTestPayload(size int) []byte {
+	data := make([]byte, size)
+	for i := range data {
+		data[i] = byte(i * 31 % 251)
+	}
+	return data
+}
+
+func TestDownloadService_SegmentedDownload(t *testing.T) {
+	payload := createTestPayload(200 * 1024)
+
+	var mu sync.Mutex
+	var ranges []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		if r.Method == http.MethodGet {
+			ranges = append(ranges, r.Header.Get("Range"))
+		}
+		mu.Unlock()
+		http.ServeContent(w, r, "go.tar.gz", time.Time{}, bytes.NewReader(payload))
+	}))
+	defer server.Close()
+
+	service := newSegmentedTestService(4)
+	destPath := filepath.Join(t.TempDir(), "go.tar.gz")
+
+	var lastDownloaded, lastTotal int64
+	err := service.Download(server.URL, destPath, func(downloaded, total int64, speed float64) {
+		lastDownloaded, lastTotal = downloaded, total
+	})
+	if err != nil {
+		t.Fatalf("分段下载失败: %v", err)
+	}
+
+	data, err := os.ReadFile(destPath)
+	if err != nil {
+		t.Fatalf("读取下载文件失败: %v", err)
+	}
+	if !bytes.Equal(data, payload) {
+		t.Error("下载文件内容与源数据不一致")
+	}
+
+	if len(ranges) != 4 {
+		t.Errorf("Range请求数量 = %d, 期望 4", len(ranges))
+	}
+	for _, r := range ranges {
+		if !strings.HasPrefix(r, "bytes=") {
+			t.Errorf("请求缺少Range头: %q", r)
+		}
+	}
+
+	if lastDownloaded != int64(len(payload)) || lastTotal != int64(len(payload)) {
+		t.Errorf("最终进度 = %d/%d, 期望 %d/%d", lastDownloaded, lastTotal, len(payload), len(payload))
+	}
+}
+
+func TestDownloadService_SegmentedDownload_RetryFailedSegment(t *testing.T) {
+	payload := createTestPayload(128 * 1024)
+
+	var mu sync.Mutex
+	failed := make(map[string]bool)
+	requests := make(map[string]int)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rangeHeader := r.Header.Get("Range")
+
+		mu.Lock()
+		requests[rangeHeader]++
+		// 第二个分段首次请求只返回部分数据后断开连接
+		inject := r.Method == http.MethodGet && strings.HasPrefix(rangeHeader, "bytes=32768-") && !failed[rangeHeader]
+		if inject {
+			failed[rangeHeader] = true
+		}
+		mu.Unlock()
+
+		if inject {
+			w.Header().Set("Content-Range", fmt.Sprintf("bytes 32768-65535/%d", len(payload)))
+			w.Header().Set("Content-Length", "32768")
+			w.WriteHeader(http.StatusPartialContent)
+			w.Write(payload[32768 : 32768+1000])
+			w.(http.Flusher).Flush()
+			if hijacker, ok := w.(http.Hijacker); ok {
+				conn, _, _ := hijacker.Hijack()
+				conn.Close()
+			}
+			return
+		}
+
+		http.ServeContent(w, r, "go.tar.gz", time.Time{}, bytes.NewReader(payload))
+	}))
+	defer server.Close()
+
+	service := newSegmentedTestService(4)
+	destPath := filepath.Join(t.TempDir(), "go.tar.gz")
+
+	if err := service.Download(server.URL, destPath, nil); err != nil {
+		t.Fatalf("分段下载失败: %v", err)
+	}
+
+	data, err := os.ReadFile(destPath)
+	if err != nil {
+		t.Fatalf("读取下载文件失败: %v", err)
+	}
+	if !bytes.Equal(data, payload) {
+		t.Error("重试后下载文件内容与源数据不一致")
+	}
+
+	// 失败的分段应从已写入位置续传，而非整体重新下载
+	if requests["bytes=33768-65535"] != 1 {
+		t.Errorf("失败分段未从断点重试, 请求记录: %v", requests)
+	}
+	if requests["bytes=0-32767"] != 1 {
+		t.Errorf("成功的分段不应重复下载, 请求记录: %v", requests)
+	}
+}
+
+func TestDownloadService_SegmentedDownload_Fallback(t *testing.T) {
+	payload := createTestPayload(100 * 1024)
+
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+	}{
+		{
+			name: "不支持Range",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Length", fmt.Sprintf("%d", len(payload)))
+				w.WriteHeader(http.StatusOK)
+				if r.Method != http.MethodHead {
+					w.Write(payload)
+				}
+			},
+		},
+		{
+			name: "声明支持Range但忽略Range头",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Accept-Ranges", "bytes")
+				w.Header().Set("Content-Length", fmt.Sprintf("%d", len(payload)))
+				w.WriteHeader(http.StatusOK)
+				if r.Method != http.MethodHead {
+					w.Write(payload)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(tt.handler)
+			defer server.Close()
+
+			service := newSegmentedTestService(4)
+			destPath := filepath.Join(t.TempDir(), "go.tar.gz")
+
+			if err := service.Download(server.URL, destPath, nil); err != nil {
+				t.Fatalf("回退到单连接下载失败: %v", err)
+			}
+
+			data, err := os.ReadFile(destPath)
+			if err != nil {
+				t.Fatalf("读取下载文件失败: %v", err)
+			}
+			if !bytes.Equal(data, payload) {
+				t.Error("下载文件内容与源数据不一致")
+			}
+		})
+	}
+}
+
+func TestDownloadService_SplitSegments(t *testing.T) {
+	service := newSegmentedTestService(4)
+
+	tests := []struct {
+		size     int64
+		expected int
+	}{
+		{size: 200 * 1024, expected: 4},
+		{size: 40 * 1024, expected: 2},
+		{size: 16*1024 + 1, expected: 1},
+	}
+
+	for _, tt := range tests {
+		segments := service.splitSegments(tt.size)
+		if len(segments) != tt.expected {
+			t.Errorf("splitSegments(%d) 分段数 = %d, 期望 %d", tt.size, len(segments), tt.expected)
+			continue
+		}
+
+		// 分段应连续覆盖整个文件
+		var next int64
+		for _, segment := range segments {
+			if segment.start != next {
+				t.Errorf("分段 %d 起始位置 = %d, 期望 %d", segment.index, segment.start, next)
+			}
+			next = segment.end + 1
+		}
+		if next != tt.size {
+			t.Errorf("分段覆盖到 %d, 期望 %d", next, tt.size)
+		}
+	}
+}
+
+// writeSegmentedPartial 写入进程中断时留下的分段下载文件和进度：每个分段只完成了前一半
+func writeSegmentedPartial(t *testing.T, service *DownloadServiceImpl, destPath string, payload []byte, validator string) []string {
+	t.Helper()
+	data := make([]byte, len(payload))
+	state := segmentResumeState{Size: int64(len(payload)), Validator: validator}
+	var remaining []string
+	for _, segment := range service.splitSegments(int64(len(payload))) {
+		offset := segment.start + (segment.end-segment.start+1)/2
+		copy(data[segment.start:offset], payload[segment.start:offset])
+		state.Segments = append(state.Segments, segmentRange{Offset: offset, End: segment.end})
+		remaining = append(remaining, fmt.Sprintf("bytes=%d-%d", offset, segment.end))
+	}
+	if err := os.WriteFile(destPath, data, 0644); err != nil {
+		t.Fatalf("写入部分文件失败: %v", err)
+	}
+	journal := &segmentJournal{path: segmentStatePath(destPath), state: state}
+	if err := journal.save(); err != nil {
+		t.Fatalf("写入分段进度失败: %v", err)
+	}
+	return remaining
+}
+
+func TestDownloadService_SegmentedDownload_ResumeAfterRestart(t *testing.T) {
+	payload := createTestPayload(128 * 1024)
+
+	var mu sync.Mutex
+	var ranges []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			mu.Lock()
+			ranges = append(ranges, r.Header.Get("Range"))
+			mu.Unlock()
+		}
+		w.Header().Set("ETag", `"v1"`)
+		http.ServeContent(w, r, "go.tar.gz", time.Time{}, bytes.NewReader(payload))
+	}))
+	defer server.Close()
+
+	t.Run("继续未完成的分段", func(t *testing.T) {
+		service := newSegmentedTestService(4)
+		destPath := filepath.Join(t.TempDir(), "go.tar.gz")
+		remaining := writeSegmentedPartial(t, service, destPath, payload, `"v1"`)
+
+		if err := service.Download(server.URL, destPath, nil); err != nil {
+			t.Fatalf("继续分段下载失败: %v", err)
+		}
+		data, _ := os.ReadFile(destPath)
+		if !bytes.Equal(data, payload) {
+			t.Error("续传后文件内容与源数据不一致")
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		sort.Strings(ranges)
+		sort.Strings(remaining)
+		if strings.Join(ranges, ",") != strings.Join(remaining, ",") {
+			t.Errorf("Range请求 = %v, 期望只下载未完成的部分 %v", ranges, remaining)
+		}
+		if _, err := os.Stat(segmentStatePath(destPath)); !os.IsNotExist(err) {
+			t.Error("下载完成后应删除分段进度")
+		}
+	})
+
+	t.Run("服务器文件已变化", func(t *testing.T) {
+		mu.Lock()
+		ranges = nil
+		mu.Unlock()
+		service := newSegmentedTestService(4)
+		destPath := filepath.Join(t.TempDir(), "go.tar.gz")
+		stale := writeSegmentedPartial(t, service, destPath, bytes.Repeat([]byte{0xff}, len(payload)), `"v0"`)
+
+		if err := service.Download(server.URL, destPath, nil); err != nil {
+			t.Fatalf("重新下载失败: %v", err)
+		}
+		data, _ := os.ReadFile(destPath)
+		if !bytes.Equal(data, payload) {
+			t.Error("重新下载后文件内容与源数据不一致")
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		for _, r := range stale {
+			for _, got := range ranges {
+				if got == r {
+					t.Errorf("ETag变化后不应继续旧的分段: %v", ranges)
+				}
+			}
+		}
+	})
+}
+
+func TestDownloadService_SegmentedDownload_FailureKeepsProgress(t *testing.T) {
+	payload := createTestPayload(128 * 1024)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet && strings.HasPrefix(r.Header.Get("Range"), "bytes=32768-") {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		http.ServeContent(w, r, "go.tar.gz", time.Time{}, bytes.NewReader(payload))
+	}))
+	defer server.Close()
+
+	service := newSegmentedTestService(4)
+	service.maxRetries = 0
+	destPath := filepath.Join(t.TempDir(), "go.tar.gz")
+	if err := service.Download(server.URL, destPath, nil); err == nil {
+		t.Fatal("分段失败时应返回错误")
+	}
+
+	journal, err := loadSegmentJournal(destPath)
+	if err != nil || journal == nil {
+		t.Fatalf("失败后应保留分段进度: %v", err)
+	}
+	if got := journal.state.Segments[1].Offset; got != 32768 {
+		t.Errorf("失败分段的进度 = %d, 期望 32768", got)
+	}
+}
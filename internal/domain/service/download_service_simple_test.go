@@ -1,11 +1,13 @@
 package service
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 )
@@ -153,3 +155,58 @@ func TestDownloadService_WithProgress(t *testing.T) {
 		t.Errorf("下载内容不匹配")
 	}
 }
+
+func TestDownloadService_ResumeWithIfRange(t *testing.T) {
+	testContent := strings.Repeat("0123456789", 100)
+	var ifRange string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			ifRange = r.Header.Get("If-Range")
+		}
+		w.Header().Set("ETag", `"v2"`)
+		http.ServeContent(w, r, "go.tar.gz", time.Time{}, strings.NewReader(testContent))
+	}))
+	defer server.Close()
+
+	service := NewDownloadService(&DownloadOptions{
+		MaxRetries: 0,
+		Timeout:    5 * time.Second,
+		ChunkSize:  1024,
+		Segments:   1,
+	})
+
+	info, err := service.(RemoteFileProber).ProbeFile(context.Background(), server.URL)
+	if err != nil {
+		t.Fatalf("获取远程文件信息失败: %v", err)
+	}
+	if info.Size != int64(len(testContent)) || info.ETag != `"v2"` || !info.AcceptRanges {
+		t.Errorf("远程文件信息 = %+v", info)
+	}
+
+	tests := []struct {
+		name      string
+		validator string
+		partial   string
+	}{
+		{"校验值一致时续传", `"v2"`, testContent[:400]},
+		{"校验值变化时重新下载", `"v1"`, strings.Repeat("x", 400)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			destPath := filepath.Join(t.TempDir(), "go.tar.gz")
+			os.WriteFile(destPath, []byte(tt.partial), 0644)
+
+			ctx := WithResumeValidator(context.Background(), tt.validator)
+			if err := service.DownloadWithContext(ctx, server.URL, destPath, nil); err != nil {
+				t.Fatalf("下载失败: %v", err)
+			}
+			if ifRange != tt.validator {
+				t.Errorf("If-Range = %q, 期望 %q", ifRange, tt.validator)
+			}
+			data, _ := os.ReadFile(destPath)
+			if string(data) != testContent {
+				t.Errorf("下载文件内容不一致, 长度 %d", len(data))
+			}
+		})
+	}
+}
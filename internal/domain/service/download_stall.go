@@ -0,0 +1,55 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrDownloadStalled 下载超过停滞超时（--timeout）没有任何进度
+var ErrDownloadStalled = errors.New("下载停滞")
+
+// stallTimer 下载停滞检测，超过 timeout 没有进度时取消下载
+// 为nil时不做检测，便于未设置超时时直接调用各方法
+type stallTimer struct {
+	ctx     context.Context
+	cancel  context.CancelCauseFunc
+	timer   *time.Timer
+	timeout time.Duration
+}
+
+// newStallTimer 创建停滞检测，返回的上下文在超过 timeout 没有调用 touch 时取消
+// timeout 不大于0时不检测，返回原上下文和nil
+func newStallTimer(parent context.Context, timeout time.Duration) (context.Context, *stallTimer) {
+	if timeout <= 0 {
+		return parent, nil
+	}
+	ctx, cancel := context.WithCancelCause(parent)
+	t := &stallTimer{ctx: ctx, cancel: cancel, timeout: timeout}
+	t.timer = time.AfterFunc(timeout, func() { cancel(ErrDownloadStalled) })
+	return ctx, t
+}
+
+// touch 记录一次下载进度，重新开始计时
+func (t *stallTimer) touch() {
+	if t != nil {
+		t.timer.Reset(t.timeout)
+	}
+}
+
+// stop 停止检测并释放上下文
+func (t *stallTimer) stop() {
+	if t != nil {
+		t.timer.Stop()
+		t.cancel(nil)
+	}
+}
+
+// wrap 下载因停滞被取消时，把上下文取消错误替换为 ErrDownloadStalled，以便切换到其他镜像
+func (t *stallTimer) wrap(err error) error {
+	if t == nil || err == nil || !errors.Is(context.Cause(t.ctx), ErrDownloadStalled) {
+		return err
+	}
+	return fmt.Errorf("超过 %d 秒没有下载进度: %w", int(t.timeout/time.Second), ErrDownloadStalled)
+}
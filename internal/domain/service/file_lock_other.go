@@ -0,0 +1,50 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly)
+
+package service
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+const (
+	// fileLockRetryInterval 锁文件已存在时重试的间隔
+	fileLockRetryInterval = 50 * time.Millisecond
+	// fileLockStaleAge 锁文件超过该时长未释放时视为持有者已异常退出
+	fileLockStaleAge = 30 * time.Second
+)
+
+// fileLock 跨进程的排他文件锁，通过独占创建锁文件实现
+type fileLock struct {
+	path string
+	file *os.File
+}
+
+// lockFile 获取文件锁，其他进程持有锁时等待，锁文件长时间未释放时将其删除后重试
+func lockFile(path string) (*fileLock, error) {
+	deadline := time.Now().Add(2 * fileLockStaleAge)
+	for {
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_RDWR, 0644)
+		if err == nil {
+			return &fileLock{path: path, file: file}, nil
+		}
+		if !os.IsExist(err) {
+			return nil, fmt.Errorf("创建锁文件失败: %v", err)
+		}
+		if info, statErr := os.Stat(path); statErr == nil && time.Since(info.ModTime()) > fileLockStaleAge {
+			os.Remove(path)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("等待文件锁 %s 超时", path)
+		}
+		time.Sleep(fileLockRetryInterval)
+	}
+}
+
+// Unlock 释放文件锁
+func (l *fileLock) Unlock() error {
+	l.file.Close()
+	return os.Remove(l.path)
+}
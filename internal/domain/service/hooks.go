@@ -0,0 +1,333 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+	"version-list/internal/domain/model"
+
+	"gopkg.in/yaml.v3"
+)
+
+// hookOutputLines 钩子失败时在错误信息中保留的输出行数
+const hookOutputLines = 10
+
+// HooksConfigPath 获取钩子配置文件路径 ~/.go-version/hooks.yaml
+func HooksConfigPath() string {
+	return filepath.Join(goVersionHomeDir(), "hooks.yaml")
+}
+
+// LoadHooksConfig 读取钩子配置（.json 文件按 JSON 解析，其他按 YAML 解析），文件不存在时返回空配置
+func LoadHooksConfig(path string) (*model.HooksConfig, error) {
+	config := &model.HooksConfig{}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return config, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取钩子配置失败: %v", err)
+	}
+
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		err = json.Unmarshal(data, config)
+	} else {
+		err = yaml.Unmarshal(data, config)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("解析钩子配置 %s 失败: %v", path, err)
+	}
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("钩子配置 %s 无效: %v", path, err)
+	}
+	return config, nil
+}
+
+// SaveHooksConfig 保存钩子配置
+func SaveHooksConfig(path string, config *model.HooksConfig) error {
+	if err := config.Validate(); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("创建配置目录失败: %v", err)
+	}
+
+	var data []byte
+	var err error
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		data, err = json.MarshalIndent(config, "", "  ")
+	} else {
+		data, err = yaml.Marshal(config)
+	}
+	if err != nil {
+		return fmt.Errorf("序列化钩子配置失败: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("写入钩子配置失败: %v", err)
+	}
+	return nil
+}
+
+// hookEnvironment 获取钩子和默认工具命令的环境变量：GOROOT 和 PATH 指向工具链，并通过 GO_VERSION_* 描述版本。
+// 按版本隔离时 GOBIN、GOCACHE 指向该版本的目录，GOBIN 同时加入 PATH；最后应用合并后的自定义环境变量
+func (s *VersionService) hookEnvironment(event model.HookEvent, version *model.GoVersion) ([]string, error) {
+	isolation, err := s.isolationEnvVars(version)
+	if err != nil {
+		return nil, err
+	}
+	dir, _ := os.Getwd()
+	vars, err := s.ResolveEnvVars(version.Version, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	path := filepath.Join(version.Path, "bin")
+	if len(isolation) > 0 {
+		path += string(os.PathListSeparator) + versionIsolationBin(version.Version)
+	}
+	env := append(os.Environ(),
+		"GOROOT="+version.Path,
+		"GOTOOLCHAIN=local",
+		"PATH="+path+string(os.PathListSeparator)+os.Getenv("PATH"),
+		"GO_VERSION_NAME="+version.Version,
+		"GO_VERSION_RELEASE="+version.ReleaseVersion(),
+		"GO_VERSION_PATH="+version.Path,
+	)
+	env = append(env, isolation...)
+	for _, v := range vars {
+		env = append(env, v.String())
+	}
+	if event != "" {
+		env = append(env, "GO_VERSION_HOOK="+string(event))
+	}
+	return env, nil
+}
+
+// shellCommand 创建通过系统 shell 执行命令的进程
+func shellCommand(ctx context.Context, command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.CommandContext(ctx, "cmd", "/C", command)
+	}
+	return exec.CommandContext(ctx, "sh", "-c", command)
+}
+
+// tailOutput 获取命令输出的最后几行
+func tailOutput(output []byte) string {
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	if len(lines) > hookOutputLines {
+		lines = lines[len(lines)-hookOutputLines:]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// runHooks 运行指定时机的钩子：必需的钩子失败时立即返回错误，其他钩子的失败作为警告返回
+func (s *VersionService) runHooks(ctx context.Context, event model.HookEvent, version *model.GoVersion) ([]string, error) {
+	config, err := LoadHooksConfig(HooksConfigPath())
+	if err != nil {
+		return nil, err
+	}
+
+	hooks := config.Hooks[event]
+	if len(hooks) == 0 {
+		return nil, nil
+	}
+	env, err := s.hookEnvironment(event, version)
+	if err != nil {
+		return nil, err
+	}
+
+	var warnings []string
+	for _, hook := range hooks {
+		err := runHook(ctx, hook, env)
+		if err == nil {
+			continue
+		}
+		if ctx.Err() != nil {
+			return warnings, ctx.Err()
+		}
+		if hook.Required {
+			return warnings, fmt.Errorf("%s 钩子 %s 失败: %w", event, hook.DisplayName(), err)
+		}
+		warnings = append(warnings, fmt.Sprintf("%s 钩子 %s 失败: %v", event, hook.DisplayName(), err))
+	}
+	return warnings, nil
+}
+
+// runHook 运行单个钩子，失败时错误信息包含命令输出的最后几行
+func runHook(ctx context.Context, hook model.Hook, env []string) error {
+	if hook.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(hook.Timeout)*time.Second)
+		defer cancel()
+	}
+
+	cmd := shellCommand(ctx, hook.Command)
+	cmd.Env = env
+	output, err := cmd.CombinedOutput()
+	if err == nil {
+		return nil
+	}
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		err = fmt.Errorf("超过 %d 秒未完成", hook.Timeout)
+	}
+	if tail := tailOutput(output); tail != "" {
+		return fmt.Errorf("%v\n%s", err, tail)
+	}
+	return err
+}
+
+// runPreInstallHooks 运行 pre-install 钩子，其他平台的安装和 --no-hooks 时跳过
+func (s *VersionService) runPreInstallHooks(ctx context.Context, context *model.InstallationContext, result *model.InstallationResult) error {
+	if context.Options.NoHooks || isCrossPlatformInstall(context.Options) {
+		return nil
+	}
+	target := &model.GoVersion{
+		Version: context.ToolchainName(),
+		Release: context.Version,
+		Path:    context.Paths.VersionDir,
+	}
+	warnings, err := s.runHooks(ctx, model.HookPreInstall, target)
+	result.Warnings = append(result.Warnings, warnings...)
+	return err
+}
+
+// saveInstalledVersion 安装默认工具后保存版本记录，再运行 post-install 钩子。
+// 必需的钩子失败时返回错误，由安装事务回滚版本记录和版本目录（没有事务时直接删除版本记录）
+func (s *VersionService) saveInstalledVersion(ctx context.Context, tx *installTransaction, goVersion *model.GoVersion, options *model.InstallOptions, result *model.InstallationResult, progressUI ProgressReporter) error {
+	if !options.NoTools {
+		tools, warnings, err := s.installDefaultTools(ctx, goVersion, progressUI)
+		if err != nil {
+			return err
+		}
+		goVersion.Tools = tools
+		result.Tools = tools
+		result.Warnings = append(result.Warnings, warnings...)
+	}
+
+	if err := tx.record(model.JournalStep{Type: model.JournalVersionRecord, Version: goVersion.Version}); err != nil {
+		return err
+	}
+	s.repoMu.Lock()
+	err := s.versionRepo.Save(goVersion)
+	s.repoMu.Unlock()
+	if err != nil {
+		return fmt.Errorf("保存到仓库失败: %v", err)
+	}
+
+	if options.NoHooks {
+		return nil
+	}
+	if progressUI != nil {
+		progressUI.SetMessage("运行 post-install 钩子...")
+	}
+	warnings, err := s.runHooks(ctx, model.HookPostInstall, goVersion)
+	result.Warnings = append(result.Warnings, warnings...)
+	if err != nil && tx == nil {
+		s.repoMu.Lock()
+		s.versionRepo.Remove(goVersion.Version)
+		s.repoMu.Unlock()
+	}
+	return err
+}
+
+// installDefaultTools 使用工具链 go install 配置的默认工具，返回安装成功的工具，失败的工具作为警告返回
+func (s *VersionService) installDefaultTools(ctx context.Context, version *model.GoVersion, progressUI ProgressReporter) ([]model.InstalledTool, []string, error) {
+	config, err := LoadHooksConfig(HooksConfigPath())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(config.DefaultTools) == 0 {
+		return nil, nil, nil
+	}
+	env, err := s.hookEnvironment("", version)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var tools []model.InstalledTool
+	var warnings []string
+	goExec := s.getGoExecutablePath(version.Path)
+	for _, spec := range config.DefaultTools {
+		tool, err := model.ParseToolSpec(spec)
+		if err != nil {
+			return nil, nil, err
+		}
+		if progressUI != nil {
+			progressUI.SetMessage(fmt.Sprintf("安装工具 %s...", tool))
+		}
+
+		cmd := exec.CommandContext(ctx, goExec, "install", tool.String())
+		cmd.Env = env
+		output, err := cmd.CombinedOutput()
+		if ctx.Err() != nil {
+			return nil, nil, ctx.Err()
+		}
+		if err != nil {
+			warning := fmt.Sprintf("安装工具 %s 失败: %v", tool, err)
+			if tail := tailOutput(output); tail != "" {
+				warning += "\n" + tail
+			}
+			warnings = append(warnings, warning)
+			continue
+		}
+		tool.InstalledAt = time.Now()
+		tools = append(tools, tool)
+	}
+	return tools, warnings, nil
+}
+
+// InstallDefaultTools 为已安装的版本安装默认工具，并更新版本记录中的工具列表
+func (s *VersionService) InstallDefaultTools(ctx context.Context, version string) ([]model.InstalledTool, []string, error) {
+	goVersion, err := s.versionRepo.FindByVersion(version)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Go版本 %s 未安装", version)
+	}
+
+	tools, warnings, err := s.installDefaultTools(ctx, goVersion, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	s.repoMu.Lock()
+	defer s.repoMu.Unlock()
+	goVersion.Tools = mergeInstalledTools(goVersion.Tools, tools)
+	goVersion.UpdatedAt = time.Now()
+	if err := s.versionRepo.Update(goVersion); err != nil {
+		return nil, nil, fmt.Errorf("更新版本记录失败: %v", err)
+	}
+	return tools, warnings, nil
+}
+
+// InstalledTools 获取已安装版本记录中的默认工具
+func (s *VersionService) InstalledTools(version string) ([]model.InstalledTool, error) {
+	goVersion, err := s.versionRepo.FindByVersion(version)
+	if err != nil {
+		return nil, fmt.Errorf("Go版本 %s 未安装", version)
+	}
+	return goVersion.Tools, nil
+}
+
+// mergeInstalledTools 合并工具列表，同一包路径使用新安装的记录
+func mergeInstalledTools(existing, installed []model.InstalledTool) []model.InstalledTool {
+	merged := make([]model.InstalledTool, 0, len(existing)+len(installed))
+	for _, tool := range existing {
+		replaced := false
+		for _, newTool := range installed {
+			if newTool.Package == tool.Package {
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			merged = append(merged, tool)
+		}
+	}
+	return append(merged, installed...)
+}
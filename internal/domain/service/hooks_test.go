@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"version-list/internal/domain/model"
+)
+
+// writeHooksConfig 在测试用户目录下写入钩子配置
+func writeHooksConfig(t *testing.T, config *model.HooksConfig) {
+	t.Helper()
+	if err := SaveHooksConfig(HooksConfigPath(), config); err != nil {
+		t.Fatalf("写入钩子配置失败: %v", err)
+	}
+}
+
+func TestLoadHooksConfig(t *testing.T) {
+	dir := t.TempDir()
+
+	config, err := LoadHooksConfig(filepath.Join(dir, "missing.yaml"))
+	if err != nil || len(config.Hooks) != 0 || len(config.DefaultTools) != 0 {
+		t.Errorf("配置文件不存在时应返回空配置, 实际 %+v, %v", config, err)
+	}
+
+	yamlConfig := `hooks:
+  post-install:
+    - name: warm
+      command: go build std
+      timeout: 30
+  pre-use:
+    - command: "true"
+      required: true
+default_tools:
+  - golang.org/x/tools/gopls
+`
+	writeTestFile(t, dir, "hooks.yaml", yamlConfig)
+	config, err = LoadHooksConfig(filepath.Join(dir, "hooks.yaml"))
+	if err != nil {
+		t.Fatalf("解析配置失败: %v", err)
+	}
+	postInstall := config.Hooks[model.HookPostInstall]
+	if len(postInstall) != 1 || postInstall[0].Name != "warm" || postInstall[0].Timeout != 30 {
+		t.Errorf("post-install 钩子 = %+v", postInstall)
+	}
+	if preUse := config.Hooks[model.HookPreUse]; len(preUse) != 1 || !preUse[0].Required {
+		t.Errorf("pre-use 钩子 = %+v", preUse)
+	}
+	if len(config.DefaultTools) != 1 {
+		t.Errorf("默认工具 = %v", config.DefaultTools)
+	}
+
+	writeTestFile(t, dir, "invalid.yaml", "hooks:\n  post-build:\n    - command: echo\n")
+	if _, err := LoadHooksConfig(filepath.Join(dir, "invalid.yaml")); err == nil {
+		t.Error("未知的钩子时机应返回错误")
+	}
+}
+
+func TestParseToolSpec(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    string
+		wantErr bool
+	}{
+		{"golang.org/x/tools/gopls", "golang.org/x/tools/gopls@latest", false},
+		{"honnef.co/go/tools/cmd/staticcheck@v0.5.1", "honnef.co/go/tools/cmd/staticcheck@v0.5.1", false},
+		{"golang.org/x/tools/gopls@", "", true},
+		{"@latest", "", true},
+		{"-exec=rm@latest", "", true},
+		{"golang.org/x/tools/gopls latest", "", true},
+	}
+
+	for _, tt := range tests {
+		tool, err := model.ParseToolSpec(tt.spec)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseToolSpec(%q) 错误 = %v, 期望错误 %v", tt.spec, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && tool.String() != tt.want {
+			t.Errorf("ParseToolSpec(%q) = %s, 期望 %s", tt.spec, tool, tt.want)
+		}
+	}
+}
+
+func TestVersionService_InstallRunsHooksAndDefaultTools(t *testing.T) {
+	server := newFakeReleaseServer(t, []string{"1.21.7"})
+	defer server.Close()
+	service, versionRepo := newTestVersionService(t, server.URL)
+
+	envFile := filepath.Join(t.TempDir(), "hook.env")
+	writeHooksConfig(t, &model.HooksConfig{
+		Hooks: map[model.HookEvent][]model.Hook{
+			model.HookPreInstall:  {{Command: "echo pre-install >> " + envFile}},
+			model.HookPostInstall: {{Command: "env >> " + envFile}, {Name: "broken", Command: "echo oops; exit 3"}},
+		},
+		DefaultTools: []string{"golang.org/x/tools/gopls@v0.16.2"},
+	})
+
+	versionDir := filepath.Join(t.TempDir(), "go1.21.7")
+	result, err := service.InstallOnlineWithContext(context.Background(), "1.21.7", &model.InstallOptions{CustomPath: versionDir, SkipVerification: true}, nil)
+	if err != nil {
+		t.Fatalf("非必需的钩子失败时不应中止安装: %v", err)
+	}
+
+	data, _ := os.ReadFile(envFile)
+	for _, want := range []string{"pre-install", "GOROOT=" + versionDir, "GO_VERSION_NAME=1.21.7", "GO_VERSION_PATH=" + versionDir, "GO_VERSION_HOOK=post-install"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("钩子环境中缺少 %s", want)
+		}
+	}
+	if len(result.Warnings) != 1 || !strings.Contains(result.Warnings[0], "broken") || !strings.Contains(result.Warnings[0], "oops") {
+		t.Errorf("警告 = %v, 期望包含失败钩子的名称和输出", result.Warnings)
+	}
+
+	recorded, err := versionRepo.FindByVersion("1.21.7")
+	if err != nil {
+		t.Fatalf("应保存版本记录: %v", err)
+	}
+	if len(recorded.Tools) != 1 || recorded.Tools[0].String() != "golang.org/x/tools/gopls@v0.16.2" {
+		t.Errorf("版本记录中的工具 = %+v", recorded.Tools)
+	}
+	if len(result.Tools) != 1 {
+		t.Errorf("安装结果中的工具 = %+v", result.Tools)
+	}
+}
+
+func TestVersionService_RequiredPostInstallHookRollsBack(t *testing.T) {
+	server := newFakeReleaseServer(t, []string{"1.21.8"})
+	defer server.Close()
+	service, versionRepo := newTestVersionService(t, server.URL)
+
+	writeHooksConfig(t, &model.HooksConfig{
+		Hooks: map[model.HookEvent][]model.Hook{
+			model.HookPostInstall: {{Name: "check", Command: "exit 1", Required: true}},
+		},
+	})
+
+	versionDir := filepath.Join(t.TempDir(), "go1.21.8")
+	_, err := service.InstallOnlineWithContext(context.Background(), "1.21.8", &model.InstallOptions{CustomPath: versionDir, SkipVerification: true}, nil)
+	if err == nil || !strings.Contains(err.Error(), "check") {
+		t.Fatalf("必需的钩子失败时应安装失败, 实际 %v", err)
+	}
+	if _, err := os.Stat(versionDir); !os.IsNotExist(err) {
+		t.Error("必需的钩子失败时应回滚版本目录")
+	}
+	if _, err := versionRepo.FindByVersion("1.21.8"); err == nil {
+		t.Error("必需的钩子失败时应回滚版本记录")
+	}
+
+	// --no-hooks 时跳过钩子
+	if _, err := service.InstallOnlineWithContext(context.Background(), "1.21.8", &model.InstallOptions{CustomPath: versionDir, SkipVerification: true, NoHooks: true}, nil); err != nil {
+		t.Errorf("跳过钩子时应安装成功: %v", err)
+	}
+}
+
+func TestVersionService_RequiredPreUseHookBlocksSwitch(t *testing.T) {
+	service, versionRepo := newTestVersionService(t, "")
+
+	versionDir := t.TempDir()
+	versionRepo.Save(&model.GoVersion{Version: "1.21.9", Path: versionDir})
+
+	writeHooksConfig(t, &model.HooksConfig{
+		Hooks: map[model.HookEvent][]model.Hook{
+			model.HookPreUse: {{Command: `test "$GO_VERSION_NAME" != 1.21.9`, Required: true}},
+		},
+	})
+
+	if _, err := service.UseWithHooks("1.21.9"); err == nil {
+		t.Fatal("必需的 pre-use 钩子失败时应返回错误")
+	}
+	if _, err := os.Lstat(filepath.Join(goVersionHomeDir(), "current")); !os.IsNotExist(err) {
+		t.Error("必需的 pre-use 钩子失败时不应切换版本")
+	}
+}
@@ -0,0 +1,549 @@
+package service
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// HTTPConfig 网络访问配置，保存在 ~/.go-version/http.json
+type HTTPConfig struct {
+	Proxy           string   `json:"proxy,omitempty"`            // 显式代理地址，为空时使用 HTTP(S)_PROXY 环境变量
+	NoProxy         string   `json:"no_proxy,omitempty"`         // 不使用代理的主机列表（逗号分隔），为空时使用 NO_PROXY 环境变量
+	CAFiles         []string `json:"ca_files,omitempty"`         // 额外信任的CA证书文件（PEM格式）
+	ClientCert      string   `json:"client_cert,omitempty"`      // 客户端证书文件
+	ClientKey       string   `json:"client_key,omitempty"`       // 客户端私钥文件
+	NetrcFile       string   `json:"netrc_file,omitempty"`       // netrc文件路径，为空时使用 $NETRC 或 ~/.netrc
+	NetrcDefault    bool     `json:"netrc_default,omitempty"`    // 对没有 machine 记录的主机使用 netrc 的 default 记录，配置了镜像凭据的主机除外
+	CredentialsFile string   `json:"credentials_file,omitempty"` // 镜像凭据文件路径，为空时使用 ~/.go-version/credentials.json
+}
+
+// MirrorCredential 镜像访问凭据，通过 Mirror.CredentialRef 引用
+type MirrorCredential struct {
+	Type        string `json:"type"`                   // 认证类型：basic 或 bearer
+	Username    string `json:"username,omitempty"`     // basic 认证用户名
+	Password    string `json:"password,omitempty"`     // basic 认证密码
+	PasswordEnv string `json:"password_env,omitempty"` // 从环境变量读取密码
+	Token       string `json:"token,omitempty"`        // bearer 令牌
+	TokenEnv    string `json:"token_env,omitempty"`    // 从环境变量读取令牌
+}
+
+// credentialStore 凭据文件格式
+type credentialStore struct {
+	Credentials map[string]MirrorCredential `json:"credentials"`
+}
+
+// netrcEntry netrc文件中的一条机器记录
+type netrcEntry struct {
+	machine   string
+	login     string
+	password  string
+	isDefault bool
+}
+
+// credentialScope 镜像地址前缀与凭据引用的对应关系
+type credentialScope struct {
+	prefix string
+	ref    string
+}
+
+// HTTPClientFactory 创建共享代理、TLS和认证配置的HTTP客户端
+// 下载服务和镜像服务使用同一个工厂，保证所有网络请求遵循相同的配置
+type HTTPClientFactory struct {
+	transport    *http.Transport
+	err          error // 配置加载失败时记录的错误，发起请求时返回
+	netrc        []netrcEntry
+	netrcDefault bool
+	credentials  map[string]MirrorCredential
+	scopes       []credentialScope
+	mu           sync.RWMutex
+}
+
+// DefaultHTTPConfigPath 获取默认的网络配置文件路径
+func DefaultHTTPConfigPath() string {
+	return filepath.Join(goVersionHomeDir(), "http.json")
+}
+
+// DefaultCredentialsPath 获取默认的镜像凭据文件路径
+func DefaultCredentialsPath() string {
+	return filepath.Join(goVersionHomeDir(), "credentials.json")
+}
+
+// goVersionHomeDir 获取版本管理器的配置目录
+func goVersionHomeDir() string {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(os.TempDir(), ".go-version")
+	}
+	return filepath.Join(homeDir, ".go-version")
+}
+
+// LoadHTTPConfig 从文件加载网络配置，文件不存在时返回空配置
+func LoadHTTPConfig(configPath string) (*HTTPConfig, error) {
+	config := &HTTPConfig{}
+	data, err := os.ReadFile(configPath)
+	if os.IsNotExist(err) {
+		return config, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取网络配置失败: %w", err)
+	}
+	if err := json.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("解析网络配置失败: %w", err)
+	}
+	return config, nil
+}
+
+// DefaultHTTPClientFactory 使用默认配置文件创建HTTP客户端工厂
+// 配置无效时仍返回可用的工厂，错误在发起请求时返回，避免静默忽略错误配置
+func DefaultHTTPClientFactory() *HTTPClientFactory {
+	config, err := LoadHTTPConfig(DefaultHTTPConfigPath())
+	if err == nil {
+		var factory *HTTPClientFactory
+		factory, err = NewHTTPClientFactory(config)
+		if err == nil {
+			return factory
+		}
+	}
+
+	return &HTTPClientFactory{
+		transport:   http.DefaultTransport.(*http.Transport).Clone(),
+		err:         err,
+		credentials: make(map[string]MirrorCredential),
+	}
+}
+
+// NewHTTPClientFactory 根据网络配置创建HTTP客户端工厂
+func NewHTTPClientFactory(config *HTTPConfig) (*HTTPClientFactory, error) {
+	if config == nil {
+		config = &HTTPConfig{}
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	proxy, err := config.proxyFunc()
+	if err != nil {
+		return nil, err
+	}
+	transport.Proxy = proxy
+
+	tlsConfig, err := config.tlsConfig()
+	if err != nil {
+		return nil, err
+	}
+	transport.TLSClientConfig = tlsConfig
+
+	netrc, err := loadNetrc(config.netrcPath())
+	if err != nil {
+		return nil, err
+	}
+
+	credentialsPath := config.CredentialsFile
+	if credentialsPath == "" {
+		credentialsPath = DefaultCredentialsPath()
+	}
+	credentials, err := loadCredentials(credentialsPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return &HTTPClientFactory{
+		transport:    transport,
+		netrc:        netrc,
+		netrcDefault: config.NetrcDefault,
+		credentials:  credentials,
+	}, nil
+}
+
+// Client 创建使用共享传输层的HTTP客户端
+func (f *HTTPClientFactory) Client(timeout time.Duration) *http.Client {
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: &authTransport{factory: f},
+	}
+}
+
+// RegisterMirror 登记镜像的凭据引用，访问该镜像地址（基础URL、URL模板和校验和URL模板）下的URL时自动添加认证信息
+func (f *HTTPClientFactory) RegisterMirror(mirror Mirror) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.addScope(mirror)
+}
+
+// SetMirrors 按镜像列表重新登记凭据引用，之前登记的镜像（如已移除或被替换的镜像）不再添加认证信息
+func (f *HTTPClientFactory) SetMirrors(mirrors []Mirror) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.scopes = nil
+	for _, mirror := range mirrors {
+		f.addScope(mirror)
+	}
+}
+
+// addScope 登记镜像地址前缀对应的凭据引用（调用方需持有锁）
+func (f *HTTPClientFactory) addScope(mirror Mirror) {
+	if mirror.CredentialRef == "" {
+		return
+	}
+
+	for _, prefix := range mirrorScopePrefixes(mirror) {
+		f.setScope(prefix, mirror.CredentialRef)
+	}
+
+	// 最长前缀优先匹配
+	sort.Slice(f.scopes, func(i, j int) bool {
+		return len(f.scopes[i].prefix) > len(f.scopes[j].prefix)
+	})
+}
+
+// setScope 设置地址前缀对应的凭据引用（调用方需持有锁）
+func (f *HTTPClientFactory) setScope(prefix, ref string) {
+	for i, scope := range f.scopes {
+		if scope.prefix == prefix {
+			f.scopes[i].ref = ref
+			return
+		}
+	}
+	f.scopes = append(f.scopes, credentialScope{prefix: prefix, ref: ref})
+}
+
+// mirrorScopePrefixes 获取镜像下载和校验和地址的固定前缀
+// 基础URL整体作为前缀；URL模板取第一个占位符之前、以 / 结尾的部分，占位符位于主机名中的模板不登记
+func mirrorScopePrefixes(mirror Mirror) []string {
+	var prefixes []string
+	if mirror.BaseURL != "" {
+		prefix := mirror.BaseURL
+		if !strings.HasSuffix(prefix, "/") {
+			prefix += "/"
+		}
+		prefixes = append(prefixes, prefix)
+	}
+	for _, template := range []string{mirror.URLTemplate, mirror.ChecksumURLTemplate} {
+		if prefix := urlTemplatePrefix(template); prefix != "" {
+			prefixes = append(prefixes, prefix)
+		}
+	}
+	return prefixes
+}
+
+// urlTemplatePrefix 获取URL模板中第一个占位符之前、以 / 结尾的固定部分，无法确定主机时返回空字符串
+func urlTemplatePrefix(template string) string {
+	fixed := template
+	if i := strings.Index(fixed, "{"); i >= 0 {
+		fixed = fixed[:i]
+	}
+	schemeEnd := strings.Index(fixed, "://")
+	if schemeEnd < 0 {
+		return ""
+	}
+	hostEnd := strings.Index(fixed[schemeEnd+3:], "/")
+	if hostEnd <= 0 {
+		return ""
+	}
+	return fixed[:strings.LastIndex(fixed, "/")+1]
+}
+
+// Authorize 为请求添加指定凭据引用的认证信息，引用为空时不做处理
+func (f *HTTPClientFactory) Authorize(req *http.Request, credentialRef string) error {
+	if credentialRef == "" {
+		return nil
+	}
+
+	f.mu.RLock()
+	credential, exists := f.credentials[credentialRef]
+	f.mu.RUnlock()
+	if !exists {
+		return fmt.Errorf("未找到镜像凭据 '%s'", credentialRef)
+	}
+	return credential.apply(req)
+}
+
+// authorizeByURL 根据请求地址匹配镜像凭据或netrc记录并添加认证信息
+// netrc 的 default 记录只在启用 NetrcDefault 时使用，且不发送给配置了镜像凭据的主机
+func (f *HTTPClientFactory) authorizeByURL(req *http.Request) error {
+	requestURL := req.URL.String()
+	host := req.URL.Hostname()
+
+	f.mu.RLock()
+	ref := ""
+	credentialHost := false
+	for _, scope := range f.scopes {
+		if ref == "" && strings.HasPrefix(requestURL, scope.prefix) {
+			ref = scope.ref
+		}
+		if u, err := url.Parse(scope.prefix); err == nil && strings.EqualFold(u.Hostname(), host) {
+			credentialHost = true
+		}
+	}
+	f.mu.RUnlock()
+
+	if ref != "" {
+		return f.Authorize(req, ref)
+	}
+
+	if entry := lookupNetrc(f.netrc, host, f.netrcDefault && !credentialHost); entry != nil {
+		req.SetBasicAuth(entry.login, entry.password)
+	}
+	return nil
+}
+
+// authTransport 在共享传输层上添加认证信息的 RoundTripper
+type authTransport struct {
+	factory *HTTPClientFactory
+}
+
+// RoundTrip 实现 http.RoundTripper 接口
+func (t *authTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.factory.err != nil {
+		return nil, fmt.Errorf("网络配置无效: %v", t.factory.err)
+	}
+
+	// 已显式设置认证信息时不覆盖；重定向时按新地址重新匹配凭据
+	if req.Header.Get("Authorization") == "" {
+		authorized := req.Clone(req.Context())
+		if err := t.factory.authorizeByURL(authorized); err != nil {
+			return nil, err
+		}
+		req = authorized
+	}
+
+	return t.factory.transport.RoundTrip(req)
+}
+
+// apply 将凭据添加到请求头
+func (c MirrorCredential) apply(req *http.Request) error {
+	switch strings.ToLower(c.Type) {
+	case "basic":
+		password := c.Password
+		if c.PasswordEnv != "" {
+			password = os.Getenv(c.PasswordEnv)
+		}
+		req.SetBasicAuth(c.Username, password)
+	case "bearer":
+		token := c.Token
+		if c.TokenEnv != "" {
+			token = os.Getenv(c.TokenEnv)
+		}
+		if token == "" {
+			return fmt.Errorf("bearer 凭据缺少令牌")
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	default:
+		return fmt.Errorf("不支持的凭据类型: %s", c.Type)
+	}
+	return nil
+}
+
+// proxyFunc 构建代理选择函数
+func (c *HTTPConfig) proxyFunc() (func(*http.Request) (*url.URL, error), error) {
+	if c.Proxy == "" {
+		return http.ProxyFromEnvironment, nil
+	}
+
+	proxyURL, err := url.Parse(c.Proxy)
+	if err != nil || proxyURL.Host == "" {
+		return nil, fmt.Errorf("无效的代理地址: %s", c.Proxy)
+	}
+
+	noProxy := c.NoProxy
+	if noProxy == "" {
+		noProxy = os.Getenv("NO_PROXY")
+	}
+	if noProxy == "" {
+		noProxy = os.Getenv("no_proxy")
+	}
+
+	return func(req *http.Request) (*url.URL, error) {
+		if matchNoProxy(noProxy, req.URL.Hostname()) {
+			return nil, nil
+		}
+		return proxyURL, nil
+	}, nil
+}
+
+// matchNoProxy 判断主机是否在不使用代理的列表中
+func matchNoProxy(noProxy, host string) bool {
+	host = strings.ToLower(host)
+	for _, pattern := range strings.Split(noProxy, ",") {
+		pattern = strings.ToLower(strings.TrimSpace(pattern))
+		if pattern == "" {
+			continue
+		}
+		if pattern == "*" {
+			return true
+		}
+		if h, _, err := net.SplitHostPort(pattern); err == nil {
+			pattern = h
+		}
+		pattern = strings.TrimPrefix(pattern, ".")
+		if host == pattern || strings.HasSuffix(host, "."+pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// tlsConfig 构建包含额外CA和客户端证书的TLS配置
+func (c *HTTPConfig) tlsConfig() (*tls.Config, error) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if len(c.CAFiles) > 0 {
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		for _, caFile := range c.CAFiles {
+			data, err := os.ReadFile(caFile)
+			if err != nil {
+				return nil, fmt.Errorf("读取CA证书失败: %w", err)
+			}
+			if !pool.AppendCertsFromPEM(data) {
+				return nil, fmt.Errorf("CA证书文件中没有有效的PEM证书: %s", caFile)
+			}
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if c.ClientCert != "" || c.ClientKey != "" {
+		if c.ClientCert == "" || c.ClientKey == "" {
+			return nil, fmt.Errorf("客户端证书和私钥必须同时配置")
+		}
+		cert, err := tls.LoadX509KeyPair(c.ClientCert, c.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("加载客户端证书失败: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
+
+// netrcPath 获取netrc文件路径
+func (c *HTTPConfig) netrcPath() string {
+	if c.NetrcFile != "" {
+		return c.NetrcFile
+	}
+	if path := os.Getenv("NETRC"); path != "" {
+		return path
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	if runtime.GOOS == "windows" {
+		return filepath.Join(homeDir, "_netrc")
+	}
+	return filepath.Join(homeDir, ".netrc")
+}
+
+// loadCredentials 加载镜像凭据文件，文件不存在时返回空集合
+func loadCredentials(path string) (map[string]MirrorCredential, error) {
+	store := credentialStore{Credentials: make(map[string]MirrorCredential)}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return store.Credentials, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取镜像凭据失败: %w", err)
+	}
+	if err := json.Unmarshal(data, &store); err != nil {
+		return nil, fmt.Errorf("解析镜像凭据失败: %w", err)
+	}
+	if store.Credentials == nil {
+		store.Credentials = make(map[string]MirrorCredential)
+	}
+	return store.Credentials, nil
+}
+
+// loadNetrc 加载netrc文件，文件不存在时返回空列表
+func loadNetrc(path string) ([]netrcEntry, error) {
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取netrc文件失败: %w", err)
+	}
+	return parseNetrc(string(data)), nil
+}
+
+// parseNetrc 解析netrc文件内容，忽略 macdef 宏定义
+func parseNetrc(data string) []netrcEntry {
+	// macdef 宏定义持续到下一个空行，先将其移除
+	var lines []string
+	inMacro := false
+	for _, line := range strings.Split(data, "\n") {
+		if inMacro {
+			if strings.TrimSpace(line) == "" {
+				inMacro = false
+			}
+			continue
+		}
+		if fields := strings.Fields(line); len(fields) > 0 && fields[0] == "macdef" {
+			inMacro = true
+			continue
+		}
+		lines = append(lines, line)
+	}
+
+	var entries []netrcEntry
+	current := -1
+	fields := strings.Fields(strings.Join(lines, "\n"))
+	for i := 0; i < len(fields); i++ {
+		switch fields[i] {
+		case "machine":
+			if i+1 < len(fields) {
+				entries = append(entries, netrcEntry{machine: fields[i+1]})
+				current = len(entries) - 1
+				i++
+			}
+		case "default":
+			entries = append(entries, netrcEntry{isDefault: true})
+			current = len(entries) - 1
+		case "login":
+			if current >= 0 && i+1 < len(fields) {
+				entries[current].login = fields[i+1]
+				i++
+			}
+		case "password":
+			if current >= 0 && i+1 < len(fields) {
+				entries[current].password = fields[i+1]
+				i++
+			}
+		case "account":
+			i++
+		}
+	}
+	return entries
+}
+
+// lookupNetrc 查找主机对应的netrc记录，没有精确匹配且 allowDefault 时使用 default 记录
+func lookupNetrc(entries []netrcEntry, host string, allowDefault bool) *netrcEntry {
+	var fallback *netrcEntry
+	for i := range entries {
+		if entries[i].isDefault {
+			if fallback == nil && allowDefault {
+				fallback = &entries[i]
+			}
+			continue
+		}
+		if strings.EqualFold(entries[i].machine, host) {
+			return &entries[i]
+		}
+	}
+	return fallback
+}
@@ -0,0 +1,350 @@
+package service
+
+import (
+	"context"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newAuthCheckServer 创建只接受指定 Authorization 头的测试服务器
+func newAuthCheckServer(t *testing.T, expected string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != expected {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprint(w, "ok")
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// getStatus 使用客户端发起GET请求并返回状态码
+func getStatus(t *testing.T, client *http.Client, url string) int {
+	t.Helper()
+	resp, err := client.Get(url)
+	if err != nil {
+		t.Fatalf("请求失败: %v", err)
+	}
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+func TestParseNetrc(t *testing.T) {
+	entries := parseNetrc(`machine example.com login alice password secret
+macdef init
+  cd /pub
+  machine ignored.com
+
+machine mirror.corp
+  login bob
+  account ignored
+  password hunter2
+default login anonymous password guest
+`)
+
+	if len(entries) != 3 {
+		t.Fatalf("期望3条记录，实际 %d: %+v", len(entries), entries)
+	}
+
+	entry := lookupNetrc(entries, "mirror.corp", false)
+	if entry == nil || entry.login != "bob" || entry.password != "hunter2" {
+		t.Errorf("mirror.corp 记录不正确: %+v", entry)
+	}
+	if entry := lookupNetrc(entries, "unknown.host", false); entry != nil {
+		t.Errorf("未启用 default 记录时不应匹配未知主机: %+v", entry)
+	}
+	entry = lookupNetrc(entries, "unknown.host", true)
+	if entry == nil || entry.login != "anonymous" {
+		t.Errorf("启用后未匹配的主机应使用 default 记录: %+v", entry)
+	}
+	if lookupNetrc(entries[:2], "unknown.host", true) != nil {
+		t.Error("没有 default 记录时不应匹配未知主机")
+	}
+}
+
+func TestMatchNoProxy(t *testing.T) {
+	tests := []struct {
+		noProxy string
+		host    string
+		want    bool
+	}{
+		{"corp.local", "corp.local", true},
+		{"corp.local", "mirror.corp.local", true},
+		{".corp.local", "mirror.corp.local", true},
+		{"corp.local", "notcorp.local", false},
+		{"localhost:8080, 10.0.0.1", "10.0.0.1", true},
+		{"*", "golang.org", true},
+		{"", "golang.org", false},
+	}
+
+	for _, tt := range tests {
+		if got := matchNoProxy(tt.noProxy, tt.host); got != tt.want {
+			t.Errorf("matchNoProxy(%q, %q) = %v, 期望 %v", tt.noProxy, tt.host, got, tt.want)
+		}
+	}
+}
+
+func TestHTTPClientFactory_MirrorCredentials(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("CORP_TOKEN", "s3cr3t")
+	credentialsPath := writeTestFile(t, dir, "credentials.json", `{
+  "credentials": {
+    "corp": {"type": "bearer", "token_env": "CORP_TOKEN"},
+    "basic": {"type": "basic", "username": "alice", "password": "pw"}
+  }
+}`)
+
+	factory, err := NewHTTPClientFactory(&HTTPConfig{
+		CredentialsFile: credentialsPath,
+		NetrcFile:       filepath.Join(dir, "missing-netrc"),
+	})
+	if err != nil {
+		t.Fatalf("创建HTTP客户端工厂失败: %v", err)
+	}
+
+	bearerServer := newAuthCheckServer(t, "Bearer s3cr3t")
+	client := factory.Client(5 * time.Second)
+
+	// 未登记镜像时不添加凭据
+	if status := getStatus(t, client, bearerServer.URL+"/golang/go.tar.gz"); status != http.StatusUnauthorized {
+		t.Errorf("未登记镜像时期望401，实际 %d", status)
+	}
+
+	factory.RegisterMirror(Mirror{Name: "corp", BaseURL: bearerServer.URL + "/golang", CredentialRef: "corp"})
+	if status := getStatus(t, client, bearerServer.URL+"/golang/go.tar.gz"); status != http.StatusOK {
+		t.Errorf("登记镜像后期望200，实际 %d", status)
+	}
+	if status := getStatus(t, client, bearerServer.URL+"/other/go.tar.gz"); status != http.StatusUnauthorized {
+		t.Errorf("镜像地址之外的URL不应添加凭据，实际 %d", status)
+	}
+
+	// 未知的凭据引用应返回错误
+	factory.RegisterMirror(Mirror{Name: "broken", BaseURL: bearerServer.URL + "/broken/", CredentialRef: "missing"})
+	if _, err := client.Get(bearerServer.URL + "/broken/go.tar.gz"); err == nil || !strings.Contains(err.Error(), "missing") {
+		t.Errorf("未知凭据引用应返回错误，实际: %v", err)
+	}
+
+	// 镜像测速同样使用凭据
+	basicServer := newAuthCheckServer(t, "Basic YWxpY2U6cHc=")
+	mirrorService, err := NewMirrorServiceWithHTTPClientFactory("", factory)
+	if err != nil {
+		t.Fatalf("创建镜像服务失败: %v", err)
+	}
+	result, err := mirrorService.TestMirrorSpeed(context.Background(), Mirror{Name: "basic-test", BaseURL: basicServer.URL, CredentialRef: "basic"})
+	if err != nil || !result.Available {
+		t.Errorf("带凭据的镜像测速应成功: %+v, %v", result, err)
+	}
+}
+
+func TestHTTPClientFactory_MirrorTemplateCredentials(t *testing.T) {
+	dir := t.TempDir()
+	credentialsPath := writeTestFile(t, dir, "credentials.json", `{"credentials": {"corp": {"type": "bearer", "token": "s3cr3t"}}}`)
+	factory, err := NewHTTPClientFactory(&HTTPConfig{
+		CredentialsFile: credentialsPath,
+		NetrcFile:       filepath.Join(dir, "missing-netrc"),
+	})
+	if err != nil {
+		t.Fatalf("创建HTTP客户端工厂失败: %v", err)
+	}
+
+	// 压缩包和校验和位于与基础URL不同的主机
+	base := newAuthCheckServer(t, "Bearer s3cr3t")
+	artifacts := newAuthCheckServer(t, "Bearer s3cr3t")
+	checksums := newAuthCheckServer(t, "Bearer s3cr3t")
+	factory.RegisterMirror(Mirror{
+		Name:                "corp",
+		BaseURL:             base.URL + "/golang/",
+		URLTemplate:         artifacts.URL + "/go/{version}/{filename}",
+		ChecksumURLTemplate: checksums.URL + "/sums/go{version}.{os}-{arch}.{ext}.sha256",
+		CredentialRef:       "corp",
+	})
+	client := factory.Client(5 * time.Second)
+
+	tests := []struct {
+		url      string
+		expected int
+	}{
+		{base.URL + "/golang/go1.22.7.linux-amd64.tar.gz", http.StatusOK},
+		{artifacts.URL + "/go/1.22.7/go1.22.7.linux-amd64.tar.gz", http.StatusOK},
+		{checksums.URL + "/sums/go1.22.7.linux-amd64.tar.gz.sha256", http.StatusOK},
+		{artifacts.URL + "/other/go.tar.gz", http.StatusUnauthorized},
+		{checksums.URL + "/go1.22.7.linux-amd64.tar.gz.sha256", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		if status := getStatus(t, client, tt.url); status != tt.expected {
+			t.Errorf("GET %s = %d, 期望 %d", tt.url, status, tt.expected)
+		}
+	}
+}
+
+func TestURLTemplatePrefix(t *testing.T) {
+	tests := map[string]string{
+		"https://repo.corp/go/{version}/{filename}": "https://repo.corp/go/",
+		"https://repo.corp/go/go{version}.{ext}":    "https://repo.corp/go/",
+		"https://repo.corp/{filename}.sha256":       "https://repo.corp/",
+		"https://{os}.repo.corp/go/{filename}":      "",
+		"https://repo.corp{filename}":               "",
+		"not a url/{filename}":                      "",
+	}
+	for template, expected := range tests {
+		if got := urlTemplatePrefix(template); got != expected {
+			t.Errorf("urlTemplatePrefix(%q) = %q, 期望 %q", template, got, expected)
+		}
+	}
+}
+
+func TestHTTPClientFactory_Netrc(t *testing.T) {
+	server := newAuthCheckServer(t, "Basic Ym9iOmh1bnRlcjI=")
+	host := strings.Split(strings.TrimPrefix(server.URL, "http://"), ":")[0]
+
+	dir := t.TempDir()
+	netrcPath := writeTestFile(t, dir, "netrc", fmt.Sprintf("machine %s login bob password hunter2\n", host))
+	factory, err := NewHTTPClientFactory(&HTTPConfig{
+		NetrcFile:       netrcPath,
+		CredentialsFile: filepath.Join(dir, "missing.json"),
+	})
+	if err != nil {
+		t.Fatalf("创建HTTP客户端工厂失败: %v", err)
+	}
+
+	if status := getStatus(t, factory.Client(5*time.Second), server.URL+"/go.tar.gz"); status != http.StatusOK {
+		t.Errorf("netrc 凭据应生效，实际状态码 %d", status)
+	}
+}
+
+func TestHTTPClientFactory_NetrcDefault(t *testing.T) {
+	server := newAuthCheckServer(t, "Basic YW5vbnltb3VzOmd1ZXN0")
+	dir := t.TempDir()
+	netrcPath := writeTestFile(t, dir, "netrc", "machine other.host login bob password hunter2\ndefault login anonymous password guest\n")
+	credentialsPath := writeTestFile(t, dir, "credentials.json", `{"credentials": {"corp": {"type": "bearer", "token": "t0k3n"}}}`)
+
+	newFactory := func(netrcDefault bool) *HTTPClientFactory {
+		factory, err := NewHTTPClientFactory(&HTTPConfig{
+			NetrcFile:       netrcPath,
+			NetrcDefault:    netrcDefault,
+			CredentialsFile: credentialsPath,
+		})
+		if err != nil {
+			t.Fatalf("创建HTTP客户端工厂失败: %v", err)
+		}
+		return factory
+	}
+
+	// 默认不把 default 记录发送给未知主机
+	if status := getStatus(t, newFactory(false).Client(5*time.Second), server.URL+"/go.tar.gz"); status != http.StatusUnauthorized {
+		t.Errorf("未启用 netrc_default 时不应发送 default 记录，实际状态码 %d", status)
+	}
+
+	factory := newFactory(true)
+	if status := getStatus(t, factory.Client(5*time.Second), server.URL+"/go.tar.gz"); status != http.StatusOK {
+		t.Errorf("启用 netrc_default 后应使用 default 记录，实际状态码 %d", status)
+	}
+
+	// 主机配置了镜像凭据时，镜像地址之外的URL也不使用 default 记录
+	factory.RegisterMirror(Mirror{Name: "corp", BaseURL: server.URL + "/golang/", CredentialRef: "corp"})
+	if status := getStatus(t, factory.Client(5*time.Second), server.URL+"/go.tar.gz"); status != http.StatusUnauthorized {
+		t.Errorf("配置了镜像凭据的主机不应使用 default 记录，实际状态码 %d", status)
+	}
+}
+
+func TestHTTPClientFactory_CustomCA(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "ok")
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	caPath := writeTestFile(t, dir, "ca.pem", string(caPEM))
+	missingCredentials := filepath.Join(dir, "missing.json")
+
+	plain, err := NewHTTPClientFactory(&HTTPConfig{CredentialsFile: missingCredentials})
+	if err != nil {
+		t.Fatalf("创建HTTP客户端工厂失败: %v", err)
+	}
+	if _, err := plain.Client(5 * time.Second).Get(server.URL); err == nil {
+		t.Error("未配置CA时应拒绝自签名证书")
+	}
+
+	withCA, err := NewHTTPClientFactory(&HTTPConfig{CAFiles: []string{caPath}, CredentialsFile: missingCredentials})
+	if err != nil {
+		t.Fatalf("创建HTTP客户端工厂失败: %v", err)
+	}
+	if status := getStatus(t, withCA.Client(5*time.Second), server.URL); status != http.StatusOK {
+		t.Errorf("配置CA后期望200，实际 %d", status)
+	}
+
+	invalidPath := writeTestFile(t, dir, "invalid.pem", "not a certificate")
+	if _, err := NewHTTPClientFactory(&HTTPConfig{CAFiles: []string{invalidPath}}); err == nil {
+		t.Error("无效的CA文件应返回错误")
+	}
+	if _, err := NewHTTPClientFactory(&HTTPConfig{ClientCert: caPath}); err == nil {
+		t.Error("只配置客户端证书而没有私钥时应返回错误")
+	}
+}
+
+func TestHTTPClientFactory_Proxy(t *testing.T) {
+	var proxied []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = append(proxied, r.URL.String())
+		fmt.Fprint(w, "proxied")
+	}))
+	defer proxy.Close()
+
+	dir := t.TempDir()
+	factory, err := NewHTTPClientFactory(&HTTPConfig{
+		Proxy:           proxy.URL,
+		NoProxy:         "direct.invalid",
+		CredentialsFile: filepath.Join(dir, "missing.json"),
+	})
+	if err != nil {
+		t.Fatalf("创建HTTP客户端工厂失败: %v", err)
+	}
+	client := factory.Client(5 * time.Second)
+
+	if status := getStatus(t, client, "http://golang.invalid/dl/go.tar.gz"); status != http.StatusOK {
+		t.Errorf("通过代理请求期望200，实际 %d", status)
+	}
+	if len(proxied) != 1 || proxied[0] != "http://golang.invalid/dl/go.tar.gz" {
+		t.Errorf("请求应经过代理: %v", proxied)
+	}
+
+	// NO_PROXY 中的主机直接连接，无法解析时应返回错误而不是经过代理
+	if _, err := client.Get("http://direct.invalid/"); err == nil {
+		t.Error("NO_PROXY 中的主机不应经过代理")
+	}
+	if len(proxied) != 1 {
+		t.Errorf("NO_PROXY 中的主机不应经过代理: %v", proxied)
+	}
+
+	if _, err := NewHTTPClientFactory(&HTTPConfig{Proxy: "://bad"}); err == nil {
+		t.Error("无效的代理地址应返回错误")
+	}
+}
+
+func TestDefaultHTTPClientFactory_InvalidConfig(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	if err := os.MkdirAll(filepath.Join(home, ".go-version"), 0755); err != nil {
+		t.Fatalf("创建配置目录失败: %v", err)
+	}
+	writeTestFile(t, filepath.Join(home, ".go-version"), "http.json", `{"ca_files": ["/nonexistent/ca.pem"]}`)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	_, err := DefaultHTTPClientFactory().Client(5 * time.Second).Get(server.URL)
+	if err == nil || !strings.Contains(err.Error(), "网络配置无效") {
+		t.Errorf("配置无效时请求应返回配置错误，实际: %v", err)
+	}
+}
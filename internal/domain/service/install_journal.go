@@ -0,0 +1,360 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"sync"
+	"syscall"
+	"time"
+	"version-list/internal/domain/model"
+)
+
+// InstallJournalDir 获取安装事务日志目录 ~/.go-version/journal
+func InstallJournalDir() string {
+	return filepath.Join(goVersionHomeDir(), "journal")
+}
+
+// installTransaction 安装事务：每个步骤执行前先写入日志并注册回滚操作，
+// 失败时通过回滚管理器按相反顺序撤销，进程异常退出时日志保留在磁盘上供下次运行时回滚或继续
+type installTransaction struct {
+	mu       sync.Mutex
+	service  *VersionService
+	journal  *model.InstallJournal
+	path     string
+	rollback *RollbackManager
+	keep     bool // 回滚时保留临时目录中已下载的文件
+}
+
+// beginInstallTransaction 开始安装事务并创建日志文件
+func (s *VersionService) beginInstallTransaction(source model.InstallSource, version string, options *model.InstallOptions) (*installTransaction, error) {
+	dir := InstallJournalDir()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("创建安装日志目录失败: %v", err)
+	}
+
+	journal := &model.InstallJournal{
+		ID:        fmt.Sprintf("%d-%d", time.Now().UnixNano(), os.Getpid()),
+		Version:   version,
+		Source:    source,
+		PID:       os.Getpid(),
+		StartedAt: time.Now(),
+		Status:    model.StatusPending,
+		Steps:     []model.JournalStep{},
+	}
+	if options != nil {
+		journalOptions := *options
+		journal.Options = &journalOptions
+		journal.Name = options.Name
+	}
+
+	tx := &installTransaction{
+		service:  s,
+		journal:  journal,
+		path:     filepath.Join(dir, journal.ID+".json"),
+		rollback: NewRollbackManager(),
+	}
+	if err := saveInstallJournal(tx.path, journal); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+// record 写入步骤并注册对应的回滚操作，必须在执行步骤之前调用；tx 为 nil 时不记录
+func (tx *installTransaction) record(step model.JournalStep) error {
+	if tx == nil {
+		return nil
+	}
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+
+	tx.journal.Steps = append(tx.journal.Steps, step)
+	tx.service.registerJournalRollback(tx.rollback, step)
+	return saveInstallJournal(tx.path, tx.journal)
+}
+
+// setStatus 更新日志中记录的安装阶段
+func (tx *installTransaction) setStatus(status model.InstallStatus) error {
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+
+	tx.journal.Status = status
+	return saveInstallJournal(tx.path, tx.journal)
+}
+
+// identify 记录压缩包安装中识别出的版本号和压缩包路径
+func (tx *installTransaction) identify(version, archivePath string) error {
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+
+	tx.journal.Version = version
+	tx.journal.Archive = archivePath
+	return saveInstallJournal(tx.path, tx.journal)
+}
+
+// commit 提交事务，删除日志后安装结果不再回滚
+func (tx *installTransaction) commit() error {
+	if err := os.Remove(tx.path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("删除安装日志失败: %v", err)
+	}
+	tx.rollback.Disable()
+	return nil
+}
+
+// keepDownload 回滚时保留临时目录、压缩包和安装状态，下次安装同一版本时继续下载
+func (tx *installTransaction) keepDownload() {
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+	tx.keep = true
+}
+
+// abort 回滚已执行的步骤，回滚成功后删除日志；回滚失败时保留日志，供 recover 命令重试
+func (tx *installTransaction) abort() error {
+	if !tx.rollback.IsEnabled() {
+		return nil
+	}
+	rollback := tx.rollback
+	if tx.keep {
+		rollback = NewRollbackManager()
+		for _, step := range tx.journal.Steps {
+			if !isDownloadStep(step) {
+				tx.service.registerJournalRollback(rollback, step)
+			}
+		}
+	}
+	if err := rollback.Execute(); err != nil {
+		return err
+	}
+	tx.rollback.Disable()
+	if err := os.Remove(tx.path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("删除安装日志失败: %v", err)
+	}
+	return nil
+}
+
+// registerJournalRollback 为日志步骤注册回滚操作，回滚操作允许步骤尚未执行或只执行了一部分
+func (s *VersionService) registerJournalRollback(rm *RollbackManager, step model.JournalStep) {
+	switch step.Type {
+	case model.JournalTempDir, model.JournalExtractDir, model.JournalVersionDir:
+		rm.RegisterDirectoryCreation(step.Path)
+	case model.JournalArchive:
+		rm.RegisterFileCreation(step.Path)
+	case model.JournalMove:
+		rm.Register(func() error {
+			for _, name := range step.Files {
+				if err := os.RemoveAll(filepath.Join(step.Path, name)); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	case model.JournalVersionRecord:
+		rm.Register(func() error {
+			s.repoMu.Lock()
+			defer s.repoMu.Unlock()
+			if _, err := s.versionRepo.FindByVersion(step.Version); err != nil {
+				return nil
+			}
+			return s.versionRepo.Remove(step.Version)
+		})
+	}
+}
+
+// recordDirectory 记录即将创建的目录，version_dir 只在目录不存在时记录，避免回滚时删除已有的目录
+func (tx *installTransaction) recordDirectory(stepType model.JournalStepType, path string) error {
+	if stepType == model.JournalVersionDir {
+		if _, err := os.Stat(path); err == nil {
+			return nil
+		}
+	}
+	return tx.record(model.JournalStep{Type: stepType, Path: path})
+}
+
+// recordMove 记录即将从解压目录移动到版本目录的顶层文件和目录
+func (tx *installTransaction) recordMove(extractDir, rootDir, destDir string) error {
+	if tx == nil {
+		return nil
+	}
+	srcDir := extractDir
+	if rootDir != "" {
+		srcDir = filepath.Join(extractDir, rootDir)
+	}
+	entries, err := os.ReadDir(srcDir)
+	if err != nil {
+		return fmt.Errorf("读取解压目录失败: %v", err)
+	}
+	files := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		files = append(files, entry.Name())
+	}
+	return tx.record(model.JournalStep{Type: model.JournalMove, Path: destDir, Files: files})
+}
+
+// saveInstallJournal 写入安装日志，先写临时文件再重命名，避免进程中断时留下不完整的日志
+func saveInstallJournal(path string, journal *model.InstallJournal) error {
+	data, err := json.MarshalIndent(journal, "", "  ")
+	if err != nil {
+		return fmt.Errorf("序列化安装日志失败: %v", err)
+	}
+	tempPath := path + ".tmp"
+	if err := os.WriteFile(tempPath, data, 0644); err != nil {
+		return fmt.Errorf("写入安装日志失败: %v", err)
+	}
+	if err := os.Rename(tempPath, path); err != nil {
+		os.Remove(tempPath)
+		return fmt.Errorf("写入安装日志失败: %v", err)
+	}
+	return nil
+}
+
+// loadInstallJournal 读取安装日志
+func loadInstallJournal(path string) (*model.InstallJournal, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取安装日志失败: %w", err)
+	}
+	var journal model.InstallJournal
+	if err := json.Unmarshal(data, &journal); err != nil {
+		return nil, fmt.Errorf("解析安装日志 %s 失败: %v", filepath.Base(path), err)
+	}
+	return &journal, nil
+}
+
+// PendingInstallJournals 获取中断的安装（执行安装的进程已退出但日志仍存在），按开始时间排列
+func (s *VersionService) PendingInstallJournals() ([]*model.InstallJournal, error) {
+	paths, err := filepath.Glob(filepath.Join(InstallJournalDir(), "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("读取安装日志目录失败: %v", err)
+	}
+
+	var journals []*model.InstallJournal
+	for _, path := range paths {
+		journal, err := loadInstallJournal(path)
+		if err != nil {
+			return nil, err
+		}
+		if processAlive(journal.PID) {
+			continue
+		}
+		journals = append(journals, journal)
+	}
+	sort.Slice(journals, func(i, j int) bool {
+		return journals[i].StartedAt.Before(journals[j].StartedAt)
+	})
+	return journals, nil
+}
+
+// RollbackInstallJournal 撤销中断的安装留下的所有文件和记录，并删除日志
+func (s *VersionService) RollbackInstallJournal(journal *model.InstallJournal) error {
+	return s.rollbackJournal(journal, func(model.JournalStep) bool { return true })
+}
+
+// ResumeInstallJournal 继续中断的安装：撤销未完成的步骤后重新安装，
+// 在线安装保留临时目录中已下载和解压的文件，由安装状态决定从哪个阶段继续
+func (s *VersionService) ResumeInstallJournal(ctx context.Context, journal *model.InstallJournal, progressUI ProgressReporter) (*model.InstallationResult, error) {
+	if !journal.Resumable() {
+		return nil, fmt.Errorf("无法继续 %s 的安装，请先回滚", journalDescription(journal))
+	}
+
+	if err := s.rollbackJournal(journal, keepOnlineDownload(journal)); err != nil {
+		return nil, err
+	}
+
+	options := journal.Options
+	if options == nil {
+		options = &model.InstallOptions{}
+	}
+	if journal.Source == model.SourceArchive {
+		return s.installFromArchive(ctx, journal.Archive, options, nil, progressUI)
+	}
+	return s.InstallOnlineWithContext(ctx, journal.Version, options, progressUI)
+}
+
+// rollbackJournal 按相反顺序撤销日志中满足 include 的步骤，全部成功后删除日志
+func (s *VersionService) rollbackJournal(journal *model.InstallJournal, include func(model.JournalStep) bool) error {
+	rm := NewRollbackManager()
+	for _, step := range journal.Steps {
+		if include(step) {
+			s.registerJournalRollback(rm, step)
+		}
+	}
+	if err := rm.Execute(); err != nil {
+		return fmt.Errorf("回滚 %s 失败: %v", journalDescription(journal), err)
+	}
+
+	path := filepath.Join(InstallJournalDir(), journal.ID+".json")
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("删除安装日志失败: %v", err)
+	}
+	return nil
+}
+
+// rollbackStaleJournals 回滚同一工具链之前中断的安装，避免残留的目录和记录影响本次安装；
+// 在线安装已下载的文件保留在临时目录中，由本次安装根据安装状态继续使用
+func (s *VersionService) rollbackStaleJournals(name string) error {
+	journals, err := s.PendingInstallJournals()
+	if err != nil {
+		return err
+	}
+	for _, journal := range journals {
+		if journal.ToolchainName() != name {
+			continue
+		}
+		if err := s.rollbackJournal(journal, keepOnlineDownload(journal)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isDownloadStep 检查步骤是否为临时目录中的下载或解压，这些文件由安装状态判断能否继续使用
+func isDownloadStep(step model.JournalStep) bool {
+	switch step.Type {
+	case model.JournalTempDir, model.JournalArchive, model.JournalExtractDir:
+		return true
+	default:
+		return false
+	}
+}
+
+// keepOnlineDownload 获取回滚中断的安装时要撤销的步骤：在线安装保留下载的文件，压缩包安装全部撤销
+func keepOnlineDownload(journal *model.InstallJournal) func(model.JournalStep) bool {
+	if journal.Source != model.SourceOnline {
+		return func(model.JournalStep) bool { return true }
+	}
+	return func(step model.JournalStep) bool { return !isDownloadStep(step) }
+}
+
+// journalDescription 获取中断的安装的说明
+func journalDescription(journal *model.InstallJournal) string {
+	if journal.Version == "" {
+		return "压缩包 " + journal.Archive
+	}
+	return "Go " + journal.ToolchainName()
+}
+
+// processAlive 检查进程是否仍在运行
+func processAlive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	if pid == os.Getpid() {
+		return true
+	}
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	if runtime.GOOS == "windows" {
+		// Windows 上 FindProcess 会打开进程句柄，进程不存在时返回错误
+		process.Release()
+		return true
+	}
+	err = process.Signal(syscall.Signal(0))
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
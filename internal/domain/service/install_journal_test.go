@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"version-list/internal/domain/model"
+)
+
+// simulateCrashedInstall 模拟执行到保存版本记录后进程异常退出的安装，返回临时目录和版本目录
+func simulateCrashedInstall(t *testing.T, service *VersionService, versionRepo *MockVersionRepository, version string) (string, string) {
+	t.Helper()
+
+	tx, err := service.beginInstallTransaction(model.SourceOnline, version, &model.InstallOptions{SkipVerification: true})
+	if err != nil {
+		t.Fatalf("开始安装事务失败: %v", err)
+	}
+	tempDir := InstallTempDir(version)
+	versionDir := filepath.Join(t.TempDir(), "versions", version)
+
+	tx.recordDirectory(model.JournalTempDir, tempDir)
+	os.MkdirAll(filepath.Join(tempDir, "extracted", "go"), 0755)
+	writeTestFile(t, tempDir, "archive.tar.gz", "partial")
+	tx.record(model.JournalStep{Type: model.JournalArchive, Path: filepath.Join(tempDir, "archive.tar.gz")})
+	tx.recordDirectory(model.JournalVersionDir, versionDir)
+	os.MkdirAll(versionDir, 0755)
+	writeTestFile(t, filepath.Join(tempDir, "extracted", "go"), "VERSION", "go"+version)
+	tx.recordMove(filepath.Join(tempDir, "extracted"), "go", versionDir)
+	writeTestFile(t, versionDir, "VERSION", "go"+version)
+	tx.record(model.JournalStep{Type: model.JournalVersionRecord, Version: version})
+	versionRepo.Save(&model.GoVersion{Version: version, Path: versionDir})
+
+	// 进程已退出
+	tx.journal.PID = 0
+	if err := saveInstallJournal(tx.path, tx.journal); err != nil {
+		t.Fatalf("写入安装日志失败: %v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(tempDir) })
+	return tempDir, versionDir
+}
+
+func TestVersionService_RollbackInstallJournal(t *testing.T) {
+	service, versionRepo := newTestVersionService(t, "http://127.0.0.1:0")
+	tempDir, versionDir := simulateCrashedInstall(t, service, versionRepo, "1.18.10")
+
+	journals, err := service.PendingInstallJournals()
+	if err != nil {
+		t.Fatalf("读取安装日志失败: %v", err)
+	}
+	if len(journals) != 1 || journals[0].Version != "1.18.10" || len(journals[0].Steps) != 5 {
+		t.Fatalf("中断的安装 = %+v, 期望1个包含5个步骤的日志", journals)
+	}
+
+	if err := service.RollbackInstallJournal(journals[0]); err != nil {
+		t.Fatalf("回滚失败: %v", err)
+	}
+	for _, path := range []string{tempDir, versionDir} {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("回滚后 %s 应被删除", path)
+		}
+	}
+	if _, err := versionRepo.FindByVersion("1.18.10"); err == nil {
+		t.Error("回滚后版本记录应被删除")
+	}
+	if journals, _ := service.PendingInstallJournals(); len(journals) != 0 {
+		t.Errorf("回滚后不应有中断的安装: %+v", journals)
+	}
+
+	// 重复回滚已撤销的步骤不报错
+	if err := service.RollbackInstallJournal(&model.InstallJournal{ID: "missing", Steps: []model.JournalStep{
+		{Type: model.JournalVersionRecord, Version: "1.18.10"},
+		{Type: model.JournalVersionDir, Path: versionDir},
+	}}); err != nil {
+		t.Errorf("步骤未执行时回滚不应失败: %v", err)
+	}
+}
+
+func TestVersionService_ResumeInstallJournal(t *testing.T) {
+	server := newFakeReleaseServer(t, []string{"1.18.10"})
+	defer server.Close()
+	service, versionRepo := newTestVersionService(t, server.URL)
+	simulateCrashedInstall(t, service, versionRepo, "1.18.10")
+
+	journals, err := service.PendingInstallJournals()
+	if err != nil || len(journals) != 1 {
+		t.Fatalf("中断的安装 = %+v, %v", journals, err)
+	}
+	result, err := service.ResumeInstallJournal(context.Background(), journals[0], nil)
+	if err != nil {
+		t.Fatalf("继续安装失败: %v", err)
+	}
+	if !result.Success {
+		t.Errorf("安装结果 = %+v", result)
+	}
+	installed, err := versionRepo.FindByVersion("1.18.10")
+	if err != nil || installed.Path != result.Path {
+		t.Errorf("版本记录 = %+v, %v, 期望指向 %s", installed, err, result.Path)
+	}
+	if journals, _ := service.PendingInstallJournals(); len(journals) != 0 {
+		t.Errorf("继续安装后不应有中断的安装: %+v", journals)
+	}
+}
+
+func TestVersionService_InstallRollsBackStaleJournal(t *testing.T) {
+	server := newFakeReleaseServer(t, []string{"1.18.10"})
+	defer server.Close()
+	service, versionRepo := newTestVersionService(t, server.URL)
+	_, staleDir := simulateCrashedInstall(t, service, versionRepo, "1.18.10")
+
+	// 中断的安装留下的版本记录不会导致“已安装”错误
+	if _, err := service.InstallOnlineWithContext(context.Background(), "1.18.10", &model.InstallOptions{SkipVerification: true}, nil); err != nil {
+		t.Fatalf("安装失败: %v", err)
+	}
+	if _, err := os.Stat(staleDir); !os.IsNotExist(err) {
+		t.Error("中断的安装留下的版本目录应被删除")
+	}
+	entries, _ := os.ReadDir(InstallJournalDir())
+	if len(entries) != 0 {
+		t.Errorf("安装完成后日志目录应为空, 实际 %d 个文件", len(entries))
+	}
+}
+
+func TestVersionService_FailedInstallRemovesJournal(t *testing.T) {
+	server := newFakeReleaseServer(t, nil)
+	defer server.Close()
+	service, _ := newTestVersionService(t, server.URL)
+
+	versionDir := filepath.Join(t.TempDir(), "go1.18.9")
+	if _, err := service.InstallOnlineWithContext(context.Background(), "1.18.9", &model.InstallOptions{CustomPath: versionDir, NoFailover: true}, nil); err == nil {
+		t.Fatal("版本不存在时应安装失败")
+	}
+	if _, err := os.Stat(versionDir); !os.IsNotExist(err) {
+		t.Error("失败的安装应回滚版本目录")
+	}
+	if _, err := os.Stat(InstallTempDir("1.18.9")); !os.IsNotExist(err) {
+		t.Error("失败的安装应回滚临时目录")
+	}
+	entries, _ := os.ReadDir(InstallJournalDir())
+	if len(entries) != 0 {
+		t.Errorf("回滚后日志目录应为空, 实际 %d 个文件", len(entries))
+	}
+}
@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"version-list/internal/domain/model"
+)
+
+// installStateFile 临时目录中安装状态文件的名称
+const installStateFile = "install-state.json"
+
+// loadInstallState 读取临时目录中的安装状态，文件不存在时返回 nil
+func loadInstallState(tempDir string) (*model.InstallState, error) {
+	data, err := os.ReadFile(filepath.Join(tempDir, installStateFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取安装状态失败: %v", err)
+	}
+	var state model.InstallState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("解析安装状态失败: %v", err)
+	}
+	return &state, nil
+}
+
+// saveInstallState 写入安装状态，先写临时文件再重命名，避免进程中断时留下不完整的状态
+func saveInstallState(tempDir string, state *model.InstallState) error {
+	state.UpdatedAt = time.Now()
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("序列化安装状态失败: %v", err)
+	}
+	path := filepath.Join(tempDir, installStateFile)
+	tempPath := path + ".tmp"
+	if err := os.WriteFile(tempPath, data, 0600); err != nil {
+		return fmt.Errorf("写入安装状态失败: %v", err)
+	}
+	if err := os.Rename(tempPath, path); err != nil {
+		os.Remove(tempPath)
+		return fmt.Errorf("写入安装状态失败: %v", err)
+	}
+	return nil
+}
+
+// InstallTempDir 获取在线安装的临时目录，位于 ~/.go-version/tmp 中，只有当前用户可以访问
+func InstallTempDir(name string) string {
+	return filepath.Join(goVersionHomeDir(), "tmp", "go-install-"+name)
+}
+
+// prepareInstallTempDir 准备临时目录：状态文件属于本次安装时保留之前下载和解压的结果，
+// 否则（没有状态文件、状态损坏或属于其他版本）清空临时目录重新开始
+func (s *VersionService) prepareInstallTempDir(context *model.InstallationContext) error {
+	state, err := loadInstallState(context.TempDir)
+	if err != nil || state == nil || !state.Matches(context) {
+		if err := os.RemoveAll(context.TempDir); err != nil {
+			return fmt.Errorf("清理临时目录失败: %v", err)
+		}
+		state = &model.InstallState{
+			Version:  context.Version,
+			Name:     context.Name,
+			OS:       context.SystemInfo.OS,
+			Arch:     context.SystemInfo.Arch,
+			Filename: context.SystemInfo.Filename,
+		}
+	}
+
+	if err := os.MkdirAll(context.TempDir, 0700); err != nil {
+		return fmt.Errorf("创建临时目录失败: %v", err)
+	}
+	// 目录可能由旧版本以较宽的权限创建
+	for _, dir := range []string{filepath.Dir(context.TempDir), context.TempDir} {
+		if err := os.Chmod(dir, 0700); err != nil {
+			return fmt.Errorf("设置临时目录权限失败: %v", err)
+		}
+	}
+	context.State = state
+	return saveInstallState(context.TempDir, state)
+}
+
+// updateInstallState 修改并保存安装状态，安装上下文没有状态时不记录
+func updateInstallState(context *model.InstallationContext, update func(state *model.InstallState)) error {
+	if context.State == nil {
+		return nil
+	}
+	update(context.State)
+	return saveInstallState(context.TempDir, context.State)
+}
+
+// completedDownload 检查之前的运行是否已完整下载压缩包，返回下载时使用的镜像
+func completedDownload(context *model.InstallationContext, candidates []model.MirrorCandidate) (model.MirrorCandidate, bool) {
+	state := context.State
+	if state == nil || state.Stage == model.StageNone || state.Size <= 0 {
+		return model.MirrorCandidate{}, false
+	}
+	info, err := os.Stat(context.Paths.ArchiveFile)
+	if err != nil || info.Size() != state.Size {
+		return model.MirrorCandidate{}, false
+	}
+	if _, err := os.Stat(segmentStatePath(context.Paths.ArchiveFile)); err == nil {
+		return model.MirrorCandidate{}, false
+	}
+	for _, candidate := range candidates {
+		if candidate.URL == state.URL {
+			return candidate, true
+		}
+	}
+	return model.MirrorCandidate{}, false
+}
+
+// probeArchive 获取镜像上压缩包的大小和校验值，下载服务不支持时只获取文件大小
+func (s *VersionService) probeArchive(ctx context.Context, url string) (*RemoteFileInfo, error) {
+	if prober, ok := s.downloadService.(RemoteFileProber); ok {
+		return prober.ProbeFile(ctx, url)
+	}
+	size, err := s.downloadService.GetFileSize(url)
+	if err != nil {
+		return nil, err
+	}
+	return &RemoteFileInfo{Size: size}, nil
+}
+
+// extractedTreeComplete 检查之前的运行是否已完整解压：状态为已解压，解压时的压缩包与本次校验的压缩包
+// SHA-256 一致，且解压目录中普通文件的总大小与压缩包一致
+func extractedTreeComplete(state *model.InstallState, archiveSHA256, extractDir string, archiveInfo *ArchiveInfo) bool {
+	if state == nil || state.Stage != model.StageExtracted {
+		return false
+	}
+	if state.SHA256 == "" || !strings.EqualFold(state.SHA256, archiveSHA256) {
+		return false
+	}
+	var total int64
+	err := filepath.WalkDir(extractDir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.Type().IsRegular() {
+			info, err := entry.Info()
+			if err != nil {
+				return err
+			}
+			total += info.Size()
+		}
+		return nil
+	})
+	return err == nil && total == archiveInfo.TotalSize
+}
@@ -0,0 +1,272 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	"version-list/internal/domain/model"
+)
+
+// resumableRequest 测试服务器收到的下载请求
+type resumableRequest struct {
+	Range   string
+	IfRange string
+}
+
+// newResumableReleaseServer 创建支持Range和If-Range并返回ETag的测试服务器，返回压缩包内容和收到的GET请求
+func newResumableReleaseServer(t *testing.T, version, etag string) (*httptest.Server, []byte, func() []resumableRequest) {
+	t.Helper()
+	payload := createFakeGoArchive(t, version)
+	filename := fmt.Sprintf("/go%s.%s-%s.tar.gz", version, runtime.GOOS, runtime.GOARCH)
+
+	var mu sync.Mutex
+	var requests []resumableRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != filename {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Method == http.MethodGet {
+			mu.Lock()
+			requests = append(requests, resumableRequest{Range: r.Header.Get("Range"), IfRange: r.Header.Get("If-Range")})
+			mu.Unlock()
+		}
+		w.Header().Set("ETag", etag)
+		http.ServeContent(w, r, filename, time.Time{}, bytes.NewReader(payload))
+	}))
+	t.Cleanup(server.Close)
+
+	return server, payload, func() []resumableRequest {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]resumableRequest(nil), requests...)
+	}
+}
+
+// writeInstallState 在安装临时目录中写入之前运行留下的文件和安装状态
+func writeInstallState(t *testing.T, version string, state *model.InstallState, archive []byte) string {
+	t.Helper()
+	tempDir := InstallTempDir(version)
+	if err := os.MkdirAll(tempDir, 0700); err != nil {
+		t.Fatalf("创建临时目录失败: %v", err)
+	}
+
+	filename := fmt.Sprintf("go%s.%s-%s.tar.gz", version, runtime.GOOS, runtime.GOARCH)
+	if archive != nil {
+		if err := os.WriteFile(filepath.Join(tempDir, filename), archive, 0644); err != nil {
+			t.Fatalf("写入压缩包失败: %v", err)
+		}
+	}
+	if state != nil {
+		state.Version = version
+		state.OS = runtime.GOOS
+		state.Arch = runtime.GOARCH
+		state.Filename = filename
+		if err := saveInstallState(tempDir, state); err != nil {
+			t.Fatalf("写入安装状态失败: %v", err)
+		}
+	}
+	return tempDir
+}
+
+func TestVersionService_InstallResumesPartialDownload(t *testing.T) {
+	server, payload, requests := newResumableReleaseServer(t, "1.21.3", `"v1"`)
+	service, _ := newTestVersionService(t, server.URL)
+	half := len(payload) / 2
+
+	tempDir := writeInstallState(t, "1.21.3", &model.InstallState{
+		URL:  server.URL + fmt.Sprintf("/go1.21.3.%s-%s.tar.gz", runtime.GOOS, runtime.GOARCH),
+		Size: int64(len(payload)),
+		ETag: `"v1"`,
+	}, payload[:half])
+
+	versionDir := filepath.Join(t.TempDir(), "go1.21.3")
+	result, err := service.InstallOnlineWithContext(context.Background(), "1.21.3", &model.InstallOptions{CustomPath: versionDir, SkipVerification: true}, nil)
+	if err != nil {
+		t.Fatalf("安装失败: %v", err)
+	}
+	if !result.Success {
+		t.Errorf("安装结果 = %+v", result)
+	}
+
+	got := requests()
+	want := resumableRequest{Range: fmt.Sprintf("bytes=%d-", half), IfRange: `"v1"`}
+	if len(got) != 1 || got[0] != want {
+		t.Errorf("下载请求 = %+v, 期望从第 %d 字节续传并校验ETag", got, half)
+	}
+	if _, err := os.Stat(tempDir); !os.IsNotExist(err) {
+		t.Error("安装完成后应删除临时目录")
+	}
+}
+
+func TestVersionService_InstallDiscardsStaleDownload(t *testing.T) {
+	tests := []struct {
+		name  string
+		state func(url string, size int64) *model.InstallState
+	}{
+		{
+			name: "ETag已变化",
+			state: func(url string, size int64) *model.InstallState {
+				return &model.InstallState{URL: url, Size: size, ETag: `"v0"`}
+			},
+		},
+		{
+			name:  "没有安装状态",
+			state: func(string, int64) *model.InstallState { return nil },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, payload, requests := newResumableReleaseServer(t, "1.21.4", `"v1"`)
+			service, _ := newTestVersionService(t, server.URL)
+			url := server.URL + fmt.Sprintf("/go1.21.4.%s-%s.tar.gz", runtime.GOOS, runtime.GOARCH)
+
+			// 部分文件的内容与服务器上的文件不同，续传会得到损坏的压缩包
+			stale := bytes.Repeat([]byte{0xff}, len(payload)/2)
+			writeInstallState(t, "1.21.4", tt.state(url, int64(len(payload))), stale)
+
+			versionDir := filepath.Join(t.TempDir(), "go1.21.4")
+			if _, err := service.InstallOnlineWithContext(context.Background(), "1.21.4", &model.InstallOptions{CustomPath: versionDir, SkipVerification: true}, nil); err != nil {
+				t.Fatalf("安装失败: %v", err)
+			}
+			if got := requests(); len(got) != 1 || got[0].Range != "" {
+				t.Errorf("下载请求 = %+v, 期望重新下载完整文件", got)
+			}
+		})
+	}
+}
+
+func TestVersionService_InstallSkipsCompletedStages(t *testing.T) {
+	tests := []struct {
+		name   string
+		sha256 func(payload []byte) string // 之前解压时记录的压缩包SHA-256
+		reuse  bool
+	}{
+		{"与本次压缩包一致", func(payload []byte) string { return fmt.Sprintf("%x", sha256.Sum256(payload)) }, true},
+		{"来自其他压缩包", func(payload []byte) string { return strings.Repeat("0", 64) }, false},
+		{"未记录", func(payload []byte) string { return "" }, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, payload, requests := newResumableReleaseServer(t, "1.21.5", `"v1"`)
+			service, _ := newTestVersionService(t, server.URL)
+
+			tempDir := writeInstallState(t, "1.21.5", &model.InstallState{
+				URL:    server.URL + fmt.Sprintf("/go1.21.5.%s-%s.tar.gz", runtime.GOOS, runtime.GOARCH),
+				Size:   int64(len(payload)),
+				ETag:   `"v1"`,
+				Stage:  model.StageExtracted,
+				SHA256: tt.sha256(payload),
+			}, payload)
+
+			// 之前已完整解压，用大小相同但内容不同的文件标记
+			extracted := filepath.Join(tempDir, "extracted", "go")
+			os.MkdirAll(filepath.Join(extracted, "bin"), 0755)
+			script := fmt.Sprintf("#!/bin/sh\necho \"go version go1.21.5 %s/%s\"\n", runtime.GOOS, runtime.GOARCH)
+			writeTestFile(t, filepath.Join(extracted, "bin"), "go", script)
+			os.Chmod(filepath.Join(extracted, "bin", "go"), 0755)
+			writeTestFile(t, extracted, "VERSION", "GO1.21.5\n")
+
+			versionDir := filepath.Join(t.TempDir(), "go1.21.5")
+			if _, err := service.InstallOnlineWithContext(context.Background(), "1.21.5", &model.InstallOptions{CustomPath: versionDir, SkipVerification: true}, nil); err != nil {
+				t.Fatalf("安装失败: %v", err)
+			}
+			if got := requests(); len(got) != 0 {
+				t.Errorf("压缩包已下载时不应再次下载: %+v", got)
+			}
+			data, _ := os.ReadFile(filepath.Join(versionDir, "VERSION"))
+			if reused := string(data) == "GO1.21.5\n"; reused != tt.reuse {
+				t.Errorf("VERSION = %q, 期望复用之前解压的文件: %v", data, tt.reuse)
+			}
+		})
+	}
+}
+
+func TestVersionService_InstallTempDirIsPrivate(t *testing.T) {
+	server, _, _ := newResumableReleaseServer(t, "1.21.7", `"v1"`)
+	service, _ := newTestVersionService(t, server.URL)
+
+	// 旧版本创建的临时目录权限较宽
+	tempDir := InstallTempDir("1.21.7")
+	if err := os.MkdirAll(tempDir, 0755); err != nil {
+		t.Fatalf("创建临时目录失败: %v", err)
+	}
+	os.Chmod(filepath.Dir(tempDir), 0755)
+
+	installContext := &model.InstallationContext{
+		Version:    "1.21.7",
+		SystemInfo: &model.SystemInfo{OS: runtime.GOOS, Arch: runtime.GOARCH, Filename: "go1.21.7.tar.gz"},
+		TempDir:    tempDir,
+	}
+	if err := service.prepareInstallTempDir(installContext); err != nil {
+		t.Fatalf("准备临时目录失败: %v", err)
+	}
+	if !strings.HasPrefix(tempDir, goVersionHomeDir()) {
+		t.Errorf("临时目录 %s 应位于 %s 中", tempDir, goVersionHomeDir())
+	}
+	for _, dir := range []string{filepath.Dir(tempDir), tempDir} {
+		if info, err := os.Stat(dir); err != nil || info.Mode().Perm() != 0700 {
+			t.Errorf("%s 的权限应为 0700", dir)
+		}
+	}
+	if info, err := os.Stat(filepath.Join(tempDir, installStateFile)); err != nil || info.Mode().Perm() != 0600 {
+		t.Error("安装状态文件的权限应为 0600")
+	}
+}
+
+func TestVersionService_FailedDownloadKeepsPartialArchive(t *testing.T) {
+	payload := createFakeGoArchive(t, "1.21.6")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Content-Length", fmt.Sprintf("%d", len(payload)))
+		w.WriteHeader(http.StatusOK)
+		if r.Method == http.MethodHead {
+			return
+		}
+		// 发送一半数据后断开连接
+		w.Write(payload[:len(payload)/2])
+		w.(http.Flusher).Flush()
+		conn, _, _ := w.(http.Hijacker).Hijack()
+		conn.Close()
+	}))
+	defer server.Close()
+	service, _ := newTestVersionService(t, server.URL)
+
+	tempDir := InstallTempDir("1.21.6")
+
+	versionDir := filepath.Join(t.TempDir(), "go1.21.6")
+	if _, err := service.InstallOnlineWithContext(context.Background(), "1.21.6", &model.InstallOptions{CustomPath: versionDir, NoFailover: true}, nil); err == nil {
+		t.Fatal("连接断开时应安装失败")
+	}
+
+	filename := fmt.Sprintf("go1.21.6.%s-%s.tar.gz", runtime.GOOS, runtime.GOARCH)
+	info, err := os.Stat(filepath.Join(tempDir, filename))
+	if err != nil || info.Size() != int64(len(payload)/2) {
+		t.Fatalf("应保留已下载的部分文件: %v", err)
+	}
+	state, err := loadInstallState(tempDir)
+	if err != nil || state == nil {
+		t.Fatalf("应保留安装状态: %v", err)
+	}
+	if state.ETag != `"v1"` || state.Size != int64(len(payload)) || state.Stage != model.StageNone {
+		t.Errorf("安装状态 = %+v", state)
+	}
+	if _, err := os.Stat(versionDir); !os.IsNotExist(err) {
+		t.Error("失败的安装应回滚版本目录")
+	}
+	entries, _ := os.ReadDir(InstallJournalDir())
+	if len(entries) != 0 {
+		t.Errorf("回滚后日志目录应为空, 实际 %d 个文件", len(entries))
+	}
+}
@@ -0,0 +1,189 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// 镜像审计问题类型
+const (
+	AuditIssueMissing          = "missing"           // 镜像缺少该文件
+	AuditIssueSizeMismatch     = "size_mismatch"     // 文件大小与官方不一致
+	AuditIssueChecksumMismatch = "checksum_mismatch" // SHA-256与官方不一致
+	AuditIssueError            = "error"             // 请求失败，无法判断
+)
+
+// MirrorAuditOptions 镜像审计选项
+type MirrorAuditOptions struct {
+	Releases       []GoRelease // 需要检查的版本，通常为最新的若干个稳定版本
+	Platforms      []string    // 需要检查的平台，格式为 os/arch
+	VerifyChecksum bool        // 完整下载文件并校验SHA-256，否则只通过Range请求检查文件大小
+}
+
+// MirrorAuditIssue 审计发现的问题
+type MirrorAuditIssue struct {
+	Version  string // 版本号
+	Platform string // 平台，格式为 os/arch
+	Filename string // 文件名
+	Type     string // 问题类型
+	Detail   string // 详细说明
+}
+
+// MirrorAuditReport 单个镜像的审计报告
+type MirrorAuditReport struct {
+	Mirror  Mirror
+	Checked int // 检查的文件数
+	Issues  []MirrorAuditIssue
+}
+
+// Stale 镜像是否缺少最新版本的文件
+func (r *MirrorAuditReport) Stale() bool {
+	return r.hasIssue(AuditIssueMissing)
+}
+
+// Inconsistent 镜像是否存在与官方不一致的文件
+func (r *MirrorAuditReport) Inconsistent() bool {
+	return r.hasIssue(AuditIssueSizeMismatch) || r.hasIssue(AuditIssueChecksumMismatch)
+}
+
+// hasIssue 是否存在指定类型的问题
+func (r *MirrorAuditReport) hasIssue(issueType string) bool {
+	for _, issue := range r.Issues {
+		if issue.Type == issueType {
+			return true
+		}
+	}
+	return false
+}
+
+// AuditMirror 对照发布目录检查镜像是否提供指定版本和平台的压缩包，以及文件大小和校验和是否一致
+func (s *mirrorServiceImpl) AuditMirror(ctx context.Context, mirror Mirror, options *MirrorAuditOptions) *MirrorAuditReport {
+	report := &MirrorAuditReport{Mirror: mirror}
+
+	for _, release := range options.Releases {
+		for _, platform := range options.Platforms {
+			goos, goarch, err := ParsePlatform(platform)
+			if err != nil {
+				report.Issues = append(report.Issues, MirrorAuditIssue{Version: release.VersionNumber(), Platform: platform, Type: AuditIssueError, Detail: err.Error()})
+				continue
+			}
+			file, exists := release.FindArchive(goos, goarch)
+			if !exists {
+				// 该版本没有发布此平台的压缩包
+				continue
+			}
+
+			report.Checked++
+			issue := s.auditFile(ctx, mirror, release, goos, goarch, file, options.VerifyChecksum)
+			if issue != nil {
+				issue.Version = release.VersionNumber()
+				issue.Platform = platform
+				issue.Filename = file.Filename
+				report.Issues = append(report.Issues, *issue)
+			}
+		}
+	}
+
+	return report
+}
+
+// auditFile 检查镜像上的单个文件，没有问题时返回nil
+func (s *mirrorServiceImpl) auditFile(ctx context.Context, mirror Mirror, release GoRelease, goos, goarch string, file *GoReleaseFile, verifyChecksum bool) *MirrorAuditIssue {
+	url := mirror.DownloadURL(file.Filename)
+	if mirror.URLTemplate != "" {
+		url = ExpandURLTemplate(mirror.URLTemplate, release.VersionNumber(), goos, goarch)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return &MirrorAuditIssue{Type: AuditIssueError, Detail: err.Error()}
+	}
+	if err := s.clientFactory.Authorize(req, mirror.CredentialRef); err != nil {
+		return &MirrorAuditIssue{Type: AuditIssueError, Detail: err.Error()}
+	}
+
+	// 不校验内容时只请求第一个字节，从响应头中获取文件总大小
+	client := s.clientFactory.Client(0)
+	if !verifyChecksum {
+		req.Header.Set("Range", "bytes=0-0")
+		client = s.httpClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return &MirrorAuditIssue{Type: AuditIssueError, Detail: err.Error()}
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		return &MirrorAuditIssue{Type: AuditIssueMissing, Detail: "镜像上不存在该文件"}
+	case resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusPartialContent:
+		return &MirrorAuditIssue{Type: AuditIssueError, Detail: newHTTPStatusError(resp).Error()}
+	}
+
+	if !verifyChecksum {
+		size := responseTotalSize(resp)
+		if size >= 0 && size != file.Size {
+			return &MirrorAuditIssue{Type: AuditIssueSizeMismatch, Detail: fmt.Sprintf("文件大小 %d，官方为 %d", size, file.Size)}
+		}
+		return nil
+	}
+
+	hasher := sha256.New()
+	size, err := io.Copy(hasher, resp.Body)
+	if err != nil {
+		return &MirrorAuditIssue{Type: AuditIssueError, Detail: fmt.Sprintf("下载文件失败: %v", err)}
+	}
+	if size != file.Size {
+		return &MirrorAuditIssue{Type: AuditIssueSizeMismatch, Detail: fmt.Sprintf("文件大小 %d，官方为 %d", size, file.Size)}
+	}
+	if checksum := hex.EncodeToString(hasher.Sum(nil)); !strings.EqualFold(checksum, file.SHA256) {
+		return &MirrorAuditIssue{Type: AuditIssueChecksumMismatch, Detail: fmt.Sprintf("SHA-256为 %s，官方为 %s", checksum, file.SHA256)}
+	}
+	return nil
+}
+
+// responseTotalSize 从响应中获取文件总大小，Range响应使用 Content-Range 中的总长度，未知时返回-1
+func responseTotalSize(resp *http.Response) int64 {
+	if resp.StatusCode == http.StatusPartialContent {
+		// 格式: bytes 0-0/1000
+		contentRange := resp.Header.Get("Content-Range")
+		slash := strings.LastIndex(contentRange, "/")
+		if slash < 0 {
+			return -1
+		}
+		size, err := strconv.ParseInt(contentRange[slash+1:], 10, 64)
+		if err != nil {
+			return -1
+		}
+		return size
+	}
+	return resp.ContentLength
+}
+
+// DemoteMirror 将镜像的优先级降到所有其他镜像之后，返回调整后的优先级
+func (s *mirrorServiceImpl) DemoteMirror(name string) (int, error) {
+	mirror, err := s.GetMirrorByName(name)
+	if err != nil {
+		return 0, err
+	}
+
+	lowest := mirror.Priority
+	for _, other := range s.GetAvailableMirrors() {
+		if other.Name != name && other.Priority >= lowest {
+			lowest = other.Priority + 1
+		}
+	}
+
+	if lowest != mirror.Priority {
+		s.config.SetPriorityOverride(name, lowest)
+	}
+	return lowest, nil
+}
@@ -0,0 +1,210 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// auditTestFiles 审计测试使用的模拟发布文件内容
+var auditTestFiles = map[string][]byte{
+	"go1.22.7.linux-amd64.tar.gz":  bytes.Repeat([]byte("7"), 4096),
+	"go1.22.6.linux-amd64.tar.gz":  bytes.Repeat([]byte("6"), 2048),
+	"go1.21.13.linux-amd64.tar.gz": bytes.Repeat([]byte("3"), 1024),
+}
+
+// newAuditCatalogServer 创建提供发布目录的测试服务器
+func newAuditCatalogServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	release := func(version string, stable bool) GoRelease {
+		filename := version + ".linux-amd64.tar.gz"
+		data := auditTestFiles[filename]
+		sum := sha256.Sum256(data)
+		return GoRelease{Version: version, Stable: stable, Files: []GoReleaseFile{
+			{Filename: filename, OS: "linux", Arch: "amd64", Version: version, SHA256: hex.EncodeToString(sum[:]), Size: int64(len(data)), Kind: "archive"},
+			{Filename: version + ".src.tar.gz", Version: version, Kind: "source"},
+		}}
+	}
+	catalog := []GoRelease{
+		release("go1.23rc1", false),
+		release("go1.21.13", true),
+		release("go1.22.7", true),
+		release("go1.22.6", true),
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(catalog)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// newAuditMirrorServer 创建模拟镜像，files 中不存在的文件返回404
+func newAuditMirrorServer(t *testing.T, files map[string][]byte) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, exists := files[path.Base(r.URL.Path)]
+		if !exists {
+			http.NotFound(w, r)
+			return
+		}
+		http.ServeContent(w, r, path.Base(r.URL.Path), time.Time{}, bytes.NewReader(data))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestLatestStableReleases(t *testing.T) {
+	catalogServer := newAuditCatalogServer(t)
+	catalog, err := FetchReleaseCatalog(context.Background(), http.DefaultClient, catalogServer.URL)
+	if err != nil {
+		t.Fatalf("获取发布目录失败: %v", err)
+	}
+
+	releases := LatestStableReleases(catalog, 2)
+	if len(releases) != 2 || releases[0].VersionNumber() != "1.22.7" || releases[1].VersionNumber() != "1.22.6" {
+		t.Errorf("最新稳定版本不正确: %+v", releases)
+	}
+	if _, exists := releases[0].FindArchive("linux", "amd64"); !exists {
+		t.Error("应找到 linux/amd64 的压缩包")
+	}
+	if _, exists := releases[0].FindArchive("darwin", "arm64"); exists {
+		t.Error("不应找到未发布平台的压缩包")
+	}
+}
+
+func TestMirrorService_AuditMirror(t *testing.T) {
+	catalogServer := newAuditCatalogServer(t)
+	catalog, err := FetchReleaseCatalog(context.Background(), http.DefaultClient, catalogServer.URL)
+	if err != nil {
+		t.Fatalf("获取发布目录失败: %v", err)
+	}
+
+	good := newAuditMirrorServer(t, auditTestFiles)
+	stale := newAuditMirrorServer(t, map[string][]byte{
+		"go1.22.6.linux-amd64.tar.gz": auditTestFiles["go1.22.6.linux-amd64.tar.gz"],
+	})
+	// 大小一致但内容被篡改的镜像
+	tampered := newAuditMirrorServer(t, map[string][]byte{
+		"go1.22.7.linux-amd64.tar.gz": bytes.Repeat([]byte("x"), 4096),
+		"go1.22.6.linux-amd64.tar.gz": auditTestFiles["go1.22.6.linux-amd64.tar.gz"],
+	})
+	truncated := newAuditMirrorServer(t, map[string][]byte{
+		"go1.22.7.linux-amd64.tar.gz": auditTestFiles["go1.22.7.linux-amd64.tar.gz"][:1000],
+		"go1.22.6.linux-amd64.tar.gz": auditTestFiles["go1.22.6.linux-amd64.tar.gz"],
+	})
+
+	configPath := filepath.Join(t.TempDir(), "mirrors.json")
+	mirrorService, err := NewMirrorServiceWithHTTPClientFactory(configPath, DefaultHTTPClientFactory())
+	if err != nil {
+		t.Fatalf("创建镜像服务失败: %v", err)
+	}
+
+	options := &MirrorAuditOptions{
+		Releases:  LatestStableReleases(catalog, 2),
+		Platforms: []string{"linux/amd64", "darwin/arm64"},
+	}
+	ctx := context.Background()
+
+	report := mirrorService.AuditMirror(ctx, Mirror{Name: "good", BaseURL: good.URL}, options)
+	if report.Checked != 2 || len(report.Issues) != 0 {
+		t.Errorf("正常镜像的审计结果不正确: %+v", report)
+	}
+
+	report = mirrorService.AuditMirror(ctx, Mirror{Name: "stale", BaseURL: stale.URL}, options)
+	if !report.Stale() || report.Inconsistent() || len(report.Issues) != 1 || report.Issues[0].Version != "1.22.7" {
+		t.Errorf("落后镜像的审计结果不正确: %+v", report)
+	}
+
+	report = mirrorService.AuditMirror(ctx, Mirror{Name: "truncated", BaseURL: truncated.URL}, options)
+	if !report.Inconsistent() || report.Issues[0].Type != AuditIssueSizeMismatch {
+		t.Errorf("文件被截断的镜像应报告大小不一致: %+v", report)
+	}
+
+	// 只检查大小时无法发现内容被篡改，校验SHA-256时可以发现
+	report = mirrorService.AuditMirror(ctx, Mirror{Name: "tampered", BaseURL: tampered.URL}, options)
+	if len(report.Issues) != 0 {
+		t.Errorf("只检查大小时不应报告问题: %+v", report)
+	}
+	options.VerifyChecksum = true
+	report = mirrorService.AuditMirror(ctx, Mirror{Name: "tampered", BaseURL: tampered.URL}, options)
+	if !report.Inconsistent() || report.Issues[0].Type != AuditIssueChecksumMismatch {
+		t.Errorf("内容被篡改的镜像应报告校验和不一致: %+v", report)
+	}
+}
+
+func TestMirrorService_AuditMirrorURLTemplateArtifactArch(t *testing.T) {
+	data := bytes.Repeat([]byte("a"), 512)
+	release := GoRelease{Version: "go1.22.7", Stable: true, Files: []GoReleaseFile{
+		{Filename: "go1.22.7.linux-armv6l.tar.gz", OS: "linux", Arch: "armv6l", Version: "go1.22.7", Size: int64(len(data)), Kind: "archive"},
+	}}
+
+	// 镜像按 /{os}/{arch}/ 组织文件，目录名使用发布文件中的架构名称
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/linux/armv6l/go1.22.7.linux-armv6l.tar.gz" {
+			http.NotFound(w, r)
+			return
+		}
+		http.ServeContent(w, r, path.Base(r.URL.Path), time.Time{}, bytes.NewReader(data))
+	}))
+	t.Cleanup(server.Close)
+
+	mirrorService, err := NewMirrorServiceWithHTTPClientFactory(filepath.Join(t.TempDir(), "mirrors.json"), DefaultHTTPClientFactory())
+	if err != nil {
+		t.Fatalf("创建镜像服务失败: %v", err)
+	}
+	mirror := Mirror{Name: "layout", BaseURL: server.URL, URLTemplate: server.URL + "/{os}/{arch}/{filename}"}
+	report := mirrorService.AuditMirror(context.Background(), mirror, &MirrorAuditOptions{
+		Releases:  []GoRelease{release},
+		Platforms: []string{"linux/arm"},
+	})
+	if report.Checked != 1 || len(report.Issues) != 0 {
+		t.Errorf("URL模板中的 {arch} 应使用 armv6l: %+v", report)
+	}
+}
+
+func TestMirrorService_DemoteMirror(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "mirrors.json")
+	mirrorService, err := NewMirrorServiceWithHTTPClientFactory(configPath, DefaultHTTPClientFactory())
+	if err != nil {
+		t.Fatalf("创建镜像服务失败: %v", err)
+	}
+
+	priority, err := mirrorService.DemoteMirror("goproxy-cn")
+	if err != nil {
+		t.Fatalf("调整优先级失败: %v", err)
+	}
+	mirrors := mirrorService.GetAvailableMirrors()
+	if last := mirrors[len(mirrors)-1]; last.Name != "goproxy-cn" || last.Priority != priority {
+		t.Errorf("降级后的镜像应排在最后: %+v", mirrors)
+	}
+
+	// 已经排在最后时保持不变
+	if again, _ := mirrorService.DemoteMirror("goproxy-cn"); again != priority {
+		t.Errorf("重复降级时优先级不应变化: %d -> %d", priority, again)
+	}
+
+	if err := mirrorService.SaveConfig(""); err != nil {
+		t.Fatalf("保存镜像配置失败: %v", err)
+	}
+	reloaded, err := NewMirrorServiceWithConfig(configPath)
+	if err != nil {
+		t.Fatalf("重新加载镜像配置失败: %v", err)
+	}
+	if mirror, _ := reloaded.GetMirrorByName("goproxy-cn"); mirror.Priority != priority {
+		t.Errorf("优先级覆盖应持久化，实际 %d", mirror.Priority)
+	}
+
+	if _, err := mirrorService.DemoteMirror("missing"); err == nil {
+		t.Error("未知镜像应返回错误")
+	}
+}
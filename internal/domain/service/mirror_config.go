@@ -13,8 +13,17 @@ import (
 type MirrorConfig struct {
 	CustomMirrors []Mirror                   `json:"custom_mirrors"`
 	TestResults   map[string]MirrorTestCache `json:"test_results"`
-	LastUpdated   time.Time                  `json:"last_updated"`
-	mu            sync.RWMutex
+	Health        map[string]*MirrorHealth   `json:"health,omitempty"`
+	// Overrides 按镜像名称覆盖镜像（包括预设镜像）的优先级和启用状态
+	Overrides   map[string]MirrorOverride `json:"overrides,omitempty"`
+	LastUpdated time.Time                 `json:"last_updated"`
+	mu          sync.RWMutex
+}
+
+// MirrorOverride 镜像设置覆盖项，未设置的字段保持镜像原有的值
+type MirrorOverride struct {
+	Priority *int  `json:"priority,omitempty"`
+	Enabled  *bool `json:"enabled,omitempty"`
 }
 
 // MirrorTestCache 镜像测试结果缓存
@@ -30,6 +39,7 @@ func NewMirrorConfig() *MirrorConfig {
 	return &MirrorConfig{
 		CustomMirrors: make([]Mirror, 0),
 		TestResults:   make(map[string]MirrorTestCache),
+		Health:        make(map[string]*MirrorHealth),
 		LastUpdated:   time.Now(),
 	}
 }
@@ -86,6 +96,12 @@ func (c *MirrorConfig) AddCustomMirror(mirror Mirror) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	// 添加时指定的优先级取代之前的覆盖项
+	if override, exists := c.Overrides[mirror.Name]; exists {
+		override.Priority = nil
+		c.setOverride(mirror.Name, override)
+	}
+
 	// 检查是否已存在
 	for i, existing := range c.CustomMirrors {
 		if existing.Name == mirror.Name {
@@ -112,6 +128,56 @@ func (c *MirrorConfig) RemoveCustomMirror(name string) bool {
 	return false
 }
 
+// SetPriorityOverride 覆盖指定镜像的优先级
+func (c *MirrorConfig) SetPriorityOverride(mirrorName string, priority int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	override := c.Overrides[mirrorName]
+	override.Priority = &priority
+	c.setOverride(mirrorName, override)
+}
+
+// SetEnabledOverride 覆盖指定镜像的启用状态
+func (c *MirrorConfig) SetEnabledOverride(mirrorName string, enabled bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	override := c.Overrides[mirrorName]
+	override.Enabled = &enabled
+	c.setOverride(mirrorName, override)
+}
+
+// setOverride 保存覆盖项，所有字段都未设置时删除该项（调用方需持有锁）
+func (c *MirrorConfig) setOverride(mirrorName string, override MirrorOverride) {
+	if override.Priority == nil && override.Enabled == nil {
+		delete(c.Overrides, mirrorName)
+		return
+	}
+	if c.Overrides == nil {
+		c.Overrides = make(map[string]MirrorOverride)
+	}
+	c.Overrides[mirrorName] = override
+}
+
+// applyOverrides 将配置中的覆盖项应用到镜像
+func (c *MirrorConfig) applyOverrides(mirror Mirror) Mirror {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	override, exists := c.Overrides[mirror.Name]
+	if !exists {
+		return mirror
+	}
+	if override.Priority != nil {
+		mirror.Priority = *override.Priority
+	}
+	if override.Enabled != nil {
+		mirror.Disabled = !*override.Enabled
+	}
+	return mirror
+}
+
 // GetCustomMirrors 获取自定义镜像列表
 func (c *MirrorConfig) GetCustomMirrors() []Mirror {
 	c.mu.RLock()
@@ -158,6 +224,36 @@ func (c *MirrorConfig) GetCachedResult(mirrorName string, maxAge time.Duration)
 	return &cache, true
 }
 
+// RecordHealthSample 记录镜像的测速结果，返回更新后的历史记录副本
+func (c *MirrorConfig) RecordHealthSample(mirrorName string, sample MirrorHealthSample) *MirrorHealth {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.Health == nil {
+		c.Health = make(map[string]*MirrorHealth)
+	}
+	health, exists := c.Health[mirrorName]
+	if !exists {
+		health = &MirrorHealth{}
+		c.Health[mirrorName] = health
+	}
+	health.addSample(sample)
+
+	return &MirrorHealth{Samples: append([]MirrorHealthSample(nil), health.Samples...)}
+}
+
+// GetHealth 获取镜像历史测速记录的副本
+func (c *MirrorConfig) GetHealth(mirrorName string) (*MirrorHealth, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	health, exists := c.Health[mirrorName]
+	if !exists || len(health.Samples) == 0 {
+		return nil, false
+	}
+	return &MirrorHealth{Samples: append([]MirrorHealthSample(nil), health.Samples...)}, true
+}
+
 // ClearExpiredCache 清理过期缓存
 func (c *MirrorConfig) ClearExpiredCache(maxAge time.Duration) {
 	c.mu.Lock()
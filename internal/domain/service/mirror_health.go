@@ -0,0 +1,218 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"runtime"
+	"time"
+)
+
+const (
+	// mirrorBenchmarkVersion 测速时下载的压缩包版本，所有镜像都应提供该版本
+	mirrorBenchmarkVersion = "1.21.13"
+	// mirrorBenchmarkBytes 测速时下载的字节数
+	mirrorBenchmarkBytes = 2 * 1024 * 1024
+	// mirrorHealthHistorySize 每个镜像保留的测速记录数
+	mirrorHealthHistorySize = 10
+	// DefaultBenchmarkCacheAge 选择镜像时直接使用的测速结果有效期
+	DefaultBenchmarkCacheAge = 5 * time.Minute
+
+	// 评分参考值：吞吐量达到 1MB/s、延迟为 200ms 时对应分项得分为一半
+	scoreReferenceThroughput = 1024 * 1024
+	scoreReferenceLatency    = 200 * time.Millisecond
+)
+
+// MirrorHealthSample 单次镜像测速记录
+type MirrorHealthSample struct {
+	TestedAt   time.Time     `json:"tested_at"`
+	Success    bool          `json:"success"`
+	Latency    time.Duration `json:"latency"`              // 首字节延迟
+	Throughput float64       `json:"throughput,omitempty"` // 吞吐量（字节/秒）
+	Error      string        `json:"error,omitempty"`
+}
+
+// MirrorHealth 镜像的历史测速记录，按时间顺序保存最近的若干次结果
+type MirrorHealth struct {
+	Samples []MirrorHealthSample `json:"samples"`
+}
+
+// addSample 追加测速记录，超出保留数量时丢弃最早的记录
+func (h *MirrorHealth) addSample(sample MirrorHealthSample) {
+	h.Samples = append(h.Samples, sample)
+	if len(h.Samples) > mirrorHealthHistorySize {
+		h.Samples = h.Samples[len(h.Samples)-mirrorHealthHistorySize:]
+	}
+}
+
+// Latest 获取最近一次测速记录
+func (h *MirrorHealth) Latest() (MirrorHealthSample, bool) {
+	if h == nil || len(h.Samples) == 0 {
+		return MirrorHealthSample{}, false
+	}
+	return h.Samples[len(h.Samples)-1], true
+}
+
+// SuccessRate 测速成功的比例
+func (h *MirrorHealth) SuccessRate() float64 {
+	if h == nil || len(h.Samples) == 0 {
+		return 0
+	}
+	successes := 0
+	for _, sample := range h.Samples {
+		if sample.Success {
+			successes++
+		}
+	}
+	return float64(successes) / float64(len(h.Samples))
+}
+
+// AverageLatency 成功测速的平均延迟
+func (h *MirrorHealth) AverageLatency() time.Duration {
+	var total time.Duration
+	count := 0
+	for _, sample := range h.successfulSamples() {
+		total += sample.Latency
+		count++
+	}
+	if count == 0 {
+		return 0
+	}
+	return total / time.Duration(count)
+}
+
+// AverageThroughput 成功测速的平均吞吐量（字节/秒）
+func (h *MirrorHealth) AverageThroughput() float64 {
+	var total float64
+	count := 0
+	for _, sample := range h.successfulSamples() {
+		total += sample.Throughput
+		count++
+	}
+	if count == 0 {
+		return 0
+	}
+	return total / float64(count)
+}
+
+// Score 根据历史成功率、吞吐量和延迟计算镜像评分（0-100）
+// 吞吐量占70%，延迟占30%，再乘以成功率，偶发失败的镜像评分随之降低
+func (h *MirrorHealth) Score() float64 {
+	successRate := h.SuccessRate()
+	if successRate == 0 {
+		return 0
+	}
+
+	throughput := h.AverageThroughput()
+	throughputScore := throughput / (throughput + scoreReferenceThroughput)
+	latencyScore := 1 / (1 + float64(h.AverageLatency())/float64(scoreReferenceLatency))
+
+	score := 100 * successRate * (0.7*throughputScore + 0.3*latencyScore)
+	return math.Round(score*10) / 10
+}
+
+// successfulSamples 获取成功的测速记录
+func (h *MirrorHealth) successfulSamples() []MirrorHealthSample {
+	if h == nil {
+		return nil
+	}
+	samples := make([]MirrorHealthSample, 0, len(h.Samples))
+	for _, sample := range h.Samples {
+		if sample.Success {
+			samples = append(samples, sample)
+		}
+	}
+	return samples
+}
+
+// benchmarkURL 获取镜像上用于测速的压缩包地址
+func benchmarkURL(mirror Mirror) string {
+	if mirror.URLTemplate != "" {
+		return ExpandURLTemplate(mirror.URLTemplate, mirrorBenchmarkVersion, runtime.GOOS, runtime.GOARCH)
+	}
+	filename := (&SystemDetectorImpl{}).GetExpectedFilename(mirrorBenchmarkVersion, runtime.GOOS, runtime.GOARCH)
+	return mirror.DownloadURL(filename)
+}
+
+// BenchmarkMirror 下载镜像上压缩包的前一段内容测量延迟和吞吐量，并记录到镜像的历史测速中
+// 最近一次测速在 maxAge 内时直接使用该结果，maxAge 为0时总是重新测速
+func (s *mirrorServiceImpl) BenchmarkMirror(ctx context.Context, mirror Mirror, maxAge time.Duration) (*MirrorTestResult, error) {
+	if maxAge > 0 {
+		if health, exists := s.config.GetHealth(mirror.Name); exists {
+			if latest, ok := health.Latest(); ok && time.Since(latest.TestedAt) <= maxAge {
+				return newBenchmarkResult(mirror, latest, health), nil
+			}
+		}
+	}
+
+	sample := s.measureThroughput(ctx, mirror)
+	health := s.config.RecordHealthSample(mirror.Name, sample)
+	return newBenchmarkResult(mirror, sample, health), nil
+}
+
+// GetMirrorHealth 获取镜像的历史测速记录
+func (s *mirrorServiceImpl) GetMirrorHealth(name string) (*MirrorHealth, bool) {
+	return s.config.GetHealth(name)
+}
+
+// measureThroughput 使用Range请求下载压缩包的前 benchmarkBytes 字节
+func (s *mirrorServiceImpl) measureThroughput(ctx context.Context, mirror Mirror) MirrorHealthSample {
+	sample := MirrorHealthSample{TestedAt: time.Now()}
+	fail := func(err error) MirrorHealthSample {
+		sample.Error = err.Error()
+		return sample
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, benchmarkURL(mirror), nil)
+	if err != nil {
+		return fail(fmt.Errorf("创建测速请求失败: %w", err))
+	}
+	req.Header.Set("Range", fmt.Sprintf("bytes=0-%d", s.benchmarkBytes-1))
+	if err := s.clientFactory.Authorize(req, mirror.CredentialRef); err != nil {
+		return fail(err)
+	}
+
+	start := time.Now()
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fail(fmt.Errorf("测速请求失败: %w", err))
+	}
+	defer resp.Body.Close()
+	sample.Latency = time.Since(start)
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusPartialContent {
+		return fail(fmt.Errorf("测速请求响应错误: %w", newHTTPStatusError(resp)))
+	}
+
+	// 服务器忽略Range头时只读取指定长度
+	transferStart := time.Now()
+	read, err := io.Copy(io.Discard, io.LimitReader(resp.Body, s.benchmarkBytes))
+	if err != nil {
+		return fail(fmt.Errorf("读取测速数据失败: %w", err))
+	}
+	if elapsed := time.Since(transferStart).Seconds(); elapsed > 0 {
+		sample.Throughput = float64(read) / elapsed
+	}
+
+	sample.Success = true
+	return sample
+}
+
+// newBenchmarkResult 根据测速记录创建测试结果
+func newBenchmarkResult(mirror Mirror, sample MirrorHealthSample, health *MirrorHealth) *MirrorTestResult {
+	result := &MirrorTestResult{
+		Mirror:       mirror,
+		ResponseTime: sample.Latency,
+		Available:    sample.Success,
+		Throughput:   sample.Throughput,
+		Score:        health.Score(),
+		ErrorMessage: sample.Error,
+	}
+	if sample.Error != "" {
+		result.Error = errors.New(sample.Error)
+	}
+	return result
+}
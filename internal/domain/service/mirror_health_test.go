@@ -0,0 +1,154 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMirrorHealth_Score(t *testing.T) {
+	health := &MirrorHealth{}
+	if health.Score() != 0 {
+		t.Errorf("没有测速记录时评分应为0，实际 %.1f", health.Score())
+	}
+
+	for i := 0; i < 4; i++ {
+		health.addSample(MirrorHealthSample{Success: true, Latency: 200 * time.Millisecond, Throughput: scoreReferenceThroughput})
+	}
+	// 吞吐量和延迟都等于参考值时，各分项得分为一半
+	if score := health.Score(); score != 50 {
+		t.Errorf("评分 = %.1f, 期望 50", score)
+	}
+
+	// 失败记录按成功率降低评分，但不影响平均吞吐量和延迟
+	health.addSample(MirrorHealthSample{Success: false, Error: "timeout"})
+	if score := health.Score(); score != 40 {
+		t.Errorf("评分 = %.1f, 期望 40", score)
+	}
+	if health.AverageLatency() != 200*time.Millisecond {
+		t.Errorf("平均延迟 = %v, 期望 200ms", health.AverageLatency())
+	}
+
+	// 只保留最近的记录
+	for i := 0; i < mirrorHealthHistorySize; i++ {
+		health.addSample(MirrorHealthSample{Success: true, Throughput: 10 * scoreReferenceThroughput})
+	}
+	if len(health.Samples) != mirrorHealthHistorySize {
+		t.Errorf("期望保留 %d 条记录，实际 %d", mirrorHealthHistorySize, len(health.Samples))
+	}
+	if health.SuccessRate() != 1 {
+		t.Errorf("旧的失败记录应被丢弃，成功率 = %.2f", health.SuccessRate())
+	}
+}
+
+func TestMirrorService_BenchmarkMirror(t *testing.T) {
+	payload := bytes.Repeat([]byte("x"), 256*1024)
+
+	var mu sync.Mutex
+	var ranges []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		ranges = append(ranges, r.Header.Get("Range"))
+		mu.Unlock()
+		http.ServeContent(w, r, "go.tar.gz", time.Time{}, bytes.NewReader(payload))
+	}))
+	defer server.Close()
+	requestedRanges := func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), ranges...)
+	}
+
+	configPath := filepath.Join(t.TempDir(), "mirrors.json")
+	mirrorService, err := NewMirrorServiceWithHTTPClientFactory(configPath, DefaultHTTPClientFactory())
+	if err != nil {
+		t.Fatalf("创建镜像服务失败: %v", err)
+	}
+	mirrorService.(*mirrorServiceImpl).benchmarkBytes = 64 * 1024
+	mirror := Mirror{Name: "bench", BaseURL: server.URL + "/golang/"}
+
+	result, err := mirrorService.BenchmarkMirror(context.Background(), mirror, DefaultBenchmarkCacheAge)
+	if err != nil || !result.Available {
+		t.Fatalf("测速应成功: %+v, %v", result, err)
+	}
+	if result.Throughput <= 0 || result.Score <= 0 {
+		t.Errorf("测速结果应包含吞吐量和评分: %+v", result)
+	}
+	if got := requestedRanges(); len(got) != 1 || got[0] != "bytes=0-65535" {
+		t.Errorf("期望只请求前64KB，实际Range请求: %q", got)
+	}
+
+	// 有效期内直接使用上次结果
+	if _, err := mirrorService.BenchmarkMirror(context.Background(), mirror, DefaultBenchmarkCacheAge); err != nil {
+		t.Fatalf("测速失败: %v", err)
+	}
+	if got := requestedRanges(); len(got) != 1 {
+		t.Errorf("有效期内不应重新测速，请求次数 %d", len(got))
+	}
+
+	// 失败的测速同样记录在历史中，错误信息可以序列化
+	broken := Mirror{Name: "broken", BaseURL: "http://127.0.0.1:1/"}
+	result, _ = mirrorService.BenchmarkMirror(context.Background(), broken, 0)
+	if result.Available || result.Error == nil || result.ErrorMessage == "" {
+		t.Errorf("不可用镜像的测速结果不正确: %+v", result)
+	}
+
+	if err := mirrorService.SaveConfig(""); err != nil {
+		t.Fatalf("保存镜像配置失败: %v", err)
+	}
+	reloaded, err := NewMirrorServiceWithConfig(configPath)
+	if err != nil {
+		t.Fatalf("重新加载镜像配置失败: %v", err)
+	}
+	health, exists := reloaded.GetMirrorHealth("bench")
+	if !exists || len(health.Samples) != 1 || health.Score() <= 0 {
+		t.Errorf("测速记录应持久化: %+v", health)
+	}
+	if health, exists := reloaded.GetMirrorHealth("broken"); !exists || health.Samples[0].Error == "" {
+		t.Errorf("失败记录应持久化错误信息: %+v", health)
+	}
+}
+
+func TestMirrorService_SelectFastestMirror_PrefersThroughput(t *testing.T) {
+	// 响应头返回很快但传输缓慢的镜像
+	trickle := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusPartialContent)
+		chunk := bytes.Repeat([]byte("x"), 16*1024)
+		for i := 0; i < 4; i++ {
+			w.Write(chunk)
+			w.(http.Flusher).Flush()
+			time.Sleep(30 * time.Millisecond)
+		}
+	}))
+	defer trickle.Close()
+
+	// 首字节稍慢但传输很快的镜像
+	bulk := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(40 * time.Millisecond)
+		w.WriteHeader(http.StatusPartialContent)
+		w.Write(bytes.Repeat([]byte("x"), 64*1024))
+	}))
+	defer bulk.Close()
+
+	mirrorService, err := NewMirrorServiceWithHTTPClientFactory("", DefaultHTTPClientFactory())
+	if err != nil {
+		t.Fatalf("创建镜像服务失败: %v", err)
+	}
+	mirrorService.(*mirrorServiceImpl).benchmarkBytes = 64 * 1024
+
+	fastest, err := mirrorService.SelectFastestMirror(context.Background(), []Mirror{
+		{Name: "trickle", BaseURL: trickle.URL, Priority: 1},
+		{Name: "bulk", BaseURL: bulk.URL, Priority: 2},
+	})
+	if err != nil {
+		t.Fatalf("选择镜像失败: %v", err)
+	}
+	if fastest.Name != "bulk" {
+		t.Errorf("应选择吞吐量更高的镜像，实际 %s", fastest.Name)
+	}
+}
@@ -2,9 +2,12 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"path/filepath"
 	"sort"
+	"strings"
 	"sync"
 	"time"
 )
@@ -12,12 +15,22 @@ import (
 // MirrorService 镜像管理服务接口
 type MirrorService interface {
 	GetAvailableMirrors() []Mirror
+	ListMirrors() []Mirror
+	RankMirrors(mirrors []Mirror) []Mirror
 	TestMirrorSpeed(ctx context.Context, mirror Mirror) (*MirrorTestResult, error)
+	BenchmarkMirror(ctx context.Context, mirror Mirror, maxAge time.Duration) (*MirrorTestResult, error)
+	GetMirrorHealth(name string) (*MirrorHealth, bool)
+	AuditMirror(ctx context.Context, mirror Mirror, options *MirrorAuditOptions) *MirrorAuditReport
+	DemoteMirror(name string) (int, error)
 	SelectFastestMirror(ctx context.Context, mirrors []Mirror) (*Mirror, error)
 	ValidateMirror(ctx context.Context, mirror Mirror) error
 	GetMirrorByName(name string) (*Mirror, error)
 	AddCustomMirror(mirror Mirror) error
 	RemoveCustomMirror(name string) error
+	SetMirrorEnabled(name string, enabled bool) error
+	SetMirrorPriority(name string, priority int) error
+	ExportMirrors() ([]byte, error)
+	ImportMirrors(ctx context.Context, source string, replace, keepCredentials bool) (*MirrorImportResult, error)
 	SaveConfig(configPath string) error
 }
 
@@ -28,6 +41,29 @@ type Mirror struct {
 	Description string `json:"description"`
 	Region      string `json:"region"`
 	Priority    int    `json:"priority"`
+	// CredentialRef 引用凭据文件中的凭据名称，凭据本身不保存在镜像配置中
+	CredentialRef string `json:"credential_ref,omitempty"`
+	// URLTemplate 非标准目录结构的下载地址模板，如 https://mirror/{version}/{os}/{arch}/go.{ext}
+	URLTemplate string `json:"url_template,omitempty"`
+	// ChecksumURLTemplate SHA-256校验和文件的地址模板，安装时用于校验下载的压缩包
+	ChecksumURLTemplate string `json:"checksum_url_template,omitempty"`
+
+	// Source 镜像来源（预设、组织配置或用户配置），不保存到配置文件
+	Source string `json:"-"`
+	// Disabled 镜像是否已被禁用，由配置中的覆盖项决定
+	Disabled bool `json:"-"`
+}
+
+// 镜像来源
+const (
+	MirrorSourceBuiltin = "builtin" // 预设镜像
+	MirrorSourceOrg     = "org"     // 组织镜像配置文件
+	MirrorSourceUser    = "user"    // 用户镜像配置文件
+)
+
+// DefaultMirrorConfigPath 获取默认的镜像配置文件路径
+func DefaultMirrorConfigPath() string {
+	return filepath.Join(goVersionHomeDir(), "mirrors.json")
 }
 
 // MirrorTestResult 镜像测试结果
@@ -35,69 +71,187 @@ type MirrorTestResult struct {
 	Mirror       Mirror        `json:"mirror"`
 	ResponseTime time.Duration `json:"response_time"`
 	Available    bool          `json:"available"`
-	Error        error         `json:"error,omitempty"`
+	Throughput   float64       `json:"throughput,omitempty"` // 测速吞吐量（字节/秒）
+	Score        float64       `json:"score,omitempty"`      // 根据历史测速计算的评分（0-100）
+	Error        error         `json:"-"`
+	ErrorMessage string        `json:"error,omitempty"` // 错误信息，用于序列化
 }
 
 // mirrorServiceImpl 镜像服务实现
 type mirrorServiceImpl struct {
-	httpClient *http.Client
-	mirrors    []Mirror
-	config     *MirrorConfig
-	configPath string
-	mu         sync.RWMutex
+	httpClient    *http.Client
+	clientFactory *HTTPClientFactory
+	mirrors       []Mirror
+	config        *MirrorConfig
+	configPath    string
+	// orgConfig 只读的组织镜像配置，位于用户配置之下
+	orgConfig *MirrorConfig
+	// benchmarkBytes 吞吐量测速时下载的字节数
+	benchmarkBytes int64
+	mu             sync.RWMutex
 }
 
 // NewMirrorService 创建镜像服务实例
 func NewMirrorService() MirrorService {
-	config := NewMirrorConfig()
-
-	return &mirrorServiceImpl{
-		httpClient: &http.Client{
-			Timeout: 10 * time.Second,
-		},
-		mirrors: getDefaultMirrors(),
-		config:  config,
-	}
+	service, _ := NewMirrorServiceWithHTTPClientFactory("", DefaultHTTPClientFactory())
+	return service
 }
 
 // NewMirrorServiceWithConfig 使用指定配置创建镜像服务实例
 func NewMirrorServiceWithConfig(configPath string) (MirrorService, error) {
+	return NewMirrorServiceWithHTTPClientFactory(configPath, DefaultHTTPClientFactory())
+}
+
+// NewMirrorServiceWithHTTPClientFactory 使用指定配置和HTTP客户端工厂创建镜像服务实例
+// configPath 为空时不加载自定义镜像配置，否则同时加载组织镜像配置作为底层
+func NewMirrorServiceWithHTTPClientFactory(configPath string, factory *HTTPClientFactory) (MirrorService, error) {
 	config := NewMirrorConfig()
-	if err := config.LoadFromFile(configPath); err != nil {
-		return nil, fmt.Errorf("加载镜像配置失败: %w", err)
+	orgConfig := NewMirrorConfig()
+	if configPath != "" {
+		if err := config.LoadFromFile(configPath); err != nil {
+			return nil, fmt.Errorf("加载镜像配置失败: %w", err)
+		}
+		if orgPath := DefaultOrgMirrorConfigPath(); orgPath != "" {
+			if err := orgConfig.LoadFromFile(orgPath); err != nil {
+				return nil, fmt.Errorf("加载组织镜像配置失败（%s）: %w", orgPath, err)
+			}
+		}
 	}
 
-	return &mirrorServiceImpl{
-		httpClient: &http.Client{
-			Timeout: 10 * time.Second,
-		},
-		mirrors:    getDefaultMirrors(),
-		config:     config,
-		configPath: configPath,
-	}, nil
+	service := &mirrorServiceImpl{
+		httpClient:     factory.Client(10 * time.Second),
+		clientFactory:  factory,
+		mirrors:        getDefaultMirrors(),
+		config:         config,
+		configPath:     configPath,
+		orgConfig:      orgConfig,
+		benchmarkBytes: mirrorBenchmarkBytes,
+	}
+
+	// 登记镜像凭据，使下载请求也能使用对应的认证信息
+	for _, mirror := range service.customMirrors() {
+		factory.RegisterMirror(mirror)
+	}
+
+	return service, nil
 }
 
-// GetAvailableMirrors 获取可用镜像列表
+// GetAvailableMirrors 获取可用镜像列表，不包括已禁用的镜像
 func (s *mirrorServiceImpl) GetAvailableMirrors() []Mirror {
+	var enabled []Mirror
+	for _, mirror := range s.ListMirrors() {
+		if !mirror.Disabled {
+			enabled = append(enabled, mirror)
+		}
+	}
+	return enabled
+}
+
+// ListMirrors 获取所有镜像（包括已禁用的镜像），按优先级排序
+func (s *mirrorServiceImpl) ListMirrors() []Mirror {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
 	// 合并默认镜像和自定义镜像
-	allMirrors := make([]Mirror, len(s.mirrors))
-	copy(allMirrors, s.mirrors)
+	allMirrors := make([]Mirror, 0, len(s.mirrors))
+	for _, mirror := range s.mirrors {
+		mirror.Source = MirrorSourceBuiltin
+		allMirrors = append(allMirrors, mirror)
+	}
+	allMirrors = append(allMirrors, s.customMirrors()...)
 
-	// 添加自定义镜像
-	customMirrors := s.config.GetCustomMirrors()
-	allMirrors = append(allMirrors, customMirrors...)
+	// 依次应用组织配置和用户配置中的覆盖项
+	for i := range allMirrors {
+		allMirrors[i] = s.config.applyOverrides(s.orgConfig.applyOverrides(allMirrors[i]))
+	}
 
 	// 按优先级排序
-	sort.Slice(allMirrors, func(i, j int) bool {
+	sort.SliceStable(allMirrors, func(i, j int) bool {
 		return allMirrors[i].Priority < allMirrors[j].Priority
 	})
 
 	return allMirrors
 }
 
+// customMirrors 获取组织配置和用户配置中的自定义镜像，同名时用户配置优先
+func (s *mirrorServiceImpl) customMirrors() []Mirror {
+	var mirrors []Mirror
+	userMirrors := s.config.GetCustomMirrors()
+	userNames := make(map[string]bool, len(userMirrors))
+	for _, mirror := range userMirrors {
+		userNames[mirror.Name] = true
+	}
+
+	for _, mirror := range s.orgConfig.GetCustomMirrors() {
+		if !userNames[mirror.Name] {
+			mirror.Source = MirrorSourceOrg
+			mirrors = append(mirrors, mirror)
+		}
+	}
+	for _, mirror := range userMirrors {
+		mirror.Source = MirrorSourceUser
+		mirrors = append(mirrors, mirror)
+	}
+	return mirrors
+}
+
+// mirrorRankCacheAge 镜像排序时参考的测速结果有效期
+const mirrorRankCacheAge = time.Hour
+
+// RankMirrors 按下载时的尝试顺序排列镜像
+// 最近测速不可用的镜像排在最后，其余按优先级排序，优先级相同时评分高、响应快的镜像在前
+func (s *mirrorServiceImpl) RankMirrors(mirrors []Mirror) []Mirror {
+	ranked := make([]Mirror, len(mirrors))
+	copy(ranked, mirrors)
+
+	type rankInfo struct {
+		unavailable  bool
+		scored       bool
+		score        float64
+		tested       bool
+		responseTime time.Duration
+	}
+	infos := make(map[string]rankInfo, len(ranked))
+	for _, mirror := range ranked {
+		var info rankInfo
+		if cached, exists := s.config.GetCachedResult(mirror.Name, mirrorRankCacheAge); exists {
+			info.unavailable = !cached.Available
+			info.tested = true
+			info.responseTime = cached.ResponseTime
+		}
+		if health, exists := s.config.GetHealth(mirror.Name); exists {
+			if latest, _ := health.Latest(); time.Since(latest.TestedAt) <= mirrorRankCacheAge && !latest.Success {
+				info.unavailable = true
+			}
+			info.scored = true
+			info.score = health.Score()
+		}
+		infos[mirror.Name] = info
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		a, b := infos[ranked[i].Name], infos[ranked[j].Name]
+		if a.unavailable != b.unavailable {
+			return b.unavailable
+		}
+		if ranked[i].Priority != ranked[j].Priority {
+			return ranked[i].Priority < ranked[j].Priority
+		}
+		if a.scored != b.scored {
+			return a.scored
+		}
+		if a.score != b.score {
+			return a.score > b.score
+		}
+		if a.tested != b.tested {
+			return a.tested
+		}
+		return a.responseTime < b.responseTime
+	})
+
+	return ranked
+}
+
 // TestMirrorSpeed 测试镜像速度
 func (s *mirrorServiceImpl) TestMirrorSpeed(ctx context.Context, mirror Mirror) (*MirrorTestResult, error) {
 	// 检查缓存
@@ -108,7 +262,8 @@ func (s *mirrorServiceImpl) TestMirrorSpeed(ctx context.Context, mirror Mirror)
 			ResponseTime: cached.ResponseTime,
 		}
 		if cached.Error != "" {
-			result.Error = fmt.Errorf(cached.Error)
+			result.Error = errors.New(cached.Error)
+			result.ErrorMessage = cached.Error
 		}
 		return result, nil
 	}
@@ -118,11 +273,7 @@ func (s *mirrorServiceImpl) TestMirrorSpeed(ctx context.Context, mirror Mirror)
 		Available: false,
 	}
 
-	// 构建测试URL
-	testURL := mirror.BaseURL
-	if testURL[len(testURL)-1] != '/' {
-		testURL += "/"
-	}
+	testURL := probeURL(mirror)
 
 	start := time.Now()
 
@@ -136,6 +287,10 @@ func (s *mirrorServiceImpl) TestMirrorSpeed(ctx context.Context, mirror Mirror)
 			lastErr = fmt.Errorf("创建%s请求失败: %w", method, err)
 			continue
 		}
+		if err := s.clientFactory.Authorize(req, mirror.CredentialRef); err != nil {
+			lastErr = err
+			break
+		}
 
 		resp, err := s.httpClient.Do(req)
 		if err != nil {
@@ -161,13 +316,30 @@ func (s *mirrorServiceImpl) TestMirrorSpeed(ctx context.Context, mirror Mirror)
 	}
 
 	result.Error = lastErr
+	if lastErr != nil {
+		result.ErrorMessage = lastErr.Error()
+	}
 	// 缓存失败结果
 	s.config.CacheTestResult(mirror.Name, result)
 
 	return result, nil
 }
 
-// SelectFastestMirror 选择最快的镜像
+// probeURL 获取检查镜像可用性时请求的地址
+// 配置了URL模板时请求按模板展开的测速压缩包地址（模板可能指向与基础URL不同的主机），否则请求基础URL
+func probeURL(mirror Mirror) string {
+	if mirror.URLTemplate != "" {
+		return benchmarkURL(mirror)
+	}
+	testURL := mirror.BaseURL
+	if !strings.HasSuffix(testURL, "/") {
+		testURL += "/"
+	}
+	return testURL
+}
+
+// SelectFastestMirror 选择评分最高的镜像
+// 并发对所有镜像进行吞吐量测速（5分钟内测过的镜像直接使用上次结果），按历史评分选择，评分相同时按优先级选择
 func (s *mirrorServiceImpl) SelectFastestMirror(ctx context.Context, mirrors []Mirror) (*Mirror, error) {
 	if len(mirrors) == 0 {
 		return nil, fmt.Errorf("没有可用的镜像")
@@ -181,7 +353,7 @@ func (s *mirrorServiceImpl) SelectFastestMirror(ctx context.Context, mirrors []M
 		wg.Add(1)
 		go func(m Mirror) {
 			defer wg.Done()
-			result, _ := s.TestMirrorSpeed(ctx, m)
+			result, _ := s.BenchmarkMirror(ctx, m, DefaultBenchmarkCacheAge)
 			results <- result
 		}(mirror)
 	}
@@ -204,9 +376,12 @@ func (s *mirrorServiceImpl) SelectFastestMirror(ctx context.Context, mirrors []M
 		return nil, fmt.Errorf("没有可用的镜像")
 	}
 
-	// 按响应时间排序，选择最快的
+	// 按评分排序，选择评分最高的
 	sort.Slice(availableResults, func(i, j int) bool {
-		return availableResults[i].ResponseTime < availableResults[j].ResponseTime
+		if availableResults[i].Score != availableResults[j].Score {
+			return availableResults[i].Score > availableResults[j].Score
+		}
+		return availableResults[i].Mirror.Priority < availableResults[j].Mirror.Priority
 	})
 
 	return &availableResults[0].Mirror, nil
@@ -229,21 +404,9 @@ func (s *mirrorServiceImpl) ValidateMirror(ctx context.Context, mirror Mirror) e
 	return nil
 }
 
-// GetMirrorByName 根据名称获取镜像
+// GetMirrorByName 根据名称获取镜像（包括已禁用的镜像）
 func (s *mirrorServiceImpl) GetMirrorByName(name string) (*Mirror, error) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-
-	// 先搜索默认镜像
-	for _, mirror := range s.mirrors {
-		if mirror.Name == name {
-			return &mirror, nil
-		}
-	}
-
-	// 再搜索自定义镜像
-	customMirrors := s.config.GetCustomMirrors()
-	for _, mirror := range customMirrors {
+	for _, mirror := range s.ListMirrors() {
 		if mirror.Name == name {
 			return &mirror, nil
 		}
@@ -252,43 +415,54 @@ func (s *mirrorServiceImpl) GetMirrorByName(name string) (*Mirror, error) {
 	return nil, fmt.Errorf("未找到名为 '%s' 的镜像", name)
 }
 
+// officialChecksumURLTemplate 官方发布的SHA-256校验和文件地址，预设镜像都使用官方校验和校验下载的压缩包
+var officialChecksumURLTemplate = "https://dl.google.com/go/{filename}.sha256"
+
+// fallbackChecksumURLTemplates 官方校验和文件无法访问时依次尝试的校验和文件地址，之后再使用官方发布目录
+var fallbackChecksumURLTemplates = []string{"https://golang.google.cn/dl/{filename}.sha256"}
+
 // getDefaultMirrors 获取预设镜像配置
 func getDefaultMirrors() []Mirror {
 	return []Mirror{
 		{
-			Name:        "official",
-			BaseURL:     "https://golang.org/dl/",
-			Description: "Go官方下载源",
-			Region:      "global",
-			Priority:    1,
+			Name:                "official",
+			BaseURL:             "https://golang.org/dl/",
+			Description:         "Go官方下载源",
+			Region:              "global",
+			Priority:            1,
+			ChecksumURLTemplate: officialChecksumURLTemplate,
 		},
 		{
-			Name:        "goproxy-cn",
-			BaseURL:     "https://goproxy.cn/golang/",
-			Description: "七牛云Go代理镜像",
-			Region:      "china",
-			Priority:    2,
+			Name:                "goproxy-cn",
+			BaseURL:             "https://goproxy.cn/golang/",
+			Description:         "七牛云Go代理镜像",
+			Region:              "china",
+			Priority:            2,
+			ChecksumURLTemplate: officialChecksumURLTemplate,
 		},
 		{
-			Name:        "aliyun",
-			BaseURL:     "https://mirrors.aliyun.com/golang/",
-			Description: "阿里云镜像源",
-			Region:      "china",
-			Priority:    3,
+			Name:                "aliyun",
+			BaseURL:             "https://mirrors.aliyun.com/golang/",
+			Description:         "阿里云镜像源",
+			Region:              "china",
+			Priority:            3,
+			ChecksumURLTemplate: officialChecksumURLTemplate,
 		},
 		{
-			Name:        "tencent",
-			BaseURL:     "https://mirrors.cloud.tencent.com/golang/",
-			Description: "腾讯云镜像源",
-			Region:      "china",
-			Priority:    4,
+			Name:                "tencent",
+			BaseURL:             "https://mirrors.cloud.tencent.com/golang/",
+			Description:         "腾讯云镜像源",
+			Region:              "china",
+			Priority:            4,
+			ChecksumURLTemplate: officialChecksumURLTemplate,
 		},
 		{
-			Name:        "huawei",
-			BaseURL:     "https://mirrors.huaweicloud.com/golang/",
-			Description: "华为云镜像源",
-			Region:      "china",
-			Priority:    5,
+			Name:                "huawei",
+			BaseURL:             "https://mirrors.huaweicloud.com/golang/",
+			Description:         "华为云镜像源",
+			Region:              "china",
+			Priority:            5,
+			ChecksumURLTemplate: officialChecksumURLTemplate,
 		},
 	}
 }
@@ -305,15 +479,18 @@ func (s *mirrorServiceImpl) AddCustomMirror(mirror Mirror) error {
 		}
 	}
 
-	customMirrors := s.config.GetCustomMirrors()
-	for _, existing := range customMirrors {
+	for _, existing := range s.customMirrors() {
 		if existing.Name == mirror.Name {
+			if existing.Source == MirrorSourceOrg {
+				return fmt.Errorf("镜像名称 '%s' 已存在（组织镜像）", mirror.Name)
+			}
 			return fmt.Errorf("镜像名称 '%s' 已存在（自定义镜像）", mirror.Name)
 		}
 	}
 
 	// 添加到配置中
 	s.config.AddCustomMirror(mirror)
+	s.clientFactory.RegisterMirror(mirror)
 
 	return nil
 }
@@ -332,8 +509,14 @@ func (s *mirrorServiceImpl) RemoveCustomMirror(name string) error {
 
 	// 从配置中移除
 	if !s.config.RemoveCustomMirror(name) {
+		for _, mirror := range s.orgConfig.GetCustomMirrors() {
+			if mirror.Name == name {
+				return fmt.Errorf("镜像源 '%s' 由组织配置提供，不能移除（可以使用 mirror disable 禁用）", name)
+			}
+		}
 		return fmt.Errorf("未找到自定义镜像源 '%s'", name)
 	}
+	s.clientFactory.SetMirrors(s.customMirrors())
 
 	return nil
 }
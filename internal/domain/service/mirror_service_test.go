@@ -4,6 +4,8 @@ import (
 	"context"
 	"net/http"
 	"net/http/httptest"
+	"runtime"
+	"strings"
 	"testing"
 	"time"
 
@@ -87,6 +89,36 @@ func TestMirrorService_TestMirrorSpeed(t *testing.T) {
 	assert.Equal(t, mirror.Name, result.Mirror.Name)
 }
 
+func TestMirrorService_TestMirrorSpeed_URLTemplate(t *testing.T) {
+	// 基础URL可用，但模板指向的地址上没有压缩包
+	base := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer base.Close()
+	var requested []string
+	artifacts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.URL.Path)
+		if strings.HasPrefix(r.URL.Path, "/go/"+mirrorBenchmarkVersion+"/") {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer artifacts.Close()
+
+	service := NewMirrorService()
+	ctx := context.Background()
+
+	result, err := service.TestMirrorSpeed(ctx, Mirror{Name: "layout", BaseURL: base.URL, URLTemplate: artifacts.URL + "/go/{version}/{filename}"})
+	require.NoError(t, err)
+	assert.True(t, result.Available)
+	require.NotEmpty(t, requested)
+	assert.Equal(t, "/go/"+mirrorBenchmarkVersion+"/"+(&SystemDetectorImpl{}).GetExpectedFilename(mirrorBenchmarkVersion, runtime.GOOS, runtime.GOARCH), requested[0])
+
+	err = service.ValidateMirror(ctx, Mirror{Name: "broken-layout", BaseURL: base.URL, URLTemplate: artifacts.URL + "/missing/{filename}"})
+	assert.Error(t, err, "模板地址不可用时验证应失败")
+}
+
 func TestMirrorService_TestMirrorSpeed_Failure(t *testing.T) {
 	service := NewMirrorService()
 	mirror := Mirror{
@@ -258,6 +290,8 @@ func TestDefaultMirrors(t *testing.T) {
 		assert.NotEmpty(t, mirror.BaseURL)
 		assert.NotEmpty(t, mirror.Description)
 		assert.NotEmpty(t, mirror.Region)
+		// 切换镜像时需要校验和才能继续下载
+		assert.Equal(t, "https://dl.google.com/go/go1.22.7.linux-armv6l.tar.gz.sha256", mirror.ChecksumURL("1.22.7", "linux", "arm"))
 		assert.Greater(t, mirror.Priority, 0)
 	}
 }
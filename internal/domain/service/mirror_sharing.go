@@ -0,0 +1,198 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// OrgMirrorConfigEnv 指定组织镜像配置文件路径的环境变量
+const OrgMirrorConfigEnv = "GO_VERSION_ORG_MIRRORS"
+
+// mirrorExport 导出的镜像配置，格式与镜像配置文件相同，可以直接作为组织镜像配置使用
+type mirrorExport struct {
+	CustomMirrors []Mirror                  `json:"custom_mirrors"`
+	Overrides     map[string]MirrorOverride `json:"overrides,omitempty"`
+}
+
+// DefaultOrgMirrorConfigPath 获取组织镜像配置文件路径
+// 优先使用 GO_VERSION_ORG_MIRRORS 环境变量，否则为 /etc/go-version/mirrors.json（Windows 为 %ProgramData%\go-version\mirrors.json）
+func DefaultOrgMirrorConfigPath() string {
+	if path := os.Getenv(OrgMirrorConfigEnv); path != "" {
+		return path
+	}
+	if runtime.GOOS == "windows" {
+		programData := os.Getenv("ProgramData")
+		if programData == "" {
+			return ""
+		}
+		return filepath.Join(programData, "go-version", "mirrors.json")
+	}
+	return "/etc/go-version/mirrors.json"
+}
+
+// SetMirrorEnabled 启用或禁用镜像，预设镜像和组织镜像同样可以禁用
+func (s *mirrorServiceImpl) SetMirrorEnabled(name string, enabled bool) error {
+	if _, err := s.GetMirrorByName(name); err != nil {
+		return err
+	}
+	s.config.SetEnabledOverride(name, enabled)
+	return nil
+}
+
+// SetMirrorPriority 设置镜像的优先级（数字越小优先级越高）
+func (s *mirrorServiceImpl) SetMirrorPriority(name string, priority int) error {
+	if _, err := s.GetMirrorByName(name); err != nil {
+		return err
+	}
+	s.config.SetPriorityOverride(name, priority)
+	return nil
+}
+
+// ExportMirrors 导出用户配置中的自定义镜像和覆盖项，不包括测速记录
+// 凭据只以引用名称导出，凭据内容需要每个成员在自己的凭据文件中配置
+func (s *mirrorServiceImpl) ExportMirrors() ([]byte, error) {
+	s.config.mu.RLock()
+	export := mirrorExport{
+		CustomMirrors: append([]Mirror{}, s.config.CustomMirrors...),
+		Overrides:     make(map[string]MirrorOverride, len(s.config.Overrides)),
+	}
+	for name, override := range s.config.Overrides {
+		export.Overrides[name] = override
+	}
+	s.config.mu.RUnlock()
+
+	data, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("序列化镜像配置失败: %v", err)
+	}
+	return data, nil
+}
+
+// MirrorImportResult 导入镜像配置的结果
+type MirrorImportResult struct {
+	Imported           int      // 导入的镜像数量
+	DroppedCredentials []string // 从 http(s) 地址导入时被移除凭据引用的镜像名称
+}
+
+// ImportMirrors 从文件或 http(s) 地址导入镜像配置
+// replace 为 true 时替换用户配置中的所有自定义镜像和覆盖项，否则按名称合并（导入的配置优先）
+// 从 http(s) 地址导入时默认移除镜像的凭据引用，避免远程配置把本地凭据指向任意地址；keepCredentials 为 true 时保留
+func (s *mirrorServiceImpl) ImportMirrors(ctx context.Context, source string, replace, keepCredentials bool) (*MirrorImportResult, error) {
+	data, err := s.readMirrorImport(ctx, source)
+	if err != nil {
+		return nil, err
+	}
+
+	var imported mirrorExport
+	if err := json.Unmarshal(data, &imported); err != nil {
+		return nil, fmt.Errorf("解析镜像配置失败: %v", err)
+	}
+
+	result := &MirrorImportResult{Imported: len(imported.CustomMirrors)}
+	names := make(map[string]bool, len(imported.CustomMirrors))
+	for i, mirror := range imported.CustomMirrors {
+		if err := s.validateImportedMirror(mirror); err != nil {
+			return nil, err
+		}
+		if names[mirror.Name] {
+			return nil, fmt.Errorf("镜像名称 '%s' 重复", mirror.Name)
+		}
+		names[mirror.Name] = true
+
+		if mirror.CredentialRef != "" && isRemoteMirrorImport(source) && !keepCredentials {
+			imported.CustomMirrors[i].CredentialRef = ""
+			result.DroppedCredentials = append(result.DroppedCredentials, mirror.Name)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if replace {
+		s.config.mu.Lock()
+		s.config.CustomMirrors = make([]Mirror, 0, len(imported.CustomMirrors))
+		s.config.Overrides = nil
+		s.config.mu.Unlock()
+	}
+	for _, mirror := range imported.CustomMirrors {
+		s.config.AddCustomMirror(mirror)
+	}
+
+	s.config.mu.Lock()
+	for name, override := range imported.Overrides {
+		s.config.setOverride(name, override)
+	}
+	s.config.mu.Unlock()
+
+	// 被替换或覆盖的镜像的凭据不再对原地址生效
+	s.clientFactory.SetMirrors(s.customMirrors())
+
+	return result, nil
+}
+
+// isRemoteMirrorImport 判断镜像配置是否来自 http(s) 地址
+func isRemoteMirrorImport(source string) bool {
+	return strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://")
+}
+
+// validateImportedMirror 验证导入的镜像配置
+func (s *mirrorServiceImpl) validateImportedMirror(mirror Mirror) error {
+	if mirror.Name == "" {
+		return fmt.Errorf("导入的镜像缺少名称")
+	}
+	for _, builtin := range s.mirrors {
+		if builtin.Name == mirror.Name {
+			return fmt.Errorf("镜像名称 '%s' 与预设镜像冲突", mirror.Name)
+		}
+	}
+	if !strings.HasPrefix(mirror.BaseURL, "http://") && !strings.HasPrefix(mirror.BaseURL, "https://") {
+		return fmt.Errorf("镜像 '%s' 的URL必须以 http:// 或 https:// 开头", mirror.Name)
+	}
+	for _, template := range []string{mirror.URLTemplate, mirror.ChecksumURLTemplate} {
+		if template == "" {
+			continue
+		}
+		if err := ValidateURLTemplate(template); err != nil {
+			return fmt.Errorf("镜像 '%s' 的URL模板无效: %v", mirror.Name, err)
+		}
+	}
+	return nil
+}
+
+// readMirrorImport 读取本地文件或下载 http(s) 地址的镜像配置
+func (s *mirrorServiceImpl) readMirrorImport(ctx context.Context, source string) ([]byte, error) {
+	if !isRemoteMirrorImport(source) {
+		data, err := os.ReadFile(source)
+		if err != nil {
+			return nil, fmt.Errorf("读取镜像配置文件失败: %v", err)
+		}
+		return data, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, source, nil)
+	if err != nil {
+		return nil, fmt.Errorf("创建请求失败: %v", err)
+	}
+	resp, err := s.clientFactory.Client(30 * time.Second).Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("下载镜像配置失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("下载镜像配置失败: %w", newHTTPStatusError(resp))
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("读取镜像配置失败: %v", err)
+	}
+	return data, nil
+}
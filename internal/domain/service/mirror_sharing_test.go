@@ -0,0 +1,312 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+	"version-list/internal/domain/model"
+)
+
+// newSharingTestService 创建使用临时用户配置和组织配置的镜像服务
+func newSharingTestService(t *testing.T, orgConfig string) MirrorService {
+	t.Helper()
+	dir := t.TempDir()
+	orgPath := filepath.Join(dir, "org-mirrors.json")
+	if orgConfig != "" {
+		writeTestFile(t, dir, "org-mirrors.json", orgConfig)
+	}
+	t.Setenv(OrgMirrorConfigEnv, orgPath)
+
+	mirrorService, err := NewMirrorServiceWithHTTPClientFactory(filepath.Join(dir, "mirrors.json"), DefaultHTTPClientFactory())
+	if err != nil {
+		t.Fatalf("创建镜像服务失败: %v", err)
+	}
+	return mirrorService
+}
+
+// findMirror 在镜像列表中查找指定名称的镜像
+func findMirror(mirrors []Mirror, name string) (Mirror, bool) {
+	for _, mirror := range mirrors {
+		if mirror.Name == name {
+			return mirror, true
+		}
+	}
+	return Mirror{}, false
+}
+
+func TestMirrorService_ExportImport(t *testing.T) {
+	source := newSharingTestService(t, "")
+	if err := source.AddCustomMirror(Mirror{Name: "corp", BaseURL: "https://mirror.corp.example/golang/", Priority: 5}); err != nil {
+		t.Fatalf("添加镜像失败: %v", err)
+	}
+	if err := source.SetMirrorEnabled("aliyun", false); err != nil {
+		t.Fatalf("禁用镜像失败: %v", err)
+	}
+	if err := source.SetMirrorPriority("corp", 0); err != nil {
+		t.Fatalf("设置优先级失败: %v", err)
+	}
+
+	data, err := source.ExportMirrors()
+	if err != nil {
+		t.Fatalf("导出镜像配置失败: %v", err)
+	}
+	exportPath := writeTestFile(t, t.TempDir(), "team.json", string(data))
+
+	// 合并导入保留已有的自定义镜像
+	target := newSharingTestService(t, "")
+	if err := target.AddCustomMirror(Mirror{Name: "local", BaseURL: "https://local.example/golang/", Priority: 6}); err != nil {
+		t.Fatalf("添加镜像失败: %v", err)
+	}
+	result, err := target.ImportMirrors(context.Background(), exportPath, false, false)
+	if err != nil || result.Imported != 1 {
+		t.Fatalf("导入镜像配置失败: %+v, %v", result, err)
+	}
+	mirrors := target.ListMirrors()
+	if corp, exists := findMirror(mirrors, "corp"); !exists || corp.Priority != 0 || corp.Source != MirrorSourceUser {
+		t.Errorf("导入的镜像不正确: %+v", corp)
+	}
+	if aliyun, _ := findMirror(mirrors, "aliyun"); !aliyun.Disabled {
+		t.Error("导入的禁用状态应生效")
+	}
+	if _, exists := findMirror(mirrors, "local"); !exists {
+		t.Error("合并导入不应删除已有的自定义镜像")
+	}
+
+	// 替换导入删除已有的自定义镜像
+	if _, err := target.ImportMirrors(context.Background(), exportPath, true, false); err != nil {
+		t.Fatalf("导入镜像配置失败: %v", err)
+	}
+	if _, exists := findMirror(target.ListMirrors(), "local"); exists {
+		t.Error("替换导入应删除已有的自定义镜像")
+	}
+}
+
+func TestMirrorService_ImportMirrorsFromURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/mirrors.json" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{"custom_mirrors": [{"name": "remote", "base_url": "https://remote.example/golang/", "priority": 3}]}`))
+	}))
+	defer server.Close()
+
+	mirrorService := newSharingTestService(t, "")
+	if _, err := mirrorService.ImportMirrors(context.Background(), server.URL+"/mirrors.json", false, false); err != nil {
+		t.Fatalf("从URL导入失败: %v", err)
+	}
+	if _, err := mirrorService.GetMirrorByName("remote"); err != nil {
+		t.Errorf("应能找到导入的镜像: %v", err)
+	}
+
+	if _, err := mirrorService.ImportMirrors(context.Background(), server.URL+"/missing.json", false, false); err == nil {
+		t.Error("下载失败时应返回错误")
+	}
+}
+
+func TestMirrorService_ImportMirrorsCredentials(t *testing.T) {
+	dir := t.TempDir()
+	credentialsPath := writeTestFile(t, dir, "credentials.json", `{"credentials": {"corp": {"type": "bearer", "token": "s3cr3t"}}}`)
+	factory, err := NewHTTPClientFactory(&HTTPConfig{
+		CredentialsFile: credentialsPath,
+		NetrcFile:       filepath.Join(dir, "missing-netrc"),
+	})
+	if err != nil {
+		t.Fatalf("创建HTTP客户端工厂失败: %v", err)
+	}
+	t.Setenv(OrgMirrorConfigEnv, filepath.Join(dir, "org-mirrors.json"))
+	mirrorService, err := NewMirrorServiceWithHTTPClientFactory(filepath.Join(dir, "mirrors.json"), factory)
+	if err != nil {
+		t.Fatalf("创建镜像服务失败: %v", err)
+	}
+
+	corpServer := newAuthCheckServer(t, "Bearer s3cr3t")
+	attacker := newAuthCheckServer(t, "Bearer s3cr3t")
+	client := factory.Client(5 * time.Second)
+	if err := mirrorService.AddCustomMirror(Mirror{Name: "corp", BaseURL: corpServer.URL + "/golang/", CredentialRef: "corp"}); err != nil {
+		t.Fatalf("添加镜像失败: %v", err)
+	}
+
+	// 远程配置把已有的凭据引用指向其他地址
+	remote := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"custom_mirrors": [{"name": "corp", "base_url": "` + attacker.URL + `/golang/", "credential_ref": "corp"}]}`))
+	}))
+	defer remote.Close()
+
+	result, err := mirrorService.ImportMirrors(context.Background(), remote.URL+"/mirrors.json", true, false)
+	if err != nil {
+		t.Fatalf("从URL导入失败: %v", err)
+	}
+	if strings.Join(result.DroppedCredentials, ",") != "corp" {
+		t.Errorf("被移除凭据引用的镜像 = %v, 期望 [corp]", result.DroppedCredentials)
+	}
+	if corp, _ := mirrorService.GetMirrorByName("corp"); corp.CredentialRef != "" {
+		t.Errorf("从URL导入的镜像不应保留凭据引用: %+v", corp)
+	}
+	if status := getStatus(t, client, attacker.URL+"/golang/go.tar.gz"); status != http.StatusUnauthorized {
+		t.Errorf("凭据不应发送给远程配置中的地址，实际 %d", status)
+	}
+	// 替换导入后原镜像地址不再使用凭据
+	if status := getStatus(t, client, corpServer.URL+"/golang/go.tar.gz"); status != http.StatusUnauthorized {
+		t.Errorf("替换导入后原镜像地址不应再添加凭据，实际 %d", status)
+	}
+
+	// 确认来源可信时保留凭据引用
+	if _, err := mirrorService.ImportMirrors(context.Background(), remote.URL+"/mirrors.json", true, true); err != nil {
+		t.Fatalf("从URL导入失败: %v", err)
+	}
+	if status := getStatus(t, client, attacker.URL+"/golang/go.tar.gz"); status != http.StatusOK {
+		t.Errorf("保留凭据引用时期望200，实际 %d", status)
+	}
+
+	// 本地文件中的凭据引用直接导入
+	localPath := writeTestFile(t, dir, "team.json", `{"custom_mirrors": [{"name": "corp", "base_url": "`+corpServer.URL+`/golang/", "credential_ref": "corp"}]}`)
+	result, err = mirrorService.ImportMirrors(context.Background(), localPath, false, false)
+	if err != nil || len(result.DroppedCredentials) != 0 {
+		t.Fatalf("导入本地文件失败: %+v, %v", result, err)
+	}
+	if status := getStatus(t, client, corpServer.URL+"/golang/go.tar.gz"); status != http.StatusOK {
+		t.Errorf("导入本地文件后期望200，实际 %d", status)
+	}
+	if status := getStatus(t, client, attacker.URL+"/golang/go.tar.gz"); status != http.StatusUnauthorized {
+		t.Errorf("被覆盖的镜像地址不应再添加凭据，实际 %d", status)
+	}
+
+	// 移除镜像后不再添加凭据
+	if err := mirrorService.RemoveCustomMirror("corp"); err != nil {
+		t.Fatalf("移除镜像失败: %v", err)
+	}
+	if status := getStatus(t, client, corpServer.URL+"/golang/go.tar.gz"); status != http.StatusUnauthorized {
+		t.Errorf("移除镜像后不应再添加凭据，实际 %d", status)
+	}
+}
+
+func TestMirrorService_ImportMirrorsValidation(t *testing.T) {
+	mirrorService := newSharingTestService(t, "")
+	dir := t.TempDir()
+
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"预设镜像重名", `{"custom_mirrors": [{"name": "official", "base_url": "https://example.com/"}]}`},
+		{"无效URL", `{"custom_mirrors": [{"name": "bad", "base_url": "ftp://example.com/"}]}`},
+		{"名称重复", `{"custom_mirrors": [{"name": "a", "base_url": "https://a.example/"}, {"name": "a", "base_url": "https://b.example/"}]}`},
+		{"无效JSON", `{`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeTestFile(t, dir, "import.json", tt.content)
+			if _, err := mirrorService.ImportMirrors(context.Background(), path, true, false); err == nil {
+				t.Error("期望返回错误")
+			}
+		})
+	}
+
+	// 验证失败时不修改现有配置
+	if len(mirrorService.ListMirrors()) != len(getDefaultMirrors()) {
+		t.Error("导入失败时不应修改镜像配置")
+	}
+}
+
+func TestMirrorService_OrgMirrorLayer(t *testing.T) {
+	mirrorService := newSharingTestService(t, `{
+  "custom_mirrors": [{"name": "corp", "base_url": "https://mirror.corp.example/golang/", "priority": 0}],
+  "overrides": {"official": {"enabled": false}, "goproxy-cn": {"priority": 20}}
+}`)
+
+	corp, err := mirrorService.GetMirrorByName("corp")
+	if err != nil || corp.Source != MirrorSourceOrg {
+		t.Fatalf("应能找到组织镜像: %+v, %v", corp, err)
+	}
+	if _, exists := findMirror(mirrorService.GetAvailableMirrors(), "official"); exists {
+		t.Error("组织配置禁用的镜像不应出现在可用镜像中")
+	}
+	if mirror, _ := mirrorService.GetMirrorByName("goproxy-cn"); mirror.Priority != 20 {
+		t.Errorf("组织配置的优先级应生效，实际 %d", mirror.Priority)
+	}
+
+	// 组织镜像不能移除或重名添加，但用户可以覆盖其设置
+	if err := mirrorService.RemoveCustomMirror("corp"); err == nil || !strings.Contains(err.Error(), "组织配置") {
+		t.Errorf("移除组织镜像应返回错误: %v", err)
+	}
+	if err := mirrorService.AddCustomMirror(Mirror{Name: "corp", BaseURL: "https://other.example/"}); err == nil {
+		t.Error("添加与组织镜像同名的镜像应返回错误")
+	}
+	if err := mirrorService.SetMirrorEnabled("official", true); err != nil {
+		t.Fatalf("启用镜像失败: %v", err)
+	}
+	if err := mirrorService.SetMirrorPriority("goproxy-cn", 1); err != nil {
+		t.Fatalf("设置优先级失败: %v", err)
+	}
+	if mirror, _ := mirrorService.GetMirrorByName("official"); mirror.Disabled {
+		t.Error("用户配置应覆盖组织配置的禁用状态")
+	}
+	if mirror, _ := mirrorService.GetMirrorByName("goproxy-cn"); mirror.Priority != 1 {
+		t.Errorf("用户配置应覆盖组织配置的优先级，实际 %d", mirror.Priority)
+	}
+
+	// 导出只包括用户配置
+	data, err := mirrorService.ExportMirrors()
+	if err != nil {
+		t.Fatalf("导出镜像配置失败: %v", err)
+	}
+	if strings.Contains(string(data), "mirror.corp.example") {
+		t.Errorf("导出不应包括组织镜像: %s", data)
+	}
+}
+
+func TestMirrorService_InvalidOrgConfig(t *testing.T) {
+	orgPath := writeTestFile(t, t.TempDir(), "org-mirrors.json", "{")
+	t.Setenv(OrgMirrorConfigEnv, orgPath)
+
+	_, err := NewMirrorServiceWithHTTPClientFactory(filepath.Join(t.TempDir(), "mirrors.json"), DefaultHTTPClientFactory())
+	if err == nil || !strings.Contains(err.Error(), orgPath) {
+		t.Errorf("组织配置无效时应返回包含路径的错误: %v", err)
+	}
+}
+
+func TestMirrorService_DisabledMirror(t *testing.T) {
+	mirrorService := newSharingTestService(t, "")
+	if err := mirrorService.SetMirrorEnabled("aliyun", false); err != nil {
+		t.Fatalf("禁用镜像失败: %v", err)
+	}
+	if _, exists := findMirror(mirrorService.GetAvailableMirrors(), "aliyun"); exists {
+		t.Error("禁用的镜像不应出现在可用镜像中")
+	}
+	if mirror, exists := findMirror(mirrorService.ListMirrors(), "aliyun"); !exists || !mirror.Disabled {
+		t.Error("禁用的镜像应出现在完整列表中并标记为禁用")
+	}
+
+	if err := mirrorService.SetMirrorEnabled("missing", false); err == nil {
+		t.Error("未知镜像应返回错误")
+	}
+}
+
+func TestVersionService_SelectMirror_Disabled(t *testing.T) {
+	mirrorService := newSharingTestService(t, "")
+	versionService := &VersionService{mirrorService: mirrorService}
+
+	if err := mirrorService.SetMirrorEnabled("aliyun", false); err != nil {
+		t.Fatalf("禁用镜像失败: %v", err)
+	}
+	if _, err := versionService.selectMirror(context.Background(), &model.InstallOptions{Mirror: "aliyun"}); err == nil || !strings.Contains(err.Error(), "已禁用") {
+		t.Errorf("指定已禁用的镜像应返回错误: %v", err)
+	}
+
+	// 官方源被禁用时默认使用排序最靠前的可用镜像
+	if err := mirrorService.SetMirrorEnabled("official", false); err != nil {
+		t.Fatalf("禁用镜像失败: %v", err)
+	}
+	mirror, err := versionService.selectMirror(context.Background(), &model.InstallOptions{})
+	if err != nil {
+		t.Fatalf("选择镜像失败: %v", err)
+	}
+	if mirror.Name == "official" || mirror.Disabled {
+		t.Errorf("不应选择已禁用的镜像: %+v", mirror)
+	}
+}
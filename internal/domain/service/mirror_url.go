@@ -0,0 +1,100 @@
+package service
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// urlTemplatePlaceholders 镜像URL模板支持的占位符
+var urlTemplatePlaceholders = map[string]bool{
+	"version":  true,
+	"os":       true,
+	"arch":     true,
+	"ext":      true,
+	"filename": true,
+}
+
+// urlTemplatePlaceholderPattern 匹配URL模板中的占位符
+var urlTemplatePlaceholderPattern = regexp.MustCompile(`\{([^{}]*)\}`)
+
+// DownloadURL 根据镜像的基础URL构建文件下载地址
+func (m Mirror) DownloadURL(filename string) string {
+	baseURL := m.BaseURL
+	if !strings.HasSuffix(baseURL, "/") {
+		baseURL += "/"
+	}
+	return baseURL + filename
+}
+
+// URLPattern 获取镜像的下载地址格式，配置了URL模板时返回模板，否则返回基础URL
+func (m Mirror) URLPattern() string {
+	if m.URLTemplate != "" {
+		return m.URLTemplate
+	}
+	return m.BaseURL
+}
+
+// ChecksumURL 根据校验和URL模板构建指定版本的校验和文件地址，未配置模板时返回空字符串
+func (m Mirror) ChecksumURL(version, os, arch string) string {
+	if m.ChecksumURLTemplate == "" {
+		return ""
+	}
+	return ExpandURLTemplate(m.ChecksumURLTemplate, version, os, arch)
+}
+
+// IsURLTemplate 判断镜像地址是否为包含占位符的URL模板
+func IsURLTemplate(pattern string) bool {
+	return strings.Contains(pattern, "{")
+}
+
+// ValidateURLTemplate 验证URL模板格式及占位符
+// 模板必须包含 {version} 或 {filename}，否则所有版本会解析为同一个地址
+func ValidateURLTemplate(template string) error {
+	if !strings.HasPrefix(template, "http://") && !strings.HasPrefix(template, "https://") {
+		return fmt.Errorf("URL模板必须以 http:// 或 https:// 开头")
+	}
+
+	matches := urlTemplatePlaceholderPattern.FindAllStringSubmatch(template, -1)
+	hasVersion := false
+	for _, match := range matches {
+		name := match[1]
+		if !urlTemplatePlaceholders[name] {
+			return fmt.Errorf("不支持的占位符 {%s}，可用占位符: {version} {os} {arch} {ext} {filename}", name)
+		}
+		if name == "version" || name == "filename" {
+			hasVersion = true
+		}
+	}
+
+	// 去除合法占位符后不应再有花括号
+	if remaining := urlTemplatePlaceholderPattern.ReplaceAllString(template, ""); strings.ContainsAny(remaining, "{}") {
+		return fmt.Errorf("URL模板中的花括号不匹配: %s", template)
+	}
+	if !hasVersion {
+		return fmt.Errorf("URL模板必须包含 {version} 或 {filename}")
+	}
+	return nil
+}
+
+// ExpandURLTemplate 使用版本和平台信息替换URL模板中的占位符
+// {arch} 使用发布文件中的架构名称（如 linux/arm 为 armv6l），平台表中没有时使用 GOARCH
+func ExpandURLTemplate(template, version, os, arch string) string {
+	filename := (&SystemDetectorImpl{}).GetExpectedFilename(version, os, arch)
+	artifactArch := arch
+	if platform, err := LookupPlatform(os, arch); err == nil {
+		artifactArch = platform.ArtifactArch
+	}
+	ext := "tar.gz"
+	if strings.HasSuffix(filename, ".zip") {
+		ext = "zip"
+	}
+
+	return strings.NewReplacer(
+		"{version}", version,
+		"{os}", os,
+		"{arch}", artifactArch,
+		"{ext}", ext,
+		"{filename}", filename,
+	).Replace(template)
+}
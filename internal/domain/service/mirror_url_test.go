@@ -0,0 +1,82 @@
+package service
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateURLTemplate(t *testing.T) {
+	tests := []struct {
+		template    string
+		expectError bool
+	}{
+		{"https://repo.corp/go/{version}/{os}/{arch}/go.{ext}", false},
+		{"https://repo.corp/go/{filename}?token=abc", false},
+		{"http://repo.corp/{version}.{os}-{arch}.{ext}", false},
+		{"ftp://repo.corp/{version}", true},
+		{"https://repo.corp/{os}/{arch}/go.tar.gz", true},
+		{"https://repo.corp/{version}/{platform}", true},
+		{"https://repo.corp/{version/go.tar.gz", true},
+		{"https://repo.corp/{version}}/go.tar.gz", true},
+	}
+
+	for _, tt := range tests {
+		err := ValidateURLTemplate(tt.template)
+		if tt.expectError && err == nil {
+			t.Errorf("ValidateURLTemplate(%q) 应该返回错误", tt.template)
+		}
+		if !tt.expectError && err != nil {
+			t.Errorf("ValidateURLTemplate(%q) 返回错误: %v", tt.template, err)
+		}
+	}
+}
+
+func TestExpandURLTemplate(t *testing.T) {
+	tests := []struct {
+		template string
+		os       string
+		arch     string
+		expected string
+	}{
+		{"https://repo.corp/{version}/{os}/{arch}/go.{ext}", "linux", "amd64", "https://repo.corp/1.22.7/linux/amd64/go.tar.gz"},
+		{"https://repo.corp/{version}/{os}/{arch}/go.{ext}", "windows", "amd64", "https://repo.corp/1.22.7/windows/amd64/go.zip"},
+		{"https://repo.corp/dl?file={filename}&v={version}", "darwin", "arm64", "https://repo.corp/dl?file=go1.22.7.darwin-arm64.tar.gz&v=1.22.7"},
+		{"https://repo.corp/{version}/{os}/{arch}/go.{ext}", "linux", "arm", "https://repo.corp/1.22.7/linux/armv6l/go.tar.gz"},
+		{"https://repo.corp/{version}/go{version}.{os}-{arch}.{ext}", "freebsd", "arm", "https://repo.corp/1.22.7/go1.22.7.freebsd-armv6l.tar.gz"},
+	}
+
+	for _, tt := range tests {
+		if got := ExpandURLTemplate(tt.template, "1.22.7", tt.os, tt.arch); got != tt.expected {
+			t.Errorf("ExpandURLTemplate(%q, %s/%s) = %s, 期望 %s", tt.template, tt.os, tt.arch, got, tt.expected)
+		}
+	}
+}
+
+func TestMirror_URLTemplate(t *testing.T) {
+	mirror := Mirror{
+		Name:                "layout",
+		BaseURL:             "https://repo.corp/go/",
+		URLTemplate:         "https://repo.corp/go/{version}/{os}/{arch}/go.{ext}",
+		ChecksumURLTemplate: "https://repo.corp/go/{version}/{os}/{arch}/go.{ext}.sha256",
+	}
+
+	detector := &SystemDetectorImpl{}
+	url := detector.GetDownloadURLWithMirror("1.22.7", "linux", "arm64", mirror.URLPattern())
+	if url != "https://repo.corp/go/1.22.7/linux/arm64/go.tar.gz" {
+		t.Errorf("模板镜像下载地址不正确: %s", url)
+	}
+	if checksumURL := mirror.ChecksumURL("1.22.7", "linux", "arm64"); checksumURL != url+".sha256" {
+		t.Errorf("校验和地址不正确: %s", checksumURL)
+	}
+	if (Mirror{BaseURL: "https://repo.corp/go/"}).ChecksumURL("1.22.7", "linux", "arm64") != "" {
+		t.Error("未配置校验和模板时应返回空字符串")
+	}
+
+	if err := detector.ValidateMirrorURL(mirror.URLPattern(), "1.22.7", "linux", "arm64"); err != nil {
+		t.Errorf("合法的URL模板验证失败: %v", err)
+	}
+	err := detector.ValidateMirrorURL("https://repo.corp/{os}/{arch}/go.tar.gz", "1.22.7", "linux", "arm64")
+	if err == nil || !strings.Contains(err.Error(), "{version}") {
+		t.Errorf("缺少版本占位符的模板应验证失败，实际: %v", err)
+	}
+}
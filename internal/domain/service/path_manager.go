@@ -143,8 +143,7 @@ func (pm *PathManagerImpl) GetVersionDirectory(baseDir, version string) string {
 
 // GetTempDirectory 获取临时目录路径
 func (pm *PathManagerImpl) GetTempDirectory(version string) string {
-	tempBase := os.TempDir()
-	return filepath.Join(tempBase, fmt.Sprintf("go-install-%s", version))
+	return InstallTempDir(version)
 }
 
 // CheckDiskSpace 检查磁盘空间
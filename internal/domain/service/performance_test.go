@@ -158,7 +158,11 @@ func TestCacheEfficiency(t *testing.T) {
 	// 第二次从缓存获取
 	destFile2 := filepath.Join(os.TempDir(), "cache_dest2.txt")
 	start2 := time.Now()
-	if err := service.copyFromCache(filepath.Join(cacheDir, fmt.Sprintf("%x", "test://url")), destFile2, nil); err != nil {
+	cachedFile, err := service.getCachedFile("test://url")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := service.copyFromCache(cachedFile, destFile2, nil); err != nil {
 		t.Fatal(err)
 	}
 	duration2 := time.Since(start2)
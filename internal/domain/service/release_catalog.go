@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"version-list/internal/domain/model"
+)
+
+// DefaultReleaseCatalogURL 官方发布目录地址，包含所有版本的文件大小和SHA-256校验和
+const DefaultReleaseCatalogURL = "https://go.dev/dl/?mode=json&include=all"
+
+// chinaReleaseCatalogURL 官方在中国大陆提供的发布目录地址，go.dev 无法访问时使用
+const chinaReleaseCatalogURL = "https://golang.google.cn/dl/?mode=json&include=all"
+
+// GoRelease 发布目录中的Go版本
+type GoRelease struct {
+	Version string          `json:"version"` // 带 go 前缀的版本号，如 go1.22.7
+	Stable  bool            `json:"stable"`
+	Files   []GoReleaseFile `json:"files"`
+}
+
+// GoReleaseFile 发布目录中的文件
+type GoReleaseFile struct {
+	Filename string `json:"filename"`
+	OS       string `json:"os"`
+	Arch     string `json:"arch"`
+	Version  string `json:"version"`
+	SHA256   string `json:"sha256"`
+	Size     int64  `json:"size"`
+	Kind     string `json:"kind"` // archive, installer 或 source
+}
+
+// VersionNumber 获取不带 go 前缀的版本号
+func (r GoRelease) VersionNumber() string {
+	return strings.TrimPrefix(r.Version, "go")
+}
+
+// FindArchive 查找指定平台的压缩包文件
+func (r GoRelease) FindArchive(goos, goarch string) (*GoReleaseFile, bool) {
+	// 发布目录中使用发布名称（如32位ARM为 armv6l）
+	artifactArch := goarch
+	if platform, err := LookupPlatform(goos, goarch); err == nil {
+		artifactArch = platform.ArtifactArch
+	}
+
+	for _, file := range r.Files {
+		if file.Kind == "archive" && file.OS == goos && file.Arch == artifactArch {
+			return &file, true
+		}
+	}
+	return nil, false
+}
+
+// FindSource 查找源码包文件
+func (r GoRelease) FindSource() (*GoReleaseFile, bool) {
+	for _, file := range r.Files {
+		if file.Kind == "source" {
+			return &file, true
+		}
+	}
+	return nil, false
+}
+
+// UnknownPlatforms 获取发布目录中提供了压缩包、但不在平台表中的平台，用于检查平台表是否需要更新
+func (r GoRelease) UnknownPlatforms() []string {
+	var unknown []string
+	for _, file := range r.Files {
+		if file.Kind != "archive" {
+			continue
+		}
+		if _, exists := LookupPlatformByArtifact(file.OS, file.Arch); !exists {
+			unknown = append(unknown, file.OS+"/"+file.Arch)
+		}
+	}
+	return unknown
+}
+
+// FetchReleaseCatalog 下载并解析发布目录
+func FetchReleaseCatalog(ctx context.Context, client *http.Client, catalogURL string) ([]GoRelease, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, catalogURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("创建发布目录请求失败: %v", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("获取发布目录失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("获取发布目录失败: %w", newHTTPStatusError(resp))
+	}
+
+	var releases []GoRelease
+	if err := json.NewDecoder(resp.Body).Decode(&releases); err != nil {
+		return nil, fmt.Errorf("解析发布目录失败: %v", err)
+	}
+	return releases, nil
+}
+
+// fetchReleaseIndex 使用下载服务的HTTP客户端获取发布目录，并按不带 go 前缀的版本号建立索引
+// catalogURL 为空时使用官方发布目录
+func (s *VersionService) fetchReleaseIndex(ctx context.Context, catalogURL string) (map[string]GoRelease, error) {
+	if catalogURL == "" {
+		catalogURL = DefaultReleaseCatalogURL
+	}
+	catalog, err := FetchReleaseCatalog(ctx, s.httpClient(), catalogURL)
+	if err != nil {
+		return nil, err
+	}
+	releases := make(map[string]GoRelease, len(catalog))
+	for _, release := range catalog {
+		releases[release.VersionNumber()] = release
+	}
+	return releases, nil
+}
+
+// LatestStableReleases 获取最新的 n 个稳定版本，按版本号从新到旧排列
+func LatestStableReleases(releases []GoRelease, n int) []GoRelease {
+	var stable []GoRelease
+	for _, release := range releases {
+		if release.Stable {
+			stable = append(stable, release)
+		}
+	}
+
+	sort.SliceStable(stable, func(i, j int) bool {
+		return model.CompareVersionStrings(stable[i].VersionNumber(), stable[j].VersionNumber()).Result > 0
+	})
+
+	if n > 0 && len(stable) > n {
+		stable = stable[:n]
+	}
+	return stable
+}
@@ -3,24 +3,132 @@ package service
 import (
 	"fmt"
 	"runtime"
+	"strings"
 	"version-list/internal/domain/model"
 )
 
 // SystemDetector 系统信息检测服务接口
 type SystemDetector interface {
-	DetectOS() (string, error)                                                 // 检测操作系统
-	DetectArch() (string, error)                                               // 检测CPU架构
-	GetDownloadURL(version, os, arch string) string                            // 构建官方下载URL
-	GetDownloadURLWithMirror(version, os, arch, mirror string) string          // 使用镜像构建下载URL
-	GetExpectedFilename(version, os, arch string) string                       // 获取预期文件名
-	GetSystemInfo(version string) (*model.SystemInfo, error)                   // 获取完整系统信息
-	GetSystemInfoWithMirror(version, mirror string) (*model.SystemInfo, error) // 使用镜像获取系统信息
-	ValidateMirrorURL(mirrorBaseURL, version, os, arch string) error           // 验证镜像URL格式
+	DetectOS() (string, error)                                                            // 检测操作系统
+	DetectArch() (string, error)                                                          // 检测CPU架构
+	GetDownloadURL(version, os, arch string) string                                       // 构建官方下载URL
+	GetDownloadURLWithMirror(version, os, arch, mirror string) string                     // 使用镜像构建下载URL
+	GetExpectedFilename(version, os, arch string) string                                  // 获取预期文件名
+	GetSystemInfo(version string) (*model.SystemInfo, error)                              // 获取完整系统信息
+	GetSystemInfoWithMirror(version, mirror string) (*model.SystemInfo, error)            // 使用镜像获取系统信息
+	GetSystemInfoForPlatform(version, os, arch, mirror string) (*model.SystemInfo, error) // 获取指定平台的系统信息
+	ValidateMirrorURL(mirrorBaseURL, version, os, arch string) error                      // 验证镜像URL格式
 }
 
 // SystemDetectorImpl 系统检测服务实现
 type SystemDetectorImpl struct{}
 
+// 压缩包格式
+const (
+	ArchiveKindTarGz = "tar.gz"
+	ArchiveKindZip   = "zip"
+)
+
+// Platform 官方发布了二进制压缩包的平台
+type Platform struct {
+	OS           string // GOOS
+	Arch         string // GOARCH
+	ArtifactArch string // 发布文件名中的架构名称，32位ARM为 armv6l，其余与 GOARCH 相同
+	ArchiveKind  string // 压缩包格式
+}
+
+// String 返回 os/arch 格式的平台标识
+func (p Platform) String() string {
+	return p.OS + "/" + p.Arch
+}
+
+// Filename 获取指定版本在该平台的压缩包文件名
+func (p Platform) Filename(version string) string {
+	return fmt.Sprintf("go%s.%s-%s.%s", version, p.OS, p.ArtifactArch, p.ArchiveKind)
+}
+
+// platforms 官方发布的所有平台，与 https://go.dev/dl/?mode=json&include=all 中 kind 为 archive 的文件对应
+// 部分平台只在较新的版本中提供（如 openbsd/riscv64 从1.23开始），具体版本是否提供以发布目录为准
+var platforms = []Platform{
+	{"aix", "ppc64", "ppc64", ArchiveKindTarGz},
+	{"darwin", "amd64", "amd64", ArchiveKindTarGz},
+	{"darwin", "arm64", "arm64", ArchiveKindTarGz},
+	{"dragonfly", "amd64", "amd64", ArchiveKindTarGz},
+	{"freebsd", "386", "386", ArchiveKindTarGz},
+	{"freebsd", "amd64", "amd64", ArchiveKindTarGz},
+	{"freebsd", "arm", "armv6l", ArchiveKindTarGz},
+	{"freebsd", "arm64", "arm64", ArchiveKindTarGz},
+	{"freebsd", "riscv64", "riscv64", ArchiveKindTarGz},
+	{"illumos", "amd64", "amd64", ArchiveKindTarGz},
+	{"linux", "386", "386", ArchiveKindTarGz},
+	{"linux", "amd64", "amd64", ArchiveKindTarGz},
+	{"linux", "arm", "armv6l", ArchiveKindTarGz},
+	{"linux", "arm64", "arm64", ArchiveKindTarGz},
+	{"linux", "loong64", "loong64", ArchiveKindTarGz},
+	{"linux", "mips", "mips", ArchiveKindTarGz},
+	{"linux", "mips64", "mips64", ArchiveKindTarGz},
+	{"linux", "mips64le", "mips64le", ArchiveKindTarGz},
+	{"linux", "mipsle", "mipsle", ArchiveKindTarGz},
+	{"linux", "ppc64", "ppc64", ArchiveKindTarGz},
+	{"linux", "ppc64le", "ppc64le", ArchiveKindTarGz},
+	{"linux", "riscv64", "riscv64", ArchiveKindTarGz},
+	{"linux", "s390x", "s390x", ArchiveKindTarGz},
+	{"netbsd", "386", "386", ArchiveKindTarGz},
+	{"netbsd", "amd64", "amd64", ArchiveKindTarGz},
+	{"netbsd", "arm", "armv6l", ArchiveKindTarGz},
+	{"netbsd", "arm64", "arm64", ArchiveKindTarGz},
+	{"openbsd", "386", "386", ArchiveKindTarGz},
+	{"openbsd", "amd64", "amd64", ArchiveKindTarGz},
+	{"openbsd", "arm", "armv6l", ArchiveKindTarGz},
+	{"openbsd", "arm64", "arm64", ArchiveKindTarGz},
+	{"openbsd", "ppc64", "ppc64", ArchiveKindTarGz},
+	{"openbsd", "riscv64", "riscv64", ArchiveKindTarGz},
+	{"plan9", "386", "386", ArchiveKindTarGz},
+	{"plan9", "amd64", "amd64", ArchiveKindTarGz},
+	{"plan9", "arm", "armv6l", ArchiveKindTarGz},
+	{"solaris", "amd64", "amd64", ArchiveKindTarGz},
+	{"windows", "386", "386", ArchiveKindZip},
+	{"windows", "amd64", "amd64", ArchiveKindZip},
+	{"windows", "arm", "armv6l", ArchiveKindZip},
+	{"windows", "arm64", "arm64", ArchiveKindZip},
+}
+
+// SupportedPlatforms 获取官方发布的所有平台
+func SupportedPlatforms() []Platform {
+	return append([]Platform(nil), platforms...)
+}
+
+// LookupPlatform 查找平台信息，官方未发布该平台的压缩包时返回 ErrorTypeUnsupported 错误
+func LookupPlatform(goos, goarch string) (Platform, error) {
+	osKnown := false
+	for _, platform := range platforms {
+		if platform.OS == goos {
+			if platform.Arch == goarch {
+				return platform, nil
+			}
+			osKnown = true
+		}
+	}
+
+	if !osKnown {
+		return Platform{}, NewInstallError(ErrorTypeUnsupported, fmt.Sprintf("不支持的操作系统: %s", goos), nil).
+			WithContext("os", goos)
+	}
+	return Platform{}, NewInstallError(ErrorTypeUnsupported, fmt.Sprintf("不支持的平台: %s/%s（官方未发布该平台的压缩包）", goos, goarch), nil).
+		WithContext("os", goos).
+		WithContext("arch", goarch)
+}
+
+// LookupPlatformByArtifact 根据发布文件中的操作系统和架构名称（如 armv6l）查找平台信息
+func LookupPlatformByArtifact(goos, artifactArch string) (Platform, bool) {
+	for _, platform := range platforms {
+		if platform.OS == goos && platform.ArtifactArch == artifactArch {
+			return platform, true
+		}
+	}
+	return Platform{}, false
+}
+
 // NewSystemDetector 创建系统检测服务实例
 func NewSystemDetector() SystemDetector {
 	return &SystemDetectorImpl{}
@@ -28,30 +136,20 @@ func NewSystemDetector() SystemDetector {
 
 // DetectOS 检测操作系统
 func (s *SystemDetectorImpl) DetectOS() (string, error) {
-	switch runtime.GOOS {
-	case "windows":
-		return "windows", nil
-	case "linux":
-		return "linux", nil
-	case "darwin":
-		return "darwin", nil
-	default:
-		return "", fmt.Errorf("不支持的操作系统: %s", runtime.GOOS)
+	for _, platform := range platforms {
+		if platform.OS == runtime.GOOS {
+			return runtime.GOOS, nil
+		}
 	}
+	return "", NewInstallError(ErrorTypeUnsupported, fmt.Sprintf("不支持的操作系统: %s", runtime.GOOS), nil)
 }
 
-// DetectArch 检测CPU架构
+// DetectArch 检测CPU架构，操作系统和架构的组合必须是官方发布的平台
 func (s *SystemDetectorImpl) DetectArch() (string, error) {
-	switch runtime.GOARCH {
-	case "amd64":
-		return "amd64", nil
-	case "arm64":
-		return "arm64", nil
-	case "386":
-		return "386", nil
-	default:
-		return "", fmt.Errorf("不支持的CPU架构: %s", runtime.GOARCH)
+	if _, err := LookupPlatform(runtime.GOOS, runtime.GOARCH); err != nil {
+		return "", NewInstallError(ErrorTypeUnsupported, fmt.Sprintf("不支持的CPU架构: %s", runtime.GOARCH), err)
 	}
+	return runtime.GOARCH, nil
 }
 
 // GetDownloadURL 构建Go官方下载URL
@@ -61,50 +159,35 @@ func (s *SystemDetectorImpl) GetDownloadURL(version, os, arch string) string {
 }
 
 // GetDownloadURLWithMirror 使用镜像构建下载URL
+// mirror 可以是URL模板、镜像的基础URL或预设镜像名称，未知名称或空值时使用官方源
 func (s *SystemDetectorImpl) GetDownloadURLWithMirror(version, os, arch, mirror string) string {
 	filename := s.GetExpectedFilename(version, os, arch)
 
-	// 根据镜像名称构建URL
-	switch mirror {
-	case "official":
-		return fmt.Sprintf("https://golang.org/dl/%s", filename)
-	case "goproxy-cn":
-		return fmt.Sprintf("https://goproxy.cn/golang/%s", filename)
-	case "aliyun":
-		return fmt.Sprintf("https://mirrors.aliyun.com/golang/%s", filename)
-	case "tencent":
-		return fmt.Sprintf("https://mirrors.cloud.tencent.com/golang/%s", filename)
-	case "huawei":
-		return fmt.Sprintf("https://mirrors.huaweicloud.com/golang/%s", filename)
-	default:
-		// 如果是自定义镜像URL（包含http://或https://），直接拼接
-		if mirror != "" && (containsString(mirror, "http://") || containsString(mirror, "https://")) {
-			// 确保镜像URL以/结尾
-			if mirror[len(mirror)-1] != '/' {
-				mirror += "/"
-			}
-			return fmt.Sprintf("%s%s", mirror, filename)
+	// 自定义镜像URL：模板替换占位符，基础URL直接拼接文件名
+	if strings.HasPrefix(mirror, "http://") || strings.HasPrefix(mirror, "https://") {
+		if IsURLTemplate(mirror) {
+			return ExpandURLTemplate(mirror, version, os, arch)
+		}
+		return Mirror{BaseURL: mirror}.DownloadURL(filename)
+	}
+
+	// 预设镜像名称从镜像列表中查找基础URL
+	for _, m := range getDefaultMirrors() {
+		if m.Name == mirror {
+			return m.DownloadURL(filename)
 		}
-		// 未知镜像名称或空镜像，默认使用官方源
-		return s.GetDownloadURL(version, os, arch)
 	}
+
+	return s.GetDownloadURL(version, os, arch)
 }
 
 // GetExpectedFilename 获取预期的下载文件名
+// 已知平台使用平台表中的发布名称和压缩包格式，未知平台按 go<版本>.<os>-<arch>.tar.gz 构建
 func (s *SystemDetectorImpl) GetExpectedFilename(version, os, arch string) string {
-	switch os {
-	case "windows":
-		if arch == "386" {
-			return fmt.Sprintf("go%s.windows-386.zip", version)
-		}
-		return fmt.Sprintf("go%s.windows-%s.zip", version, arch)
-	case "linux":
-		return fmt.Sprintf("go%s.linux-%s.tar.gz", version, arch)
-	case "darwin":
-		return fmt.Sprintf("go%s.darwin-%s.tar.gz", version, arch)
-	default:
-		return fmt.Sprintf("go%s.%s-%s.tar.gz", version, os, arch)
+	if platform, err := LookupPlatform(os, arch); err == nil {
+		return platform.Filename(version)
 	}
+	return fmt.Sprintf("go%s.%s-%s.tar.gz", version, os, arch)
 }
 
 // GetSystemInfo 获取完整的系统信息
@@ -124,6 +207,18 @@ func (s *SystemDetectorImpl) GetSystemInfoWithMirror(version, mirror string) (*m
 		return nil, fmt.Errorf("检测CPU架构失败: %v", err)
 	}
 
+	return s.GetSystemInfoForPlatform(version, os, arch, mirror)
+}
+
+// GetSystemInfoForPlatform 获取指定平台的系统信息，用于下载或安装其他平台的Go
+func (s *SystemDetectorImpl) GetSystemInfoForPlatform(version, os, arch, mirror string) (*model.SystemInfo, error) {
+	if os == "" || arch == "" {
+		return nil, fmt.Errorf("目标平台不完整: %s/%s", os, arch)
+	}
+	if _, err := LookupPlatform(os, arch); err != nil {
+		return nil, err
+	}
+
 	filename := s.GetExpectedFilename(version, os, arch)
 	url := s.GetDownloadURLWithMirror(version, os, arch, mirror)
 
@@ -152,8 +247,16 @@ func (s *SystemDetectorImpl) ValidateMirrorURL(mirrorBaseURL, version, os, arch
 		return fmt.Errorf("构建的下载URL为空")
 	}
 
-	// 检查是否包含预期的文件名
-	if !containsString(fullURL, filename) {
+	// URL模板的目录结构不固定，只要求模板合法且地址中包含版本号
+	if IsURLTemplate(mirrorBaseURL) {
+		if err := ValidateURLTemplate(mirrorBaseURL); err != nil {
+			return err
+		}
+		if !containsString(fullURL, version) {
+			return fmt.Errorf("构建的URL不包含版本号: %s", version)
+		}
+	} else if !containsString(fullURL, filename) {
+		// 检查是否包含预期的文件名
 		return fmt.Errorf("构建的URL不包含预期的文件名: %s", filename)
 	}
 
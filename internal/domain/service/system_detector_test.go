@@ -1,6 +1,7 @@
 package service
 
 import (
+	"errors"
 	"runtime"
 	"strings"
 	"testing"
@@ -394,3 +395,157 @@ func TestContainsStringFunction(t *testing.T) {
 		})
 	}
 }
+
+func TestSystemDetectorImpl_GetSystemInfoForPlatform(t *testing.T) {
+	detector := NewSystemDetector()
+
+	systemInfo, err := detector.GetSystemInfoForPlatform("1.22.7", "windows", "arm64", "aliyun")
+	if err != nil {
+		t.Fatalf("GetSystemInfoForPlatform() 返回错误: %v", err)
+	}
+	if systemInfo.OS != "windows" || systemInfo.Arch != "arm64" {
+		t.Errorf("平台 = %s/%s, 期望 windows/arm64", systemInfo.OS, systemInfo.Arch)
+	}
+	if systemInfo.Filename != "go1.22.7.windows-arm64.zip" {
+		t.Errorf("SystemInfo.Filename = %s, 期望 go1.22.7.windows-arm64.zip", systemInfo.Filename)
+	}
+	if systemInfo.URL != "https://mirrors.aliyun.com/golang/go1.22.7.windows-arm64.zip" {
+		t.Errorf("SystemInfo.URL = %s", systemInfo.URL)
+	}
+
+	if _, err := detector.GetSystemInfoForPlatform("1.22.7", "", "arm64", "official"); err == nil {
+		t.Error("平台不完整时应返回错误")
+	}
+}
+
+func TestSystemDetectorImpl_PublishedPlatforms(t *testing.T) {
+	detector := &SystemDetectorImpl{}
+
+	testCases := []struct {
+		os       string
+		arch     string
+		expected string
+	}{
+		{"aix", "ppc64", "go1.23.2.aix-ppc64.tar.gz"},
+		{"darwin", "amd64", "go1.23.2.darwin-amd64.tar.gz"},
+		{"darwin", "arm64", "go1.23.2.darwin-arm64.tar.gz"},
+		{"dragonfly", "amd64", "go1.23.2.dragonfly-amd64.tar.gz"},
+		{"freebsd", "386", "go1.23.2.freebsd-386.tar.gz"},
+		{"freebsd", "amd64", "go1.23.2.freebsd-amd64.tar.gz"},
+		{"freebsd", "arm", "go1.23.2.freebsd-armv6l.tar.gz"},
+		{"freebsd", "arm64", "go1.23.2.freebsd-arm64.tar.gz"},
+		{"freebsd", "riscv64", "go1.23.2.freebsd-riscv64.tar.gz"},
+		{"illumos", "amd64", "go1.23.2.illumos-amd64.tar.gz"},
+		{"linux", "386", "go1.23.2.linux-386.tar.gz"},
+		{"linux", "amd64", "go1.23.2.linux-amd64.tar.gz"},
+		{"linux", "arm", "go1.23.2.linux-armv6l.tar.gz"},
+		{"linux", "arm64", "go1.23.2.linux-arm64.tar.gz"},
+		{"linux", "loong64", "go1.23.2.linux-loong64.tar.gz"},
+		{"linux", "mips", "go1.23.2.linux-mips.tar.gz"},
+		{"linux", "mips64", "go1.23.2.linux-mips64.tar.gz"},
+		{"linux", "mips64le", "go1.23.2.linux-mips64le.tar.gz"},
+		{"linux", "mipsle", "go1.23.2.linux-mipsle.tar.gz"},
+		{"linux", "ppc64", "go1.23.2.linux-ppc64.tar.gz"},
+		{"linux", "ppc64le", "go1.23.2.linux-ppc64le.tar.gz"},
+		{"linux", "riscv64", "go1.23.2.linux-riscv64.tar.gz"},
+		{"linux", "s390x", "go1.23.2.linux-s390x.tar.gz"},
+		{"netbsd", "386", "go1.23.2.netbsd-386.tar.gz"},
+		{"netbsd", "amd64", "go1.23.2.netbsd-amd64.tar.gz"},
+		{"netbsd", "arm", "go1.23.2.netbsd-armv6l.tar.gz"},
+		{"netbsd", "arm64", "go1.23.2.netbsd-arm64.tar.gz"},
+		{"openbsd", "386", "go1.23.2.openbsd-386.tar.gz"},
+		{"openbsd", "amd64", "go1.23.2.openbsd-amd64.tar.gz"},
+		{"openbsd", "arm", "go1.23.2.openbsd-armv6l.tar.gz"},
+		{"openbsd", "arm64", "go1.23.2.openbsd-arm64.tar.gz"},
+		{"openbsd", "ppc64", "go1.23.2.openbsd-ppc64.tar.gz"},
+		{"openbsd", "riscv64", "go1.23.2.openbsd-riscv64.tar.gz"},
+		{"plan9", "386", "go1.23.2.plan9-386.tar.gz"},
+		{"plan9", "amd64", "go1.23.2.plan9-amd64.tar.gz"},
+		{"plan9", "arm", "go1.23.2.plan9-armv6l.tar.gz"},
+		{"solaris", "amd64", "go1.23.2.solaris-amd64.tar.gz"},
+		{"windows", "386", "go1.23.2.windows-386.zip"},
+		{"windows", "amd64", "go1.23.2.windows-amd64.zip"},
+		{"windows", "arm", "go1.23.2.windows-armv6l.zip"},
+		{"windows", "arm64", "go1.23.2.windows-arm64.zip"},
+	}
+
+	if len(testCases) != len(SupportedPlatforms()) {
+		t.Errorf("测试用例数量 %d 与平台表 %d 不一致", len(testCases), len(SupportedPlatforms()))
+	}
+
+	// 模拟发布目录中 go1.23.2 的压缩包列表
+	release := GoRelease{Version: "go1.23.2", Stable: true}
+	for _, tc := range testCases {
+		platform, err := LookupPlatform(tc.os, tc.arch)
+		if err != nil {
+			t.Fatalf("LookupPlatform(%s, %s) 返回错误: %v", tc.os, tc.arch, err)
+		}
+		release.Files = append(release.Files, GoReleaseFile{
+			Filename: tc.expected,
+			OS:       tc.os,
+			Arch:     platform.ArtifactArch,
+			Kind:     "archive",
+		})
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.os+"/"+tc.arch, func(t *testing.T) {
+			if result := detector.GetExpectedFilename("1.23.2", tc.os, tc.arch); result != tc.expected {
+				t.Errorf("GetExpectedFilename() = %s, 期望 %s", result, tc.expected)
+			}
+
+			systemInfo, err := detector.GetSystemInfoForPlatform("1.23.2", tc.os, tc.arch, "official")
+			if err != nil {
+				t.Fatalf("GetSystemInfoForPlatform() 返回错误: %v", err)
+			}
+			if systemInfo.Arch != tc.arch || !strings.HasSuffix(systemInfo.URL, tc.expected) {
+				t.Errorf("系统信息不正确: %+v", systemInfo)
+			}
+
+			file, exists := release.FindArchive(tc.os, tc.arch)
+			if !exists || file.Filename != tc.expected {
+				t.Errorf("FindArchive() = %+v, 期望 %s", file, tc.expected)
+			}
+		})
+	}
+
+	if unknown := release.UnknownPlatforms(); len(unknown) != 0 {
+		t.Errorf("发布目录中存在平台表未收录的平台: %v", unknown)
+	}
+}
+
+func TestLookupPlatform_Unsupported(t *testing.T) {
+	testCases := []struct {
+		os   string
+		arch string
+	}{
+		{"linux", "wasm"},
+		{"darwin", "386"},
+		{"windows", "riscv64"},
+		{"js", "wasm"},
+		{"android", "arm64"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.os+"/"+tc.arch, func(t *testing.T) {
+			_, err := LookupPlatform(tc.os, tc.arch)
+			var installErr *InstallError
+			if !errors.As(err, &installErr) || installErr.Type != ErrorTypeUnsupported {
+				t.Errorf("期望返回 ErrorTypeUnsupported 错误，实际 %v", err)
+			}
+
+			if _, err := NewSystemDetector().GetSystemInfoForPlatform("1.23.2", tc.os, tc.arch, "official"); err == nil {
+				t.Error("GetSystemInfoForPlatform() 应返回错误")
+			}
+			if _, _, err := ParsePlatform(tc.os + "/" + tc.arch); err == nil {
+				t.Error("ParsePlatform() 应返回错误")
+			}
+		})
+	}
+
+	// 发布目录中出现平台表未收录的平台时能够发现
+	release := GoRelease{Files: []GoReleaseFile{{OS: "linux", Arch: "sparc64", Kind: "archive"}, {Kind: "source"}}}
+	if unknown := release.UnknownPlatforms(); len(unknown) != 1 || unknown[0] != "linux/sparc64" {
+		t.Errorf("UnknownPlatforms() = %v", unknown)
+	}
+}
@@ -0,0 +1,146 @@
+package service
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"version-list/internal/domain/model"
+)
+
+// IsolatedEnvironment 按版本隔离的 GOBIN、GOCACHE 等目录
+type IsolatedEnvironment struct {
+	Version string // 版本列表中的名称
+	Path    string // 目录路径
+	Size    int64  // 占用空间（字节）
+}
+
+// IsolationDir 获取按版本隔离的目录的根目录 ~/.go-version/isolated
+func IsolationDir() string {
+	return filepath.Join(goVersionHomeDir(), "isolated")
+}
+
+// versionIsolationDir 获取指定版本的隔离目录
+func versionIsolationDir(version string) string {
+	return filepath.Join(IsolationDir(), version)
+}
+
+// versionIsolationBin 获取按版本隔离时指定版本的 GOBIN
+func versionIsolationBin(version string) string {
+	return filepath.Join(versionIsolationDir(version), "bin")
+}
+
+// applyIsolation 按隔离方式设置 GOBIN、GOCACHE 和 GOMODCACHE：
+// 按版本隔离时指向 ~/.go-version/isolated/<版本> 下的目录，否则 GOBIN 使用 GOROOT/bin，缓存使用 go 的默认值
+func applyIsolation(env *model.Environment, version *model.GoVersion) error {
+	if !env.IsVersionIsolated() {
+		env.GOBIN = filepath.Join(env.GOROOT, "bin")
+		env.GOCACHE = ""
+		env.GOMODCACHE = ""
+		return nil
+	}
+
+	dir := versionIsolationDir(version.Version)
+	env.GOBIN = versionIsolationBin(version.Version)
+	env.GOCACHE = filepath.Join(dir, "cache")
+	env.GOMODCACHE = ""
+	if env.IsolateModCache {
+		env.GOMODCACHE = filepath.Join(dir, "pkg", "mod")
+	}
+	for _, path := range []string{env.GOBIN, env.GOCACHE} {
+		if err := os.MkdirAll(path, 0755); err != nil {
+			return fmt.Errorf("创建隔离目录失败: %v", err)
+		}
+	}
+	return nil
+}
+
+// isolationEnvVars 获取指定版本在按版本隔离时的 GOBIN、GOCACHE、GOMODCACHE 环境变量，未隔离时返回 nil
+func (s *VersionService) isolationEnvVars(version *model.GoVersion) ([]string, error) {
+	current, err := s.environmentRepo.Get()
+	if err != nil {
+		return nil, fmt.Errorf("获取环境变量配置失败: %v", err)
+	}
+	if !current.IsVersionIsolated() {
+		return nil, nil
+	}
+
+	env := &model.Environment{GOROOT: version.Path, Isolation: current.Isolation, IsolateModCache: current.IsolateModCache}
+	if err := applyIsolation(env, version); err != nil {
+		return nil, err
+	}
+	vars := []string{"GOBIN=" + env.GOBIN, "GOCACHE=" + env.GOCACHE}
+	if env.GOMODCACHE != "" {
+		vars = append(vars, "GOMODCACHE="+env.GOMODCACHE)
+	}
+	return vars, nil
+}
+
+// SetIsolation 设置 GOBIN、GOCACHE 的隔离方式，并按新的方式更新当前版本的环境变量配置
+func (s *VersionService) SetIsolation(mode model.IsolationMode, isolateModCache bool) (*model.Environment, error) {
+	env, err := s.environmentRepo.Get()
+	if err != nil {
+		return nil, fmt.Errorf("获取环境变量配置失败: %v", err)
+	}
+	env.Isolation = mode
+	env.IsolateModCache = isolateModCache && mode == model.IsolationVersion
+
+	if active, err := s.versionRepo.FindActive(); err == nil && env.GOROOT != "" {
+		if err := applyIsolation(env, active); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.environmentRepo.Save(env); err != nil {
+		return nil, fmt.Errorf("保存环境变量配置失败: %v", err)
+	}
+	return env, nil
+}
+
+// Environment 获取当前的环境变量配置
+func (s *VersionService) Environment() (*model.Environment, error) {
+	return s.environmentRepo.Get()
+}
+
+// CollectGarbage 清理已移除版本的隔离目录，dryRun 为 true 时只返回将被清理的目录
+func (s *VersionService) CollectGarbage(dryRun bool) ([]IsolatedEnvironment, error) {
+	entries, err := os.ReadDir(IsolationDir())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取隔离目录失败: %v", err)
+	}
+
+	pathManager := NewPathManager()
+	var orphaned []IsolatedEnvironment
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		if _, err := s.versionRepo.FindByVersion(entry.Name()); err == nil {
+			continue
+		}
+
+		path := filepath.Join(IsolationDir(), entry.Name())
+		size, _ := pathManager.GetDirectorySize(path)
+		if !dryRun {
+			if err := removeIsolationDir(path); err != nil {
+				return orphaned, fmt.Errorf("删除 %s 失败: %v", path, err)
+			}
+		}
+		orphaned = append(orphaned, IsolatedEnvironment{Version: entry.Name(), Path: path, Size: size})
+	}
+	return orphaned, nil
+}
+
+// removeIsolationDir 删除隔离目录，模块缓存中的目录是只读的，需要先恢复写权限
+func removeIsolationDir(path string) error {
+	filepath.WalkDir(path, func(p string, entry fs.DirEntry, err error) error {
+		if err == nil && entry.IsDir() {
+			os.Chmod(p, 0755)
+		}
+		return nil
+	})
+	return os.RemoveAll(path)
+}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"version-list/internal/domain/model"
+)
+
+func TestVersionService_UseAppliesIsolation(t *testing.T) {
+	service, versionRepo := newTestVersionService(t, "")
+	envRepo := service.environmentRepo.(*MockEnvironmentRepository)
+	versionRepo.Save(&model.GoVersion{Version: "1.21.0", Path: t.TempDir()})
+	versionRepo.Save(&model.GoVersion{Version: "1.22.0", Path: t.TempDir()})
+
+	if err := service.Use("1.21.0"); err != nil {
+		t.Fatalf("切换版本失败: %v", err)
+	}
+	current := filepath.Join(goVersionHomeDir(), "current")
+	if envRepo.env.GOBIN != filepath.Join(current, "bin") || envRepo.env.GOCACHE != "" {
+		t.Errorf("未隔离时 GOBIN = %s, GOCACHE = %s", envRepo.env.GOBIN, envRepo.env.GOCACHE)
+	}
+
+	if _, err := service.SetIsolation(model.IsolationVersion, true); err != nil {
+		t.Fatalf("设置隔离方式失败: %v", err)
+	}
+	dir := filepath.Join(IsolationDir(), "1.21.0")
+	if envRepo.env.GOBIN != filepath.Join(dir, "bin") || envRepo.env.GOMODCACHE != filepath.Join(dir, "pkg", "mod") {
+		t.Errorf("设置隔离方式后应更新当前版本的配置, 实际 %+v", envRepo.env)
+	}
+
+	if err := service.Use("1.22.0"); err != nil {
+		t.Fatalf("切换版本失败: %v", err)
+	}
+	dir = filepath.Join(IsolationDir(), "1.22.0")
+	if envRepo.env.GOBIN != filepath.Join(dir, "bin") || envRepo.env.GOCACHE != filepath.Join(dir, "cache") {
+		t.Errorf("按版本隔离时 GOBIN = %s, GOCACHE = %s", envRepo.env.GOBIN, envRepo.env.GOCACHE)
+	}
+	if envRepo.env.GOROOT != current {
+		t.Errorf("GOROOT = %s, 期望 %s", envRepo.env.GOROOT, current)
+	}
+	for _, path := range []string{envRepo.env.GOBIN, envRepo.env.GOCACHE} {
+		if info, err := os.Stat(path); err != nil || !info.IsDir() {
+			t.Errorf("应创建隔离目录 %s", path)
+		}
+	}
+
+	if _, err := service.SetIsolation(model.IsolationGlobal, true); err != nil {
+		t.Fatalf("设置隔离方式失败: %v", err)
+	}
+	if envRepo.env.GOBIN != filepath.Join(current, "bin") || envRepo.env.GOCACHE != "" || envRepo.env.GOMODCACHE != "" || envRepo.env.IsolateModCache {
+		t.Errorf("恢复为共用后 = %+v", envRepo.env)
+	}
+}
+
+func TestVersionService_HookEnvironmentUsesIsolation(t *testing.T) {
+	service, _ := newTestVersionService(t, "")
+	if _, err := service.SetIsolation(model.IsolationVersion, false); err != nil {
+		t.Fatalf("设置隔离方式失败: %v", err)
+	}
+
+	version := &model.GoVersion{Version: "1.21.0", Path: t.TempDir()}
+	env, err := service.hookEnvironment(model.HookPostInstall, version)
+	if err != nil {
+		t.Fatalf("获取钩子环境失败: %v", err)
+	}
+
+	joined := strings.Join(env, "\n")
+	gobin := filepath.Join(IsolationDir(), "1.21.0", "bin")
+	if !strings.Contains(joined, "GOBIN="+gobin) || !strings.Contains(joined, string(os.PathListSeparator)+gobin+string(os.PathListSeparator)) {
+		t.Errorf("钩子环境应使用隔离的 GOBIN 并加入 PATH")
+	}
+	if strings.Contains(joined, "GOMODCACHE=") {
+		t.Error("未启用 --modcache 时不应设置 GOMODCACHE")
+	}
+}
+
+func TestVersionService_CollectGarbage(t *testing.T) {
+	service, versionRepo := newTestVersionService(t, "")
+	versionRepo.Save(&model.GoVersion{Version: "1.22.0", Path: t.TempDir()})
+
+	for _, version := range []string{"1.21.0", "1.22.0"} {
+		writeTestFile(t, mkdirAll(t, filepath.Join(IsolationDir(), version, "bin")), "gopls", "binary")
+	}
+	// 模块缓存中的目录是只读的
+	modDir := mkdirAll(t, filepath.Join(IsolationDir(), "1.21.0", "pkg", "mod", "example.com", "m@v1.0.0"))
+	writeTestFile(t, modDir, "go.mod", "module example.com/m\n")
+	os.Chmod(modDir, 0555)
+
+	orphaned, err := service.CollectGarbage(true)
+	if err != nil {
+		t.Fatalf("列出待清理目录失败: %v", err)
+	}
+	if len(orphaned) != 1 || orphaned[0].Version != "1.21.0" || orphaned[0].Size == 0 {
+		t.Fatalf("待清理目录 = %+v, 期望只有已移除的 1.21.0", orphaned)
+	}
+	if _, err := os.Stat(orphaned[0].Path); err != nil {
+		t.Error("--dry-run 时不应删除目录")
+	}
+
+	if _, err := service.CollectGarbage(false); err != nil {
+		t.Fatalf("清理失败: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(IsolationDir(), "1.21.0")); !os.IsNotExist(err) {
+		t.Error("应删除已移除版本的目录")
+	}
+	if _, err := os.Stat(filepath.Join(IsolationDir(), "1.22.0", "bin", "gopls")); err != nil {
+		t.Error("不应删除已安装版本的目录")
+	}
+}
+
+// mkdirAll 创建目录并返回路径
+func mkdirAll(t *testing.T, path string) string {
+	t.Helper()
+	if err := os.MkdirAll(path, 0755); err != nil {
+		t.Fatalf("创建目录失败: %v", err)
+	}
+	return path
+}
@@ -0,0 +1,222 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+	"time"
+	"version-list/internal/domain/model"
+)
+
+// DefaultLockFileName 团队锁文件的默认文件名
+const DefaultLockFileName = "go-version.lock"
+
+// LockOptions 生成锁文件的选项
+type LockOptions struct {
+	Versions   []string // 锁定的版本，为空时使用已安装的发布版本
+	Platforms  []string // 锁定的平台（os/arch），为空时使用当前平台
+	CatalogURL string   // 发布目录地址，为空时使用官方发布目录
+	AllowCache bool     // 发布目录中没有时使用下载缓存中压缩包的校验和，条目标记为未经官方确认
+}
+
+// LoadLockFile 读取锁文件
+func LoadLockFile(path string) (*model.LockFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取锁文件失败: %w", err)
+	}
+
+	var lock model.LockFile
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return nil, fmt.Errorf("解析锁文件失败: %v", err)
+	}
+	if lock.FormatVersion > model.LockFormatVersion {
+		return nil, fmt.Errorf("不支持的锁文件格式版本 %d，请升级 go-version", lock.FormatVersion)
+	}
+	for _, entry := range lock.Entries {
+		if entry.Version == "" || entry.OS == "" || entry.Arch == "" || entry.SHA256 == "" {
+			return nil, fmt.Errorf("锁文件条目不完整: %+v", entry)
+		}
+	}
+	return &lock, nil
+}
+
+// SaveLockFile 保存锁文件，条目按版本号和平台排序，便于在版本控制中比较差异
+func SaveLockFile(path string, lock *model.LockFile) error {
+	lock.Sort()
+	data, err := json.MarshalIndent(lock, "", "  ")
+	if err != nil {
+		return fmt.Errorf("序列化锁文件失败: %v", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("写入锁文件失败: %v", err)
+	}
+	return nil
+}
+
+// GenerateLock 生成锁文件：校验和取自官方发布目录，发布目录使用下载服务的HTTP客户端（代理、CA和认证配置）获取
+// 发布目录中没有时（如无法访问网络）只有 options.AllowCache 为 true 才使用下载缓存中的压缩包，并将条目标记为未经官方确认
+func (s *VersionService) GenerateLock(ctx context.Context, options *LockOptions) (*model.LockFile, error) {
+	if options == nil {
+		options = &LockOptions{}
+	}
+
+	versions := options.Versions
+	if len(versions) == 0 {
+		installed, err := s.installedReleaseVersions()
+		if err != nil {
+			return nil, err
+		}
+		versions = installed
+	}
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("没有要锁定的版本，请指定版本号或先安装Go版本")
+	}
+
+	platformNames := options.Platforms
+	if len(platformNames) == 0 {
+		platformNames = []string{runtime.GOOS + "/" + runtime.GOARCH}
+	}
+	var platforms []Platform
+	for _, name := range platformNames {
+		goos, goarch, err := ParsePlatform(name)
+		if err != nil {
+			return nil, err
+		}
+		platform, _ := LookupPlatform(goos, goarch)
+		platforms = append(platforms, platform)
+	}
+
+	releases, catalogErr := s.fetchReleaseIndex(ctx, options.CatalogURL)
+
+	official, err := s.mirrorService.GetMirrorByName("official")
+	if err != nil {
+		return nil, fmt.Errorf("获取官方镜像失败: %v", err)
+	}
+
+	cache := s.downloadCache()
+	lock := &model.LockFile{
+		FormatVersion: model.LockFormatVersion,
+		GeneratedAt:   time.Now().UTC().Truncate(time.Second),
+	}
+	for _, version := range versions {
+		for _, platform := range platforms {
+			entry := model.LockEntry{
+				Version:  version,
+				OS:       platform.OS,
+				Arch:     platform.Arch,
+				Filename: platform.Filename(version),
+			}
+
+			if file, found := releases[version].FindArchive(platform.OS, platform.Arch); found {
+				entry.Filename = file.Filename
+				entry.SHA256 = file.SHA256
+				entry.Size = file.Size
+				entry.URL = official.DownloadURL(file.Filename)
+			} else if cached, _, err := cache.LookupFilename(entry.Filename); err == nil {
+				if !options.AllowCache {
+					reason := "发布目录中没有该压缩包"
+					if catalogErr != nil {
+						reason = catalogErr.Error()
+					}
+					return nil, fmt.Errorf("无法锁定 Go %s（%s）: %s；下载缓存中的 %s 未经官方校验，确认可信后使用 --allow-cache 锁定", version, platform, reason, entry.Filename)
+				}
+				entry.SHA256 = cached.SHA256
+				entry.Size = cached.Size
+				entry.URL = official.DownloadURL(entry.Filename)
+				if len(cached.URLs) > 0 {
+					entry.URL = cached.URLs[0]
+				}
+				entry.Unverified = true
+			} else {
+				if catalogErr != nil {
+					return nil, fmt.Errorf("无法锁定 Go %s（%s）: 下载缓存中没有 %s，且%v", version, platform, entry.Filename, catalogErr)
+				}
+				return nil, fmt.Errorf("无法锁定 Go %s（%s）: 发布目录和下载缓存中均没有 %s", version, platform, entry.Filename)
+			}
+
+			lock.Entries = append(lock.Entries, entry)
+		}
+	}
+
+	lock.Sort()
+	return lock, nil
+}
+
+// installedReleaseVersions 获取已安装的发布版本号（不包括自定义名称的工具链和开发版本）
+func (s *VersionService) installedReleaseVersions() ([]string, error) {
+	installed, err := s.versionRepo.FindAll()
+	if err != nil {
+		return nil, fmt.Errorf("获取已安装版本失败: %v", err)
+	}
+	installed = model.SortVersions(installed, &model.VersionSorter{Field: "version", Direction: "asc"})
+
+	var versions []string
+	for _, version := range installed {
+		if version.IsNamed() || releaseNumber(version.Version) == "" {
+			continue
+		}
+		versions = append(versions, version.Version)
+	}
+	return versions, nil
+}
+
+// InstallLocked 按锁文件安装指定版本
+// 压缩包的SHA-256必须与锁文件一致，即使镜像提供了不同的文件也会拒绝安装，且不能跳过验证
+func (s *VersionService) InstallLocked(ctx context.Context, version string, lock *model.LockFile, options *model.InstallOptions, progressUI ProgressReporter) (*model.InstallationResult, error) {
+	locked := model.InstallOptions{}
+	if options != nil {
+		locked = *options
+	}
+
+	goos, goarch := runtime.GOOS, runtime.GOARCH
+	if locked.TargetSystem != nil {
+		goos, goarch = locked.TargetSystem.OS, locked.TargetSystem.Arch
+	}
+	entry, found := lock.Find(version, goos, goarch)
+	if !found {
+		return nil, fmt.Errorf("锁文件中没有 Go %s（%s/%s）", version, goos, goarch)
+	}
+	if locked.SHA256 != "" && !strings.EqualFold(locked.SHA256, entry.SHA256) {
+		return nil, fmt.Errorf("指定的校验和 %s 与锁文件中的 %s 不一致", locked.SHA256, entry.SHA256)
+	}
+
+	locked.SHA256 = entry.SHA256
+	locked.SkipVerification = false
+	locked.Locked = true
+	return s.InstallOnlineWithContext(ctx, version, &locked, progressUI)
+}
+
+// CheckLockDrift 对比锁文件与已安装版本的下载记录，报告当前平台上校验和不一致、未记录校验和以及未安装的版本
+func (s *VersionService) CheckLockDrift(lock *model.LockFile) ([]model.LockDrift, error) {
+	var drifts []model.LockDrift
+	for _, version := range lock.Versions() {
+		entry, found := lock.Find(version, runtime.GOOS, runtime.GOARCH)
+		if !found {
+			continue
+		}
+
+		drift := model.LockDrift{
+			Version:  version,
+			Platform: entry.Platform(),
+			Expected: entry.SHA256,
+		}
+		installed, err := s.versionRepo.FindByVersion(version)
+		switch {
+		case err != nil:
+			drift.Reason = "未安装"
+		case installed.DownloadInfo == nil || installed.DownloadInfo.Checksum == "":
+			drift.Reason = "安装记录中没有校验和"
+		case !strings.EqualFold(installed.DownloadInfo.Checksum, entry.SHA256):
+			drift.Actual = installed.DownloadInfo.Checksum
+			drift.Reason = "校验和与锁文件不一致"
+		default:
+			continue
+		}
+		drifts = append(drifts, drift)
+	}
+	return drifts, nil
+}
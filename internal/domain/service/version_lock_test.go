@@ -0,0 +1,292 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+	"version-list/internal/domain/model"
+)
+
+// newLockCatalogServer 创建提供发布目录的测试服务器
+func newLockCatalogServer(t *testing.T, releases []GoRelease) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(releases)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// lockForArchive 创建锁定当前平台指定版本的锁文件
+func lockForArchive(version, checksum string) *model.LockFile {
+	return &model.LockFile{
+		FormatVersion: model.LockFormatVersion,
+		Entries: []model.LockEntry{{
+			Version:  version,
+			OS:       runtime.GOOS,
+			Arch:     runtime.GOARCH,
+			Filename: fmt.Sprintf("go%s.%s-%s.tar.gz", version, runtime.GOOS, runtime.GOARCH),
+			SHA256:   checksum,
+		}},
+	}
+}
+
+func TestLockFile_SaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), DefaultLockFileName)
+	lock := &model.LockFile{
+		FormatVersion: model.LockFormatVersion,
+		Entries: []model.LockEntry{
+			{Version: "1.22.10", OS: "linux", Arch: "amd64", SHA256: "c"},
+			{Version: "1.22.9", OS: "linux", Arch: "amd64", SHA256: "b"},
+			{Version: "1.22.9", OS: "darwin", Arch: "arm64", SHA256: "a"},
+		},
+	}
+	if err := SaveLockFile(path, lock); err != nil {
+		t.Fatalf("保存锁文件失败: %v", err)
+	}
+
+	loaded, err := LoadLockFile(path)
+	if err != nil {
+		t.Fatalf("读取锁文件失败: %v", err)
+	}
+	if got := strings.Join(loaded.Versions(), ","); got != "1.22.9,1.22.10" {
+		t.Errorf("版本 = %s, 期望按版本号排序", got)
+	}
+	if got := strings.Join(loaded.Platforms(), ","); got != "darwin/arm64,linux/amd64" {
+		t.Errorf("平台 = %s", got)
+	}
+	if loaded.Entries[0].SHA256 != "a" || loaded.Entries[2].Version != "1.22.10" {
+		t.Errorf("条目未按版本号和平台排序: %+v", loaded.Entries)
+	}
+	if entry, found := loaded.Find("1.22.9", "linux", "amd64"); !found || entry.SHA256 != "b" {
+		t.Errorf("Find 返回 %+v, %v", entry, found)
+	}
+
+	if _, err := LoadLockFile(filepath.Join(t.TempDir(), "missing.lock")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("锁文件不存在时应返回 os.ErrNotExist: %v", err)
+	}
+	writeTestFile(t, filepath.Dir(path), "bad.lock", `{"format_version":1,"entries":[{"version":"1.22.9"}]}`)
+	if _, err := LoadLockFile(filepath.Join(filepath.Dir(path), "bad.lock")); err == nil {
+		t.Error("条目不完整的锁文件应返回错误")
+	}
+}
+
+func TestVersionService_GenerateLock(t *testing.T) {
+	service, versionRepo := newTestVersionService(t, "http://127.0.0.1:0")
+	catalog := newLockCatalogServer(t, []GoRelease{{
+		Version: "go1.22.7",
+		Stable:  true,
+		Files: []GoReleaseFile{
+			{Filename: "go1.22.7.linux-amd64.tar.gz", OS: "linux", Arch: "amd64", SHA256: "aaaa", Size: 100, Kind: "archive"},
+			{Filename: "go1.22.7.windows-amd64.zip", OS: "windows", Arch: "amd64", SHA256: "bbbb", Size: 200, Kind: "archive"},
+		},
+	}})
+
+	lock, err := service.GenerateLock(context.Background(), &LockOptions{
+		Versions:   []string{"1.22.7"},
+		Platforms:  []string{"windows/amd64", "linux/amd64"},
+		CatalogURL: catalog.URL,
+	})
+	if err != nil {
+		t.Fatalf("生成锁文件失败: %v", err)
+	}
+	if len(lock.Entries) != 2 || lock.FormatVersion != model.LockFormatVersion {
+		t.Fatalf("锁文件 = %+v, 期望2个条目", lock)
+	}
+	entry, _ := lock.Find("1.22.7", "windows", "amd64")
+	if entry == nil || entry.SHA256 != "bbbb" || entry.Size != 200 || !strings.HasSuffix(entry.URL, "/go1.22.7.windows-amd64.zip") {
+		t.Errorf("windows 条目 = %+v", entry)
+	}
+
+	// 未指定版本时使用已安装的发布版本，跳过自定义名称的工具链
+	versionRepo.Save(&model.GoVersion{Version: "1.22.7", Path: t.TempDir()})
+	versionRepo.Save(&model.GoVersion{Version: "patched", Release: "1.22.7", Path: t.TempDir()})
+	lock, err = service.GenerateLock(context.Background(), &LockOptions{Platforms: []string{"linux/amd64"}, CatalogURL: catalog.URL})
+	if err != nil {
+		t.Fatalf("生成锁文件失败: %v", err)
+	}
+	if got := strings.Join(lock.Versions(), ","); got != "1.22.7" {
+		t.Errorf("锁定的版本 = %s, 期望 1.22.7", got)
+	}
+
+	// 发布目录中没有且缓存中也没有时返回错误
+	if _, err := service.GenerateLock(context.Background(), &LockOptions{Versions: []string{"1.21.0"}, Platforms: []string{"linux/amd64"}, CatalogURL: catalog.URL}); err == nil {
+		t.Error("无法获取校验和时应返回错误")
+	}
+}
+
+func TestVersionService_GenerateLock_FromCache(t *testing.T) {
+	service, _ := newTestVersionService(t, "http://127.0.0.1:0")
+	filename := fmt.Sprintf("go1.22.7.%s-%s.tar.gz", runtime.GOOS, runtime.GOARCH)
+	archivePath, checksum := writeFakeGoArchive(t, filename, "1.22.7")
+	if _, err := service.downloadCache().Store("https://mirror.example.com/go/"+filename, archivePath); err != nil {
+		t.Fatalf("写入缓存失败: %v", err)
+	}
+
+	// 发布目录无法访问时，未允许使用缓存则拒绝锁定
+	unavailable := httptest.NewServer(http.NotFoundHandler())
+	defer unavailable.Close()
+	_, err := service.GenerateLock(context.Background(), &LockOptions{Versions: []string{"1.22.7"}, CatalogURL: unavailable.URL})
+	if err == nil || !strings.Contains(err.Error(), "--allow-cache") {
+		t.Errorf("未允许使用缓存时应返回错误并提示 --allow-cache: %v", err)
+	}
+
+	// 允许时使用下载缓存，并标记为未经官方确认
+	lock, err := service.GenerateLock(context.Background(), &LockOptions{Versions: []string{"1.22.7"}, CatalogURL: unavailable.URL, AllowCache: true})
+	if err != nil {
+		t.Fatalf("生成锁文件失败: %v", err)
+	}
+	entry, found := lock.Find("1.22.7", runtime.GOOS, runtime.GOARCH)
+	if !found || entry.SHA256 != checksum || entry.URL != "https://mirror.example.com/go/"+filename || !entry.Unverified {
+		t.Errorf("缓存条目 = %+v, 期望校验和 %s 且标记为未确认", entry, checksum)
+	}
+}
+
+func TestVersionService_GenerateLock_UsesConfiguredHTTPClient(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	catalog := []GoRelease{{Version: "go1.22.7", Stable: true, Files: []GoReleaseFile{
+		{Filename: "go1.22.7.linux-amd64.tar.gz", OS: "linux", Arch: "amd64", SHA256: "aaaa", Kind: "archive"},
+	}}}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, password, ok := r.BasicAuth(); !ok || user != "bob" || password != "hunter2" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		json.NewEncoder(w).Encode(catalog)
+	}))
+	defer server.Close()
+
+	// 发布目录需要 netrc 中的凭据才能访问
+	dir := t.TempDir()
+	host := strings.Split(strings.TrimPrefix(server.URL, "http://"), ":")[0]
+	factory, err := NewHTTPClientFactory(&HTTPConfig{
+		NetrcFile:       writeTestFile(t, dir, "netrc", fmt.Sprintf("machine %s login bob password hunter2\n", host)),
+		CredentialsFile: filepath.Join(dir, "missing.json"),
+	})
+	if err != nil {
+		t.Fatalf("创建HTTP客户端工厂失败: %v", err)
+	}
+	service := NewVersionServiceWithDependencies(
+		NewMockVersionRepository(),
+		NewMockEnvironmentRepository(),
+		NewSystemDetector(),
+		NewDownloadService(&DownloadOptions{Timeout: 10 * time.Second, HTTPClientFactory: factory}),
+		NewArchiveExtractor(nil),
+		NewMirrorService(),
+	)
+
+	lock, err := service.GenerateLock(context.Background(), &LockOptions{
+		Versions:   []string{"1.22.7"},
+		Platforms:  []string{"linux/amd64"},
+		CatalogURL: server.URL,
+	})
+	if err != nil {
+		t.Fatalf("应使用下载服务配置的HTTP客户端获取发布目录: %v", err)
+	}
+	if entry, _ := lock.Find("1.22.7", "linux", "amd64"); entry == nil || entry.SHA256 != "aaaa" || entry.Unverified {
+		t.Errorf("锁文件条目 = %+v", entry)
+	}
+}
+
+func TestVersionService_InstallLocked(t *testing.T) {
+	server := newFakeReleaseServer(t, []string{"1.22.7"})
+	defer server.Close()
+	service, versionRepo := newTestVersionService(t, server.URL)
+
+	sum := sha256.Sum256(createFakeGoArchive(t, "1.22.7"))
+	checksum := hex.EncodeToString(sum[:])
+
+	result, err := service.InstallLocked(context.Background(), "1.22.7", lockForArchive("1.22.7", checksum), &model.InstallOptions{SkipVerification: true}, nil)
+	if err != nil {
+		t.Fatalf("按锁文件安装失败: %v", err)
+	}
+	if !result.Success {
+		t.Errorf("安装结果 = %+v", result)
+	}
+	installed, err := versionRepo.FindByVersion("1.22.7")
+	if err != nil {
+		t.Fatalf("版本未保存到仓库: %v", err)
+	}
+	if installed.DownloadInfo == nil || installed.DownloadInfo.Checksum != checksum {
+		t.Errorf("安装记录中的校验和 = %+v, 期望 %s", installed.DownloadInfo, checksum)
+	}
+
+	drifts, err := service.CheckLockDrift(lockForArchive("1.22.7", checksum))
+	if err != nil || len(drifts) != 0 {
+		t.Errorf("安装后不应有差异: %+v, %v", drifts, err)
+	}
+}
+
+func TestVersionService_InstallLocked_Errors(t *testing.T) {
+	server := newFakeReleaseServer(t, []string{"1.22.7"})
+	defer server.Close()
+
+	t.Run("镜像提供的压缩包与锁文件不一致", func(t *testing.T) {
+		service, versionRepo := newTestVersionService(t, server.URL)
+		_, err := service.InstallLocked(context.Background(), "1.22.7", lockForArchive("1.22.7", strings.Repeat("0", 64)), nil, nil)
+		if err == nil || !strings.Contains(err.Error(), "与锁文件不一致") {
+			t.Errorf("期望校验和与锁文件不一致的错误, 实际 %v", err)
+		}
+		if _, err := versionRepo.FindByVersion("1.22.7"); err == nil {
+			t.Error("校验失败的版本不应保存到仓库")
+		}
+	})
+
+	t.Run("锁文件中没有该版本", func(t *testing.T) {
+		service, _ := newTestVersionService(t, server.URL)
+		_, err := service.InstallLocked(context.Background(), "1.21.0", lockForArchive("1.22.7", strings.Repeat("0", 64)), nil, nil)
+		if err == nil || !strings.Contains(err.Error(), "锁文件中没有") {
+			t.Errorf("期望锁文件中没有该版本的错误, 实际 %v", err)
+		}
+	})
+
+	t.Run("指定的校验和与锁文件冲突", func(t *testing.T) {
+		service, _ := newTestVersionService(t, server.URL)
+		_, err := service.InstallLocked(context.Background(), "1.22.7", lockForArchive("1.22.7", strings.Repeat("0", 64)), &model.InstallOptions{SHA256: strings.Repeat("1", 64)}, nil)
+		if err == nil || !strings.Contains(err.Error(), "不一致") {
+			t.Errorf("期望校验和冲突的错误, 实际 %v", err)
+		}
+	})
+}
+
+func TestVersionService_CheckLockDrift(t *testing.T) {
+	service, versionRepo := newTestVersionService(t, "http://127.0.0.1:0")
+	lock := lockForArchive("1.22.7", strings.Repeat("a", 64))
+	lock.Entries = append(lock.Entries, lockForArchive("1.21.0", strings.Repeat("b", 64)).Entries...)
+	lock.Entries = append(lock.Entries, lockForArchive("1.20.0", strings.Repeat("c", 64)).Entries...)
+
+	versionRepo.Save(&model.GoVersion{Version: "1.22.7", Path: t.TempDir(), DownloadInfo: &model.DownloadInfo{Checksum: strings.Repeat("f", 64)}})
+	versionRepo.Save(&model.GoVersion{Version: "1.21.0", Path: t.TempDir()})
+
+	drifts, err := service.CheckLockDrift(lock)
+	if err != nil {
+		t.Fatalf("检查差异失败: %v", err)
+	}
+	reasons := make(map[string]model.LockDrift)
+	for _, drift := range drifts {
+		reasons[drift.Version] = drift
+	}
+	if len(drifts) != 3 {
+		t.Fatalf("差异 = %+v, 期望3处", drifts)
+	}
+	if drift := reasons["1.22.7"]; drift.Actual != strings.Repeat("f", 64) || drift.Reason != "校验和与锁文件不一致" {
+		t.Errorf("1.22.7 差异 = %+v", drift)
+	}
+	if reasons["1.21.0"].Reason != "安装记录中没有校验和" {
+		t.Errorf("1.21.0 差异 = %+v", reasons["1.21.0"])
+	}
+	if reasons["1.20.0"].Reason != "未安装" {
+		t.Errorf("1.20.0 差异 = %+v", reasons["1.20.0"])
+	}
+}
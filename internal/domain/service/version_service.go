@@ -2,21 +2,29 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"net/http"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"regexp"
 	"runtime"
+	"strings"
+	"sync"
 	"time"
 	"version-list/internal/domain/model"
 	"version-list/internal/domain/repository"
 )
 
+// sha256HexPattern 匹配十六进制格式的SHA-256值
+var sha256HexPattern = regexp.MustCompile(`^[0-9a-fA-F]{64}$`)
+
 // VersionInfo 版本信息视图模型
 type VersionInfo struct {
-	Version  string // 版本号
+	Version  string // 版本号或工具链名称
+	Release  string // Go发布版本号
 	Path     string // 安装路径
 	IsActive bool   // 是否为当前激活版本
 }
@@ -37,17 +45,35 @@ type VersionService struct {
 	downloadService  DownloadService
 	archiveExtractor ArchiveExtractor
 	mirrorService    MirrorService
+	downloadManager  *DownloadManager
+	repoMu           sync.Mutex // 串行化并行安装时的版本仓库读写
+	mirrorConfigErr  error      // 加载自定义镜像配置失败时的错误
+	catalogURL       string     // 校验源码包以及官方校验和文件无法获取时使用的发布目录地址，为空时使用官方发布目录
 }
 
 // NewVersionService 创建版本服务实例
 func NewVersionService(versionRepo repository.VersionRepository, environmentRepo repository.EnvironmentRepository) *VersionService {
+	// 下载服务与镜像服务共享同一个HTTP客户端工厂，镜像凭据对下载请求同样生效
+	clientFactory := DefaultHTTPClientFactory()
+	downloadOptions := DefaultDownloadOptions()
+	downloadOptions.HTTPClientFactory = clientFactory
+	downloadService := NewDownloadService(downloadOptions)
+
+	// 加载自定义镜像配置，失败时仍可使用预设镜像
+	mirrorService, mirrorConfigErr := NewMirrorServiceWithHTTPClientFactory(DefaultMirrorConfigPath(), clientFactory)
+	if mirrorConfigErr != nil {
+		mirrorService, _ = NewMirrorServiceWithHTTPClientFactory("", clientFactory)
+	}
+
 	return &VersionService{
 		versionRepo:      versionRepo,
 		environmentRepo:  environmentRepo,
 		systemDetector:   NewSystemDetector(),
-		downloadService:  NewDownloadService(nil),
+		downloadService:  downloadService,
 		archiveExtractor: NewArchiveExtractor(nil),
-		mirrorService:    NewMirrorService(),
+		mirrorService:    mirrorService,
+		downloadManager:  NewDownloadManagerWithService(downloadService),
+		mirrorConfigErr:  mirrorConfigErr,
 	}
 }
 
@@ -67,6 +93,7 @@ func NewVersionServiceWithDependencies(
 		downloadService:  downloadService,
 		archiveExtractor: archiveExtractor,
 		mirrorService:    mirrorService,
+		downloadManager:  NewDownloadManagerWithService(downloadService),
 	}
 }
 
@@ -95,11 +122,32 @@ func (s *VersionService) List() ([]*model.GoVersion, error) {
 
 // Use 切换到指定版本的Go
 func (s *VersionService) Use(version string) error {
-	// 检查版本是否存在
+	_, err := s.UseWithHooks(version)
+	return err
+}
+
+// UseWithHooks 运行 pre-use 钩子后切换到指定版本，再运行 post-use 钩子，返回非必需钩子失败的警告
+// 必需的 pre-use 钩子失败时不切换版本
+func (s *VersionService) UseWithHooks(version string) ([]string, error) {
 	targetVersion, err := s.versionRepo.FindByVersion(version)
 	if err != nil {
-		return fmt.Errorf("Go版本 %s 未安装", version)
+		return nil, fmt.Errorf("Go版本 %s 未安装", version)
+	}
+
+	warnings, err := s.runHooks(context.Background(), model.HookPreUse, targetVersion)
+	if err != nil {
+		return warnings, err
 	}
+	if err := s.switchVersion(targetVersion); err != nil {
+		return warnings, err
+	}
+	postWarnings, err := s.runHooks(context.Background(), model.HookPostUse, targetVersion)
+	return append(warnings, postWarnings...), err
+}
+
+// switchVersion 将 current 符号链接指向指定版本并更新环境变量配置
+func (s *VersionService) switchVersion(targetVersion *model.GoVersion) error {
+	version := targetVersion.Version
 
 	// 获取环境变量配置
 	env, err := s.environmentRepo.Get()
@@ -159,9 +207,11 @@ func (s *VersionService) Use(version string) error {
 		}
 	}
 
-	// 更新环境变量配置，使用符号链接路径
+	// 更新环境变量配置，使用符号链接路径，按版本隔离时 GOBIN、GOCACHE 指向该版本的目录
 	env.GOROOT = symlinkDir
-	env.GOBIN = filepath.Join(symlinkDir, "bin")
+	if err := applyIsolation(env, targetVersion); err != nil {
+		return err
+	}
 
 	// 保存环境变量配置
 	if err := s.environmentRepo.Save(env); err != nil {
@@ -199,19 +249,24 @@ func (s *VersionService) Remove(version string) error {
 	return s.versionRepo.Remove(version)
 }
 
-// ImportLocal 导入本地已安装的Go版本
-func (s *VersionService) ImportLocal(path string) (string, error) {
+// ImportLocal 导入本地已安装的Go版本，name 为自定义工具链名称（为空时使用检测到的版本号），返回版本列表中的名称
+func (s *VersionService) ImportLocal(path, name string) (string, error) {
 	// 检查路径是否存在
 	if _, err := os.Stat(path); os.IsNotExist(err) {
 		return "", fmt.Errorf("路径 %s 不存在", path)
 	}
 
 	// 尝试从路径中获取Go版本号
-	version, err := s.extractVersionFromPath(path)
+	release, output, err := s.readGoVersion(path)
 	if err != nil {
 		return "", fmt.Errorf("无法从路径中提取Go版本: %v", err)
 	}
 
+	version, err := resolveToolchainName(release, &model.InstallOptions{Name: name})
+	if err != nil {
+		return "", err
+	}
+
 	// 检查版本是否已存在
 	_, err = s.versionRepo.FindByVersion(version)
 	if err == nil {
@@ -219,10 +274,20 @@ func (s *VersionService) ImportLocal(path string) (string, error) {
 	}
 
 	// 创建新版本记录
+	now := time.Now()
 	newVersion := &model.GoVersion{
 		Version:  version,
+		Release:  release,
 		Path:     path,
 		IsActive: false,
+		Source:   model.SourceLocal,
+		ValidationInfo: &model.ValidationInfo{
+			ExecutableValid: true,
+			VersionValid:    true,
+			GoVersionOutput: output,
+		},
+		CreatedAt: now,
+		UpdatedAt: now,
 	}
 
 	// 保存版本记录
@@ -233,8 +298,25 @@ func (s *VersionService) ImportLocal(path string) (string, error) {
 	return version, nil
 }
 
+// resolveToolchainName 获取安装后在版本列表中使用的名称，并验证自定义名称
+func resolveToolchainName(version string, options *model.InstallOptions) (string, error) {
+	name := options.ToolchainName(version)
+	if name != version {
+		if err := model.ValidateToolchainName(name); err != nil {
+			return "", err
+		}
+	}
+	return name, nil
+}
+
 // extractVersionFromPath 从Go安装路径中提取版本号
 func (s *VersionService) extractVersionFromPath(path string) (string, error) {
+	version, _, err := s.readGoVersion(path)
+	return version, err
+}
+
+// readGoVersion 执行安装路径中的 go version，返回Go发布版本号和命令输出
+func (s *VersionService) readGoVersion(path string) (string, string, error) {
 	// 确定go可执行文件的路径
 	var goExecPath string
 	if runtime.GOOS == "windows" {
@@ -245,14 +327,14 @@ func (s *VersionService) extractVersionFromPath(path string) (string, error) {
 
 	// 检查go可执行文件是否存在
 	if _, err := os.Stat(goExecPath); os.IsNotExist(err) {
-		return "", fmt.Errorf("在路径 %s 中未找到go可执行文件", path)
+		return "", "", fmt.Errorf("在路径 %s 中未找到go可执行文件", path)
 	}
 
 	// 执行go version命令获取版本信息
 	cmd := exec.Command(goExecPath, "version")
 	output, err := cmd.Output()
 	if err != nil {
-		return "", fmt.Errorf("执行go version命令失败: %v", err)
+		return "", "", fmt.Errorf("执行go version命令失败: %v", err)
 	}
 
 	// 解析输出获取版本号
@@ -260,13 +342,13 @@ func (s *VersionService) extractVersionFromPath(path string) (string, error) {
 	versionStr := string(output)
 
 	// 使用正则表达式提取版本号
-	re := regexp.MustCompile(`go(\d+\.\d+\.\d+)`)
+	re := regexp.MustCompile(`go(\d+\.\d+(?:\.\d+)?(?:(?:rc|beta)\d+)?)`)
 	matches := re.FindStringSubmatch(versionStr)
 	if len(matches) < 2 {
-		return "", fmt.Errorf("无法解析go version输出: %s", versionStr)
+		return "", "", fmt.Errorf("无法解析go version输出: %s", versionStr)
 	}
 
-	return matches[1], nil
+	return matches[1], strings.TrimSpace(versionStr), nil
 }
 
 // InstallOnline 在线安装指定版本的Go
@@ -276,18 +358,26 @@ func (s *VersionService) InstallOnline(version string, options *model.InstallOpt
 
 // InstallOnlineWithProgress 带进度显示的在线安装指定版本的Go
 func (s *VersionService) InstallOnlineWithProgress(version string, options *model.InstallOptions, progressUI ProgressReporter) (*model.InstallationResult, error) {
-	// 检查版本是否已存在
-	_, err := s.versionRepo.FindByVersion(version)
-	if err == nil {
-		if options == nil || !options.Force {
-			return nil, fmt.Errorf("go版本 %s 已安装，使用 --force 选项强制重新安装", version)
+	return s.InstallOnlineWithContext(context.Background(), version, options, progressUI)
+}
+
+// InstallOnlineWithContext 在线安装指定版本的Go，ctx 取消时中断安装
+// 中断后清理临时目录和未完成的版本目录，返回的错误包含 context.Canceled 或 context.DeadlineExceeded
+// options.Timeout 不限制整个安装的时间，只在下载超过该秒数没有进度时中断当前镜像
+func (s *VersionService) InstallOnlineWithContext(ctx context.Context, version string, options *model.InstallOptions, progressUI ProgressReporter) (*model.InstallationResult, error) {
+	if isCrossPlatformInstall(options) {
+		// 其他平台的Go无法在当前系统使用，只安装到指定路径，不检查和记录版本列表
+		if options.CustomPath == "" {
+			return nil, fmt.Errorf("安装其他平台（%s/%s）的Go时必须指定安装路径", options.TargetSystem.OS, options.TargetSystem.Arch)
 		}
-		// 强制重新安装，先删除现有版本
-		if progressUI != nil {
-			progressUI.SetMessage("删除现有版本...")
+	} else {
+		// 检查版本是否已存在
+		name, err := resolveToolchainName(version, options)
+		if err != nil {
+			return nil, err
 		}
-		if err := s.Remove(version); err != nil {
-			return nil, fmt.Errorf("删除现有版本失败: %v", err)
+		if err := s.prepareForInstall(name, options, progressUI); err != nil {
+			return nil, err
 		}
 	}
 
@@ -298,8 +388,11 @@ func (s *VersionService) InstallOnlineWithProgress(version string, options *mode
 		progressUI.SetMessage("正在检测系统信息...")
 	}
 
-	context, err := s.createInstallationContext(version, options)
+	context, err := s.createInstallationContext(ctx, version, options)
 	if err != nil {
+		if ctx.Err() != nil {
+			return nil, interruptedError(ctx)
+		}
 		return nil, fmt.Errorf("创建安装上下文失败: %v", err)
 	}
 
@@ -308,25 +401,62 @@ func (s *VersionService) InstallOnlineWithProgress(version string, options *mode
 		progressUI.SetMessage(fmt.Sprintf("检测到系统: %s/%s", context.SystemInfo.OS, context.SystemInfo.Arch))
 	}
 
-	// 执行安装流程
-	result, err := s.executeInstallationWithProgress(context, progressUI)
+	// 执行安装流程，失败时已按安装日志回滚
+	result, err := s.executeInstallationWithProgress(ctx, context, progressUI)
 	if err != nil {
-		// 清理失败的安装
-		s.cleanupFailedInstallation(context)
+		if ctx.Err() != nil {
+			return s.createFailedResult(result, interruptedError(ctx))
+		}
 		return result, err
 	}
 
 	return result, nil
 }
 
+// interruptedError 安装因取消或超时中断时的错误，保留上下文错误以便调用方区分
+func interruptedError(ctx context.Context) error {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("安装超时，已取消并清理未完成的安装: %w", ctx.Err())
+	}
+	return fmt.Errorf("安装已取消，已清理未完成的安装: %w", ctx.Err())
+}
+
+// prepareForInstall 回滚同一版本之前中断的安装，检查版本是否已安装，强制模式下先删除现有版本
+func (s *VersionService) prepareForInstall(version string, options *model.InstallOptions, progressUI ProgressReporter) error {
+	if err := s.rollbackStaleJournals(version); err != nil {
+		return err
+	}
+
+	s.repoMu.Lock()
+	defer s.repoMu.Unlock()
+
+	if _, err := s.versionRepo.FindByVersion(version); err != nil {
+		return nil
+	}
+
+	if options == nil || !options.Force {
+		return fmt.Errorf("go版本 %s 已安装，使用 --force 选项强制重新安装", version)
+	}
+
+	// 强制重新安装，先删除现有版本
+	if progressUI != nil {
+		progressUI.SetMessage("删除现有版本...")
+	}
+	if err := s.Remove(version); err != nil {
+		return fmt.Errorf("删除现有版本失败: %v", err)
+	}
+
+	return nil
+}
+
 // createInstallationContext 创建安装上下文
-func (s *VersionService) createInstallationContext(version string, options *model.InstallOptions) (*model.InstallationContext, error) {
+func (s *VersionService) createInstallationContext(ctx context.Context, version string, options *model.InstallOptions) (*model.InstallationContext, error) {
 	// 设置默认选项
 	if options == nil {
 		options = &model.InstallOptions{
 			Force:            false,
 			SkipVerification: false,
-			Timeout:          300, // 5分钟
+			Timeout:          300, // 下载停滞5分钟时中断
 			MaxRetries:       3,
 			Mirror:           "official", // 默认使用官方源
 			AutoMirror:       false,
@@ -334,16 +464,18 @@ func (s *VersionService) createInstallationContext(version string, options *mode
 	}
 
 	// 选择镜像
-	selectedMirror, err := s.selectMirror(options)
+	selectedMirror, err := s.selectMirror(ctx, options)
 	if err != nil {
 		return nil, fmt.Errorf("选择镜像失败: %v", err)
 	}
 
-	// 使用选定的镜像获取系统信息
-	systemInfo, err := s.systemDetector.GetSystemInfoWithMirror(version, selectedMirror)
+	// 使用选定镜像的URL模板或基础URL获取系统信息
+	systemInfo, err := s.getTargetSystemInfo(version, selectedMirror, options)
 	if err != nil {
 		return nil, fmt.Errorf("获取系统信息失败: %v", err)
 	}
+	systemInfo.Mirror = selectedMirror.Name
+	systemInfo.ChecksumURL = selectedMirror.ChecksumURL(version, systemInfo.OS, systemInfo.Arch)
 
 	// 确定安装路径
 	var versionDir string
@@ -356,9 +488,9 @@ func (s *VersionService) createInstallationContext(version string, options *mode
 	} else {
 		// 使用默认路径结构
 		baseDir = s.getBaseInstallDir()
-		versionDir = filepath.Join(baseDir, version)
+		versionDir = filepath.Join(baseDir, options.ToolchainName(version))
 	}
-	tempDir := filepath.Join(os.TempDir(), "go-install-"+version)
+	tempDir := InstallTempDir(options.ToolchainName(version))
 
 	paths := &model.InstallPaths{
 		BaseDir:     baseDir,
@@ -369,34 +501,96 @@ func (s *VersionService) createInstallationContext(version string, options *mode
 
 	return &model.InstallationContext{
 		Version:    version,
+		Name:       options.Name,
+		Source:     model.SourceOnline,
 		SystemInfo: systemInfo,
 		Paths:      paths,
 		TempDir:    tempDir,
 		Options:    options,
 		StartTime:  time.Now(),
 		Status:     model.StatusPending,
+		Mirrors:    s.buildMirrorCandidates(selectedMirror, systemInfo, options),
 	}, nil
 }
 
+// getTargetSystemInfo 获取安装目标平台的系统信息，未指定目标平台时使用当前系统
+func (s *VersionService) getTargetSystemInfo(version string, mirror *Mirror, options *model.InstallOptions) (*model.SystemInfo, error) {
+	if target := options.TargetSystem; target != nil {
+		return s.systemDetector.GetSystemInfoForPlatform(version, target.OS, target.Arch, mirror.URLPattern())
+	}
+	return s.systemDetector.GetSystemInfoWithMirror(version, mirror.URLPattern())
+}
+
+// isCrossPlatformInstall 判断是否安装与当前系统不同平台的Go
+func isCrossPlatformInstall(options *model.InstallOptions) bool {
+	if options == nil || options.TargetSystem == nil {
+		return false
+	}
+	return options.TargetSystem.OS != runtime.GOOS || options.TargetSystem.Arch != runtime.GOARCH
+}
+
+// buildMirrorCandidates 构建下载失败时依次尝试的镜像列表，选定的镜像排在第一位
+func (s *VersionService) buildMirrorCandidates(selected *Mirror, systemInfo *model.SystemInfo, options *model.InstallOptions) []model.MirrorCandidate {
+	candidates := []model.MirrorCandidate{{
+		Name:        selected.Name,
+		URL:         systemInfo.URL,
+		ChecksumURL: systemInfo.ChecksumURL,
+	}}
+	if options.NoFailover {
+		return candidates
+	}
+
+	for _, mirror := range s.mirrorService.RankMirrors(s.mirrorService.GetAvailableMirrors()) {
+		if mirror.Name == selected.Name {
+			continue
+		}
+		candidates = append(candidates, model.MirrorCandidate{
+			Name:        mirror.Name,
+			URL:         s.systemDetector.GetDownloadURLWithMirror(systemInfo.Version, systemInfo.OS, systemInfo.Arch, mirror.URLPattern()),
+			ChecksumURL: mirror.ChecksumURL(systemInfo.Version, systemInfo.OS, systemInfo.Arch),
+		})
+	}
+	return candidates
+}
+
 // executeInstallation 执行安装流程
-func (s *VersionService) executeInstallation(context *model.InstallationContext) (*model.InstallationResult, error) {
-	return s.executeInstallationWithProgress(context, nil)
+func (s *VersionService) executeInstallation(installContext *model.InstallationContext) (*model.InstallationResult, error) {
+	return s.executeInstallationWithProgress(context.Background(), installContext, nil)
 }
 
-// executeInstallationWithProgress 带进度显示的执行安装流程
-func (s *VersionService) executeInstallationWithProgress(context *model.InstallationContext, progressUI ProgressReporter) (*model.InstallationResult, error) {
+// executeInstallationWithProgress 带进度显示的执行安装流程，ctx 取消时在当前阶段中断
+func (s *VersionService) executeInstallationWithProgress(ctx context.Context, context *model.InstallationContext, progressUI ProgressReporter) (*model.InstallationResult, error) {
 	result := &model.InstallationResult{
-		Version: context.Version,
+		Version: context.ToolchainName(),
 		Path:    context.Paths.VersionDir,
 	}
 
+	if err := s.runPreInstallHooks(ctx, context, result); err != nil {
+		return s.createFailedResult(result, err)
+	}
+
+	// 每个步骤执行前写入安装日志，失败或中断时按相反顺序回滚
+	tx, err := s.beginInstallTransaction(context.Source, context.Version, context.Options)
+	if err != nil {
+		return s.createFailedResult(result, err)
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			tx.abort()
+		}
+	}()
+
 	// 1. 创建临时目录
 	if progressUI != nil {
 		progressUI.SetProgress(25)
 		progressUI.SetMessage("创建临时目录...")
 	}
-	if err := os.MkdirAll(context.TempDir, 0755); err != nil {
-		return s.createFailedResult(result, fmt.Errorf("创建临时目录失败: %v", err))
+	if err := tx.recordDirectory(model.JournalTempDir, context.TempDir); err != nil {
+		return s.createFailedResult(result, err)
+	}
+	if err := s.prepareInstallTempDir(context); err != nil {
+		return s.createFailedResult(result, err)
 	}
 
 	// 2. 下载阶段
@@ -406,25 +600,61 @@ func (s *VersionService) executeInstallationWithProgress(context *model.Installa
 		progressUI.SetProgress(30)
 		progressUI.SetMessage(fmt.Sprintf("正在下载 %s...", context.SystemInfo.Filename))
 	}
+	if err := tx.setStatus(context.Status); err != nil {
+		return s.createFailedResult(result, err)
+	}
+	if err := tx.record(model.JournalStep{Type: model.JournalArchive, Path: context.Paths.ArchiveFile}); err != nil {
+		return s.createFailedResult(result, err)
+	}
 
-	downloadInfo, err := s.downloadGoArchiveWithProgress(context, progressUI)
+	// 下载服务启用缓存时优先使用缓存中的压缩包，同样需要通过验证
+	cache := s.installDownloadCache()
+	cached, _, err := s.copyCachedArchive(cache, context)
 	if err != nil {
-		return s.createFailedResult(result, fmt.Errorf("下载失败: %v", err))
+		return s.createFailedResult(result, err)
+	}
+	var downloadInfo *model.DownloadInfo
+	if cached != nil {
+		downloadInfo = cachedDownloadInfo(context, cached)
+	} else {
+		downloadInfo, err = s.downloadGoArchiveWithProgress(ctx, context, progressUI)
+		if err != nil {
+			// 下载中途失败时保留已下载的部分和安装状态，再次安装时继续下载；取消时仍全部清理
+			if _, statErr := os.Stat(context.Paths.ArchiveFile); statErr == nil && ctx.Err() == nil {
+				tx.keepDownload()
+			}
+			return s.createFailedResult(result, fmt.Errorf("下载失败: %w", err))
+		}
 	}
 	result.DownloadInfo = downloadInfo
 
 	// 3. 验证阶段（如果未跳过）
+	var verified string
 	if !context.Options.SkipVerification {
 		if progressUI != nil {
 			progressUI.SetStage("验证文件")
 			progressUI.SetProgress(60)
 			progressUI.SetMessage("验证下载文件完整性...")
 		}
-		if err := s.verifyDownload(context); err != nil {
+		verified, err = s.verifyDownload(ctx, context)
+		if err != nil {
+			evictCachedArchive(cache, cached)
 			return s.createFailedResult(result, fmt.Errorf("验证失败: %v", err))
 		}
 	}
 
+	// 记录压缩包的SHA-256，用于与锁文件对比，并判断之前的解压结果是否来自同一压缩包
+	if checksum, err := NewFileValidator().CalculateChecksum(context.Paths.ArchiveFile, ChecksumTypeSHA256); err == nil {
+		downloadInfo.Checksum = checksum
+		downloadInfo.ChecksumType = ChecksumTypeSHA256
+		context.ArchiveSHA256 = checksum
+	}
+
+	// 通过SHA-256验证的新下载压缩包加入缓存，缓存失败不影响安装
+	if cache != nil && cached == nil && verified != "" {
+		cache.StoreVerified(context.SystemInfo.URL, context.Paths.ArchiveFile, verified)
+	}
+
 	// 4. 解压阶段
 	context.Status = model.StatusExtracting
 	if progressUI != nil {
@@ -432,37 +662,53 @@ func (s *VersionService) executeInstallationWithProgress(context *model.Installa
 		progressUI.SetProgress(65)
 		progressUI.SetMessage("正在解压安装包...")
 	}
+	if err := tx.setStatus(context.Status); err != nil {
+		return s.createFailedResult(result, err)
+	}
 
-	extractInfo, err := s.extractGoArchiveWithProgress(context, progressUI)
+	extractInfo, err := s.extractGoArchiveWithProgress(ctx, tx, context, progressUI)
 	if err != nil {
 		return s.createFailedResult(result, fmt.Errorf("解压失败: %v", err))
 	}
 	result.ExtractInfo = extractInfo
 
 	// 5. 配置阶段
+	if err := ctx.Err(); err != nil {
+		return s.createFailedResult(result, err)
+	}
 	context.Status = model.StatusConfiguring
 	if progressUI != nil {
 		progressUI.SetStage("配置安装")
 		progressUI.SetProgress(85)
 		progressUI.SetMessage("配置Go环境...")
 	}
+	if err := tx.setStatus(context.Status); err != nil {
+		return s.createFailedResult(result, err)
+	}
 	if err := s.configureInstallation(context); err != nil {
 		return s.createFailedResult(result, fmt.Errorf("配置失败: %v", err))
 	}
 
-	// 6. 保存版本信息
-	if progressUI != nil {
-		progressUI.SetProgress(90)
-		progressUI.SetMessage("保存版本信息...")
-	}
-	goVersion, err := s.createGoVersionRecord(context, downloadInfo, extractInfo)
-	if err != nil {
-		return s.createFailedResult(result, fmt.Errorf("保存版本记录失败: %v", err))
+	// 6. 安装默认工具并保存版本信息，运行 post-install 钩子（其他平台的Go不加入版本列表）
+	if !isCrossPlatformInstall(context.Options) {
+		if progressUI != nil {
+			progressUI.SetProgress(90)
+			progressUI.SetMessage("保存版本信息...")
+		}
+		goVersion, err := s.createGoVersionRecord(context, downloadInfo, extractInfo)
+		if err != nil {
+			return s.createFailedResult(result, fmt.Errorf("保存版本记录失败: %v", err))
+		}
+		if err := s.saveInstalledVersion(ctx, tx, goVersion, context.Options, result, progressUI); err != nil {
+			return s.createFailedResult(result, err)
+		}
 	}
 
-	if err := s.versionRepo.Save(goVersion); err != nil {
-		return s.createFailedResult(result, fmt.Errorf("保存到仓库失败: %v", err))
+	// 提交安装事务，此后不再回滚
+	if err := tx.commit(); err != nil {
+		return s.createFailedResult(result, err)
 	}
+	committed = true
 
 	// 7. 清理临时文件
 	if progressUI != nil {
@@ -485,25 +731,196 @@ func (s *VersionService) executeInstallationWithProgress(context *model.Installa
 }
 
 // downloadGoArchive 下载Go压缩包
-func (s *VersionService) downloadGoArchive(context *model.InstallationContext) (*model.DownloadInfo, error) {
-	return s.downloadGoArchiveWithProgress(context, nil)
+func (s *VersionService) downloadGoArchive(installContext *model.InstallationContext) (*model.DownloadInfo, error) {
+	return s.downloadGoArchiveWithProgress(context.Background(), installContext, nil)
+}
+
+// installDownloadCache 获取安装时使用的下载缓存，下载服务未启用缓存时返回nil
+func (s *VersionService) installDownloadCache() *DownloadCache {
+	if provider, ok := s.downloadService.(interface{ Cache() *DownloadCache }); ok {
+		return provider.Cache()
+	}
+	return nil
+}
+
+// copyCachedArchive 将缓存中的压缩包复制到安装临时目录，优先按URL查找，其次按文件名查找其他镜像下载的相同压缩包
+// 缓存中没有时返回nil；复制后的压缩包仍需验证，验证失败时由 evictCachedArchive 移除该缓存条目
+func (s *VersionService) copyCachedArchive(cache *DownloadCache, context *model.InstallationContext) (*CacheEntry, string, error) {
+	if cache == nil {
+		return nil, "", nil
+	}
+	cached, cachedPath, err := cache.Lookup(context.SystemInfo.URL)
+	if err != nil {
+		cached, cachedPath, err = cache.LookupFilename(context.SystemInfo.Filename)
+	}
+	if err != nil {
+		return nil, "", nil
+	}
+
+	// 缓存文件以内容哈希命名，复制为原始文件名后再验证压缩包格式
+	if err := s.copyFileOptimized(cachedPath, context.Paths.ArchiveFile); err != nil {
+		return nil, "", fmt.Errorf("读取缓存文件失败: %v", err)
+	}
+	return cached, cachedPath, nil
+}
+
+// cachedDownloadInfo 根据缓存条目生成下载信息
+func cachedDownloadInfo(context *model.InstallationContext, cached *CacheEntry) *model.DownloadInfo {
+	return &model.DownloadInfo{
+		URL:          context.SystemInfo.URL,
+		Filename:     context.SystemInfo.Filename,
+		Size:         cached.Size,
+		DownloadedAt: cached.CreatedAt,
+		Mirror:       context.SystemInfo.Mirror,
+	}
+}
+
+// evictCachedArchive 移除未通过验证的缓存压缩包，避免之后再次使用
+func evictCachedArchive(cache *DownloadCache, cached *CacheEntry) {
+	if cache != nil && cached != nil {
+		cache.Remove(cached.SHA256)
+	}
 }
 
 // downloadGoArchiveWithProgress 带进度显示的下载Go压缩包
-func (s *VersionService) downloadGoArchiveWithProgress(context *model.InstallationContext, progressUI ProgressReporter) (*model.DownloadInfo, error) {
+// 当前镜像下载失败时依次切换到候选镜像，文件大小一致且校验和已知时从已下载的位置继续
+// 安装状态记录压缩包已下载完成时跳过下载
+func (s *VersionService) downloadGoArchiveWithProgress(ctx context.Context, context *model.InstallationContext, progressUI ProgressReporter) (*model.DownloadInfo, error) {
 	startTime := time.Now()
 
-	// 获取文件大小
-	size, err := s.downloadService.GetFileSize(context.SystemInfo.URL)
+	candidates := context.Mirrors
+	if len(candidates) == 0 {
+		candidates = []model.MirrorCandidate{{
+			Name:        context.SystemInfo.Mirror,
+			URL:         context.SystemInfo.URL,
+			ChecksumURL: context.SystemInfo.ChecksumURL,
+		}}
+	}
+
+	if candidate, ok := completedDownload(context, candidates); ok {
+		if progressUI != nil {
+			progressUI.SetProgress(60)
+			progressUI.SetMessage("使用之前已下载的压缩包...")
+		}
+		context.SystemInfo.URL = candidate.URL
+		context.SystemInfo.Mirror = candidate.Name
+		context.SystemInfo.ChecksumURL = candidate.ChecksumURL
+		return &model.DownloadInfo{
+			URL:              candidate.URL,
+			Filename:         context.SystemInfo.Filename,
+			Size:             context.State.Size,
+			DownloadedAt:     context.State.UpdatedAt,
+			Mirror:           candidate.Name,
+			AttemptedMirrors: []string{candidate.Name},
+		}, nil
+	}
+
+	var attempted []string
+	var previousSize int64
+	var lastErr error
+	for i, candidate := range candidates {
+		if i > 0 && progressUI != nil {
+			progressUI.SetMessage(fmt.Sprintf("镜像 %s 下载失败，切换到 %s...", candidates[i-1].Name, candidate.Name))
+		}
+		attempted = append(attempted, candidate.Name)
+
+		size, err := s.downloadFromMirror(ctx, context, candidate, i > 0, previousSize, progressUI)
+		if err == nil {
+			context.SystemInfo.URL = candidate.URL
+			context.SystemInfo.Mirror = candidate.Name
+			context.SystemInfo.ChecksumURL = candidate.ChecksumURL
+
+			duration := time.Since(startTime)
+			return &model.DownloadInfo{
+				URL:              candidate.URL,
+				Filename:         context.SystemInfo.Filename,
+				Size:             size,
+				DownloadedAt:     time.Now(),
+				Duration:         duration.Milliseconds(),
+				Speed:            float64(size) / duration.Seconds(),
+				Mirror:           candidate.Name,
+				AttemptedMirrors: attempted,
+			}, nil
+		}
+
+		// 已取消时不再尝试其他镜像
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		lastErr = err
+		if size > 0 {
+			previousSize = size
+		}
+		if !isFailoverError(err) {
+			break
+		}
+	}
+
+	if len(attempted) > 1 {
+		return nil, fmt.Errorf("所有镜像均下载失败（已尝试 %s）: %w", strings.Join(attempted, ", "), lastErr)
+	}
+	return nil, lastErr
+}
+
+// downloadFromMirror 从指定镜像下载压缩包，返回镜像报告的文件大小
+// 切换镜像时，只有新镜像的文件大小与之前一致且能校验下载结果，才保留已下载的部分继续下载
+// 继续之前运行留下的部分下载时，要求下载地址、文件大小和 ETag/Last-Modified 与安装状态一致，并通过 If-Range 校验
+// 下载失败时按 options.MaxRetries 重试，ctx 取消时立即停止；超过 options.Timeout 秒没有下载进度时中断并返回 ErrDownloadStalled
+func (s *VersionService) downloadFromMirror(ctx context.Context, context *model.InstallationContext, candidate model.MirrorCandidate, switched bool, previousSize int64, progressUI ProgressReporter) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	ctx, stall := newStallTimer(ctx, time.Duration(context.Options.Timeout)*time.Second)
+	defer stall.stop()
+
+	// 获取文件大小和校验值
+	remote, err := s.probeArchive(ctx, candidate.URL)
 	if err != nil {
-		return nil, fmt.Errorf("获取文件大小失败: %v", err)
+		return 0, fmt.Errorf("获取文件大小失败: %w", stall.wrap(err))
 	}
+	size := remote.Size
 
-	// 下载文件
-	err = s.downloadService.Download(
-		context.SystemInfo.URL,
+	if switched {
+		checksumKnown := !context.Options.SkipVerification &&
+			(context.Options.SHA256 != "" || candidate.ChecksumURL != "")
+		if info, err := os.Stat(context.Paths.ArchiveFile); err == nil {
+			if !checksumKnown || size != previousSize || info.Size() >= size {
+				if err := removeSegmentedDownload(context.Paths.ArchiveFile); err != nil {
+					return size, fmt.Errorf("删除未完成的下载文件失败: %v", err)
+				}
+			}
+		}
+	} else if state := context.State; state != nil {
+		resumable := state.URL == candidate.URL && state.Size == size && state.Validator() == remote.Validator()
+		if !resumable {
+			if err := removeSegmentedDownload(context.Paths.ArchiveFile); err != nil {
+				return size, fmt.Errorf("删除未完成的下载文件失败: %v", err)
+			}
+		} else if validator := state.Validator(); validator != "" {
+			ctx = WithResumeValidator(ctx, validator)
+		}
+	}
+
+	err = updateInstallState(context, func(state *model.InstallState) {
+		state.URL = candidate.URL
+		state.Mirror = candidate.Name
+		state.Size = size
+		state.ETag = remote.ETag
+		state.LastModified = remote.LastModified
+		state.Stage = model.StageNone
+	})
+	if err != nil {
+		return size, err
+	}
+
+	// 下载文件（通过下载管理器跟踪活跃下载）
+	downloadCtx, err := s.downloadManager.StartDownloadWithContext(
+		WithMaxRetries(ctx, context.Options.MaxRetries),
+		candidate.URL,
 		context.Paths.ArchiveFile,
 		func(downloaded, total int64, speed float64) {
+			stall.touch()
 			if progressUI != nil {
 				// 计算下载进度 (30-60%)
 				downloadProgress := float64(downloaded)/float64(total)*30.0 + 30.0
@@ -517,44 +934,126 @@ func (s *VersionService) downloadGoArchiveWithProgress(context *model.Installati
 		},
 	)
 	if err != nil {
-		return nil, err
+		return size, stall.wrap(err)
 	}
+	if err := <-downloadCtx.Done; err != nil {
+		return size, stall.wrap(err)
+	}
+	return size, updateInstallState(context, func(state *model.InstallState) {
+		state.Stage = model.StageDownloaded
+	})
+}
 
-	duration := time.Since(startTime)
-	avgSpeed := float64(size) / duration.Seconds()
-
-	return &model.DownloadInfo{
-		URL:          context.SystemInfo.URL,
-		Filename:     context.SystemInfo.Filename,
-		Size:         size,
-		DownloadedAt: time.Now(),
-		Duration:     duration.Milliseconds(),
-		Speed:        avgSpeed,
-	}, nil
+// isFailoverError 判断下载错误是否应切换到其他镜像
+// 版本不存在（404/410）、主动取消和超时时不再尝试其他镜像
+func isFailoverError(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var statusErr *HTTPStatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode != http.StatusNotFound && statusErr.StatusCode != http.StatusGone
+	}
+	return true
 }
 
-// verifyDownload 验证下载的文件
-func (s *VersionService) verifyDownload(context *model.InstallationContext) error {
+// verifyDownload 验证下载的文件，返回验证时使用的SHA-256（没有可用的校验和时为空）
+func (s *VersionService) verifyDownload(ctx context.Context, context *model.InstallationContext) (string, error) {
 	// 验证文件是否存在
 	if _, err := os.Stat(context.Paths.ArchiveFile); os.IsNotExist(err) {
-		return fmt.Errorf("下载文件不存在: %s", context.Paths.ArchiveFile)
+		return "", fmt.Errorf("下载文件不存在: %s", context.Paths.ArchiveFile)
 	}
 
 	// 验证压缩包完整性
 	if err := s.archiveExtractor.ValidateArchive(context.Paths.ArchiveFile); err != nil {
-		return fmt.Errorf("压缩包验证失败: %v", err)
+		return "", fmt.Errorf("压缩包验证失败: %v", err)
 	}
 
-	return nil
+	// 验证SHA-256校验和：优先使用指定的校验和，否则使用镜像提供的校验和文件
+	expected := context.Options.SHA256
+	if expected == "" && context.SystemInfo != nil && context.SystemInfo.ChecksumURL != "" {
+		checksum, err := s.archiveChecksum(ctx, context)
+		if err != nil {
+			return "", err
+		}
+		expected = checksum
+	}
+	if expected != "" {
+		if err := NewFileValidator().ValidateChecksum(context.Paths.ArchiveFile, expected, ChecksumTypeSHA256); err != nil {
+			if context.Options.Locked {
+				return "", fmt.Errorf("镜像 %s 提供的压缩包与锁文件不一致，拒绝安装: %v", context.SystemInfo.Mirror, err)
+			}
+			return "", err
+		}
+	}
+
+	return strings.ToLower(expected), nil
+}
+
+// archiveChecksum 获取镜像配置的校验和文件中的SHA-256
+// 镜像使用官方校验和文件（预设镜像均是如此）且无法获取时，依次使用 golang.google.cn 的校验和文件和官方发布目录，都失败时才返回错误
+func (s *VersionService) archiveChecksum(ctx context.Context, context *model.InstallationContext) (string, error) {
+	info := context.SystemInfo
+	checksum, err := s.fetchChecksum(ctx, info.ChecksumURL, context.TempDir)
+	if err == nil || ctx.Err() != nil || info.ChecksumURL != ExpandURLTemplate(officialChecksumURLTemplate, info.Version, info.OS, info.Arch) {
+		return checksum, err
+	}
+
+	failures := []string{err.Error()}
+	for _, template := range fallbackChecksumURLTemplates {
+		checksum, fallbackErr := s.fetchChecksum(ctx, ExpandURLTemplate(template, info.Version, info.OS, info.Arch), context.TempDir)
+		if fallbackErr == nil {
+			return checksum, nil
+		}
+		failures = append(failures, fallbackErr.Error())
+	}
+
+	catalogURLs := []string{s.catalogURL}
+	if s.catalogURL == "" {
+		catalogURLs = []string{DefaultReleaseCatalogURL, chinaReleaseCatalogURL}
+	}
+	for _, catalogURL := range catalogURLs {
+		releases, catalogErr := s.fetchReleaseIndex(ctx, catalogURL)
+		if catalogErr != nil {
+			failures = append(failures, catalogErr.Error())
+			continue
+		}
+		if file, found := releases[info.Version].FindArchive(info.OS, info.Arch); found && file.SHA256 != "" {
+			return file.SHA256, nil
+		}
+		failures = append(failures, fmt.Sprintf("发布目录 %s 中没有 %s", catalogURL, info.Filename))
+	}
+	return "", fmt.Errorf("无法获取官方校验和: %s", strings.Join(failures, "；"))
+}
+
+// fetchChecksum 下载镜像提供的校验和文件，并解析其中的SHA-256值
+// 支持只包含哈希值的文件，以及 sha256sum 输出格式（哈希值后跟文件名）
+func (s *VersionService) fetchChecksum(ctx context.Context, checksumURL, tempDir string) (string, error) {
+	checksumFile := filepath.Join(tempDir, "archive.sha256")
+	if err := s.downloadService.DownloadWithContext(ctx, checksumURL, checksumFile, nil); err != nil {
+		return "", fmt.Errorf("下载校验和文件失败: %v", err)
+	}
+	defer os.Remove(checksumFile)
+
+	data, err := os.ReadFile(checksumFile)
+	if err != nil {
+		return "", fmt.Errorf("读取校验和文件失败: %v", err)
+	}
+
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 || !sha256HexPattern.MatchString(fields[0]) {
+		return "", fmt.Errorf("校验和文件格式无效: %s", checksumURL)
+	}
+	return strings.ToLower(fields[0]), nil
 }
 
 // extractGoArchive 解压Go压缩包
-func (s *VersionService) extractGoArchive(context *model.InstallationContext) (*model.ExtractInfo, error) {
-	return s.extractGoArchiveWithProgress(context, nil)
+func (s *VersionService) extractGoArchive(installContext *model.InstallationContext) (*model.ExtractInfo, error) {
+	return s.extractGoArchiveWithProgress(context.Background(), nil, installContext, nil)
 }
 
-// extractGoArchiveWithProgress 带进度显示的解压Go压缩包
-func (s *VersionService) extractGoArchiveWithProgress(context *model.InstallationContext, progressUI ProgressReporter) (*model.ExtractInfo, error) {
+// extractGoArchiveWithProgress 带进度显示的解压Go压缩包，ctx 取消时停止解压和移动
+func (s *VersionService) extractGoArchiveWithProgress(ctx context.Context, tx *installTransaction, context *model.InstallationContext, progressUI ProgressReporter) (*model.ExtractInfo, error) {
 	startTime := time.Now()
 
 	// 获取压缩包信息
@@ -564,16 +1063,28 @@ func (s *VersionService) extractGoArchiveWithProgress(context *model.Installatio
 	}
 
 	// 创建版本目录
+	if err := tx.recordDirectory(model.JournalVersionDir, context.Paths.VersionDir); err != nil {
+		return nil, err
+	}
 	if err := os.MkdirAll(context.Paths.VersionDir, 0755); err != nil {
 		return nil, fmt.Errorf("创建版本目录失败: %v", err)
 	}
 
-	// 解压到临时目录
+	// 解压到临时目录，之前的运行已完整解压时直接使用
 	tempExtractDir := filepath.Join(context.TempDir, "extracted")
-	err = s.archiveExtractor.Extract(
-		context.Paths.ArchiveFile,
-		tempExtractDir,
-		func(progress ExtractionProgress) {
+	if err := tx.recordDirectory(model.JournalExtractDir, tempExtractDir); err != nil {
+		return nil, err
+	}
+	if extractedTreeComplete(context.State, context.ArchiveSHA256, tempExtractDir, archiveInfo) {
+		if progressUI != nil {
+			progressUI.SetMessage("使用之前已解压的文件...")
+		}
+	} else {
+		// 清除未完成的解压结果
+		if err := os.RemoveAll(tempExtractDir); err != nil {
+			return nil, fmt.Errorf("清理解压目录失败: %v", err)
+		}
+		err = s.extractArchive(ctx, context.Paths.ArchiveFile, tempExtractDir, func(progress ExtractionProgress) {
 			if progressUI != nil {
 				// 计算解压进度 (65-85%)
 				extractProgress := progress.Percentage*0.20 + 65.0
@@ -581,10 +1092,17 @@ func (s *VersionService) extractGoArchiveWithProgress(context *model.Installatio
 				progressUI.SetMessage(fmt.Sprintf("解压中... %.1f%% (%d/%d 文件)",
 					progress.Percentage, progress.ProcessedFiles, progress.TotalFiles))
 			}
-		},
-	)
-	if err != nil {
-		return nil, err
+		})
+		if err != nil {
+			return nil, err
+		}
+		err = updateInstallState(context, func(state *model.InstallState) {
+			state.Stage = model.StageExtracted
+			state.SHA256 = context.ArchiveSHA256
+		})
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	// 移动解压后的内容到最终目录
@@ -592,7 +1110,10 @@ func (s *VersionService) extractGoArchiveWithProgress(context *model.Installatio
 		progressUI.SetProgress(80)
 		progressUI.SetMessage("移动文件到最终目录...")
 	}
-	if err := s.moveExtractedContentWithProgress(tempExtractDir, context.Paths.VersionDir, archiveInfo.RootDir, progressUI); err != nil {
+	if err := tx.recordMove(tempExtractDir, archiveInfo.RootDir, context.Paths.VersionDir); err != nil {
+		return nil, err
+	}
+	if err := s.moveExtractedContentWithProgress(ctx, tempExtractDir, context.Paths.VersionDir, archiveInfo.RootDir, progressUI); err != nil {
 		return nil, fmt.Errorf("移动解压内容失败: %v", err)
 	}
 
@@ -607,13 +1128,24 @@ func (s *VersionService) extractGoArchiveWithProgress(context *model.Installatio
 	}, nil
 }
 
+// extractArchive 解压压缩包，解压服务支持上下文时 ctx 取消后停止解压
+func (s *VersionService) extractArchive(ctx context.Context, archivePath, destPath string, progress ExtractionProgressCallback) error {
+	if extractor, ok := s.archiveExtractor.(ContextArchiveExtractor); ok {
+		return extractor.ExtractWithContext(ctx, archivePath, destPath, progress)
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return s.archiveExtractor.Extract(archivePath, destPath, progress)
+}
+
 // moveExtractedContent 移动解压后的内容（优化版本）
 func (s *VersionService) moveExtractedContent(srcDir, destDir, rootDir string) error {
-	return s.moveExtractedContentWithProgress(srcDir, destDir, rootDir, nil)
+	return s.moveExtractedContentWithProgress(context.Background(), srcDir, destDir, rootDir, nil)
 }
 
-// moveExtractedContentWithProgress 带进度显示的移动解压后的内容
-func (s *VersionService) moveExtractedContentWithProgress(srcDir, destDir, rootDir string, progressUI ProgressReporter) error {
+// moveExtractedContentWithProgress 带进度显示的移动解压后的内容，ctx 取消时停止移动
+func (s *VersionService) moveExtractedContentWithProgress(ctx context.Context, srcDir, destDir, rootDir string, progressUI ProgressReporter) error {
 	// 如果有根目录，需要从根目录中移动内容
 	if rootDir != "" {
 		srcDir = filepath.Join(srcDir, rootDir)
@@ -624,10 +1156,6 @@ func (s *VersionService) moveExtractedContentWithProgress(srcDir, destDir, rootD
 		return fmt.Errorf("源目录不存在: %s", srcDir)
 	}
 
-	// 创建带超时的上下文（10分钟超时）
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
-	defer cancel()
-
 	// 优化策略：尝试直接移动整个目录，如果失败再逐个文件处理
 	if progressUI != nil {
 		progressUI.SetMessage("尝试快速目录移动...")
@@ -672,11 +1200,11 @@ func (s *VersionService) tryDirectoryMove(srcDir, destDir string) error {
 
 // moveContentBatch 批量移动文件内容（优化版本）
 func (s *VersionService) moveContentBatch(srcDir, destDir string) error {
-	return s.moveContentBatchWithProgress(srcDir, destDir, nil)
+	return s.moveContentBatchWithProgress(context.Background(), srcDir, destDir, nil)
 }
 
 // moveContentBatchWithProgress 带进度显示的批量移动文件内容
-func (s *VersionService) moveContentBatchWithProgress(srcDir, destDir string, progressUI ProgressReporter) error {
+func (s *VersionService) moveContentBatchWithProgress(ctx context.Context, srcDir, destDir string, progressUI ProgressReporter) error {
 	// 使用并发处理来提高性能
 	const maxWorkers = 4
 	const batchSize = 100
@@ -710,6 +1238,9 @@ func (s *VersionService) moveContentBatchWithProgress(srcDir, destDir string, pr
 		progressUI.SetMessage(fmt.Sprintf("创建 %d 个目录...", len(dirs)))
 	}
 	for i, dir := range dirs {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		relPath, err := filepath.Rel(srcDir, dir)
 		if err != nil {
 			return err
@@ -729,16 +1260,16 @@ func (s *VersionService) moveContentBatchWithProgress(srcDir, destDir string, pr
 	if progressUI != nil {
 		progressUI.SetMessage(fmt.Sprintf("移动 %d 个文件...", len(files)))
 	}
-	return s.moveFilesConcurrentWithProgress(files, srcDir, destDir, maxWorkers, batchSize, progressUI)
+	return s.moveFilesConcurrentWithProgress(ctx, files, srcDir, destDir, maxWorkers, batchSize, progressUI)
 }
 
 // moveFilesConcurrent 并发移动文件
 func (s *VersionService) moveFilesConcurrent(files []string, srcDir, destDir string, maxWorkers, batchSize int) error {
-	return s.moveFilesConcurrentWithProgress(files, srcDir, destDir, maxWorkers, batchSize, nil)
+	return s.moveFilesConcurrentWithProgress(context.Background(), files, srcDir, destDir, maxWorkers, batchSize, nil)
 }
 
-// moveFilesConcurrentWithProgress 带进度显示的并发移动文件
-func (s *VersionService) moveFilesConcurrentWithProgress(files []string, srcDir, destDir string, maxWorkers, batchSize int, progressUI ProgressReporter) error {
+// moveFilesConcurrentWithProgress 带进度显示的并发移动文件，ctx 取消后不再移动新的文件
+func (s *VersionService) moveFilesConcurrentWithProgress(ctx context.Context, files []string, srcDir, destDir string, maxWorkers, batchSize int, progressUI ProgressReporter) error {
 	if len(files) == 0 {
 		return nil
 	}
@@ -756,6 +1287,10 @@ func (s *VersionService) moveFilesConcurrentWithProgress(files []string, srcDir,
 
 			processedCount := 0
 			for file := range fileChan {
+				if err := ctx.Err(); err != nil {
+					errorChan <- err
+					return
+				}
 				if err := s.moveFile(file, srcDir, destDir); err != nil {
 					errorChan <- err
 					return
@@ -776,7 +1311,11 @@ func (s *VersionService) moveFilesConcurrentWithProgress(files []string, srcDir,
 	go func() {
 		defer close(fileChan)
 		for _, file := range files {
-			fileChan <- file
+			select {
+			case fileChan <- file:
+			case <-ctx.Done():
+				return
+			}
 		}
 	}()
 
@@ -888,6 +1427,11 @@ func (s *VersionService) copyFileOptimized(src, dst string) error {
 
 // configureInstallation 配置安装
 func (s *VersionService) configureInstallation(context *model.InstallationContext) error {
+	// 其他平台的Go无法在当前系统执行，只检查文件
+	if isCrossPlatformInstall(context.Options) {
+		return verifyCrossPlatformInstallation(context)
+	}
+
 	// 验证Go二进制文件
 	goExecPath := s.getGoExecutablePath(context.Paths.VersionDir)
 	if _, err := os.Stat(goExecPath); os.IsNotExist(err) {
@@ -907,6 +1451,29 @@ func (s *VersionService) configureInstallation(context *model.InstallationContex
 	return nil
 }
 
+// verifyCrossPlatformInstallation 通过可执行文件和VERSION文件验证其他平台的安装结果
+func verifyCrossPlatformInstallation(context *model.InstallationContext) error {
+	goExecName := "go"
+	if context.SystemInfo.OS == "windows" {
+		goExecName = "go.exe"
+	}
+	goExecPath := filepath.Join(context.Paths.VersionDir, "bin", goExecName)
+	if _, err := os.Stat(goExecPath); os.IsNotExist(err) {
+		return fmt.Errorf("go可执行文件不存在: %s", goExecPath)
+	}
+
+	data, err := os.ReadFile(filepath.Join(context.Paths.VersionDir, "VERSION"))
+	if err != nil {
+		return fmt.Errorf("读取VERSION文件失败: %v", err)
+	}
+	lines := strings.SplitN(string(data), "\n", 2)
+	actualVersion := strings.TrimPrefix(strings.TrimSpace(lines[0]), "go")
+	if actualVersion != context.Version {
+		return fmt.Errorf("版本不匹配: 期望 %s, 实际 %s", context.Version, actualVersion)
+	}
+	return nil
+}
+
 // createGoVersionRecord 创建GoVersion记录
 func (s *VersionService) createGoVersionRecord(
 	context *model.InstallationContext,
@@ -914,24 +1481,28 @@ func (s *VersionService) createGoVersionRecord(
 	extractInfo *model.ExtractInfo,
 ) (*model.GoVersion, error) {
 	now := time.Now()
+	// 保存 go version 的输出，用于区分同一发布版本的不同构建
+	_, output, _ := s.readGoVersion(context.Paths.VersionDir)
 
 	return &model.GoVersion{
-		Version:      context.Version,
+		Version:      context.ToolchainName(),
+		Release:      context.Version,
 		Path:         context.Paths.VersionDir,
 		IsActive:     false,
-		Source:       model.SourceOnline,
+		Source:       context.Source,
 		DownloadInfo: downloadInfo,
 		ExtractInfo:  extractInfo,
 		ValidationInfo: &model.ValidationInfo{
 			ChecksumValid:   true, // 简化处理
 			ExecutableValid: true,
 			VersionValid:    true,
+			GoVersionOutput: output,
 		},
 		SystemInfo:      context.SystemInfo,
 		CreatedAt:       now,
 		UpdatedAt:       now,
 		InstallDuration: time.Since(context.StartTime),
-		Tags:            []string{"online"},
+		Tags:            []string{context.Source.String()},
 	}, nil
 }
 
@@ -956,79 +1527,79 @@ func (s *VersionService) createFailedResult(result *model.InstallationResult, er
 	return result, err
 }
 
-// cleanupFailedInstallation 清理失败的安装
-func (s *VersionService) cleanupFailedInstallation(context *model.InstallationContext) {
-	// 删除临时目录
-	os.RemoveAll(context.TempDir)
-
-	// 删除部分安装的版本目录
-	if _, err := os.Stat(context.Paths.VersionDir); err == nil {
-		os.RemoveAll(context.Paths.VersionDir)
-	}
-}
-
 // cleanupTempFiles 清理临时文件
 func (s *VersionService) cleanupTempFiles(context *model.InstallationContext) {
 	os.RemoveAll(context.TempDir)
 }
 
-// moveContentBatchWithTimeout 带超时的批量移动文件内容
+// moveContentBatchWithTimeout 在 ctx 的期限内批量移动文件内容
 func (s *VersionService) moveContentBatchWithTimeout(ctx context.Context, srcDir, destDir string, progressUI ProgressReporter) error {
 	// 使用通道来处理超时
 	resultChan := make(chan error, 1)
 
 	go func() {
-		resultChan <- s.moveContentBatchWithProgress(srcDir, destDir, progressUI)
+		resultChan <- s.moveContentBatchWithProgress(ctx, srcDir, destDir, progressUI)
 	}()
 
 	select {
 	case err := <-resultChan:
 		return err
 	case <-ctx.Done():
-		return fmt.Errorf("文件移动操作超时: %v", ctx.Err())
+		// 等待移动协程停止，避免与随后的清理操作同时修改目标目录
+		<-resultChan
+		if errors.Is(ctx.Err(), context.Canceled) {
+			return fmt.Errorf("文件移动操作已取消: %w", ctx.Err())
+		}
+		return fmt.Errorf("文件移动操作超时: %w", ctx.Err())
 	}
 }
 
 // selectMirror 选择镜像源
-func (s *VersionService) selectMirror(options *model.InstallOptions) (string, error) {
-	ctx := context.Background()
-
+func (s *VersionService) selectMirror(ctx context.Context, options *model.InstallOptions) (*Mirror, error) {
 	// 如果启用了自动镜像选择
 	if options.AutoMirror {
 		mirrors := s.mirrorService.GetAvailableMirrors()
-		if len(mirrors) == 0 {
-			return "official", nil // 回退到官方源
+		if len(mirrors) > 0 {
+			// 选择评分最高的镜像，失败时回退到官方源
+			fastest, err := s.mirrorService.SelectFastestMirror(ctx, mirrors)
+			// 保存本次测速记录，供之后的镜像评分使用（未加载配置文件时忽略）
+			s.mirrorService.SaveConfig("")
+			if err == nil {
+				return fastest, nil
+			}
 		}
+		return s.mirrorService.GetMirrorByName("official")
+	}
 
-		// 选择最快的镜像
-		fastest, err := s.mirrorService.SelectFastestMirror(ctx, mirrors)
-		if err != nil {
-			// 如果选择失败，回退到官方源
-			return "official", nil
+	name := options.Mirror
+	if name == "" {
+		// 默认使用官方源，官方源被禁用时使用排序最靠前的可用镜像
+		name = "official"
+		if official, err := s.mirrorService.GetMirrorByName(name); err == nil && official.Disabled {
+			if ranked := s.mirrorService.RankMirrors(s.mirrorService.GetAvailableMirrors()); len(ranked) > 0 {
+				return &ranked[0], nil
+			}
 		}
-
-		return fastest.Name, nil
 	}
 
-	// 如果指定了镜像名称
-	if options.Mirror != "" {
-		// 验证镜像是否存在
-		_, err := s.mirrorService.GetMirrorByName(options.Mirror)
-		if err != nil {
-			return "", fmt.Errorf("指定的镜像 '%s' 不存在", options.Mirror)
+	// 预设镜像和自定义镜像统一从镜像服务中查找
+	mirror, err := s.mirrorService.GetMirrorByName(name)
+	if err != nil {
+		if s.mirrorConfigErr != nil {
+			return nil, fmt.Errorf("指定的镜像 '%s' 不存在（%v）", name, s.mirrorConfigErr)
 		}
+		return nil, fmt.Errorf("指定的镜像 '%s' 不存在", name)
+	}
+	if mirror.Disabled && options.Mirror != "" {
+		return nil, fmt.Errorf("镜像 '%s' 已禁用（使用 'go-version mirror enable %s' 启用）", name, name)
+	}
 
-		// 验证镜像是否可用
-		mirror, _ := s.mirrorService.GetMirrorByName(options.Mirror)
-		if mirror != nil {
-			if err := s.mirrorService.ValidateMirror(ctx, *mirror); err != nil {
-				return "", fmt.Errorf("镜像 '%s' 不可用: %v", options.Mirror, err)
-			}
+	// 验证指定的镜像是否可用
+	if options.Mirror != "" {
+		if err := s.mirrorService.ValidateMirror(ctx, *mirror); err != nil {
+			return nil, fmt.Errorf("镜像 '%s' 不可用: %v", name, err)
 		}
-
-		return options.Mirror, nil
 	}
 
-	// 默认使用官方源
-	return "official", nil
+	return mirror, nil
 }
@@ -0,0 +1,340 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"time"
+	"version-list/internal/domain/model"
+)
+
+// archiveFilenamePattern Go官方压缩包文件名格式，如 go1.22.7.linux-amd64.tar.gz
+var archiveFilenamePattern = regexp.MustCompile(`^go(\d+\.\d+(?:\.\d+)?(?:(?:rc|beta)\d+)?)\.([a-z0-9]+)-([a-z0-9]+)\.(?:tar\.gz|zip)$`)
+
+// ArchiveFilenameInfo 从压缩包文件名中解析出的信息
+type ArchiveFilenameInfo struct {
+	Version string // Go版本号
+	OS      string // 操作系统
+	Arch    string // CPU架构（已转换为GOARCH格式）
+}
+
+// ParseArchiveFilename 从Go官方压缩包文件名中解析版本和平台，不匹配时返回nil
+func ParseArchiveFilename(filename string) *ArchiveFilenameInfo {
+	matches := archiveFilenamePattern.FindStringSubmatch(filename)
+	if matches == nil {
+		return nil
+	}
+
+	// 发布文件名使用的架构名称（如 armv6l）按平台表转换为 GOARCH，平台表中没有时保留原名
+	arch := matches[3]
+	if platform, ok := LookupPlatformByArtifact(matches[2], arch); ok {
+		arch = platform.Arch
+	}
+
+	return &ArchiveFilenameInfo{
+		Version: matches[1],
+		OS:      matches[2],
+		Arch:    arch,
+	}
+}
+
+// InstallFromArchive 从本地压缩包安装Go，版本号从文件名或压缩包中的VERSION文件识别，ctx 取消时停止解压和移动
+func (s *VersionService) InstallFromArchive(ctx context.Context, archivePath string, options *model.InstallOptions, progressUI ProgressReporter) (*model.InstallationResult, error) {
+	return s.installFromArchive(ctx, archivePath, options, nil, progressUI)
+}
+
+// installFromArchive 从本地压缩包安装Go，origin 不为空时在版本记录中保留压缩包的原始下载信息，ctx 取消时停止解压和移动
+func (s *VersionService) installFromArchive(ctx context.Context, archivePath string, options *model.InstallOptions, origin *model.DownloadInfo, progressUI ProgressReporter) (*model.InstallationResult, error) {
+	if options == nil {
+		options = &model.InstallOptions{}
+	}
+	startTime := time.Now()
+
+	absPath, err := filepath.Abs(archivePath)
+	if err != nil {
+		return nil, fmt.Errorf("解析压缩包路径失败: %v", err)
+	}
+	fileInfo, err := os.Stat(absPath)
+	if err != nil {
+		return nil, fmt.Errorf("压缩包不存在: %s", archivePath)
+	}
+	if fileInfo.IsDir() {
+		return nil, fmt.Errorf("路径不是文件: %s", archivePath)
+	}
+
+	// 从文件名识别版本和平台
+	filename := filepath.Base(absPath)
+	nameInfo := ParseArchiveFilename(filename)
+	if nameInfo != nil && (nameInfo.OS != runtime.GOOS || nameInfo.Arch != runtime.GOARCH) {
+		return nil, fmt.Errorf("压缩包平台 %s/%s 与当前系统 %s/%s 不匹配", nameInfo.OS, nameInfo.Arch, runtime.GOOS, runtime.GOARCH)
+	}
+
+	// 验证校验和及压缩包完整性
+	if progressUI != nil {
+		progressUI.SetStage("验证文件")
+		progressUI.SetProgress(10)
+		progressUI.SetMessage(fmt.Sprintf("正在验证 %s...", filename))
+	}
+	checksum, err := NewFileValidator().CalculateChecksum(absPath, ChecksumTypeSHA256)
+	if err != nil {
+		return nil, fmt.Errorf("计算压缩包校验和失败: %v", err)
+	}
+	if options.SHA256 != "" && !strings.EqualFold(checksum, options.SHA256) {
+		return nil, fmt.Errorf("压缩包校验和不匹配: 期望 %s, 实际 %s", options.SHA256, checksum)
+	}
+	if !options.SkipVerification {
+		if err := s.archiveExtractor.ValidateArchive(absPath); err != nil {
+			return nil, fmt.Errorf("压缩包验证失败: %v", err)
+		}
+	}
+
+	archiveInfo, err := s.archiveExtractor.GetArchiveInfo(absPath)
+	if err != nil {
+		return nil, fmt.Errorf("获取压缩包信息失败: %v", err)
+	}
+
+	// 每个步骤执行前写入安装日志，失败或中断时按相反顺序回滚
+	tx, err := s.beginInstallTransaction(model.SourceArchive, "", options)
+	if err != nil {
+		return nil, err
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			tx.abort()
+		}
+	}()
+	if err := tx.identify("", absPath); err != nil {
+		return nil, err
+	}
+
+	// 解压到临时目录
+	tempDir, err := os.MkdirTemp("", "go-install-archive-")
+	if err != nil {
+		return nil, fmt.Errorf("创建临时目录失败: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+	if err := tx.record(model.JournalStep{Type: model.JournalTempDir, Path: tempDir}); err != nil {
+		return nil, err
+	}
+	if err := tx.setStatus(model.StatusExtracting); err != nil {
+		return nil, err
+	}
+
+	if progressUI != nil {
+		progressUI.SetStage("解压文件")
+		progressUI.SetProgress(20)
+		progressUI.SetMessage("正在解压安装包...")
+	}
+	extractStart := time.Now()
+	tempExtractDir := filepath.Join(tempDir, "extracted")
+	err = s.extractArchive(ctx, absPath, tempExtractDir, func(progress ExtractionProgress) {
+		if progressUI != nil {
+			// 计算解压进度 (20-70%)
+			progressUI.SetProgress(progress.Percentage*0.50 + 20.0)
+			progressUI.SetMessage(fmt.Sprintf("解压中... %.1f%% (%d/%d 文件)",
+				progress.Percentage, progress.ProcessedFiles, progress.TotalFiles))
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("解压失败: %v", err)
+	}
+
+	// 确定版本号：文件名与VERSION文件都存在时必须一致
+	fileVersion := readGoVersionFile(filepath.Join(tempExtractDir, archiveInfo.RootDir))
+	var version string
+	switch {
+	case nameInfo != nil && fileVersion != "" && nameInfo.Version != fileVersion:
+		return nil, fmt.Errorf("版本不匹配: 文件名为 %s, VERSION文件为 %s", nameInfo.Version, fileVersion)
+	case nameInfo != nil:
+		version = nameInfo.Version
+	case fileVersion != "":
+		version = fileVersion
+	default:
+		return nil, fmt.Errorf("无法从文件名或VERSION文件识别Go版本: %s", filename)
+	}
+
+	// 检查版本是否已存在
+	name, err := resolveToolchainName(version, options)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.prepareForInstall(name, options, progressUI); err != nil {
+		return nil, err
+	}
+	if err := tx.identify(version, absPath); err != nil {
+		return nil, err
+	}
+
+	context := s.createArchiveInstallationContext(version, absPath, tempDir, options)
+	context.StartTime = startTime
+	result := &model.InstallationResult{
+		Version: name,
+		Path:    context.Paths.VersionDir,
+	}
+	if err := s.runPreInstallHooks(ctx, context, result); err != nil {
+		return s.createFailedResult(result, err)
+	}
+
+	downloadInfo := &model.DownloadInfo{
+		URL:          "file://" + filepath.ToSlash(absPath),
+		Filename:     filename,
+		Size:         fileInfo.Size(),
+		Checksum:     checksum,
+		ChecksumType: ChecksumTypeSHA256,
+		DownloadedAt: fileInfo.ModTime(),
+	}
+	if origin != nil {
+		downloadInfo.URL = origin.URL
+		downloadInfo.DownloadedAt = origin.DownloadedAt
+		downloadInfo.Duration = origin.Duration
+		downloadInfo.Speed = origin.Speed
+	}
+
+	installResult, err := s.completeArchiveInstallation(ctx, tx, context, tempExtractDir, archiveInfo, extractStart, downloadInfo, result, progressUI)
+	if err != nil {
+		return installResult, err
+	}
+
+	// 提交安装事务，此后不再回滚
+	if err := tx.commit(); err != nil {
+		return s.createFailedResult(installResult, err)
+	}
+	committed = true
+	return installResult, nil
+}
+
+// createArchiveInstallationContext 创建压缩包安装的上下文
+func (s *VersionService) createArchiveInstallationContext(version, archivePath, tempDir string, options *model.InstallOptions) *model.InstallationContext {
+	var versionDir, baseDir string
+	if options.CustomPath != "" {
+		versionDir = options.CustomPath
+		baseDir = filepath.Dir(options.CustomPath)
+	} else {
+		baseDir = s.getBaseInstallDir()
+		versionDir = filepath.Join(baseDir, options.ToolchainName(version))
+	}
+
+	return &model.InstallationContext{
+		Version: version,
+		Name:    options.Name,
+		Source:  model.SourceArchive,
+		SystemInfo: &model.SystemInfo{
+			OS:       runtime.GOOS,
+			Arch:     runtime.GOARCH,
+			Version:  version,
+			Filename: filepath.Base(archivePath),
+		},
+		Paths: &model.InstallPaths{
+			BaseDir:     baseDir,
+			VersionDir:  versionDir,
+			TempDir:     tempDir,
+			ArchiveFile: archivePath,
+		},
+		TempDir:   tempDir,
+		Options:   options,
+		StartTime: time.Now(),
+		Status:    model.StatusExtracting,
+	}
+}
+
+// completeArchiveInstallation 将解压后的内容移动到版本目录并保存版本记录
+func (s *VersionService) completeArchiveInstallation(
+	ctx context.Context,
+	tx *installTransaction,
+	context *model.InstallationContext,
+	tempExtractDir string,
+	archiveInfo *ArchiveInfo,
+	extractStart time.Time,
+	downloadInfo *model.DownloadInfo,
+	result *model.InstallationResult,
+	progressUI ProgressReporter,
+) (*model.InstallationResult, error) {
+	// 移动解压后的内容到最终目录
+	if progressUI != nil {
+		progressUI.SetProgress(75)
+		progressUI.SetMessage("移动文件到最终目录...")
+	}
+	if err := tx.recordDirectory(model.JournalVersionDir, context.Paths.VersionDir); err != nil {
+		return s.createFailedResult(result, err)
+	}
+	if err := os.MkdirAll(context.Paths.VersionDir, 0755); err != nil {
+		return s.createFailedResult(result, fmt.Errorf("创建版本目录失败: %v", err))
+	}
+	if err := tx.recordMove(tempExtractDir, archiveInfo.RootDir, context.Paths.VersionDir); err != nil {
+		return s.createFailedResult(result, err)
+	}
+	if err := s.moveExtractedContentWithProgress(ctx, tempExtractDir, context.Paths.VersionDir, archiveInfo.RootDir, progressUI); err != nil {
+		return s.createFailedResult(result, fmt.Errorf("移动解压内容失败: %v", err))
+	}
+
+	extractInfo := &model.ExtractInfo{
+		ArchiveSize:   downloadInfo.Size,
+		ExtractedSize: archiveInfo.TotalSize,
+		FileCount:     archiveInfo.FileCount,
+		Duration:      time.Since(extractStart),
+		RootDir:       archiveInfo.RootDir,
+	}
+	result.DownloadInfo = downloadInfo
+	result.ExtractInfo = extractInfo
+
+	// 配置阶段
+	context.Status = model.StatusConfiguring
+	if progressUI != nil {
+		progressUI.SetStage("配置安装")
+		progressUI.SetProgress(85)
+		progressUI.SetMessage("配置Go环境...")
+	}
+	if err := s.configureInstallation(context); err != nil {
+		return s.createFailedResult(result, fmt.Errorf("配置失败: %v", err))
+	}
+
+	// 保存版本信息
+	if progressUI != nil {
+		progressUI.SetProgress(90)
+		progressUI.SetMessage("保存版本信息...")
+	}
+	goVersion, err := s.createGoVersionRecord(context, downloadInfo, extractInfo)
+	if err != nil {
+		return s.createFailedResult(result, fmt.Errorf("保存版本记录失败: %v", err))
+	}
+	if err := s.saveInstalledVersion(ctx, tx, goVersion, context.Options, result, progressUI); err != nil {
+		return s.createFailedResult(result, err)
+	}
+
+	context.Status = model.StatusCompleted
+	if progressUI != nil {
+		progressUI.SetStage("完成安装")
+		progressUI.SetProgress(100)
+		progressUI.SetMessage("安装完成!")
+	}
+	result.Success = true
+	result.Duration = time.Since(context.StartTime)
+
+	return result, nil
+}
+
+// readGoVersionFile 读取Go安装目录中VERSION文件记录的版本号，读取失败时返回空字符串
+func readGoVersionFile(goRoot string) string {
+	file, err := os.Open(filepath.Join(goRoot, "VERSION"))
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+
+	// 第一行格式为 go1.22.7
+	scanner := bufio.NewScanner(file)
+	if !scanner.Scan() {
+		return ""
+	}
+	line := strings.TrimSpace(scanner.Text())
+	if !strings.HasPrefix(line, "go") {
+		return ""
+	}
+	return strings.TrimPrefix(line, "go")
+}
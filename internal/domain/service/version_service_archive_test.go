@@ -0,0 +1,224 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"version-list/internal/domain/model"
+)
+
+// writeFakeGoArchive 将模拟Go压缩包写入指定文件名并返回路径及SHA-256
+func writeFakeGoArchive(t *testing.T, filename, version string) (string, string) {
+	t.Helper()
+	data := createFakeGoArchive(t, version)
+	path := filepath.Join(t.TempDir(), filename)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("写入压缩包失败: %v", err)
+	}
+	return path, fmt.Sprintf("%x", sha256.Sum256(data))
+}
+
+func TestParseArchiveFilename(t *testing.T) {
+	tests := []struct {
+		filename string
+		expected *ArchiveFilenameInfo
+	}{
+		{"go1.22.7.linux-amd64.tar.gz", &ArchiveFilenameInfo{Version: "1.22.7", OS: "linux", Arch: "amd64"}},
+		{"go1.21.0.windows-386.zip", &ArchiveFilenameInfo{Version: "1.21.0", OS: "windows", Arch: "386"}},
+		{"go1.23rc1.darwin-arm64.tar.gz", &ArchiveFilenameInfo{Version: "1.23rc1", OS: "darwin", Arch: "arm64"}},
+		{"go1.22.7.linux-armv6l.tar.gz", &ArchiveFilenameInfo{Version: "1.22.7", OS: "linux", Arch: "arm"}},
+		{"go1.22.7.freebsd-armv6l.tar.gz", &ArchiveFilenameInfo{Version: "1.22.7", OS: "freebsd", Arch: "arm"}},
+		{"go1.22.7.linux-sparc64.tar.gz", &ArchiveFilenameInfo{Version: "1.22.7", OS: "linux", Arch: "sparc64"}},
+		{"go.tar.gz", nil},
+		{"go1.22.7.src.tar.gz", nil},
+	}
+
+	for _, tt := range tests {
+		result := ParseArchiveFilename(tt.filename)
+		if tt.expected == nil {
+			if result != nil {
+				t.Errorf("ParseArchiveFilename(%s) = %+v, 期望 nil", tt.filename, result)
+			}
+			continue
+		}
+		if result == nil || *result != *tt.expected {
+			t.Errorf("ParseArchiveFilename(%s) = %+v, 期望 %+v", tt.filename, result, tt.expected)
+		}
+	}
+}
+
+func TestVersionService_InstallFromArchive(t *testing.T) {
+	service, versionRepo := newTestVersionService(t, "")
+
+	filename := fmt.Sprintf("go1.22.7.%s-%s.tar.gz", runtime.GOOS, runtime.GOARCH)
+	archivePath, checksum := writeFakeGoArchive(t, filename, "1.22.7")
+
+	result, err := service.InstallFromArchive(context.Background(), archivePath, &model.InstallOptions{SHA256: strings.ToUpper(checksum)}, &MockProgressReporter{})
+	if err != nil {
+		t.Fatalf("从压缩包安装失败: %v", err)
+	}
+	if !result.Success || result.Version != "1.22.7" {
+		t.Errorf("安装结果 = %+v, 期望成功安装 1.22.7", result)
+	}
+
+	goVersion, err := versionRepo.FindByVersion("1.22.7")
+	if err != nil {
+		t.Fatalf("版本未保存到仓库: %v", err)
+	}
+	if goVersion.Source != model.SourceArchive {
+		t.Errorf("安装来源 = %v, 期望 %v", goVersion.Source, model.SourceArchive)
+	}
+	if !goVersion.HasTag("archive") {
+		t.Errorf("标签 = %v, 期望包含 archive", goVersion.Tags)
+	}
+	if goVersion.DownloadInfo == nil || goVersion.DownloadInfo.Checksum != checksum {
+		t.Errorf("未记录压缩包校验和")
+	}
+	if err := goVersion.Validate(); err != nil {
+		t.Errorf("版本记录无效: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(goVersion.Path, "bin", "go")); err != nil {
+		t.Errorf("go可执行文件未安装: %v", err)
+	}
+}
+
+func TestVersionService_InstallFromArchive_VersionFromFile(t *testing.T) {
+	service, versionRepo := newTestVersionService(t, "")
+
+	// 文件名不包含版本信息时从VERSION文件识别
+	archivePath, _ := writeFakeGoArchive(t, "go-toolchain.tar.gz", "1.21.13")
+
+	result, err := service.InstallFromArchive(context.Background(), archivePath, nil, nil)
+	if err != nil {
+		t.Fatalf("从压缩包安装失败: %v", err)
+	}
+	if result.Version != "1.21.13" {
+		t.Errorf("识别的版本 = %s, 期望 1.21.13", result.Version)
+	}
+	if _, err := versionRepo.FindByVersion("1.21.13"); err != nil {
+		t.Errorf("版本未保存到仓库: %v", err)
+	}
+}
+
+func TestVersionService_InstallFromArchive_Errors(t *testing.T) {
+	service, versionRepo := newTestVersionService(t, "")
+
+	otherArch := "arm64"
+	if runtime.GOARCH == "arm64" {
+		otherArch = "amd64"
+	}
+
+	tests := []struct {
+		name     string
+		filename string
+		version  string
+		options  *model.InstallOptions
+		errText  string
+	}{
+		{
+			name:     "校验和不匹配",
+			filename: fmt.Sprintf("go1.22.7.%s-%s.tar.gz", runtime.GOOS, runtime.GOARCH),
+			version:  "1.22.7",
+			options:  &model.InstallOptions{SHA256: strings.Repeat("0", 64)},
+			errText:  "校验和不匹配",
+		},
+		{
+			name:     "平台不匹配",
+			filename: fmt.Sprintf("go1.22.7.%s-%s.tar.gz", runtime.GOOS, otherArch),
+			version:  "1.22.7",
+			errText:  "不匹配",
+		},
+		{
+			name:     "文件名与VERSION文件不一致",
+			filename: fmt.Sprintf("go1.22.7.%s-%s.tar.gz", runtime.GOOS, runtime.GOARCH),
+			version:  "1.22.6",
+			errText:  "版本不匹配",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			archivePath, _ := writeFakeGoArchive(t, tt.filename, tt.version)
+			_, err := service.InstallFromArchive(context.Background(), archivePath, tt.options, nil)
+			if err == nil || !strings.Contains(err.Error(), tt.errText) {
+				t.Errorf("错误 = %v, 期望包含 %q", err, tt.errText)
+			}
+		})
+	}
+
+	if versions, _ := versionRepo.FindAll(); len(versions) != 0 {
+		t.Errorf("安装失败时不应保存版本记录, 实际保存了 %d 个", len(versions))
+	}
+}
+
+func TestVersionService_InstallFromArchive_Named(t *testing.T) {
+	service, versionRepo := newTestVersionService(t, "")
+
+	filename := fmt.Sprintf("go1.22.7.%s-%s.tar.gz", runtime.GOOS, runtime.GOARCH)
+	archivePath, _ := writeFakeGoArchive(t, filename, "1.22.7")
+
+	// 同一发布版本的原版和自定义构建可以共存
+	if _, err := service.InstallFromArchive(context.Background(), archivePath, nil, nil); err != nil {
+		t.Fatalf("安装原版失败: %v", err)
+	}
+	result, err := service.InstallFromArchive(context.Background(), archivePath, &model.InstallOptions{Name: "1.22.7-fips"}, nil)
+	if err != nil {
+		t.Fatalf("以自定义名称安装失败: %v", err)
+	}
+	if result.Version != "1.22.7-fips" {
+		t.Errorf("安装结果版本 = %s, 期望 1.22.7-fips", result.Version)
+	}
+
+	named, err := versionRepo.FindByVersion("1.22.7-fips")
+	if err != nil {
+		t.Fatalf("自定义名称的版本未保存: %v", err)
+	}
+	if named.Release != "1.22.7" || !named.IsNamed() {
+		t.Errorf("发布版本 = %s, 期望 1.22.7", named.Release)
+	}
+	if filepath.Base(named.Path) != "1.22.7-fips" {
+		t.Errorf("安装目录 = %s, 期望以工具链名称命名", named.Path)
+	}
+	if named.ValidationInfo == nil || !strings.Contains(named.ValidationInfo.GoVersionOutput, "go version go1.22.7") {
+		t.Errorf("未记录 go version 输出: %+v", named.ValidationInfo)
+	}
+	if err := named.Validate(); err != nil {
+		t.Errorf("版本记录无效: %v", err)
+	}
+	if upstream, err := versionRepo.FindByVersion("1.22.7"); err != nil || upstream.Path == named.Path {
+		t.Errorf("原版记录被覆盖: %v", err)
+	}
+
+	if _, err := service.InstallFromArchive(context.Background(), archivePath, &model.InstallOptions{Name: "../fips"}, nil); err == nil {
+		t.Errorf("期望无效名称的错误")
+	}
+
+	// 导入已有安装时同样可以指定名称
+	imported, err := service.ImportLocal(named.Path, "corp-fips")
+	if err != nil {
+		t.Fatalf("以自定义名称导入失败: %v", err)
+	}
+	record, err := versionRepo.FindByVersion(imported)
+	if err != nil || record.Release != "1.22.7" || record.Source != model.SourceLocal {
+		t.Errorf("导入记录 = %+v, 期望名称 corp-fips、发布版本 1.22.7", record)
+	}
+	if _, err := service.ImportLocal(named.Path, "corp-fips"); err == nil {
+		t.Errorf("期望名称已存在的错误")
+	}
+
+	// 名称不能是其他Go发布版本号，否则会冒充该发布版本
+	if _, err := service.ImportLocal(named.Path, "1.23.0"); err == nil {
+		t.Errorf("期望发布版本号名称与检测到的版本不一致的错误")
+	}
+	if _, err := versionRepo.FindByVersion("1.23.0"); err == nil {
+		t.Errorf("不应以其他发布版本号保存记录")
+	}
+	if _, err := service.InstallFromArchive(context.Background(), archivePath, &model.InstallOptions{Name: "go1.23.0"}, nil); err == nil {
+		t.Errorf("期望发布版本号名称的错误")
+	}
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"version-list/internal/domain/model"
+)
+
+// BatchProgressReporter 批量安装进度报告接口
+type BatchProgressReporter interface {
+	ReporterFor(version string) ProgressReporter // 获取指定版本的进度报告器
+	Complete(result *model.InstallationResult)   // 报告单个版本安装完成
+}
+
+// InstallOnlineBatch 并行在线安装多个Go版本
+// parallel 限制同时进行的安装数量，返回结果与 versions 顺序一致，ctx 取消时中断所有安装
+func (s *VersionService) InstallOnlineBatch(ctx context.Context, versions []string, options *model.InstallOptions, parallel int, progressUI BatchProgressReporter) []*model.InstallationResult {
+	if parallel < 1 {
+		parallel = 1
+	}
+
+	results := make([]*model.InstallationResult, len(versions))
+	slots := make(chan struct{}, parallel)
+	var wg sync.WaitGroup
+
+	for i, version := range versions {
+		wg.Add(1)
+		go func(index int, version string) {
+			defer wg.Done()
+
+			slots <- struct{}{}
+			defer func() { <-slots }()
+
+			var reporter ProgressReporter
+			if progressUI != nil {
+				reporter = progressUI.ReporterFor(version)
+			}
+
+			result, err := s.InstallOnlineWithContext(ctx, version, options, reporter)
+			if result == nil {
+				result = &model.InstallationResult{Version: version}
+			}
+			if err != nil {
+				result.Success = false
+				result.Error = err.Error()
+			}
+
+			results[index] = result
+			if progressUI != nil {
+				progressUI.Complete(result)
+			}
+		}(i, version)
+	}
+
+	wg.Wait()
+	return results
+}
@@ -0,0 +1,212 @@
+package service
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	"version-list/internal/domain/model"
+)
+
+// testSystemDetector 将下载地址指向测试服务器的系统检测器
+type testSystemDetector struct {
+	SystemDetectorImpl
+	baseURL string
+}
+
+func (d *testSystemDetector) GetSystemInfoWithMirror(version, mirror string) (*model.SystemInfo, error) {
+	return d.GetSystemInfoForPlatform(version, runtime.GOOS, runtime.GOARCH, mirror)
+}
+
+func (d *testSystemDetector) GetSystemInfoForPlatform(version, goos, goarch, mirror string) (*model.SystemInfo, error) {
+	filename := fmt.Sprintf("go%s.%s-%s.tar.gz", version, goos, goarch)
+	return &model.SystemInfo{
+		OS:       goos,
+		Arch:     goarch,
+		Version:  version,
+		Filename: filename,
+		URL:      d.baseURL + "/" + filename,
+		Mirror:   mirror,
+	}, nil
+}
+
+// createFakeGoArchive 创建包含模拟go可执行文件的tar.gz压缩包
+func createFakeGoArchive(t *testing.T, version string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	gzWriter := gzip.NewWriter(&buf)
+	tarWriter := tar.NewWriter(gzWriter)
+
+	script := fmt.Sprintf("#!/bin/sh\necho \"go version go%s %s/%s\"\n", version, runtime.GOOS, runtime.GOARCH)
+	files := []struct {
+		name    string
+		content string
+		mode    int64
+	}{
+		{"go/VERSION", "go" + version + "\n", 0644},
+		{"go/bin/go", script, 0755},
+	}
+
+	for _, file := range files {
+		header := &tar.Header{
+			Name:     file.name,
+			Mode:     file.mode,
+			Size:     int64(len(file.content)),
+			Typeflag: tar.TypeReg,
+		}
+		if err := tarWriter.WriteHeader(header); err != nil {
+			t.Fatalf("写入tar文件头失败: %v", err)
+		}
+		if _, err := tarWriter.Write([]byte(file.content)); err != nil {
+			t.Fatalf("写入tar文件内容失败: %v", err)
+		}
+	}
+
+	tarWriter.Close()
+	gzWriter.Close()
+	return buf.Bytes()
+}
+
+// newFakeReleaseServer 创建提供模拟Go压缩包的测试服务器，missing 中的版本返回404
+func newFakeReleaseServer(t *testing.T, versions []string, missing ...string) *httptest.Server {
+	t.Helper()
+
+	archives := make(map[string][]byte)
+	for _, version := range versions {
+		filename := fmt.Sprintf("go%s.%s-%s.tar.gz", version, runtime.GOOS, runtime.GOARCH)
+		archives["/"+filename] = createFakeGoArchive(t, version)
+	}
+	for _, version := range missing {
+		filename := fmt.Sprintf("go%s.%s-%s.tar.gz", version, runtime.GOOS, runtime.GOARCH)
+		delete(archives, "/"+filename)
+	}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, exists := archives[r.URL.Path]
+		if !exists {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Length", fmt.Sprintf("%d", len(data)))
+		w.WriteHeader(http.StatusOK)
+		if r.Method != http.MethodHead {
+			w.Write(data)
+		}
+	}))
+}
+
+// newTestVersionService 创建使用测试服务器下载的版本服务
+func newTestVersionService(t *testing.T, serverURL string) (*VersionService, *MockVersionRepository) {
+	t.Helper()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("模拟go可执行文件依赖shell脚本")
+	}
+	t.Setenv("HOME", t.TempDir())
+
+	versionRepo := NewMockVersionRepository()
+	service := NewVersionServiceWithDependencies(
+		versionRepo,
+		NewMockEnvironmentRepository(),
+		&testSystemDetector{baseURL: serverURL},
+		NewDownloadService(&DownloadOptions{
+			MaxRetries:         0,
+			Timeout:            10 * time.Second,
+			ChunkSize:          32 * 1024,
+			ProgressUpdateRate: 10 * time.Millisecond,
+		}),
+		NewArchiveExtractor(nil),
+		NewMirrorService(),
+	)
+	return service, versionRepo
+}
+
+// recordingBatchReporter 记录批量安装进度的报告器
+type recordingBatchReporter struct {
+	mu        sync.Mutex
+	reporters map[string]*MockProgressReporter
+	completed []string
+}
+
+func (r *recordingBatchReporter) ReporterFor(version string) ProgressReporter {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	reporter := &MockProgressReporter{}
+	r.reporters[version] = reporter
+	return reporter
+}
+
+func (r *recordingBatchReporter) Complete(result *model.InstallationResult) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.completed = append(r.completed, result.Version)
+}
+
+func TestVersionService_InstallOnlineBatch(t *testing.T) {
+	versions := []string{"1.21.13", "1.22.7", "1.23.2"}
+	server := newFakeReleaseServer(t, versions)
+	defer server.Close()
+
+	service, versionRepo := newTestVersionService(t, server.URL)
+	reporter := &recordingBatchReporter{reporters: make(map[string]*MockProgressReporter)}
+
+	results := service.InstallOnlineBatch(context.Background(), versions, &model.InstallOptions{SkipVerification: true}, 2, reporter)
+
+	if len(results) != len(versions) {
+		t.Fatalf("结果数量 = %d, 期望 %d", len(results), len(versions))
+	}
+
+	for i, result := range results {
+		if result.Version != versions[i] {
+			t.Errorf("结果顺序错误: 第 %d 个为 %s, 期望 %s", i, result.Version, versions[i])
+		}
+		if !result.Success {
+			t.Errorf("版本 %s 安装失败: %s", result.Version, result.Error)
+		}
+		if _, err := versionRepo.FindByVersion(result.Version); err != nil {
+			t.Errorf("版本 %s 未保存到仓库", result.Version)
+		}
+	}
+
+	if len(reporter.completed) != len(versions) {
+		t.Errorf("完成通知数量 = %d, 期望 %d", len(reporter.completed), len(versions))
+	}
+	for _, version := range versions {
+		if r := reporter.reporters[version]; r == nil || len(r.updates) == 0 {
+			t.Errorf("版本 %s 没有收到进度更新", version)
+		}
+	}
+
+	if active := service.downloadManager.GetActiveDownloads(); len(active) != 0 {
+		t.Errorf("安装完成后仍有 %d 个活跃下载", len(active))
+	}
+}
+
+func TestVersionService_InstallOnlineBatch_PartialFailure(t *testing.T) {
+	versions := []string{"1.21.13", "1.22.7"}
+	server := newFakeReleaseServer(t, versions, "1.22.7")
+	defer server.Close()
+
+	service, _ := newTestVersionService(t, server.URL)
+
+	results := service.InstallOnlineBatch(context.Background(), versions, &model.InstallOptions{SkipVerification: true}, 0, nil)
+
+	if !results[0].Success {
+		t.Errorf("版本 1.21.13 应该安装成功: %s", results[0].Error)
+	}
+	if results[1].Success {
+		t.Error("版本 1.22.7 应该安装失败")
+	}
+	if !strings.Contains(results[1].Error, "404") {
+		t.Errorf("失败原因应包含HTTP状态码: %s", results[1].Error)
+	}
+}
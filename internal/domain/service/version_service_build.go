@@ -0,0 +1,588 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"time"
+	"version-list/internal/domain/model"
+)
+
+// sourceBuildOutputLines 编译失败时在错误信息中保留的输出行数
+const sourceBuildOutputLines = 20
+
+// bootstrapRequirements 各版本编译所需的最低引导工具链版本，按版本从新到旧排列
+var bootstrapRequirements = []struct {
+	since   string // 从该版本开始
+	minimum string // 最低引导版本
+}{
+	{"1.26", "1.24.6"},
+	{"1.24", "1.22.6"},
+	{"1.22", "1.20"},
+	{"1.20", "1.17.13"},
+	{"1.5", "1.4"},
+}
+
+// goReleaseVersionPattern Go发布版本号格式，如 1.21、1.21.5、1.22rc1
+var goReleaseVersionPattern = regexp.MustCompile(`^(\d+\.\d+(?:\.\d+)?)(?:(?:rc|beta)\d+)?$`)
+
+// makeBashStages make.bash 输出中标志编译阶段的前缀及对应的安装进度（40-85%）
+var makeBashStages = []struct {
+	prefix   string
+	progress float64
+}{
+	{"Building Go cmd/dist", 40},
+	{"Building Go toolchain1", 45},
+	{"Building Go bootstrap cmd/go", 55},
+	{"Building Go toolchain2", 60},
+	{"Building Go toolchain3", 70},
+	{"Building packages and commands", 78},
+	{"Installed Go", 85},
+}
+
+// MinimumBootstrapVersion 获取编译指定版本所需的最低引导工具链版本，无法识别版本时返回空字符串
+func MinimumBootstrapVersion(version string) string {
+	release := releaseNumber(version)
+	if release == "" {
+		return ""
+	}
+	for _, requirement := range bootstrapRequirements {
+		if model.CompareVersionStrings(release, requirement.since).Result >= 0 {
+			return requirement.minimum
+		}
+	}
+	return ""
+}
+
+// InstallFromSource 从源码编译安装Go
+// source 为版本号时下载 go<版本>.src.tar.gz，为目录时使用本地Go源码（如git检出）
+// 使用已安装的版本作为引导工具链（GOROOT_BOOTSTRAP）运行 make.bash，编译输出实时报告到 progressUI，ctx 取消时停止下载和编译
+func (s *VersionService) InstallFromSource(ctx context.Context, source string, options *model.InstallOptions, progressUI ProgressReporter) (*model.InstallationResult, error) {
+	if options == nil {
+		options = &model.InstallOptions{}
+	}
+	startTime := time.Now()
+
+	tempDir, err := os.MkdirTemp("", "go-build-")
+	if err != nil {
+		return nil, fmt.Errorf("创建临时目录失败: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	// 准备源码：下载源码包或使用本地目录
+	if progressUI != nil {
+		progressUI.SetStage("准备源码")
+		progressUI.SetProgress(5)
+	}
+	prepared, err := s.prepareSourceTree(ctx, source, tempDir, options, progressUI)
+	if err != nil {
+		return nil, err
+	}
+	version := prepared.version
+
+	name, err := resolveToolchainName(version, options)
+	if err != nil {
+		return nil, err
+	}
+
+	// 先选择引导工具链，避免强制重新安装时删除现有版本后才发现无法编译
+	bootstrap, err := s.selectBootstrapToolchain(name, version, options.BootstrapVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.prepareForInstall(name, options, progressUI); err != nil {
+		return nil, err
+	}
+
+	context := s.createSourceInstallationContext(version, tempDir, options)
+	context.StartTime = startTime
+	result := &model.InstallationResult{
+		Version:      name,
+		Path:         context.Paths.VersionDir,
+		DownloadInfo: prepared.downloadInfo,
+	}
+	if err := s.runPreInstallHooks(ctx, context, result); err != nil {
+		return s.createFailedResult(result, err)
+	}
+
+	buildResult, err := s.buildFromSource(ctx, context, prepared, bootstrap, result, progressUI)
+	if err != nil {
+		os.RemoveAll(context.Paths.VersionDir)
+		return buildResult, err
+	}
+	return buildResult, nil
+}
+
+// preparedSource 准备好的Go源码
+type preparedSource struct {
+	version      string              // 版本号，开发分支为 devel-<提交哈希前12位>
+	dir          string              // 源码根目录（包含 src/make.bash）
+	commit       string              // git提交哈希（源码包为空）
+	downloadInfo *model.DownloadInfo // 源码包下载信息（本地目录为空）
+	fileCount    int                 // 源码包解压的文件数
+	size         int64               // 源码包解压后的大小
+}
+
+// prepareSourceTree 下载并解压源码包，或检查本地源码目录
+func (s *VersionService) prepareSourceTree(ctx context.Context, source, tempDir string, options *model.InstallOptions, progressUI ProgressReporter) (*preparedSource, error) {
+	if info, err := os.Stat(source); err == nil && info.IsDir() {
+		return prepareLocalSourceTree(source)
+	}
+	if releaseNumber(source) == "" {
+		return nil, fmt.Errorf("源码不是有效的版本号或目录: %s", source)
+	}
+	return s.downloadSourceArchive(ctx, source, tempDir, options, progressUI)
+}
+
+// prepareLocalSourceTree 检查本地Go源码目录，从VERSION文件或git提交确定版本号
+func prepareLocalSourceTree(dir string) (*preparedSource, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("解析源码路径失败: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(absDir, "src", makeScriptName())); err != nil {
+		return nil, fmt.Errorf("目录 %s 不是Go源码目录（缺少 src/%s）", dir, makeScriptName())
+	}
+
+	prepared := &preparedSource{
+		dir:     absDir,
+		version: readGoVersionFile(absDir),
+		commit:  gitCommitHash(absDir),
+	}
+	if prepared.version == "" {
+		// 开发分支没有VERSION文件，使用提交哈希作为版本标识
+		if prepared.commit == "" {
+			return nil, fmt.Errorf("无法确定源码版本: %s 中没有VERSION文件，也不是git检出", dir)
+		}
+		prepared.version = "devel-" + prepared.commit[:12]
+	}
+	return prepared, nil
+}
+
+// downloadSourceArchive 从镜像下载源码包并解压到临时目录
+func (s *VersionService) downloadSourceArchive(ctx context.Context, version, tempDir string, options *model.InstallOptions, progressUI ProgressReporter) (*preparedSource, error) {
+	mirror, err := s.selectMirror(ctx, options)
+	if err != nil {
+		return nil, fmt.Errorf("选择镜像失败: %v", err)
+	}
+
+	if mirror.BaseURL == "" {
+		// 只配置了URL模板的镜像无法确定源码包地址，使用官方源
+		if mirror, err = s.mirrorService.GetMirrorByName("official"); err != nil {
+			return nil, fmt.Errorf("选择镜像失败: %v", err)
+		}
+	}
+	filename := fmt.Sprintf("go%s.src.tar.gz", version)
+	url := mirror.DownloadURL(filename)
+	archivePath := filepath.Join(tempDir, filename)
+
+	if progressUI != nil {
+		progressUI.SetMessage(fmt.Sprintf("正在下载 %s...", filename))
+	}
+	downloadStart := time.Now()
+	err = s.downloadService.DownloadWithContext(ctx, url, archivePath, func(downloaded, total int64, speed float64) {
+		if progressUI != nil && total > 0 {
+			// 下载进度占 5-25%
+			progressUI.SetProgress(float64(downloaded)/float64(total)*20.0 + 5.0)
+			progressUI.SetMessage(fmt.Sprintf("下载源码包... %.1f%% (%s/s)", float64(downloaded)/float64(total)*100, formatBytes(int64(speed))))
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("下载源码包失败: %v", err)
+	}
+	duration := time.Since(downloadStart)
+
+	checksum, err := NewFileValidator().CalculateChecksum(archivePath, ChecksumTypeSHA256)
+	if err != nil {
+		return nil, fmt.Errorf("计算源码包校验和失败: %v", err)
+	}
+	if !options.SkipVerification {
+		if progressUI != nil {
+			progressUI.SetMessage("验证源码包完整性...")
+		}
+		expected, err := s.sourceArchiveChecksum(ctx, version, filename, tempDir, options)
+		if err != nil {
+			return nil, err
+		}
+		if !strings.EqualFold(checksum, expected) {
+			return nil, fmt.Errorf("源码包校验和不匹配: 期望 %s, 实际 %s", expected, checksum)
+		}
+	}
+	fileInfo, err := os.Stat(archivePath)
+	if err != nil {
+		return nil, fmt.Errorf("读取源码包失败: %v", err)
+	}
+
+	if progressUI != nil {
+		progressUI.SetProgress(25)
+		progressUI.SetMessage("正在解压源码包...")
+	}
+	archiveInfo, err := s.archiveExtractor.GetArchiveInfo(archivePath)
+	if err != nil {
+		return nil, fmt.Errorf("获取源码包信息失败: %v", err)
+	}
+	extractDir := filepath.Join(tempDir, "source")
+	if err := s.extractArchive(ctx, archivePath, extractDir, nil); err != nil {
+		return nil, fmt.Errorf("解压源码包失败: %w", err)
+	}
+	sourceDir := filepath.Join(extractDir, archiveInfo.RootDir)
+	if _, err := os.Stat(filepath.Join(sourceDir, "src", makeScriptName())); err != nil {
+		return nil, fmt.Errorf("源码包中缺少 src/%s", makeScriptName())
+	}
+
+	if fileVersion := readGoVersionFile(sourceDir); fileVersion != "" && fileVersion != version {
+		return nil, fmt.Errorf("版本不匹配: 期望 %s, 源码包VERSION文件为 %s", version, fileVersion)
+	}
+
+	return &preparedSource{
+		version:   version,
+		dir:       sourceDir,
+		fileCount: archiveInfo.FileCount,
+		size:      archiveInfo.TotalSize,
+		downloadInfo: &model.DownloadInfo{
+			URL:              url,
+			Filename:         filename,
+			Size:             fileInfo.Size(),
+			Checksum:         checksum,
+			ChecksumType:     ChecksumTypeSHA256,
+			DownloadedAt:     time.Now(),
+			Duration:         duration.Milliseconds(),
+			Speed:            float64(fileInfo.Size()) / duration.Seconds(),
+			Mirror:           mirror.Name,
+			AttemptedMirrors: []string{mirror.Name},
+		},
+	}, nil
+}
+
+// sourceArchiveChecksum 获取源码包的官方SHA-256：优先使用指定的校验和，其次使用发布目录中的源码包条目，最后使用官方的 .sha256 文件
+// 源码包可能来自任意镜像，不使用镜像自身提供的校验和
+func (s *VersionService) sourceArchiveChecksum(ctx context.Context, version, filename, tempDir string, options *model.InstallOptions) (string, error) {
+	if options.SHA256 != "" {
+		return options.SHA256, nil
+	}
+
+	releases, catalogErr := s.fetchReleaseIndex(ctx, s.catalogURL)
+	if catalogErr == nil {
+		if file, found := releases[version].FindSource(); found && file.Filename == filename && file.SHA256 != "" {
+			return file.SHA256, nil
+		}
+	}
+
+	checksumURL := strings.ReplaceAll(officialChecksumURLTemplate, "{filename}", filename)
+	checksum, err := s.fetchChecksum(ctx, checksumURL, tempDir)
+	if err != nil {
+		if catalogErr != nil {
+			return "", fmt.Errorf("无法获取源码包的官方校验和（%v；%v），可使用 --sha256 指定", catalogErr, err)
+		}
+		return "", fmt.Errorf("发布目录中没有 %s，且%v，可使用 --sha256 指定", filename, err)
+	}
+	return checksum, nil
+}
+
+// selectBootstrapToolchain 选择引导工具链：指定版本时必须已安装且满足最低版本要求，否则选择满足要求的最新已安装版本
+// name 为将要安装的工具链名称，不会被选作引导工具链
+func (s *VersionService) selectBootstrapToolchain(name, version, requested string) (*model.GoVersion, error) {
+	minimum := MinimumBootstrapVersion(version)
+	satisfies := func(candidate string) bool {
+		release := releaseNumber(candidate)
+		return release != "" && (minimum == "" || model.CompareVersionStrings(release, minimum).Result >= 0)
+	}
+
+	if requested != "" {
+		bootstrap, err := s.versionRepo.FindByVersion(requested)
+		if err != nil {
+			return nil, fmt.Errorf("引导工具链 Go %s 未安装", requested)
+		}
+		if !satisfies(bootstrap.ReleaseVersion()) {
+			return nil, fmt.Errorf("编译 Go %s 需要 Go %s 或更新的引导工具链，指定的为 Go %s", version, minimum, bootstrap.ReleaseVersion())
+		}
+		return bootstrap, nil
+	}
+
+	installed, err := s.versionRepo.FindAll()
+	if err != nil {
+		return nil, fmt.Errorf("获取已安装版本失败: %v", err)
+	}
+
+	var selected *model.GoVersion
+	for _, candidate := range installed {
+		if candidate.Version == name || !satisfies(candidate.ReleaseVersion()) {
+			continue
+		}
+		if selected == nil || candidate.CompareVersion(selected).Result > 0 {
+			selected = candidate
+		}
+	}
+	if selected == nil {
+		if minimum != "" {
+			return nil, fmt.Errorf("没有可用的引导工具链: 编译 Go %s 需要已安装 Go %s 或更新的版本", version, minimum)
+		}
+		return nil, fmt.Errorf("没有可用的引导工具链: 请先安装一个Go版本")
+	}
+	return selected, nil
+}
+
+// createSourceInstallationContext 创建源码编译安装的上下文
+func (s *VersionService) createSourceInstallationContext(version, tempDir string, options *model.InstallOptions) *model.InstallationContext {
+	context := s.createArchiveInstallationContext(version, "", tempDir, options)
+	context.Source = model.SourceBuild
+	context.SystemInfo.Filename = ""
+	context.Paths.ArchiveFile = ""
+	context.Status = model.StatusConfiguring
+	return context
+}
+
+// buildFromSource 将源码复制到版本目录并运行 make.bash，完成后安装默认工具并保存版本记录
+func (s *VersionService) buildFromSource(
+	ctx context.Context,
+	context *model.InstallationContext,
+	prepared *preparedSource,
+	bootstrap *model.GoVersion,
+	result *model.InstallationResult,
+	progressUI ProgressReporter,
+) (*model.InstallationResult, error) {
+	if progressUI != nil {
+		progressUI.SetProgress(30)
+		progressUI.SetMessage("复制源码到安装目录...")
+	}
+	if _, err := os.Stat(context.Paths.VersionDir); err == nil {
+		return s.createFailedResult(result, fmt.Errorf("安装目录已存在: %s", context.Paths.VersionDir))
+	}
+	if err := copySourceTree(prepared.dir, context.Paths.VersionDir); err != nil {
+		return s.createFailedResult(result, fmt.Errorf("复制源码失败: %v", err))
+	}
+
+	// 在版本目录中编译，使编译结果与安装位置一致
+	if progressUI != nil {
+		progressUI.SetStage("编译源码")
+		progressUI.SetProgress(35)
+		progressUI.SetMessage(fmt.Sprintf("使用 Go %s 作为引导工具链编译...", bootstrap.Version))
+	}
+	buildStart := time.Now()
+	if err := runMakeScript(ctx, context.Paths.VersionDir, bootstrap.Path, progressUI); err != nil {
+		return s.createFailedResult(result, err)
+	}
+
+	// 验证编译结果
+	if progressUI != nil {
+		progressUI.SetStage("验证安装")
+		progressUI.SetProgress(88)
+		progressUI.SetMessage("验证编译结果...")
+	}
+	goExecPath := s.getGoExecutablePath(context.Paths.VersionDir)
+	output, err := exec.Command(goExecPath, "version").Output()
+	if err != nil {
+		return s.createFailedResult(result, fmt.Errorf("执行编译得到的go失败: %v", err))
+	}
+	if !strings.HasPrefix(context.Version, "devel-") && !strings.Contains(string(output), "go"+context.Version+" ") {
+		return s.createFailedResult(result, fmt.Errorf("版本不匹配: 期望 %s, 实际 %s", context.Version, strings.TrimSpace(string(output))))
+	}
+
+	var extractInfo *model.ExtractInfo
+	if prepared.downloadInfo != nil {
+		extractInfo = &model.ExtractInfo{
+			ArchiveSize:   prepared.downloadInfo.Size,
+			ExtractedSize: prepared.size,
+			FileCount:     prepared.fileCount,
+			Duration:      time.Since(buildStart),
+			RootDir:       "go",
+		}
+	}
+	result.ExtractInfo = extractInfo
+
+	if progressUI != nil {
+		progressUI.SetProgress(92)
+		progressUI.SetMessage("保存版本信息...")
+	}
+	goVersion, err := s.createGoVersionRecord(context, prepared.downloadInfo, extractInfo)
+	if err != nil {
+		return s.createFailedResult(result, fmt.Errorf("保存版本记录失败: %v", err))
+	}
+	notes := []string{"bootstrap go" + bootstrap.ReleaseVersion()}
+	if prepared.commit != "" {
+		notes = append([]string{"commit " + prepared.commit}, notes...)
+	}
+	goVersion.Notes = strings.Join(notes, ", ")
+
+	if err := s.saveInstalledVersion(ctx, nil, goVersion, context.Options, result, progressUI); err != nil {
+		return s.createFailedResult(result, err)
+	}
+
+	if progressUI != nil {
+		progressUI.SetProgress(100)
+		progressUI.SetMessage("安装完成!")
+	}
+	result.Success = true
+	result.Duration = time.Since(context.StartTime)
+	return result, nil
+}
+
+// runMakeScript 在Go源码目录中运行 make.bash（Windows 为 make.bat），逐行将输出报告到 progressUI，ctx 取消时终止编译
+func runMakeScript(ctx context.Context, goRoot, bootstrapRoot string, progressUI ProgressReporter) error {
+	srcDir := filepath.Join(goRoot, "src")
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/c", makeScriptName())
+	} else {
+		cmd = exec.CommandContext(ctx, "bash", makeScriptName())
+	}
+	cmd.Dir = srcDir
+	cmd.Env = sourceBuildEnv(bootstrapRoot)
+	// 取消时编译脚本的子进程可能仍持有输出管道，限制等待时间
+	cmd.WaitDelay = 5 * time.Second
+
+	reader, writer := io.Pipe()
+	cmd.Stdout = writer
+	cmd.Stderr = writer
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("启动 %s 失败: %v", makeScriptName(), err)
+	}
+
+	// 保留最后若干行输出，编译失败时附在错误信息中
+	var lastLines []string
+	scanDone := make(chan struct{})
+	go func() {
+		defer close(scanDone)
+		scanner := bufio.NewScanner(reader)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" {
+				continue
+			}
+			lastLines = append(lastLines, line)
+			if len(lastLines) > sourceBuildOutputLines {
+				lastLines = lastLines[1:]
+			}
+			if progressUI != nil {
+				for _, stage := range makeBashStages {
+					if strings.HasPrefix(line, stage.prefix) {
+						progressUI.SetProgress(stage.progress)
+						break
+					}
+				}
+				progressUI.SetMessage(line)
+			}
+		}
+		// 继续读取剩余输出，避免编译进程阻塞
+		io.Copy(io.Discard, reader)
+	}()
+
+	err := cmd.Wait()
+	writer.Close()
+	<-scanDone
+	if err != nil {
+		if ctx.Err() != nil {
+			return fmt.Errorf("编译已取消: %w", ctx.Err())
+		}
+		return fmt.Errorf("编译失败: %v\n%s", err, strings.Join(lastLines, "\n"))
+	}
+	return nil
+}
+
+// sourceBuildEnv 构建编译环境：指定引导工具链，并清除可能干扰编译的Go环境变量
+func sourceBuildEnv(bootstrapRoot string) []string {
+	var env []string
+	for _, entry := range os.Environ() {
+		name := entry
+		if i := strings.Index(entry, "="); i >= 0 {
+			name = entry[:i]
+		}
+		switch name {
+		case "GOROOT", "GOROOT_BOOTSTRAP", "GOTOOLCHAIN", "GOOS", "GOARCH", "GOFLAGS":
+			continue
+		}
+		env = append(env, entry)
+	}
+	// 禁止引导工具链自动切换到其他版本
+	return append(env, "GOROOT_BOOTSTRAP="+bootstrapRoot, "GOTOOLCHAIN=local")
+}
+
+// releaseNumber 获取发布版本号的数字部分（1.22rc1 为 1.22），不是发布版本号时返回空字符串
+func releaseNumber(version string) string {
+	match := goReleaseVersionPattern.FindStringSubmatch(version)
+	if match == nil {
+		return ""
+	}
+	return match[1]
+}
+
+// makeScriptName 获取当前系统的编译脚本名称
+func makeScriptName() string {
+	if runtime.GOOS == "windows" {
+		return "make.bat"
+	}
+	return "make.bash"
+}
+
+// gitCommitHash 获取git检出的当前提交哈希，不是git检出或git不可用时返回空字符串
+func gitCommitHash(dir string) string {
+	if _, err := os.Stat(filepath.Join(dir, ".git")); err != nil {
+		return ""
+	}
+	output, err := exec.Command("git", "-C", dir, "rev-parse", "HEAD").Output()
+	if err != nil {
+		return ""
+	}
+	commit := strings.TrimSpace(string(output))
+	if len(commit) < 12 {
+		return ""
+	}
+	return commit
+}
+
+// copySourceTree 复制Go源码目录（不包括 .git），保留文件权限和符号链接
+func copySourceTree(srcDir, destDir string) error {
+	return filepath.Walk(srcDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		relPath, err := filepath.Rel(srcDir, path)
+		if err != nil {
+			return err
+		}
+		if info.IsDir() && info.Name() == ".git" {
+			return filepath.SkipDir
+		}
+		destPath := filepath.Join(destDir, relPath)
+
+		switch {
+		case info.IsDir():
+			return os.MkdirAll(destPath, info.Mode().Perm()|0700)
+		case info.Mode()&os.ModeSymlink != 0:
+			target, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(target, destPath)
+		default:
+			return copyRegularFile(path, destPath, info.Mode().Perm())
+		}
+	})
+}
+
+// copyRegularFile 复制普通文件并设置权限
+func copyRegularFile(src, dst string, perm os.FileMode) error {
+	srcFile, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer srcFile.Close()
+
+	dstFile, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(dstFile, srcFile); err != nil {
+		dstFile.Close()
+		return err
+	}
+	return dstFile.Close()
+}
@@ -0,0 +1,278 @@
+package service
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"version-list/internal/domain/model"
+)
+
+// fakeMakeScript 模拟 make.bash：输出编译阶段并生成打印VERSION文件版本号的go可执行文件
+const fakeMakeScript = `#!/bin/bash
+set -e
+echo "Building Go cmd/dist using $GOROOT_BOOTSTRAP."
+echo "Building Go toolchain1 using $GOROOT_BOOTSTRAP."
+if [ -n "$FAIL_BUILD" ]; then
+	echo "compile error: something broke"
+	exit 2
+fi
+echo "$GOROOT_BOOTSTRAP" > ../bootstrap.txt
+mkdir -p ../bin
+version=$(head -n 1 ../VERSION 2>/dev/null || echo devel)
+printf '#!/bin/sh\necho "go version %s test/test"\n' "$version" > ../bin/go
+chmod +x ../bin/go
+echo "Installed Go for test/test in $(cd .. && pwd)"
+`
+
+// createFakeSourceArchive 创建模拟的Go源码包（go/VERSION、go/src/make.bash）
+func createFakeSourceArchive(t *testing.T, version string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	gzWriter := gzip.NewWriter(&buf)
+	tarWriter := tar.NewWriter(gzWriter)
+	files := []struct {
+		name    string
+		content string
+		mode    int64
+	}{
+		{"go/VERSION", "go" + version + "\n", 0644},
+		{"go/src/make.bash", fakeMakeScript, 0755},
+	}
+	for _, file := range files {
+		header := &tar.Header{Name: file.name, Mode: file.mode, Size: int64(len(file.content)), Typeflag: tar.TypeReg}
+		if err := tarWriter.WriteHeader(header); err != nil {
+			t.Fatalf("写入tar文件头失败: %v", err)
+		}
+		if _, err := tarWriter.Write([]byte(file.content)); err != nil {
+			t.Fatalf("写入tar文件内容失败: %v", err)
+		}
+	}
+	tarWriter.Close()
+	gzWriter.Close()
+	return buf.Bytes()
+}
+
+// newSourceBuildTestService 创建提供源码包的测试服务，并注册一个已安装的引导工具链
+func newSourceBuildTestService(t *testing.T, version, bootstrap string) (*VersionService, *MockVersionRepository) {
+	t.Helper()
+
+	archive := createFakeSourceArchive(t, version)
+	sum := sha256.Sum256(archive)
+	catalog, _ := json.Marshal([]GoRelease{{
+		Version: "go" + version,
+		Files: []GoReleaseFile{{
+			Filename: "go" + version + ".src.tar.gz",
+			Version:  "go" + version,
+			SHA256:   hex.EncodeToString(sum[:]),
+			Size:     int64(len(archive)),
+			Kind:     "source",
+		}},
+	}})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/":
+			w.WriteHeader(http.StatusOK)
+		case "/dl.json":
+			w.Write(catalog)
+		case "/go" + version + ".src.tar.gz":
+			w.Write(archive)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	service, versionRepo := newTestVersionService(t, server.URL)
+	service.catalogURL = server.URL + "/dl.json"
+	if err := service.mirrorService.AddCustomMirror(Mirror{Name: "source-test", BaseURL: server.URL + "/"}); err != nil {
+		t.Fatalf("添加镜像失败: %v", err)
+	}
+	if bootstrap != "" {
+		versionRepo.Save(&model.GoVersion{Version: bootstrap, Path: filepath.Join(t.TempDir(), "go"+bootstrap), Source: model.SourceOnline})
+	}
+	return service, versionRepo
+}
+
+func TestMinimumBootstrapVersion(t *testing.T) {
+	tests := map[string]string{
+		"1.26.0":   "1.24.6",
+		"1.24.3":   "1.22.6",
+		"1.23rc1":  "1.20",
+		"1.22.7":   "1.20",
+		"1.21.13":  "1.17.13",
+		"1.19":     "1.4",
+		"devel-ab": "",
+	}
+	for version, expected := range tests {
+		if got := MinimumBootstrapVersion(version); got != expected {
+			t.Errorf("MinimumBootstrapVersion(%s) = %q, 期望 %q", version, got, expected)
+		}
+	}
+}
+
+func TestVersionService_InstallFromSource(t *testing.T) {
+	service, versionRepo := newSourceBuildTestService(t, "1.22.7", "1.21.13")
+	// 不满足最低引导版本要求的版本不会被选中
+	versionRepo.Save(&model.GoVersion{Version: "1.19.5", Path: t.TempDir(), Source: model.SourceOnline})
+
+	reporter := &MockProgressReporter{}
+	result, err := service.InstallFromSource(context.Background(), "1.22.7", &model.InstallOptions{Mirror: "source-test"}, reporter)
+	if err != nil {
+		t.Fatalf("从源码编译安装失败: %v", err)
+	}
+	if !result.Success || result.Version != "1.22.7" {
+		t.Errorf("安装结果 = %+v, 期望成功安装 1.22.7", result)
+	}
+
+	goVersion, err := versionRepo.FindByVersion("1.22.7")
+	if err != nil {
+		t.Fatalf("版本未保存到仓库: %v", err)
+	}
+	if goVersion.Source != model.SourceBuild || goVersion.Source.String() != "source-build" {
+		t.Errorf("安装来源 = %v, 期望 source-build", goVersion.Source)
+	}
+	if !goVersion.HasTag("source-build") {
+		t.Errorf("标签 = %v, 期望包含 source-build", goVersion.Tags)
+	}
+	if !strings.Contains(goVersion.Notes, "bootstrap go1.21.13") {
+		t.Errorf("备注 = %q, 期望包含引导工具链版本", goVersion.Notes)
+	}
+	if goVersion.DownloadInfo == nil || !strings.HasSuffix(goVersion.DownloadInfo.URL, "/go1.22.7.src.tar.gz") {
+		t.Errorf("未记录源码包下载信息: %+v", goVersion.DownloadInfo)
+	}
+	if err := goVersion.Validate(); err != nil {
+		t.Errorf("版本记录无效: %v", err)
+	}
+
+	bootstrapRoot, err := os.ReadFile(filepath.Join(goVersion.Path, "bootstrap.txt"))
+	if err != nil {
+		t.Fatalf("make.bash 未运行: %v", err)
+	}
+	bootstrap, _ := versionRepo.FindByVersion("1.21.13")
+	if strings.TrimSpace(string(bootstrapRoot)) != bootstrap.Path {
+		t.Errorf("GOROOT_BOOTSTRAP = %s, 期望 %s", bootstrapRoot, bootstrap.Path)
+	}
+	if !strings.Contains(strings.Join(reporter.updates, "\n"), "Message: Installed Go for test/test") {
+		t.Errorf("编译输出未报告到进度: %v", reporter.updates)
+	}
+}
+
+func TestVersionService_InstallFromSource_LocalCheckout(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("需要git")
+	}
+	service, versionRepo := newSourceBuildTestService(t, "1.22.7", "1.22.7")
+
+	// 没有VERSION文件的git检出使用提交哈希作为版本
+	checkout := t.TempDir()
+	os.MkdirAll(filepath.Join(checkout, "src"), 0755)
+	writeTestFile(t, filepath.Join(checkout, "src"), "make.bash", fakeMakeScript)
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"add", "."},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "-m", "init"},
+	} {
+		if output, err := exec.Command("git", append([]string{"-C", checkout}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v 失败: %v\n%s", args, err, output)
+		}
+	}
+	commit := gitCommitHash(checkout)
+
+	result, err := service.InstallFromSource(context.Background(), checkout, &model.InstallOptions{BootstrapVersion: "1.22.7"}, nil)
+	if err != nil {
+		t.Fatalf("从本地源码编译安装失败: %v", err)
+	}
+	if result.Version != "devel-"+commit[:12] {
+		t.Errorf("版本 = %s, 期望 devel-%s", result.Version, commit[:12])
+	}
+
+	goVersion, err := versionRepo.FindByVersion(result.Version)
+	if err != nil {
+		t.Fatalf("版本未保存到仓库: %v", err)
+	}
+	if goVersion.Notes != "commit "+commit+", bootstrap go1.22.7" {
+		t.Errorf("备注 = %q, 期望包含提交哈希和引导工具链", goVersion.Notes)
+	}
+	if goVersion.DownloadInfo != nil {
+		t.Errorf("本地源码不应有下载信息")
+	}
+	if _, err := os.Stat(filepath.Join(goVersion.Path, ".git")); !os.IsNotExist(err) {
+		t.Errorf("不应复制 .git 目录")
+	}
+}
+
+func TestVersionService_InstallFromSource_Errors(t *testing.T) {
+	t.Run("没有可用的引导工具链", func(t *testing.T) {
+		service, _ := newSourceBuildTestService(t, "1.22.7", "1.19.5")
+		_, err := service.InstallFromSource(context.Background(), "1.22.7", &model.InstallOptions{Mirror: "source-test"}, nil)
+		if err == nil || !strings.Contains(err.Error(), "1.20") {
+			t.Errorf("期望引导工具链版本不足的错误, 实际 %v", err)
+		}
+	})
+
+	t.Run("指定的引导工具链未安装", func(t *testing.T) {
+		service, _ := newSourceBuildTestService(t, "1.22.7", "1.21.13")
+		_, err := service.InstallFromSource(context.Background(), "1.22.7", &model.InstallOptions{Mirror: "source-test", BootstrapVersion: "1.20.1"}, nil)
+		if err == nil || !strings.Contains(err.Error(), "未安装") {
+			t.Errorf("期望引导工具链未安装的错误, 实际 %v", err)
+		}
+	})
+
+	t.Run("编译失败时清理安装目录", func(t *testing.T) {
+		service, versionRepo := newSourceBuildTestService(t, "1.22.7", "1.21.13")
+		t.Setenv("FAIL_BUILD", "1")
+		_, err := service.InstallFromSource(context.Background(), "1.22.7", &model.InstallOptions{Mirror: "source-test"}, nil)
+		if err == nil || !strings.Contains(err.Error(), "compile error: something broke") {
+			t.Errorf("期望包含编译输出的错误, 实际 %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(service.getBaseInstallDir(), "1.22.7")); !os.IsNotExist(err) {
+			t.Errorf("编译失败后安装目录未清理")
+		}
+		if _, err := versionRepo.FindByVersion("1.22.7"); err == nil {
+			t.Errorf("编译失败的版本不应保存到仓库")
+		}
+	})
+
+	t.Run("源码包与官方校验和不一致", func(t *testing.T) {
+		service, versionRepo := newSourceBuildTestService(t, "1.22.7", "1.21.13")
+		// 镜像提供的源码包与发布目录中的不是同一个文件
+		tampered := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write(createFakeSourceArchive(t, "1.22.6"))
+		}))
+		t.Cleanup(tampered.Close)
+		service.mirrorService.AddCustomMirror(Mirror{Name: "tampered", BaseURL: tampered.URL + "/"})
+
+		_, err := service.InstallFromSource(context.Background(), "1.22.7", &model.InstallOptions{Mirror: "tampered"}, nil)
+		if err == nil || !strings.Contains(err.Error(), "校验和不匹配") {
+			t.Errorf("期望校验和不匹配的错误, 实际 %v", err)
+		}
+		if _, err := versionRepo.FindByVersion("1.22.7"); err == nil {
+			t.Errorf("校验失败的版本不应保存到仓库")
+		}
+
+		// --sha256 指定的校验和优先于发布目录
+		_, err = service.InstallFromSource(context.Background(), "1.22.7", &model.InstallOptions{Mirror: "tampered", SHA256: strings.Repeat("0", 64)}, nil)
+		if err == nil || !strings.Contains(err.Error(), strings.Repeat("0", 64)) {
+			t.Errorf("期望与指定校验和不匹配的错误, 实际 %v", err)
+		}
+	})
+
+	t.Run("无效的源码", func(t *testing.T) {
+		service, _ := newSourceBuildTestService(t, "1.22.7", "1.21.13")
+		if _, err := service.InstallFromSource(context.Background(), "not a version", nil, nil); err == nil {
+			t.Errorf("期望无效源码的错误")
+		}
+	})
+}
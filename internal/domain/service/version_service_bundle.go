@@ -0,0 +1,484 @@
+package service
+
+import (
+	"archive/tar"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+	"version-list/internal/domain/model"
+)
+
+const (
+	// bundleManifestName 离线安装包中的清单文件名
+	bundleManifestName = "manifest.json"
+	// bundleArchiveDir 离线安装包中存放压缩包的目录
+	bundleArchiveDir = "archives"
+)
+
+// BundleCreateOptions 创建离线安装包的选项
+type BundleCreateOptions struct {
+	Versions   []string // 要打包的Go版本
+	Platforms  []string // 目标平台，格式为 os/arch，为空时使用当前平台
+	Mirror     string   // 缓存未命中时下载使用的镜像源
+	CatalogURL string   // 用于校验压缩包的发布目录地址，为空时使用官方发布目录
+}
+
+// BundleProgressCallback 离线安装包处理进度回调
+type BundleProgressCallback func(entry *model.BundleEntry, message string)
+
+// ParsePlatform 解析 os/arch 格式的平台标识，官方未发布该平台时返回 ErrorTypeUnsupported 错误
+func ParsePlatform(platform string) (string, string, error) {
+	parts := strings.Split(strings.TrimSpace(platform), "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("无效的平台格式: %s（应为 os/arch，如 linux/amd64）", platform)
+	}
+	if _, err := LookupPlatform(parts[0], parts[1]); err != nil {
+		return "", "", err
+	}
+	return parts[0], parts[1], nil
+}
+
+// CreateBundle 创建包含多个Go版本和平台压缩包的离线安装包
+// 优先使用下载缓存中的压缩包，缓存未命中时从镜像源下载
+// 每个压缩包打包前都要与官方发布目录（无法获取时为镜像源的校验和文件）中的SHA-256一致，无法校验时拒绝打包
+func (s *VersionService) CreateBundle(ctx context.Context, outputPath string, options *BundleCreateOptions, progress BundleProgressCallback) (*model.BundleManifest, error) {
+	if options == nil || len(options.Versions) == 0 {
+		return nil, fmt.Errorf("请指定要打包的Go版本")
+	}
+
+	platforms := options.Platforms
+	if len(platforms) == 0 {
+		platforms = []string{runtime.GOOS + "/" + runtime.GOARCH}
+	}
+
+	mirror := options.Mirror
+	if mirror == "" {
+		mirror = "official"
+	}
+	var namedMirror *Mirror
+	if !strings.HasPrefix(mirror, "http://") && !strings.HasPrefix(mirror, "https://") {
+		m, err := s.mirrorService.GetMirrorByName(mirror)
+		if err != nil {
+			return nil, fmt.Errorf("找不到镜像源 '%s': %v", mirror, err)
+		}
+		namedMirror = m
+		mirror = m.URLPattern()
+	}
+
+	releases, catalogErr := s.fetchReleaseIndex(ctx, options.CatalogURL)
+
+	tempDir, err := os.MkdirTemp("", "go-bundle-")
+	if err != nil {
+		return nil, fmt.Errorf("创建临时目录失败: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	cache := s.downloadCache()
+	manifest := &model.BundleManifest{
+		FormatVersion: model.BundleFormatVersion,
+		CreatedAt:     time.Now(),
+	}
+	archivePaths := make(map[string]string)
+
+	for _, version := range options.Versions {
+		for _, platform := range platforms {
+			goos, goarch, err := ParsePlatform(platform)
+			if err != nil {
+				return nil, err
+			}
+
+			filename := s.systemDetector.GetExpectedFilename(version, goos, goarch)
+			url := s.systemDetector.GetDownloadURLWithMirror(version, goos, goarch, mirror)
+			entry := model.BundleEntry{
+				Version:  version,
+				OS:       goos,
+				Arch:     goarch,
+				Filename: filename,
+			}
+
+			expected, err := s.officialArchiveChecksum(ctx, releases, catalogErr, namedMirror, version, goos, goarch, tempDir)
+			if err != nil {
+				return nil, fmt.Errorf("无法校验 %s: %v", filename, err)
+			}
+
+			archivePath, cached, downloadInfo, err := s.fetchBundleArchive(ctx, cache, url, filename, tempDir, &entry, progress)
+			if err != nil {
+				return nil, fmt.Errorf("获取 %s 失败: %v", filename, err)
+			}
+
+			checksum, err := NewFileValidator().CalculateChecksum(archivePath, ChecksumTypeSHA256)
+			if err != nil {
+				return nil, fmt.Errorf("计算 %s 校验和失败: %v", filename, err)
+			}
+			if !strings.EqualFold(checksum, expected) {
+				evictCachedArchive(cache, cached)
+				return nil, fmt.Errorf("%s 的SHA-256 %s 与官方校验和 %s 不一致，拒绝打包", filename, checksum, expected)
+			}
+			// 与官方校验和一致的新下载压缩包加入缓存以便后续复用
+			if cached == nil && cache != nil {
+				cache.StoreVerified(url, archivePath, expected)
+			}
+			downloadInfo.Checksum = checksum
+			downloadInfo.ChecksumType = ChecksumTypeSHA256
+
+			entry.SHA256 = checksum
+			entry.Size = downloadInfo.Size
+			entry.DownloadInfo = downloadInfo
+			manifest.Entries = append(manifest.Entries, entry)
+			archivePaths[filename] = archivePath
+		}
+	}
+
+	if err := writeBundle(outputPath, manifest, archivePaths); err != nil {
+		os.Remove(outputPath)
+		return nil, err
+	}
+
+	return manifest, nil
+}
+
+// officialArchiveChecksum 获取压缩包的官方SHA-256：优先使用发布目录，发布目录中没有时使用镜像源提供的校验和文件
+func (s *VersionService) officialArchiveChecksum(ctx context.Context, releases map[string]GoRelease, catalogErr error, mirror *Mirror, version, goos, goarch, tempDir string) (string, error) {
+	if file, found := releases[version].FindArchive(goos, goarch); found && file.SHA256 != "" {
+		return file.SHA256, nil
+	}
+	if mirror != nil {
+		if checksumURL := mirror.ChecksumURL(version, goos, goarch); checksumURL != "" {
+			return s.fetchChecksum(ctx, checksumURL, tempDir)
+		}
+	}
+	if catalogErr != nil {
+		return "", fmt.Errorf("镜像源未提供校验和，且%v", catalogErr)
+	}
+	return "", fmt.Errorf("发布目录中没有该压缩包，镜像源也未提供校验和")
+}
+
+// fetchBundleArchive 从缓存或镜像源获取压缩包，返回本地文件路径、使用的缓存条目（新下载时为nil）及下载信息
+func (s *VersionService) fetchBundleArchive(ctx context.Context, cache *DownloadCache, url, filename, tempDir string, entry *model.BundleEntry, progress BundleProgressCallback) (string, *CacheEntry, *model.DownloadInfo, error) {
+	// 优先按URL查找缓存，其次按文件名查找其他镜像下载的相同压缩包
+	if cache != nil {
+		cached, cachedPath, err := cache.Lookup(url)
+		if err != nil {
+			cached, cachedPath, err = cache.LookupFilename(filename)
+		}
+		if err == nil {
+			if progress != nil {
+				progress(entry, "使用缓存")
+			}
+			sourceURL := url
+			if len(cached.URLs) > 0 && !hasString(cached.URLs, url) {
+				sourceURL = cached.URLs[0]
+			}
+			return cachedPath, cached, &model.DownloadInfo{
+				URL:          sourceURL,
+				Filename:     filename,
+				Size:         cached.Size,
+				DownloadedAt: cached.CreatedAt,
+			}, nil
+		}
+	}
+
+	if progress != nil {
+		progress(entry, fmt.Sprintf("正在下载 %s", url))
+	}
+	destPath := filepath.Join(tempDir, filename)
+	startTime := time.Now()
+	if err := s.downloadService.DownloadWithContext(ctx, url, destPath, nil); err != nil {
+		return "", nil, nil, err
+	}
+	duration := time.Since(startTime)
+
+	fileInfo, err := os.Stat(destPath)
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("读取下载文件失败: %v", err)
+	}
+
+	return destPath, nil, &model.DownloadInfo{
+		URL:          url,
+		Filename:     filename,
+		Size:         fileInfo.Size(),
+		DownloadedAt: time.Now(),
+		Duration:     duration.Milliseconds(),
+		Speed:        float64(fileInfo.Size()) / duration.Seconds(),
+	}, nil
+}
+
+// httpClient 获取下载服务使用的HTTP客户端，共享代理、TLS和认证配置；下载服务未提供时使用默认配置
+func (s *VersionService) httpClient() *http.Client {
+	if provider, ok := s.downloadService.(interface{ HTTPClient() *http.Client }); ok {
+		if client := provider.HTTPClient(); client != nil {
+			return client
+		}
+	}
+	return DefaultHTTPClientFactory().Client(time.Minute)
+}
+
+// downloadCache 获取下载服务使用的缓存，下载服务未启用缓存时使用默认缓存目录
+func (s *VersionService) downloadCache() *DownloadCache {
+	if provider, ok := s.downloadService.(interface{ Cache() *DownloadCache }); ok {
+		if cache := provider.Cache(); cache != nil {
+			return cache
+		}
+	}
+	return NewDownloadCache(DefaultCacheDir(), DefaultMaxCacheSize)
+}
+
+// writeBundle 将清单和压缩包写入tar格式的离线安装包，清单作为第一个文件写入
+func writeBundle(outputPath string, manifest *model.BundleManifest, archivePaths map[string]string) error {
+	if dir := filepath.Dir(outputPath); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("创建输出目录失败: %v", err)
+		}
+	}
+
+	file, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("创建离线安装包失败: %v", err)
+	}
+	defer file.Close()
+
+	tarWriter := tar.NewWriter(file)
+
+	manifestData, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("序列化清单失败: %v", err)
+	}
+	header := &tar.Header{
+		Name:    bundleManifestName,
+		Mode:    0644,
+		Size:    int64(len(manifestData)),
+		ModTime: manifest.CreatedAt,
+	}
+	if err := tarWriter.WriteHeader(header); err != nil {
+		return fmt.Errorf("写入清单失败: %v", err)
+	}
+	if _, err := tarWriter.Write(manifestData); err != nil {
+		return fmt.Errorf("写入清单失败: %v", err)
+	}
+
+	for _, entry := range manifest.Entries {
+		if err := writeBundleArchive(tarWriter, entry, archivePaths[entry.Filename], manifest.CreatedAt); err != nil {
+			return err
+		}
+	}
+
+	if err := tarWriter.Close(); err != nil {
+		return fmt.Errorf("写入离线安装包失败: %v", err)
+	}
+	return file.Sync()
+}
+
+// writeBundleArchive 将单个压缩包写入离线安装包
+func writeBundleArchive(tarWriter *tar.Writer, entry model.BundleEntry, archivePath string, modTime time.Time) error {
+	src, err := os.Open(archivePath)
+	if err != nil {
+		return fmt.Errorf("打开压缩包 %s 失败: %v", entry.Filename, err)
+	}
+	defer src.Close()
+
+	header := &tar.Header{
+		Name:    path.Join(bundleArchiveDir, entry.Filename),
+		Mode:    0644,
+		Size:    entry.Size,
+		ModTime: modTime,
+	}
+	if err := tarWriter.WriteHeader(header); err != nil {
+		return fmt.Errorf("写入压缩包 %s 失败: %v", entry.Filename, err)
+	}
+	if _, err := io.Copy(tarWriter, src); err != nil {
+		return fmt.Errorf("写入压缩包 %s 失败: %v", entry.Filename, err)
+	}
+	return nil
+}
+
+// ReadBundleManifest 读取离线安装包的清单
+func ReadBundleManifest(bundlePath string) (*model.BundleManifest, error) {
+	file, err := os.Open(bundlePath)
+	if err != nil {
+		return nil, fmt.Errorf("打开离线安装包失败: %v", err)
+	}
+	defer file.Close()
+
+	tarReader := tar.NewReader(file)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return nil, fmt.Errorf("离线安装包中缺少清单文件 %s", bundleManifestName)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("读取离线安装包失败: %v", err)
+		}
+		if header.Name != bundleManifestName {
+			continue
+		}
+
+		var manifest model.BundleManifest
+		if err := json.NewDecoder(tarReader).Decode(&manifest); err != nil {
+			return nil, fmt.Errorf("解析清单失败: %v", err)
+		}
+		if manifest.FormatVersion != model.BundleFormatVersion {
+			return nil, fmt.Errorf("不支持的清单格式版本: %d", manifest.FormatVersion)
+		}
+		for _, entry := range manifest.Entries {
+			if err := validateBundleFilename(entry.Filename); err != nil {
+				return nil, err
+			}
+		}
+		return &manifest, nil
+	}
+}
+
+// validateBundleFilename 检查清单中的压缩包文件名：必须是不含路径的Go官方压缩包文件名，
+// 防止清单通过 ../ 或路径分隔符把文件写到临时目录之外
+func validateBundleFilename(filename string) error {
+	if filename == "" || filename == "." || filename == ".." ||
+		strings.ContainsAny(filename, `/\`) || strings.Contains(filename, "..") ||
+		filepath.Base(filename) != filename || ParseArchiveFilename(filename) == nil {
+		return fmt.Errorf("清单中的压缩包文件名无效: %q", filename)
+	}
+	return nil
+}
+
+// InstallBundle 校验离线安装包并安装适用于当前平台的Go版本
+// version 为空时安装包中当前平台的所有版本，ctx 取消时停止安装
+func (s *VersionService) InstallBundle(ctx context.Context, bundlePath, version string, options *model.InstallOptions, progress BundleProgressCallback) ([]*model.InstallationResult, error) {
+	manifest, err := ReadBundleManifest(bundlePath)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := manifest.FindEntries(runtime.GOOS, runtime.GOARCH, version)
+	if len(entries) == 0 {
+		if version != "" {
+			return nil, fmt.Errorf("离线安装包中没有适用于 %s/%s 的Go %s", runtime.GOOS, runtime.GOARCH, version)
+		}
+		return nil, fmt.Errorf("离线安装包中没有适用于 %s/%s 的Go版本", runtime.GOOS, runtime.GOARCH)
+	}
+
+	tempDir, err := os.MkdirTemp("", "go-bundle-install-")
+	if err != nil {
+		return nil, fmt.Errorf("创建临时目录失败: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	// 校验清单中的所有压缩包，并提取需要安装的压缩包
+	wanted := make(map[string]bool, len(entries))
+	for _, entry := range entries {
+		wanted[entry.Filename] = true
+	}
+	if err := verifyAndExtractBundle(bundlePath, manifest, wanted, tempDir); err != nil {
+		return nil, err
+	}
+
+	results := make([]*model.InstallationResult, 0, len(entries))
+	for i := range entries {
+		entry := &entries[i]
+		if progress != nil {
+			progress(entry, "正在安装")
+		}
+
+		entryOptions := model.InstallOptions{}
+		if options != nil {
+			entryOptions = *options
+		}
+		entryOptions.SHA256 = entry.SHA256
+
+		result, err := s.installFromArchive(ctx, filepath.Join(tempDir, entry.Filename), &entryOptions, entry.DownloadInfo, nil)
+		if result == nil {
+			result = &model.InstallationResult{Version: entry.Version}
+		}
+		if err != nil {
+			result.Success = false
+			result.Error = err.Error()
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// verifyAndExtractBundle 校验离线安装包中每个压缩包的大小和SHA-256，并将 wanted 中的压缩包写入目标目录
+func verifyAndExtractBundle(bundlePath string, manifest *model.BundleManifest, wanted map[string]bool, destDir string) error {
+	expected := make(map[string]model.BundleEntry, len(manifest.Entries))
+	for _, entry := range manifest.Entries {
+		expected[path.Join(bundleArchiveDir, entry.Filename)] = entry
+	}
+
+	file, err := os.Open(bundlePath)
+	if err != nil {
+		return fmt.Errorf("打开离线安装包失败: %v", err)
+	}
+	defer file.Close()
+
+	verified := make(map[string]bool, len(expected))
+	tarReader := tar.NewReader(file)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("读取离线安装包失败: %v", err)
+		}
+		if header.Name == bundleManifestName {
+			continue
+		}
+
+		entry, exists := expected[header.Name]
+		if !exists {
+			return fmt.Errorf("离线安装包包含清单中未列出的文件: %s", header.Name)
+		}
+
+		destPath := ""
+		if wanted[entry.Filename] {
+			destPath = filepath.Join(destDir, entry.Filename)
+		}
+		if err := verifyBundleEntry(tarReader, entry, destPath); err != nil {
+			return err
+		}
+		verified[header.Name] = true
+	}
+
+	for name, entry := range expected {
+		if !verified[name] {
+			return fmt.Errorf("离线安装包中缺少 %s", entry.Filename)
+		}
+	}
+	return nil
+}
+
+// verifyBundleEntry 校验压缩包的大小和SHA-256，destPath 不为空时同时写入该文件
+func verifyBundleEntry(reader io.Reader, entry model.BundleEntry, destPath string) error {
+	var writer io.Writer = io.Discard
+	if destPath != "" {
+		dst, err := os.Create(destPath)
+		if err != nil {
+			return fmt.Errorf("创建临时文件失败: %v", err)
+		}
+		defer dst.Close()
+		writer = dst
+	}
+
+	hasher := sha256.New()
+	size, err := io.Copy(io.MultiWriter(writer, hasher), reader)
+	if err != nil {
+		return fmt.Errorf("读取 %s 失败: %v", entry.Filename, err)
+	}
+	if size != entry.Size {
+		return fmt.Errorf("%s 大小不匹配: 期望 %d, 实际 %d", entry.Filename, entry.Size, size)
+	}
+	if actual := hex.EncodeToString(hasher.Sum(nil)); !strings.EqualFold(actual, entry.SHA256) {
+		return fmt.Errorf("%s 校验和不匹配: 期望 %s, 实际 %s", entry.Filename, entry.SHA256, actual)
+	}
+	return nil
+}
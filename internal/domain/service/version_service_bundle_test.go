@@ -0,0 +1,357 @@
+package service
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"version-list/internal/domain/model"
+)
+
+// bundleCatalogPath 测试服务器上发布目录的路径
+const bundleCatalogPath = "/dl.json"
+
+// newBundleReleaseServer 创建按文件名生成任意平台模拟压缩包的测试服务器，并统计压缩包的请求次数
+// 服务器同时提供 1.21.13 和 1.22.7 的发布目录，以及 <文件名>.sha256 校验和文件
+func newBundleReleaseServer(t *testing.T) (*httptest.Server, *int32) {
+	t.Helper()
+
+	var catalog []GoRelease
+	for _, version := range []string{"1.21.13", "1.22.7"} {
+		release := GoRelease{Version: "go" + version, Stable: true}
+		checksum := fmt.Sprintf("%x", sha256.Sum256(createFakeGoArchive(t, version)))
+		for _, platform := range platforms {
+			release.Files = append(release.Files, GoReleaseFile{
+				Filename: platform.Filename(version),
+				OS:       platform.OS,
+				Arch:     platform.ArtifactArch,
+				Version:  release.Version,
+				SHA256:   checksum,
+				Kind:     "archive",
+			})
+		}
+		catalog = append(catalog, release)
+	}
+
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == bundleCatalogPath {
+			json.NewEncoder(w).Encode(catalog)
+			return
+		}
+		if archiveName, ok := strings.CutSuffix(path.Base(r.URL.Path), ".sha256"); ok {
+			if info := ParseArchiveFilename(archiveName); info != nil {
+				fmt.Fprintf(w, "%x\n", sha256.Sum256(createFakeGoArchive(t, info.Version)))
+				return
+			}
+		}
+		info := ParseArchiveFilename(path.Base(r.URL.Path))
+		if info == nil {
+			http.NotFound(w, r)
+			return
+		}
+		atomic.AddInt32(&requests, 1)
+		data := createFakeGoArchive(t, info.Version)
+		w.Header().Set("Content-Length", fmt.Sprintf("%d", len(data)))
+		w.WriteHeader(http.StatusOK)
+		if r.Method != http.MethodHead {
+			w.Write(data)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+// bundlePlatforms 返回当前平台及另一个平台
+func bundlePlatforms() []string {
+	current := runtime.GOOS + "/" + runtime.GOARCH
+	other := "linux/arm64"
+	if current == other {
+		other = "linux/amd64"
+	}
+	return []string{current, other}
+}
+
+func TestParsePlatform(t *testing.T) {
+	goos, goarch, err := ParsePlatform("linux/amd64")
+	if err != nil || goos != "linux" || goarch != "amd64" {
+		t.Errorf("ParsePlatform(linux/amd64) = %s, %s, %v", goos, goarch, err)
+	}
+
+	for _, invalid := range []string{"", "linux", "linux/", "/amd64", "linux/amd64/v2"} {
+		if _, _, err := ParsePlatform(invalid); err == nil {
+			t.Errorf("ParsePlatform(%q) 应该返回错误", invalid)
+		}
+	}
+}
+
+func TestVersionService_CreateAndInstallBundle(t *testing.T) {
+	server, requests := newBundleReleaseServer(t)
+	service, versionRepo := newTestVersionService(t, server.URL)
+
+	options := &BundleCreateOptions{
+		Versions:   []string{"1.21.13", "1.22.7"},
+		Platforms:  bundlePlatforms(),
+		Mirror:     server.URL + "/",
+		CatalogURL: server.URL + bundleCatalogPath,
+	}
+	bundlePath := filepath.Join(t.TempDir(), "bundle.tar")
+
+	manifest, err := service.CreateBundle(context.Background(), bundlePath, options, nil)
+	if err != nil {
+		t.Fatalf("创建离线安装包失败: %v", err)
+	}
+	if len(manifest.Entries) != 4 {
+		t.Fatalf("期望4个压缩包，实际 %d", len(manifest.Entries))
+	}
+	if got := atomic.LoadInt32(requests); got != 4 {
+		t.Errorf("期望下载4次，实际 %d", got)
+	}
+	for _, entry := range manifest.Entries {
+		if entry.SHA256 == "" || entry.Size == 0 || entry.DownloadInfo == nil {
+			t.Errorf("清单条目信息不完整: %+v", entry)
+		}
+	}
+
+	// 再次创建时应全部使用缓存
+	var cachedMessages int
+	_, err = service.CreateBundle(context.Background(), filepath.Join(t.TempDir(), "bundle2.tar"), options, func(entry *model.BundleEntry, message string) {
+		if message == "使用缓存" {
+			cachedMessages++
+		}
+	})
+	if err != nil {
+		t.Fatalf("再次创建离线安装包失败: %v", err)
+	}
+	if got := atomic.LoadInt32(requests); got != 4 {
+		t.Errorf("缓存命中时不应重新下载，下载次数 %d", got)
+	}
+	if cachedMessages != 4 {
+		t.Errorf("期望4个压缩包使用缓存，实际 %d", cachedMessages)
+	}
+
+	// 安装时只安装当前平台的指定版本
+	results, err := service.InstallBundle(context.Background(), bundlePath, "1.22.7", nil, nil)
+	if err != nil {
+		t.Fatalf("从离线安装包安装失败: %v", err)
+	}
+	if len(results) != 1 || !results[0].Success {
+		t.Fatalf("安装结果不正确: %+v", results)
+	}
+
+	installed, err := versionRepo.FindByVersion("1.22.7")
+	if err != nil {
+		t.Fatalf("未找到安装的版本: %v", err)
+	}
+	if installed.Source != model.SourceArchive {
+		t.Errorf("期望安装来源为 archive，实际 %s", installed.Source)
+	}
+	expectedURL := server.URL + "/" + fmt.Sprintf("go1.22.7.%s-%s.tar.gz", runtime.GOOS, runtime.GOARCH)
+	if installed.DownloadInfo == nil || installed.DownloadInfo.URL != expectedURL {
+		t.Errorf("期望保留原始下载地址 %s，实际 %+v", expectedURL, installed.DownloadInfo)
+	}
+	if _, err := versionRepo.FindByVersion("1.21.13"); err == nil {
+		t.Error("指定版本时不应安装其他版本")
+	}
+}
+
+func TestVersionService_CreateBundle_VerifiesChecksum(t *testing.T) {
+	server, _ := newBundleReleaseServer(t)
+	service, _ := newTestVersionService(t, server.URL)
+	bundlePath := filepath.Join(t.TempDir(), "bundle.tar")
+
+	// 缓存中的压缩包被替换为其他内容时拒绝打包
+	filename := fmt.Sprintf("go1.22.7.%s-%s.tar.gz", runtime.GOOS, runtime.GOARCH)
+	planted := writeTestFile(t, t.TempDir(), filename, string(createFakeGoArchive(t, "1.21.13")))
+	if _, err := service.downloadCache().Store(server.URL+"/"+filename, planted); err != nil {
+		t.Fatalf("写入缓存失败: %v", err)
+	}
+	_, err := service.CreateBundle(context.Background(), bundlePath, &BundleCreateOptions{
+		Versions:   []string{"1.22.7"},
+		Mirror:     server.URL + "/",
+		CatalogURL: server.URL + bundleCatalogPath,
+	}, nil)
+	if err == nil || !strings.Contains(err.Error(), "不一致") {
+		t.Errorf("压缩包与官方校验和不一致时应拒绝打包: %v", err)
+	}
+	if _, statErr := os.Stat(bundlePath); !os.IsNotExist(statErr) {
+		t.Error("拒绝打包时不应生成离线安装包")
+	}
+
+	// 发布目录不可用且镜像源未提供校验和时无法校验
+	_, err = service.CreateBundle(context.Background(), bundlePath, &BundleCreateOptions{
+		Versions:   []string{"1.21.13"},
+		Mirror:     server.URL + "/",
+		CatalogURL: server.URL + "/missing.json",
+	}, nil)
+	if err == nil || !strings.Contains(err.Error(), "无法校验") {
+		t.Errorf("无法获取官方校验和时应拒绝打包: %v", err)
+	}
+
+	// 发布目录不可用时使用镜像源的校验和文件
+	service.mirrorService.AddCustomMirror(Mirror{
+		Name:                "verified",
+		BaseURL:             server.URL + "/",
+		ChecksumURLTemplate: server.URL + "/{filename}.sha256",
+	})
+	manifest, err := service.CreateBundle(context.Background(), bundlePath, &BundleCreateOptions{
+		Versions:   []string{"1.21.13"},
+		Mirror:     "verified",
+		CatalogURL: server.URL + "/missing.json",
+	}, nil)
+	if err != nil || len(manifest.Entries) != 1 {
+		t.Fatalf("使用镜像源的校验和时应打包成功: %v", err)
+	}
+}
+
+func TestVersionService_InstallBundle_Tampered(t *testing.T) {
+	server, _ := newBundleReleaseServer(t)
+	service, versionRepo := newTestVersionService(t, server.URL)
+
+	bundlePath := filepath.Join(t.TempDir(), "bundle.tar")
+	_, err := service.CreateBundle(context.Background(), bundlePath, &BundleCreateOptions{
+		Versions:   []string{"1.22.7"},
+		Mirror:     server.URL + "/",
+		CatalogURL: server.URL + bundleCatalogPath,
+	}, nil)
+	if err != nil {
+		t.Fatalf("创建离线安装包失败: %v", err)
+	}
+
+	// 将压缩包替换为其他版本的内容，保持清单不变
+	tamperedPath := filepath.Join(t.TempDir(), "tampered.tar")
+	rewriteBundle(t, bundlePath, tamperedPath, func(name string, data []byte) []byte {
+		if strings.HasPrefix(name, bundleArchiveDir+"/") {
+			return createFakeGoArchive(t, "1.21.13")
+		}
+		return data
+	})
+
+	_, err = service.InstallBundle(context.Background(), tamperedPath, "", nil, nil)
+	if err == nil {
+		t.Fatal("被篡改的离线安装包应该校验失败")
+	}
+	if !strings.Contains(err.Error(), "不匹配") {
+		t.Errorf("错误信息应说明校验失败，实际: %v", err)
+	}
+	if versions, _ := versionRepo.FindAll(); len(versions) != 0 {
+		t.Errorf("校验失败时不应安装任何版本，实际 %d 个", len(versions))
+	}
+
+	// 清单中未列出的文件同样应被拒绝
+	extraPath := filepath.Join(t.TempDir(), "extra.tar")
+	rewriteBundle(t, bundlePath, extraPath, func(name string, data []byte) []byte { return data }, "archives/extra.tar.gz")
+	if _, err := service.InstallBundle(context.Background(), extraPath, "", nil, nil); err == nil {
+		t.Error("包含多余文件的离线安装包应该校验失败")
+	}
+}
+
+func TestVersionService_InstallBundle_RejectsUnsafeFilenames(t *testing.T) {
+	service, versionRepo := newTestVersionService(t, "")
+	archive := createFakeGoArchive(t, "1.22.7")
+
+	// 临时目录位于 base/tmp，../../ 会写到 base 下
+	base := t.TempDir()
+	t.Setenv("TMPDIR", filepath.Join(base, "tmp"))
+	os.MkdirAll(filepath.Join(base, "tmp"), 0755)
+
+	for _, filename := range []string{
+		"../../evil.tar.gz",
+		"../go1.22.7." + runtime.GOOS + "-" + runtime.GOARCH + ".tar.gz",
+		"..\\go1.22.7.windows-amd64.zip",
+		"evil.sh",
+	} {
+		t.Run(filename, func(t *testing.T) {
+			manifest := model.BundleManifest{
+				FormatVersion: model.BundleFormatVersion,
+				Entries: []model.BundleEntry{{
+					Version:  "1.22.7",
+					OS:       runtime.GOOS,
+					Arch:     runtime.GOARCH,
+					Filename: filename,
+					SHA256:   fmt.Sprintf("%x", sha256.Sum256(archive)),
+					Size:     int64(len(archive)),
+				}},
+			}
+			data, _ := json.Marshal(manifest)
+
+			var buf bytes.Buffer
+			tarWriter := tar.NewWriter(&buf)
+			for name, content := range map[string][]byte{
+				bundleManifestName:                    data,
+				path.Join(bundleArchiveDir, filename): archive,
+			} {
+				tarWriter.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content))})
+				tarWriter.Write(content)
+			}
+			tarWriter.Close()
+			bundlePath := filepath.Join(t.TempDir(), "evil.tar")
+			os.WriteFile(bundlePath, buf.Bytes(), 0644)
+
+			_, err := service.InstallBundle(context.Background(), bundlePath, "", nil, nil)
+			if err == nil || !strings.Contains(err.Error(), "文件名无效") {
+				t.Fatalf("文件名 %q 应被拒绝, 实际: %v", filename, err)
+			}
+			for _, escaped := range []string{filepath.Join(base, "evil.tar.gz"), filepath.Join(base, "tmp", path.Base(filename))} {
+				if _, err := os.Stat(escaped); !os.IsNotExist(err) {
+					t.Errorf("不应写入临时目录之外的文件 %s", escaped)
+				}
+			}
+			if versions, _ := versionRepo.FindAll(); len(versions) != 0 {
+				t.Errorf("不应安装任何版本，实际 %d 个", len(versions))
+			}
+		})
+	}
+}
+
+// rewriteBundle 复制离线安装包，通过 transform 修改文件内容，并追加 extra 中的空文件
+func rewriteBundle(t *testing.T, srcPath, dstPath string, transform func(name string, data []byte) []byte, extra ...string) {
+	t.Helper()
+
+	src, err := os.Open(srcPath)
+	if err != nil {
+		t.Fatalf("打开离线安装包失败: %v", err)
+	}
+	defer src.Close()
+
+	var buf bytes.Buffer
+	tarWriter := tar.NewWriter(&buf)
+	tarReader := tar.NewReader(src)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("读取离线安装包失败: %v", err)
+		}
+		data, err := io.ReadAll(tarReader)
+		if err != nil {
+			t.Fatalf("读取离线安装包失败: %v", err)
+		}
+		data = transform(header.Name, data)
+		header.Size = int64(len(data))
+		tarWriter.WriteHeader(header)
+		tarWriter.Write(data)
+	}
+	for _, name := range extra {
+		tarWriter.WriteHeader(&tar.Header{Name: name, Mode: 0644})
+	}
+	tarWriter.Close()
+
+	if err := os.WriteFile(dstPath, buf.Bytes(), 0644); err != nil {
+		t.Fatalf("写入离线安装包失败: %v", err)
+	}
+}
@@ -0,0 +1,179 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+	"version-list/internal/domain/model"
+)
+
+// newStallingServer 创建发送部分数据后一直等待直到客户端断开的测试服务器，started 在收到下载请求时关闭
+func newStallingServer(t *testing.T) (*httptest.Server, <-chan struct{}) {
+	t.Helper()
+	started := make(chan struct{})
+	var once sync.Once
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", "1048576")
+		w.WriteHeader(http.StatusOK)
+		if r.Method == http.MethodHead {
+			return
+		}
+		w.Write(make([]byte, 1024))
+		w.(http.Flusher).Flush()
+		once.Do(func() { close(started) })
+		<-r.Context().Done()
+	}))
+	t.Cleanup(server.Close)
+	return server, started
+}
+
+// assertInstallCleanedUp 检查中断的安装没有留下临时目录、版本目录和版本记录
+func assertInstallCleanedUp(t *testing.T, versionRepo *MockVersionRepository, versionDir, version string) {
+	t.Helper()
+	if _, err := os.Stat(versionDir); !os.IsNotExist(err) {
+		t.Errorf("中断后版本目录应被删除: %s", versionDir)
+	}
+	if _, err := os.Stat(InstallTempDir(version)); !os.IsNotExist(err) {
+		t.Error("中断后临时目录应被删除")
+	}
+	if _, err := versionRepo.FindByVersion(version); err == nil {
+		t.Error("中断的安装不应保存到仓库")
+	}
+}
+
+func TestVersionService_InstallOnlineWithContext_Cancelled(t *testing.T) {
+	server, started := newStallingServer(t)
+	service, versionRepo := newTestVersionService(t, server.URL)
+	versionDir := filepath.Join(t.TempDir(), "go1.19.13")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-started
+		cancel()
+	}()
+
+	begin := time.Now()
+	_, err := service.InstallOnlineWithContext(ctx, "1.19.13", &model.InstallOptions{CustomPath: versionDir, NoFailover: true}, nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("期望返回 context.Canceled, 实际 %v", err)
+	}
+	if !strings.Contains(err.Error(), "已取消") {
+		t.Errorf("错误信息应说明安装已取消: %v", err)
+	}
+	if elapsed := time.Since(begin); elapsed > 5*time.Second {
+		t.Errorf("取消后应立即返回, 耗时 %v", elapsed)
+	}
+	assertInstallCleanedUp(t, versionRepo, versionDir, "1.19.13")
+}
+
+func TestVersionService_InstallOnlineWithContext_StallTimeout(t *testing.T) {
+	server, _ := newStallingServer(t)
+	service, versionRepo := newTestVersionService(t, server.URL)
+	versionDir := filepath.Join(t.TempDir(), "go1.19.12")
+
+	_, err := service.InstallOnlineWithContext(context.Background(), "1.19.12", &model.InstallOptions{CustomPath: versionDir, Timeout: 1, NoFailover: true}, nil)
+	if !errors.Is(err, ErrDownloadStalled) {
+		t.Fatalf("期望返回 ErrDownloadStalled, 实际 %v", err)
+	}
+	if !strings.Contains(err.Error(), "超过 1 秒没有下载进度") {
+		t.Errorf("错误信息应包含停滞时间: %v", err)
+	}
+	if !isFailoverError(err) {
+		t.Error("下载停滞时应切换到其他镜像")
+	}
+	if _, err := os.Stat(versionDir); !os.IsNotExist(err) {
+		t.Errorf("失败后版本目录应被删除: %s", versionDir)
+	}
+	if _, err := versionRepo.FindByVersion("1.19.12"); err == nil {
+		t.Error("失败的安装不应保存到仓库")
+	}
+}
+
+func TestVersionService_InstallOnlineWithContext_SlowDownloadNotLimited(t *testing.T) {
+	version := "1.19.11"
+	archive := createFakeGoArchive(t, version)
+	// 持续有进度的慢速下载，总耗时超过 Timeout
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", strconv.Itoa(len(archive)))
+		w.WriteHeader(http.StatusOK)
+		if r.Method == http.MethodHead {
+			return
+		}
+		chunk := len(archive)/8 + 1
+		for start := 0; start < len(archive); start += chunk {
+			end := min(start+chunk, len(archive))
+			w.Write(archive[start:end])
+			w.(http.Flusher).Flush()
+			time.Sleep(200 * time.Millisecond)
+		}
+	}))
+	t.Cleanup(server.Close)
+	service, _ := newTestVersionService(t, server.URL)
+
+	options := &model.InstallOptions{CustomPath: filepath.Join(t.TempDir(), "go"+version), Timeout: 1, NoFailover: true, SkipVerification: true}
+	result, err := service.InstallOnlineWithContext(context.Background(), version, options, nil)
+	if err != nil || !result.Success {
+		t.Fatalf("下载持续有进度时不应因 Timeout 中断: %v", err)
+	}
+}
+
+func TestDownloadService_WithMaxRetries(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			atomic.AddInt32(&requests, 1)
+		}
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	downloadService := NewDownloadService(&DownloadOptions{
+		MaxRetries: 5,
+		RetryDelay: time.Millisecond,
+		Timeout:    5 * time.Second,
+		ChunkSize:  32 * 1024,
+	})
+	dest := filepath.Join(t.TempDir(), "go.tar.gz")
+	ctx := WithMaxRetries(context.Background(), 1)
+	if err := downloadService.DownloadWithContext(ctx, server.URL+"/go.tar.gz", dest, nil); err == nil {
+		t.Fatal("服务器不可用时应返回错误")
+	}
+	if got := atomic.LoadInt32(&requests); got != 2 {
+		t.Errorf("请求次数 = %d, 期望 2（首次请求加1次重试）", got)
+	}
+
+	// 已取消的上下文不再发起请求
+	atomic.StoreInt32(&requests, 0)
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := downloadService.DownloadWithContext(cancelled, server.URL+"/go.tar.gz", dest, nil); !errors.Is(err, context.Canceled) {
+		t.Errorf("期望返回 context.Canceled, 实际 %v", err)
+	}
+	if got := atomic.LoadInt32(&requests); got != 0 {
+		t.Errorf("上下文已取消时请求次数 = %d, 期望 0", got)
+	}
+}
+
+func TestArchiveExtractorImpl_ExtractWithContext_Cancelled(t *testing.T) {
+	archivePath, _ := writeFakeGoArchive(t, "go1.22.7.linux-amd64.tar.gz", "1.22.7")
+	extractor := NewArchiveExtractor(nil).(ContextArchiveExtractor)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	dest := t.TempDir()
+	if err := extractor.ExtractWithContext(ctx, archivePath, dest, nil); !errors.Is(err, context.Canceled) {
+		t.Fatalf("期望返回 context.Canceled, 实际 %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dest, "go", "VERSION")); !os.IsNotExist(err) {
+		t.Error("取消后不应继续解压文件")
+	}
+}
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -208,7 +209,7 @@ func TestVersionService_InstallOnline_CreateContext(t *testing.T) {
 		MaxRetries: 3,
 	}
 
-	context, err := service.createInstallationContext(version, options)
+	context, err := service.createInstallationContext(context.Background(), version, options)
 	if err != nil {
 		t.Fatalf("创建安装上下文失败: %v", err)
 	}
@@ -370,29 +371,33 @@ func TestVersionService_CreateFailedResult(t *testing.T) {
 }
 
 func TestVersionService_CleanupOperations(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
 	versionRepo := NewMockVersionRepository()
 	envRepo := NewMockEnvironmentRepository()
 	service := NewVersionService(versionRepo, envRepo)
 
 	// 创建临时目录进行测试
-	tempDir := t.TempDir()
-	versionDir := filepath.Join(tempDir, "version")
+	tempDir := filepath.Join(t.TempDir(), "temp")
+	versionDir := filepath.Join(t.TempDir(), "version")
+
+	tx, err := service.beginInstallTransaction(model.SourceOnline, "1.21.0", nil)
+	if err != nil {
+		t.Fatalf("开始安装事务失败: %v", err)
+	}
 
 	// 创建测试目录和文件
+	tx.recordDirectory(model.JournalTempDir, tempDir)
+	os.MkdirAll(tempDir, 0755)
+	tx.recordDirectory(model.JournalVersionDir, versionDir)
 	os.MkdirAll(versionDir, 0755)
 	testFile := filepath.Join(versionDir, "test.txt")
 	os.WriteFile(testFile, []byte("test"), 0644)
 
-	context := &model.InstallationContext{
-		TempDir: tempDir,
-		Paths: &model.InstallPaths{
-			VersionDir: versionDir,
-		},
+	// 测试回滚失败的安装
+	if err := tx.abort(); err != nil {
+		t.Fatalf("回滚失败: %v", err)
 	}
 
-	// 测试清理失败的安装
-	service.cleanupFailedInstallation(context)
-
 	// 验证目录被删除
 	if _, err := os.Stat(tempDir); !os.IsNotExist(err) {
 		t.Error("临时目录应该被删除")
@@ -401,4 +406,8 @@ func TestVersionService_CleanupOperations(t *testing.T) {
 	if _, err := os.Stat(versionDir); !os.IsNotExist(err) {
 		t.Error("版本目录应该被删除")
 	}
+
+	if _, err := os.Stat(tx.path); !os.IsNotExist(err) {
+		t.Error("回滚后安装日志应该被删除")
+	}
 }
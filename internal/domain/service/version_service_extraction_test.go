@@ -66,7 +66,7 @@ func TestVersionService_MoveExtractedContentPerformance(t *testing.T) {
 
 	// 测试移动操作
 	startTime := time.Now()
-	err = service.moveExtractedContentWithProgress(filepath.Dir(srcDir), destDir, "go", progressReporter)
+	err = service.moveExtractedContentWithProgress(context.Background(), filepath.Dir(srcDir), destDir, "go", progressReporter)
 	duration := time.Since(startTime)
 
 	if err != nil {
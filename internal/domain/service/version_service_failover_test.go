@@ -0,0 +1,270 @@
+package service
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	"version-list/internal/domain/model"
+)
+
+// failoverMirrorServer 记录下载请求的模拟镜像服务器
+type failoverMirrorServer struct {
+	*httptest.Server
+	mu     sync.Mutex
+	ranges map[string][]string // 文件名 -> 每次GET请求的Range头
+}
+
+// requestedRanges 返回指定文件收到的GET请求Range头
+func (s *failoverMirrorServer) requestedRanges(filename string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.ranges[filename]...)
+}
+
+// newFailoverMirrorServer 创建模拟镜像，mode 为 drop 时只返回一半内容后断开连接，
+// error 时返回500，其余情况正常提供下载并支持Range请求；所有模式都提供 <文件名>.sha256 校验和文件
+func newFailoverMirrorServer(t *testing.T, archives map[string][]byte, mode string) *failoverMirrorServer {
+	t.Helper()
+
+	server := &failoverMirrorServer{ranges: make(map[string][]string)}
+	server.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/golang/" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+
+		filename := path.Base(r.URL.Path)
+		if archiveName, ok := strings.CutSuffix(filename, ".sha256"); ok {
+			if info := ParseArchiveFilename(archiveName); info != nil && archives[info.Version] != nil {
+				fmt.Fprintf(w, "%x  %s\n", sha256.Sum256(archives[info.Version]), archiveName)
+				return
+			}
+			http.NotFound(w, r)
+			return
+		}
+		info := ParseArchiveFilename(filename)
+		if info == nil || archives[info.Version] == nil {
+			http.NotFound(w, r)
+			return
+		}
+		data := archives[info.Version]
+
+		if r.Method == http.MethodGet {
+			server.mu.Lock()
+			server.ranges[filename] = append(server.ranges[filename], r.Header.Get("Range"))
+			server.mu.Unlock()
+		}
+
+		switch mode {
+		case "error":
+			w.WriteHeader(http.StatusInternalServerError)
+		case "drop":
+			w.Header().Set("Content-Length", fmt.Sprintf("%d", len(data)))
+			w.WriteHeader(http.StatusOK)
+			if r.Method == http.MethodHead {
+				return
+			}
+			w.Write(data[:len(data)/2])
+			w.(http.Flusher).Flush()
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err == nil {
+				conn.Close()
+			}
+		default:
+			http.ServeContent(w, r, filename, time.Time{}, bytes.NewReader(data))
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestVersionService_DownloadFailover(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("模拟go可执行文件依赖shell脚本")
+	}
+	t.Setenv("HOME", t.TempDir())
+
+	archives := map[string][]byte{
+		"1.22.7":  createFakeGoArchive(t, "1.22.7"),
+		"1.21.13": createFakeGoArchive(t, "1.21.13"),
+		"1.20.14": createFakeGoArchive(t, "1.20.14"),
+	}
+	dropping := newFailoverMirrorServer(t, archives, "drop")
+	broken := newFailoverMirrorServer(t, archives, "error")
+	healthy := newFailoverMirrorServer(t, archives, "ok")
+
+	// 自定义镜像的优先级高于预设镜像，保证切换时不会访问外部网络
+	mirrorService, err := NewMirrorServiceWithConfig(DefaultMirrorConfigPath())
+	if err != nil {
+		t.Fatalf("加载镜像配置失败: %v", err)
+	}
+	mirrorService.AddCustomMirror(Mirror{Name: "dropping", BaseURL: dropping.URL + "/golang/", Priority: -3})
+	mirrorService.AddCustomMirror(Mirror{Name: "broken", BaseURL: broken.URL + "/golang/", Priority: -2})
+	mirrorService.AddCustomMirror(Mirror{Name: "healthy", BaseURL: healthy.URL + "/golang/", Priority: -1})
+
+	versionRepo := NewMockVersionRepository()
+	service := NewVersionServiceWithDependencies(
+		versionRepo,
+		NewMockEnvironmentRepository(),
+		NewSystemDetector(),
+		NewDownloadService(&DownloadOptions{
+			MaxRetries:         0,
+			Timeout:            10 * time.Second,
+			ChunkSize:          32 * 1024,
+			ProgressUpdateRate: 10 * time.Millisecond,
+		}),
+		NewArchiveExtractor(nil),
+		mirrorService,
+	)
+	filenameOf := func(version string) string {
+		return fmt.Sprintf("go%s.%s-%s.tar.gz", version, runtime.GOOS, runtime.GOARCH)
+	}
+
+	// 校验和已知时，在新镜像上从断开的位置继续下载
+	version := "1.22.7"
+	result, err := service.InstallOnline(version, &model.InstallOptions{
+		Mirror: "dropping",
+		SHA256: fmt.Sprintf("%x", sha256.Sum256(archives[version])),
+	})
+	if err != nil || !result.Success {
+		t.Fatalf("切换镜像后安装应成功: %v", err)
+	}
+	if result.DownloadInfo.Mirror != "healthy" {
+		t.Errorf("期望由 healthy 完成下载，实际 %s", result.DownloadInfo.Mirror)
+	}
+	if expected := []string{"dropping", "broken", "healthy"}; !reflect.DeepEqual(result.DownloadInfo.AttemptedMirrors, expected) {
+		t.Errorf("尝试过的镜像 = %v, 期望 %v", result.DownloadInfo.AttemptedMirrors, expected)
+	}
+	if expectedURL := healthy.URL + "/golang/" + filenameOf(version); result.DownloadInfo.URL != expectedURL {
+		t.Errorf("下载地址 = %s, 期望 %s", result.DownloadInfo.URL, expectedURL)
+	}
+	half := len(archives[version]) / 2
+	if ranges := healthy.requestedRanges(filenameOf(version)); len(ranges) != 1 || ranges[0] != fmt.Sprintf("bytes=%d-", half) {
+		t.Errorf("期望从第 %d 字节继续下载，实际Range请求: %q", half, ranges)
+	}
+	installed, err := versionRepo.FindByVersion(version)
+	if err != nil {
+		t.Fatalf("未找到安装的版本: %v", err)
+	}
+	if installed.DownloadInfo == nil || installed.DownloadInfo.Mirror != "healthy" || len(installed.DownloadInfo.AttemptedMirrors) != 3 {
+		t.Errorf("版本记录中的镜像信息不正确: %+v", installed.DownloadInfo)
+	}
+
+	// 校验和未知时无法确认拼接结果，应重新完整下载
+	version = "1.21.13"
+	result, err = service.InstallOnline(version, &model.InstallOptions{Mirror: "dropping"})
+	if err != nil || !result.Success {
+		t.Fatalf("切换镜像后安装应成功: %v", err)
+	}
+	if ranges := healthy.requestedRanges(filenameOf(version)); len(ranges) != 1 || ranges[0] != "" {
+		t.Errorf("校验和未知时应完整下载，实际Range请求: %q", ranges)
+	}
+
+	// 禁用切换时只使用选定的镜像
+	version = "1.20.14"
+	_, err = service.InstallOnline(version, &model.InstallOptions{Mirror: "dropping", NoFailover: true})
+	if err == nil {
+		t.Fatal("禁用切换时镜像断开连接应安装失败")
+	}
+	if ranges := healthy.requestedRanges(filenameOf(version)); len(ranges) != 0 {
+		t.Errorf("禁用切换时不应访问其他镜像，实际Range请求: %q", ranges)
+	}
+
+	// 版本不存在时不切换镜像
+	_, err = service.InstallOnline("1.19.13", &model.InstallOptions{Mirror: "dropping"})
+	if err == nil {
+		t.Fatal("版本不存在时安装应失败")
+	}
+	if strings.Contains(err.Error(), "所有镜像") {
+		t.Errorf("404错误不应切换镜像: %v", err)
+	}
+}
+
+func TestVersionService_DownloadFailoverWithMirrorChecksum(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("模拟go可执行文件依赖shell脚本")
+	}
+	t.Setenv("HOME", t.TempDir())
+
+	version := "1.22.7"
+	archives := map[string][]byte{version: createFakeGoArchive(t, version)}
+	dropping := newFailoverMirrorServer(t, archives, "drop")
+	healthy := newFailoverMirrorServer(t, archives, "ok")
+
+	mirrorService, err := NewMirrorServiceWithConfig(DefaultMirrorConfigPath())
+	if err != nil {
+		t.Fatalf("加载镜像配置失败: %v", err)
+	}
+	mirrorService.AddCustomMirror(Mirror{Name: "dropping", BaseURL: dropping.URL + "/golang/", Priority: -2})
+	mirrorService.AddCustomMirror(Mirror{
+		Name:                "healthy",
+		BaseURL:             healthy.URL + "/golang/",
+		ChecksumURLTemplate: healthy.URL + "/golang/{filename}.sha256",
+		Priority:            -1,
+	})
+
+	service := NewVersionServiceWithDependencies(
+		NewMockVersionRepository(),
+		NewMockEnvironmentRepository(),
+		NewSystemDetector(),
+		NewDownloadService(&DownloadOptions{
+			MaxRetries:         0,
+			Timeout:            10 * time.Second,
+			ChunkSize:          32 * 1024,
+			ProgressUpdateRate: 10 * time.Millisecond,
+		}),
+		NewArchiveExtractor(nil),
+		mirrorService,
+	)
+
+	// 未指定 --sha256 时，新镜像提供校验和文件即可从断开的位置继续下载
+	result, err := service.InstallOnline(version, &model.InstallOptions{Mirror: "dropping"})
+	if err != nil || !result.Success {
+		t.Fatalf("切换镜像后安装应成功: %v", err)
+	}
+	if result.DownloadInfo.Mirror != "healthy" {
+		t.Errorf("期望由 healthy 完成下载，实际 %s", result.DownloadInfo.Mirror)
+	}
+	filename := fmt.Sprintf("go%s.%s-%s.tar.gz", version, runtime.GOOS, runtime.GOARCH)
+	half := len(archives[version]) / 2
+	if ranges := healthy.requestedRanges(filename); len(ranges) != 1 || ranges[0] != fmt.Sprintf("bytes=%d-", half) {
+		t.Errorf("期望从第 %d 字节继续下载，实际Range请求: %q", half, ranges)
+	}
+}
+
+func TestMirrorService_RankMirrors(t *testing.T) {
+	service, err := NewMirrorServiceWithHTTPClientFactory("", DefaultHTTPClientFactory())
+	if err != nil {
+		t.Fatalf("创建镜像服务失败: %v", err)
+	}
+	impl := service.(*mirrorServiceImpl)
+	impl.config.CacheTestResult("slow", &MirrorTestResult{Available: true, ResponseTime: 300 * time.Millisecond})
+	impl.config.CacheTestResult("fast", &MirrorTestResult{Available: true, ResponseTime: 50 * time.Millisecond})
+	impl.config.CacheTestResult("down", &MirrorTestResult{Available: false})
+
+	ranked := service.RankMirrors([]Mirror{
+		{Name: "down", Priority: 1},
+		{Name: "untested", Priority: 2},
+		{Name: "slow", Priority: 2},
+		{Name: "fast", Priority: 2},
+		{Name: "first", Priority: 1},
+	})
+
+	var names []string
+	for _, mirror := range ranked {
+		names = append(names, mirror.Name)
+	}
+	expected := []string{"first", "fast", "slow", "untested", "down"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("镜像顺序 = %v, 期望 %v", names, expected)
+	}
+}
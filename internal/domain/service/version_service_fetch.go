@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"version-list/internal/domain/model"
+)
+
+// FetchOptions 只下载不安装的选项
+type FetchOptions struct {
+	OS               string // 目标操作系统，为空时使用当前系统
+	Arch             string // 目标CPU架构，为空时使用当前系统
+	OutputDir        string // 压缩包保存目录，为空时只保存到下载缓存
+	Mirror           string // 指定镜像源名称
+	SHA256           string // 期望的压缩包SHA-256校验和（为空时使用镜像提供的校验和文件）
+	SkipVerification bool   // 跳过压缩包验证
+	NoFailover       bool   // 下载失败时不切换到其他镜像
+}
+
+// FetchResult 下载结果
+type FetchResult struct {
+	Path         string              // 压缩包路径（输出目录或下载缓存中）
+	SystemInfo   *model.SystemInfo   // 目标平台及下载地址
+	DownloadInfo *model.DownloadInfo // 下载信息，Checksum 为压缩包的SHA-256
+	Cached       bool                // 是否直接使用了下载缓存
+}
+
+// Fetch 下载并验证任意平台的Go压缩包，保存到下载缓存或指定目录，但不安装
+func (s *VersionService) Fetch(ctx context.Context, version string, options *FetchOptions, progressUI ProgressReporter) (*FetchResult, error) {
+	if options == nil {
+		options = &FetchOptions{}
+	}
+
+	target := &model.SystemInfo{OS: options.OS, Arch: options.Arch}
+	if target.OS == "" {
+		target.OS = runtime.GOOS
+	}
+	if target.Arch == "" {
+		target.Arch = runtime.GOARCH
+	}
+
+	tempDir, err := os.MkdirTemp("", "go-fetch-")
+	if err != nil {
+		return nil, fmt.Errorf("创建临时目录失败: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	context, err := s.createInstallationContext(ctx, version, &model.InstallOptions{
+		SkipVerification: options.SkipVerification,
+		Mirror:           options.Mirror,
+		SHA256:           options.SHA256,
+		NoFailover:       options.NoFailover,
+		TargetSystem:     target,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("创建下载上下文失败: %v", err)
+	}
+	context.TempDir = tempDir
+	context.Paths.TempDir = tempDir
+	context.Paths.ArchiveFile = filepath.Join(tempDir, context.SystemInfo.Filename)
+
+	if progressUI != nil {
+		progressUI.SetStage("下载文件")
+		progressUI.SetMessage(fmt.Sprintf("正在获取 %s...", context.SystemInfo.Filename))
+	}
+
+	// 优先使用下载缓存，缓存的压缩包同样需要通过验证
+	cache := s.downloadCache()
+	result := &FetchResult{SystemInfo: context.SystemInfo}
+	cached, cachedPath, err := s.copyCachedArchive(cache, context)
+	if err != nil {
+		return nil, err
+	}
+	if cached != nil {
+		result.Path = cachedPath
+		result.Cached = true
+		result.DownloadInfo = cachedDownloadInfo(context, cached)
+	} else {
+		result.DownloadInfo, err = s.downloadGoArchiveWithProgress(ctx, context, progressUI)
+		if err != nil {
+			return nil, fmt.Errorf("下载失败: %v", err)
+		}
+	}
+
+	var verified string
+	if !options.SkipVerification {
+		if progressUI != nil {
+			progressUI.SetStage("验证文件")
+			progressUI.SetMessage("验证下载文件完整性...")
+		}
+		verified, err = s.verifyDownload(ctx, context)
+		if err != nil {
+			evictCachedArchive(cache, cached)
+			return nil, fmt.Errorf("验证失败: %v", err)
+		}
+	}
+
+	checksum, err := NewFileValidator().CalculateChecksum(context.Paths.ArchiveFile, ChecksumTypeSHA256)
+	if err != nil {
+		return nil, fmt.Errorf("计算校验和失败: %v", err)
+	}
+	result.DownloadInfo.Checksum = checksum
+	result.DownloadInfo.ChecksumType = ChecksumTypeSHA256
+
+	// 通过SHA-256验证的新下载压缩包才加入缓存，未验证时只能保存到输出目录
+	if !result.Cached {
+		if verified == "" {
+			if options.OutputDir == "" {
+				return nil, fmt.Errorf("压缩包未经SHA-256验证，不能保存到下载缓存，请指定输出目录")
+			}
+		} else {
+			entry, err := cache.StoreVerified(context.SystemInfo.URL, context.Paths.ArchiveFile, verified)
+			if err != nil {
+				return nil, fmt.Errorf("保存到下载缓存失败: %v", err)
+			}
+			result.Path = cache.entryPath(entry.SHA256)
+		}
+	}
+
+	if options.OutputDir != "" {
+		if err := os.MkdirAll(options.OutputDir, 0755); err != nil {
+			return nil, fmt.Errorf("创建输出目录失败: %v", err)
+		}
+		outputPath := filepath.Join(options.OutputDir, context.SystemInfo.Filename)
+		if err := s.copyFileOptimized(context.Paths.ArchiveFile, outputPath); err != nil {
+			return nil, fmt.Errorf("保存压缩包失败: %v", err)
+		}
+		result.Path = outputPath
+	}
+
+	if progressUI != nil {
+		progressUI.SetProgress(100)
+		progressUI.SetMessage("下载完成!")
+	}
+	return result, nil
+}
@@ -0,0 +1,301 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+	"version-list/internal/domain/model"
+)
+
+// crossTestOS 测试中使用的其他平台，与当前系统不同
+const (
+	crossTestOS   = "plan9"
+	crossTestArch = "amd64"
+)
+
+// newCrossPlatformServer 创建提供其他平台压缩包的测试服务器，并统计下载次数
+func newCrossPlatformServer(t *testing.T, version string) (*httptest.Server, []byte, *int32) {
+	t.Helper()
+	archive := createFakeGoArchive(t, version)
+	filename := "/go" + version + "." + crossTestOS + "-" + crossTestArch + ".tar.gz"
+
+	var downloads int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != filename {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Method == http.MethodGet {
+			atomic.AddInt32(&downloads, 1)
+		}
+		http.ServeContent(w, r, filename, time.Time{}, bytes.NewReader(archive))
+	}))
+	t.Cleanup(server.Close)
+	return server, archive, &downloads
+}
+
+func TestVersionService_Fetch_CrossPlatform(t *testing.T) {
+	server, archive, downloads := newCrossPlatformServer(t, "1.22.7")
+	service, versionRepo := newTestVersionService(t, server.URL)
+
+	sum := sha256.Sum256(archive)
+	checksum := hex.EncodeToString(sum[:])
+	outputDir := t.TempDir()
+
+	result, err := service.Fetch(context.Background(), "1.22.7", &FetchOptions{
+		OS:        crossTestOS,
+		Arch:      crossTestArch,
+		OutputDir: outputDir,
+		SHA256:    checksum,
+	}, nil)
+	if err != nil {
+		t.Fatalf("下载失败: %v", err)
+	}
+
+	expectedPath := filepath.Join(outputDir, "go1.22.7."+crossTestOS+"-"+crossTestArch+".tar.gz")
+	if result.Path != expectedPath || result.Cached {
+		t.Errorf("下载结果不正确: %+v", result)
+	}
+	if result.DownloadInfo.Checksum != checksum {
+		t.Errorf("校验和 = %s, 期望 %s", result.DownloadInfo.Checksum, checksum)
+	}
+	if data, err := os.ReadFile(expectedPath); err != nil || string(data) != string(archive) {
+		t.Errorf("输出目录中的压缩包不正确: %v", err)
+	}
+	if versions, _ := versionRepo.FindAll(); len(versions) != 0 {
+		t.Errorf("只下载时不应记录版本: %+v", versions)
+	}
+
+	// 再次下载时直接使用缓存
+	result, err = service.Fetch(context.Background(), "1.22.7", &FetchOptions{OS: crossTestOS, Arch: crossTestArch, SHA256: checksum}, nil)
+	if err != nil {
+		t.Fatalf("下载失败: %v", err)
+	}
+	if !result.Cached || atomic.LoadInt32(downloads) != 1 {
+		t.Errorf("应使用缓存，下载次数 %d", atomic.LoadInt32(downloads))
+	}
+	if !strings.HasPrefix(result.Path, service.downloadCache().Dir()) {
+		t.Errorf("未指定输出目录时应返回缓存中的路径: %s", result.Path)
+	}
+}
+
+func TestVersionService_Fetch_ChecksumMismatch(t *testing.T) {
+	server, _, _ := newCrossPlatformServer(t, "1.22.7")
+	service, _ := newTestVersionService(t, server.URL)
+
+	_, err := service.Fetch(context.Background(), "1.22.7", &FetchOptions{
+		OS:     crossTestOS,
+		Arch:   crossTestArch,
+		SHA256: strings.Repeat("0", 64),
+	}, nil)
+	if err == nil || !strings.Contains(err.Error(), "验证失败") {
+		t.Errorf("校验和不匹配时应返回验证错误: %v", err)
+	}
+	if _, _, err := service.downloadCache().LookupFilename("go1.22.7." + crossTestOS + "-" + crossTestArch + ".tar.gz"); err == nil {
+		t.Error("验证失败的压缩包不应加入缓存")
+	}
+}
+
+func TestVersionService_InstallOnline_CrossPlatform(t *testing.T) {
+	server, _, _ := newCrossPlatformServer(t, "1.22.7")
+	service, versionRepo := newTestVersionService(t, server.URL)
+	target := &model.SystemInfo{OS: crossTestOS, Arch: crossTestArch}
+
+	_, err := service.InstallOnline("1.22.7", &model.InstallOptions{SkipVerification: true, TargetSystem: target})
+	if err == nil || !strings.Contains(err.Error(), "安装路径") {
+		t.Errorf("安装其他平台的Go时未指定路径应返回错误: %v", err)
+	}
+
+	installDir := filepath.Join(t.TempDir(), "go-"+crossTestOS)
+	result, err := service.InstallOnline("1.22.7", &model.InstallOptions{
+		SkipVerification: true,
+		CustomPath:       installDir,
+		TargetSystem:     target,
+	})
+	if err != nil {
+		t.Fatalf("安装失败: %v", err)
+	}
+	if !result.Success || result.Path != installDir {
+		t.Errorf("安装结果不正确: %+v", result)
+	}
+	if _, err := os.Stat(filepath.Join(installDir, "bin", "go")); err != nil {
+		t.Errorf("安装目录中缺少go可执行文件: %v", err)
+	}
+	if _, err := versionRepo.FindByVersion("1.22.7"); err == nil {
+		t.Error("其他平台的Go不应加入版本列表")
+	}
+}
+
+func TestVersionService_Fetch_EvictsUnverifiedCache(t *testing.T) {
+	server, archive, downloads := newCrossPlatformServer(t, "1.22.7")
+	service, _ := newTestVersionService(t, server.URL)
+	sum := sha256.Sum256(archive)
+	checksum := hex.EncodeToString(sum[:])
+	filename := "go1.22.7." + crossTestOS + "-" + crossTestArch + ".tar.gz"
+
+	// 缓存中同名但内容不同的压缩包
+	cache := service.downloadCache()
+	tampered := filepath.Join(t.TempDir(), filename)
+	os.WriteFile(tampered, createFakeGoArchive(t, "1.22.6"), 0644)
+	entry, err := cache.Store("https://tampered.example.com/"+filename, tampered)
+	if err != nil {
+		t.Fatalf("缓存文件失败: %v", err)
+	}
+
+	options := &FetchOptions{OS: crossTestOS, Arch: crossTestArch, SHA256: checksum}
+	if _, err := service.Fetch(context.Background(), "1.22.7", options, nil); err == nil || !strings.Contains(err.Error(), "验证失败") {
+		t.Fatalf("缓存的压缩包与校验和不一致时应返回验证错误: %v", err)
+	}
+	if _, _, err := cache.LookupFilename(filename); err == nil {
+		t.Error("未通过验证的缓存条目应被移除")
+	}
+	if _, err := os.Stat(filepath.Join(cache.Dir(), entry.SHA256)); !os.IsNotExist(err) {
+		t.Error("未通过验证的缓存文件应被删除")
+	}
+
+	// 再次下载时从镜像获取，并按验证过的SHA-256加入缓存
+	result, err := service.Fetch(context.Background(), "1.22.7", options, nil)
+	if err != nil {
+		t.Fatalf("下载失败: %v", err)
+	}
+	if result.Cached || atomic.LoadInt32(downloads) != 1 {
+		t.Errorf("应重新下载，下载次数 %d", atomic.LoadInt32(downloads))
+	}
+	if filepath.Base(result.Path) != checksum {
+		t.Errorf("缓存路径 = %s, 期望以验证过的SHA-256命名", result.Path)
+	}
+
+	// 未经SHA-256验证的压缩包不加入缓存
+	cache.Remove(checksum)
+	_, err = service.Fetch(context.Background(), "1.22.7", &FetchOptions{OS: crossTestOS, Arch: crossTestArch, SkipVerification: true}, nil)
+	if err == nil || !strings.Contains(err.Error(), "未经SHA-256验证") {
+		t.Errorf("跳过验证且未指定输出目录时应返回错误: %v", err)
+	}
+	if _, count, _ := cache.Size(); count != 0 {
+		t.Errorf("缓存条目数量 = %d, 期望 0", count)
+	}
+}
+
+func TestVersionService_InstallOnline_CachesOnlyVerifiedArchives(t *testing.T) {
+	server, archive, downloads := newCrossPlatformServer(t, "1.22.7")
+	service, _ := newTestVersionService(t, server.URL)
+	cache := NewDownloadCache(t.TempDir(), 0)
+	service.downloadService = NewDownloadService(&DownloadOptions{
+		Timeout:      10 * time.Second,
+		EnableCache:  true,
+		CacheDir:     cache.Dir(),
+		MaxCacheSize: DefaultMaxCacheSize,
+	})
+	sum := sha256.Sum256(archive)
+	checksum := hex.EncodeToString(sum[:])
+	target := &model.SystemInfo{OS: crossTestOS, Arch: crossTestArch}
+	install := func(options *model.InstallOptions) error {
+		options.TargetSystem = target
+		options.CustomPath = filepath.Join(t.TempDir(), "go")
+		_, err := service.InstallOnline("1.22.7", options)
+		return err
+	}
+
+	// 下载服务本身不写入缓存，跳过验证的压缩包也不加入缓存
+	if err := install(&model.InstallOptions{SkipVerification: true}); err != nil {
+		t.Fatalf("安装失败: %v", err)
+	}
+	if _, count, _ := cache.Size(); count != 0 {
+		t.Errorf("未验证的压缩包不应加入缓存，缓存条目数量 = %d", count)
+	}
+
+	if err := install(&model.InstallOptions{SHA256: checksum}); err != nil {
+		t.Fatalf("安装失败: %v", err)
+	}
+	entries, _ := cache.List()
+	if len(entries) != 1 || entries[0].SHA256 != checksum {
+		t.Fatalf("缓存条目 = %+v, 期望以验证过的SHA-256为键", entries)
+	}
+	if err := install(&model.InstallOptions{SHA256: checksum}); err != nil {
+		t.Fatalf("安装失败: %v", err)
+	}
+	if atomic.LoadInt32(downloads) != 2 {
+		t.Errorf("已验证的缓存应被复用，下载次数 %d", atomic.LoadInt32(downloads))
+	}
+
+	// 缓存的压缩包未通过验证时移除该条目
+	if err := install(&model.InstallOptions{SHA256: strings.Repeat("0", 64)}); err == nil {
+		t.Fatal("校验和不一致时应安装失败")
+	}
+	if _, count, _ := cache.Size(); count != 0 {
+		t.Errorf("未通过验证的缓存条目应被移除，缓存条目数量 = %d", count)
+	}
+}
+
+func TestVersionService_Fetch_ChecksumFallback(t *testing.T) {
+	archive := createFakeGoArchive(t, "1.22.7")
+	sum := sha256.Sum256(archive)
+	checksum := hex.EncodeToString(sum[:])
+	filename := "go1.22.7." + crossTestOS + "-" + crossTestArch + ".tar.gz"
+
+	var catalogChecksum, cnChecksum string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/" + filename:
+			http.ServeContent(w, r, filename, time.Time{}, bytes.NewReader(archive))
+		case "/cn/" + filename + ".sha256":
+			if cnChecksum == "" {
+				http.NotFound(w, r)
+				return
+			}
+			w.Write([]byte(cnChecksum + "  " + filename + "\n"))
+		case "/dl.json":
+			w.Write([]byte(`[{"version": "go1.22.7", "files": [{"filename": "` + filename + `", "os": "` + crossTestOS + `", "arch": "` + crossTestArch + `", "sha256": "` + catalogChecksum + `", "kind": "archive"}]}]`))
+		default:
+			// 官方校验和文件无法访问
+			http.Error(w, "blocked", http.StatusForbidden)
+		}
+	}))
+	defer server.Close()
+
+	official, fallbacks := officialChecksumURLTemplate, fallbackChecksumURLTemplates
+	officialChecksumURLTemplate = server.URL + "/dl-google/{filename}.sha256"
+	fallbackChecksumURLTemplates = []string{server.URL + "/cn/{filename}.sha256"}
+	t.Cleanup(func() {
+		officialChecksumURLTemplate, fallbackChecksumURLTemplates = official, fallbacks
+	})
+
+	service, _ := newTestVersionService(t, server.URL)
+	service.catalogURL = server.URL + "/dl.json"
+	fetch := func() (*FetchResult, error) {
+		return service.Fetch(context.Background(), "1.22.7", &FetchOptions{OS: crossTestOS, Arch: crossTestArch, OutputDir: t.TempDir()}, nil)
+	}
+
+	// 官方校验和文件和备用校验和文件都无法获取时使用发布目录
+	catalogChecksum = checksum
+	if result, err := fetch(); err != nil || result.DownloadInfo.Checksum != checksum {
+		t.Fatalf("使用发布目录中的校验和验证失败: %+v, %v", result, err)
+	}
+
+	// 备用校验和文件优先于发布目录
+	cnChecksum, catalogChecksum = checksum, strings.Repeat("0", 64)
+	if _, err := fetch(); err != nil {
+		t.Fatalf("使用备用校验和文件验证失败: %v", err)
+	}
+
+	// 备用来源的校验和不一致时验证失败
+	cnChecksum = ""
+	if _, err := fetch(); err == nil || !strings.Contains(err.Error(), "验证失败") {
+		t.Errorf("发布目录中的校验和不一致时应验证失败: %v", err)
+	}
+
+	// 所有来源都无法获取时返回错误
+	service.catalogURL = server.URL + "/missing.json"
+	if _, err := fetch(); err == nil || !strings.Contains(err.Error(), "无法获取官方校验和") {
+		t.Errorf("所有来源都无法获取时应返回错误: %v", err)
+	}
+}
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -69,7 +70,7 @@ echo go version go1.20.0 windows/amd64
 		}
 
 		// 测试导入本地版本
-		version, err := service.ImportLocal(goDir)
+		version, err := service.ImportLocal(goDir, "")
 		if err != nil {
 			t.Logf("导入本地版本失败（预期，因为模拟脚本）: %v", err)
 			// 这是预期的，因为我们的模拟脚本可能无法正确执行
@@ -172,7 +173,7 @@ func TestVersionServiceWithCustomDependencies(t *testing.T) {
 
 	// 测试创建安装上下文
 	version := "1.21.0"
-	context, err := service.createInstallationContext(version, nil)
+	context, err := service.createInstallationContext(context.Background(), version, nil)
 	if err != nil {
 		t.Fatalf("创建安装上下文失败: %v", err)
 	}
@@ -243,7 +244,7 @@ func TestVersionServiceErrorHandling(t *testing.T) {
 	})
 
 	t.Run("导入不存在的路径", func(t *testing.T) {
-		_, err := service.ImportLocal("/nonexistent/path")
+		_, err := service.ImportLocal("/nonexistent/path", "")
 		if err == nil {
 			t.Error("期望导入不存在路径时返回错误")
 		}
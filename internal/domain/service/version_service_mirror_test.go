@@ -0,0 +1,195 @@
+package service
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"version-list/internal/domain/model"
+)
+
+func TestMirror_DownloadURL(t *testing.T) {
+	filename := "go1.22.7.linux-amd64.tar.gz"
+	for _, baseURL := range []string{"https://mirror.corp/golang", "https://mirror.corp/golang/"} {
+		got := Mirror{BaseURL: baseURL}.DownloadURL(filename)
+		if got != "https://mirror.corp/golang/"+filename {
+			t.Errorf("DownloadURL(%s) = %s", baseURL, got)
+		}
+	}
+
+	// 预设镜像的下载地址同样由基础URL生成
+	detector := &SystemDetectorImpl{}
+	for _, mirror := range getDefaultMirrors() {
+		got := detector.GetDownloadURLWithMirror("1.22.7", "linux", "amd64", mirror.Name)
+		if got != mirror.DownloadURL(filename) {
+			t.Errorf("镜像 %s 的下载地址 = %s, 期望 %s", mirror.Name, got, mirror.DownloadURL(filename))
+		}
+	}
+}
+
+func TestVersionService_InstallWithCustomMirror(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("模拟go可执行文件依赖shell脚本")
+	}
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	version := "1.22.7"
+	filename := fmt.Sprintf("go%s.%s-%s.tar.gz", version, runtime.GOOS, runtime.GOARCH)
+	archive := createFakeGoArchive(t, version)
+
+	var mu sync.Mutex
+	var requested []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requested = append(requested, r.URL.Path)
+		mu.Unlock()
+
+		switch r.URL.Path {
+		case "/golang/":
+			w.WriteHeader(http.StatusOK)
+		case "/golang/" + filename:
+			w.Header().Set("Content-Length", fmt.Sprintf("%d", len(archive)))
+			w.WriteHeader(http.StatusOK)
+			if r.Method != http.MethodHead {
+				w.Write(archive)
+			}
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	// 与 mirror add 命令相同的方式保存自定义镜像
+	mirrorService, err := NewMirrorServiceWithConfig(DefaultMirrorConfigPath())
+	if err != nil {
+		t.Fatalf("加载镜像配置失败: %v", err)
+	}
+	if err := mirrorService.AddCustomMirror(Mirror{Name: "corp", BaseURL: server.URL + "/golang/", Priority: 1}); err != nil {
+		t.Fatalf("添加自定义镜像失败: %v", err)
+	}
+	if err := mirrorService.SaveConfig(DefaultMirrorConfigPath()); err != nil {
+		t.Fatalf("保存镜像配置失败: %v", err)
+	}
+
+	versionRepo := NewMockVersionRepository()
+	service := NewVersionService(versionRepo, NewMockEnvironmentRepository())
+
+	result, err := service.InstallOnline(version, &model.InstallOptions{Mirror: "corp", SkipVerification: true})
+	if err != nil {
+		t.Fatalf("使用自定义镜像安装失败: %v", err)
+	}
+	if !result.Success {
+		t.Fatalf("安装未成功: %s", result.Error)
+	}
+
+	expectedURL := server.URL + "/golang/" + filename
+	if result.DownloadInfo == nil || result.DownloadInfo.URL != expectedURL {
+		t.Errorf("期望从自定义镜像下载 %s，实际 %+v", expectedURL, result.DownloadInfo)
+	}
+
+	mu.Lock()
+	downloaded := false
+	for _, path := range requested {
+		if strings.HasSuffix(path, filename) {
+			downloaded = true
+		}
+	}
+	mu.Unlock()
+	if !downloaded {
+		t.Errorf("自定义镜像没有收到下载请求: %v", requested)
+	}
+
+	installed, err := versionRepo.FindByVersion(version)
+	if err != nil {
+		t.Fatalf("未找到安装的版本: %v", err)
+	}
+	if installed.DownloadInfo == nil || installed.DownloadInfo.URL != expectedURL {
+		t.Errorf("版本记录中的下载地址不正确: %+v", installed.DownloadInfo)
+	}
+
+	// 未知镜像名称应返回错误，而不是回退到官方源
+	if _, err := service.InstallOnline("1.21.13", &model.InstallOptions{Mirror: "missing"}); err == nil {
+		t.Error("未知镜像应返回错误")
+	}
+}
+
+func TestVersionService_InstallWithTemplateMirror(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("模拟go可执行文件依赖shell脚本")
+	}
+	t.Setenv("HOME", t.TempDir())
+
+	archives := map[string][]byte{
+		"1.22.7":  createFakeGoArchive(t, "1.22.7"),
+		"1.21.13": createFakeGoArchive(t, "1.21.13"),
+	}
+	checksums := map[string]string{
+		"1.22.7": fmt.Sprintf("%x  go.tar.gz\n", sha256.Sum256(archives["1.22.7"])),
+		// 校验和与压缩包内容不一致
+		"1.21.13": fmt.Sprintf("%x\n", sha256.Sum256([]byte("tampered"))),
+	}
+
+	platformPath := fmt.Sprintf("/%s/%s/go.tar.gz", runtime.GOOS, runtime.GOARCH)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/repo/" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		for version, data := range archives {
+			prefix := "/repo/" + version + platformPath
+			switch {
+			case r.URL.Path == prefix && r.URL.Query().Get("download") == "1":
+				w.Header().Set("Content-Length", fmt.Sprintf("%d", len(data)))
+				w.WriteHeader(http.StatusOK)
+				if r.Method != http.MethodHead {
+					w.Write(data)
+				}
+				return
+			case r.URL.Path == prefix+".sha256":
+				fmt.Fprint(w, checksums[version])
+				return
+			}
+		}
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	mirrorService, err := NewMirrorServiceWithConfig(DefaultMirrorConfigPath())
+	if err != nil {
+		t.Fatalf("加载镜像配置失败: %v", err)
+	}
+	mirrorService.AddCustomMirror(Mirror{
+		Name:                "layout",
+		BaseURL:             server.URL + "/repo/",
+		URLTemplate:         server.URL + "/repo/{version}/{os}/{arch}/go.{ext}?download=1",
+		ChecksumURLTemplate: server.URL + "/repo/{version}/{os}/{arch}/go.{ext}.sha256",
+	})
+	if err := mirrorService.SaveConfig(DefaultMirrorConfigPath()); err != nil {
+		t.Fatalf("保存镜像配置失败: %v", err)
+	}
+
+	service := NewVersionService(NewMockVersionRepository(), NewMockEnvironmentRepository())
+
+	result, err := service.InstallOnline("1.22.7", &model.InstallOptions{Mirror: "layout"})
+	if err != nil || !result.Success {
+		t.Fatalf("使用URL模板镜像安装失败: %v", err)
+	}
+	expectedURL := server.URL + "/repo/1.22.7" + platformPath + "?download=1"
+	if result.DownloadInfo.URL != expectedURL {
+		t.Errorf("下载地址 = %s, 期望 %s", result.DownloadInfo.URL, expectedURL)
+	}
+
+	// 镜像提供的校验和不匹配时安装失败
+	_, err = service.InstallOnline("1.21.13", &model.InstallOptions{Mirror: "layout"})
+	if err == nil {
+		t.Fatal("校验和不匹配时安装应失败")
+	}
+	if !strings.Contains(err.Error(), "校验") {
+		t.Errorf("错误信息应说明校验和不匹配，实际: %v", err)
+	}
+}
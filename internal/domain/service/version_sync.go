@@ -0,0 +1,243 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"version-list/internal/domain/model"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultSyncManifestName 工具链清单的默认文件名
+const DefaultSyncManifestName = "toolchains.yaml"
+
+// SyncProgressReporter 同步进度报告接口
+type SyncProgressReporter interface {
+	ReporterFor(action *model.SyncAction) ProgressReporter // 获取安装操作的进度报告器
+	Complete(result *model.SyncResult)                     // 报告单个操作完成
+}
+
+// LoadSyncManifest 读取工具链清单，.json 文件按JSON解析，其他按YAML解析
+func LoadSyncManifest(path string) (*model.SyncManifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取工具链清单失败: %w", err)
+	}
+
+	var manifest model.SyncManifest
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		err = json.Unmarshal(data, &manifest)
+	} else {
+		err = yaml.Unmarshal(data, &manifest)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("解析工具链清单失败: %v", err)
+	}
+	if err := validateSyncManifest(&manifest); err != nil {
+		return nil, err
+	}
+	return &manifest, nil
+}
+
+// validateSyncManifest 验证清单：版本号有效、名称不重复，且需要移除的版本不能同时被要求安装或作为默认版本
+func validateSyncManifest(manifest *model.SyncManifest) error {
+	declared := make(map[string]bool)
+	for _, toolchain := range manifest.Toolchains {
+		if releaseNumber(toolchain.Version) == "" {
+			return fmt.Errorf("清单中的版本号无效: %q", toolchain.Version)
+		}
+		name, err := resolveToolchainName(toolchain.Version, &model.InstallOptions{Name: toolchain.Name})
+		if err != nil {
+			return err
+		}
+		if declared[name] {
+			return fmt.Errorf("清单中重复声明了 %s", name)
+		}
+		declared[name] = true
+	}
+
+	for _, version := range manifest.Remove {
+		if declared[version] {
+			return fmt.Errorf("%s 同时出现在 toolchains 和 remove 中", version)
+		}
+		if version == manifest.Default {
+			return fmt.Errorf("默认版本 %s 不能出现在 remove 中", version)
+		}
+	}
+	return nil
+}
+
+// PlanSync 对比清单与已安装的版本，生成使机器与清单一致所需的操作
+func (s *VersionService) PlanSync(manifest *model.SyncManifest) (*model.SyncPlan, error) {
+	if err := validateSyncManifest(manifest); err != nil {
+		return nil, err
+	}
+
+	all, err := s.versionRepo.FindAll()
+	if err != nil {
+		return nil, fmt.Errorf("获取已安装版本失败: %v", err)
+	}
+	installed := make(map[string]*model.GoVersion, len(all))
+	for _, version := range all {
+		installed[version.Version] = version
+	}
+
+	active, _ := s.versionRepo.FindActive()
+
+	plan := &model.SyncPlan{}
+	declared := make(map[string]bool)
+	var reinstallActions, tagActions []model.SyncAction
+	for _, toolchain := range manifest.Toolchains {
+		name := toolchain.ToolchainName()
+		declared[name] = true
+
+		existing, found := installed[name]
+		if !found {
+			plan.Actions = append(plan.Actions, model.SyncAction{
+				Type:    model.SyncInstall,
+				Version: name,
+				Release: toolchain.Version,
+				Tags:    toolchain.Tags,
+			})
+			continue
+		}
+
+		// 名称相同但发布版本不一致（如清单已升级自定义名称对应的版本），需要重新安装
+		if installedRelease := existing.ReleaseVersion(); installedRelease != toolchain.Version {
+			if active != nil && active.Version == name {
+				return nil, fmt.Errorf("当前使用的 Go %s 是 go%s，与清单要求的 go%s 不一致，请先切换到其他版本后再同步", name, installedRelease, toolchain.Version)
+			}
+			tags := append([]string(nil), existing.Tags...)
+			for _, tag := range toolchain.Tags {
+				if !existing.HasTag(tag) {
+					tags = append(tags, tag)
+				}
+			}
+			reinstallActions = append(reinstallActions, model.SyncAction{
+				Type:      model.SyncReinstall,
+				Version:   name,
+				Release:   toolchain.Version,
+				Installed: installedRelease,
+				Tags:      tags,
+			})
+			continue
+		}
+
+		var missing []string
+		for _, tag := range toolchain.Tags {
+			if !existing.HasTag(tag) {
+				missing = append(missing, tag)
+			}
+		}
+		if len(missing) > 0 {
+			tagActions = append(tagActions, model.SyncAction{Type: model.SyncTag, Version: name, Tags: missing})
+			continue
+		}
+		plan.Unchanged = append(plan.Unchanged, name)
+	}
+	plan.Actions = append(plan.Actions, reinstallActions...)
+	plan.Actions = append(plan.Actions, tagActions...)
+
+	if manifest.Default != "" && (active == nil || active.Version != manifest.Default) {
+		if _, found := installed[manifest.Default]; !found && !declared[manifest.Default] {
+			return nil, fmt.Errorf("默认版本 %s 未安装，也不在清单的 toolchains 中", manifest.Default)
+		}
+		plan.Actions = append(plan.Actions, model.SyncAction{Type: model.SyncUse, Version: manifest.Default})
+		active = nil
+	}
+
+	for _, version := range manifest.Remove {
+		if _, found := installed[version]; !found {
+			continue
+		}
+		if active != nil && active.Version == version {
+			return nil, fmt.Errorf("不能移除当前使用的Go版本 %s，请在清单中指定其他默认版本", version)
+		}
+		plan.Actions = append(plan.Actions, model.SyncAction{Type: model.SyncRemove, Version: version})
+	}
+
+	return plan, nil
+}
+
+// ApplySync 按顺序执行同步计划，单个操作失败不会中断其余操作，返回结果与计划中的操作顺序一致
+// ctx 取消后不再执行剩余的操作
+func (s *VersionService) ApplySync(ctx context.Context, plan *model.SyncPlan, options *model.InstallOptions, progressUI SyncProgressReporter) []*model.SyncResult {
+	results := make([]*model.SyncResult, 0, len(plan.Actions))
+	for i := range plan.Actions {
+		action := plan.Actions[i]
+		if ctx.Err() != nil {
+			break
+		}
+		result := &model.SyncResult{Action: action}
+
+		var err error
+		switch action.Type {
+		case model.SyncInstall, model.SyncReinstall:
+			result.Result, err = s.syncInstall(ctx, &action, options, progressUI)
+		case model.SyncTag:
+			err = s.AddTags(action.Version, action.Tags)
+		case model.SyncUse:
+			err = s.Use(action.Version)
+		case model.SyncRemove:
+			err = s.Remove(action.Version)
+		default:
+			err = fmt.Errorf("未知的同步操作: %s", action.Type)
+		}
+		if err != nil {
+			result.Error = err.Error()
+		}
+
+		results = append(results, result)
+		if progressUI != nil {
+			progressUI.Complete(result)
+		}
+	}
+	return results
+}
+
+// syncInstall 安装清单中缺少的工具链并添加标签，重新安装时先删除发布版本不一致的记录
+func (s *VersionService) syncInstall(ctx context.Context, action *model.SyncAction, options *model.InstallOptions, progressUI SyncProgressReporter) (*model.InstallationResult, error) {
+	installOptions := model.InstallOptions{}
+	if options != nil {
+		installOptions = *options
+	}
+	installOptions.Name = ""
+	installOptions.Force = action.Type == model.SyncReinstall
+	if action.Version != action.Release {
+		installOptions.Name = action.Version
+	}
+
+	var reporter ProgressReporter
+	if progressUI != nil {
+		reporter = progressUI.ReporterFor(action)
+	}
+	result, err := s.InstallOnlineWithContext(ctx, action.Release, &installOptions, reporter)
+	if err != nil {
+		return result, err
+	}
+	if len(action.Tags) > 0 {
+		if err := s.AddTags(action.Version, action.Tags); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// AddTags 为已安装的版本添加标签
+func (s *VersionService) AddTags(version string, tags []string) error {
+	goVersion, err := s.versionRepo.FindByVersion(version)
+	if err != nil {
+		return fmt.Errorf("Go版本 %s 未安装", version)
+	}
+	for _, tag := range tags {
+		goVersion.AddTag(tag)
+	}
+	if err := s.versionRepo.Update(goVersion); err != nil {
+		return fmt.Errorf("保存标签失败: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,233 @@
+package service
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"version-list/internal/domain/model"
+)
+
+// recordingSyncReporter 记录同步进度的报告器
+type recordingSyncReporter struct {
+	installs  []string
+	completed []*model.SyncResult
+}
+
+func (r *recordingSyncReporter) ReporterFor(action *model.SyncAction) ProgressReporter {
+	r.installs = append(r.installs, action.Version)
+	return &MockProgressReporter{}
+}
+
+func (r *recordingSyncReporter) Complete(result *model.SyncResult) {
+	r.completed = append(r.completed, result)
+}
+
+func TestLoadSyncManifest(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "toolchains.yaml", `default: 1.22.7
+toolchains:
+  - version: 1.22.7
+    tags: [team, ci]
+  - version: 1.22.7
+    name: 1.22.7-fips
+remove:
+  - 1.20.14
+`)
+	manifest, err := LoadSyncManifest(filepath.Join(dir, "toolchains.yaml"))
+	if err != nil {
+		t.Fatalf("读取YAML清单失败: %v", err)
+	}
+	if manifest.Default != "1.22.7" || len(manifest.Toolchains) != 2 || manifest.Toolchains[1].ToolchainName() != "1.22.7-fips" {
+		t.Errorf("清单 = %+v", manifest)
+	}
+	if strings.Join(manifest.Toolchains[0].Tags, ",") != "team,ci" || strings.Join(manifest.Remove, ",") != "1.20.14" {
+		t.Errorf("标签或移除列表不正确: %+v", manifest)
+	}
+
+	writeTestFile(t, dir, "toolchains.json", `{"default": "1.21.13", "toolchains": [{"version": "1.21.13"}]}`)
+	manifest, err = LoadSyncManifest(filepath.Join(dir, "toolchains.json"))
+	if err != nil {
+		t.Fatalf("读取JSON清单失败: %v", err)
+	}
+	if manifest.Default != "1.21.13" || manifest.Toolchains[0].Version != "1.21.13" {
+		t.Errorf("清单 = %+v", manifest)
+	}
+
+	invalid := map[string]string{
+		"无效的版本号":   "toolchains:\n  - version: latest\n",
+		"重复声明":     "toolchains:\n  - version: 1.22.7\n  - version: 1.22.7\n",
+		"同时安装和移除":  "toolchains:\n  - version: 1.22.7\nremove: [1.22.7]\n",
+		"默认版本被移除":  "default: 1.21.0\nremove: [1.21.0]\n",
+		"无效的工具链名称": "toolchains:\n  - version: 1.22.7\n    name: a/b\n",
+		"YAML格式错误": "toolchains: [",
+	}
+	for name, content := range invalid {
+		writeTestFile(t, dir, "invalid.yaml", content)
+		if _, err := LoadSyncManifest(filepath.Join(dir, "invalid.yaml")); err == nil {
+			t.Errorf("%s: 期望返回错误", name)
+		}
+	}
+}
+
+func TestVersionService_PlanSync(t *testing.T) {
+	service, versionRepo := newTestVersionService(t, "http://127.0.0.1:0")
+	versionRepo.Save(&model.GoVersion{Version: "1.21.13", Path: t.TempDir(), Tags: []string{"team"}})
+	versionRepo.Save(&model.GoVersion{Version: "1.22.7", Path: t.TempDir()})
+	versionRepo.Save(&model.GoVersion{Version: "1.20.14", Path: t.TempDir()})
+	versionRepo.SetActive("1.20.14")
+
+	manifest := &model.SyncManifest{
+		Default: "1.23.2",
+		Toolchains: []model.SyncToolchain{
+			{Version: "1.21.13", Tags: []string{"team"}},
+			{Version: "1.22.7", Tags: []string{"team"}},
+			{Version: "1.23.2", Tags: []string{"ci"}},
+		},
+		Remove: []string{"1.20.14", "1.19.0"},
+	}
+	plan, err := service.PlanSync(manifest)
+	if err != nil {
+		t.Fatalf("生成执行计划失败: %v", err)
+	}
+
+	var actions []string
+	for _, action := range plan.Actions {
+		actions = append(actions, string(action.Type)+":"+action.Version)
+	}
+	// 安装、添加标签、切换默认版本在移除之前，未安装的版本不需要移除
+	expected := "install:1.23.2,tag:1.22.7,use:1.23.2,remove:1.20.14"
+	if got := strings.Join(actions, ","); got != expected {
+		t.Errorf("执行计划 = %s, 期望 %s", got, expected)
+	}
+	if strings.Join(plan.Unchanged, ",") != "1.21.13" {
+		t.Errorf("已一致的工具链 = %v", plan.Unchanged)
+	}
+
+	// 不切换默认版本时不能移除当前使用的版本
+	manifest.Default = ""
+	if _, err := service.PlanSync(manifest); err == nil || !strings.Contains(err.Error(), "当前使用") {
+		t.Errorf("期望不能移除当前使用版本的错误, 实际 %v", err)
+	}
+
+	// 默认版本必须已安装或在清单中
+	if _, err := service.PlanSync(&model.SyncManifest{Default: "1.18.0"}); err == nil {
+		t.Error("默认版本未安装且不在清单中时应返回错误")
+	}
+}
+
+func TestVersionService_ApplySync(t *testing.T) {
+	server := newFakeReleaseServer(t, []string{"1.22.7", "1.21.13"})
+	defer server.Close()
+	service, versionRepo := newTestVersionService(t, server.URL)
+	versionRepo.Save(&model.GoVersion{Version: "1.20.14", Path: t.TempDir()})
+
+	manifest := &model.SyncManifest{
+		Default: "1.22.7",
+		Toolchains: []model.SyncToolchain{
+			{Version: "1.22.7", Tags: []string{"team"}},
+			{Version: "1.21.13", Name: "legacy"},
+		},
+		Remove: []string{"1.20.14"},
+	}
+	plan, err := service.PlanSync(manifest)
+	if err != nil {
+		t.Fatalf("生成执行计划失败: %v", err)
+	}
+
+	reporter := &recordingSyncReporter{}
+	results := service.ApplySync(context.Background(), plan, &model.InstallOptions{SkipVerification: true}, reporter)
+	for _, result := range results {
+		if !result.Success() {
+			t.Errorf("%s 失败: %s", result.Action.String(), result.Error)
+		}
+	}
+	if len(reporter.completed) != len(plan.Actions) || strings.Join(reporter.installs, ",") != "1.22.7,legacy" {
+		t.Errorf("进度报告不正确: installs=%v, completed=%d", reporter.installs, len(reporter.completed))
+	}
+
+	installed, err := versionRepo.FindByVersion("1.22.7")
+	if err != nil || !installed.HasTag("team") || !installed.IsActive {
+		t.Errorf("1.22.7 应已安装、带有 team 标签并设为默认版本: %+v, %v", installed, err)
+	}
+	if named, err := versionRepo.FindByVersion("legacy"); err != nil || named.Release != "1.21.13" {
+		t.Errorf("legacy 应以自定义名称安装: %+v, %v", named, err)
+	}
+	if _, err := versionRepo.FindByVersion("1.20.14"); err == nil {
+		t.Error("1.20.14 应已移除")
+	}
+	if _, err := os.Lstat(filepath.Join(os.Getenv("HOME"), ".go-version", "current")); err != nil {
+		t.Errorf("切换默认版本后应创建符号链接: %v", err)
+	}
+
+	// 再次同步时无需操作
+	plan, err = service.PlanSync(manifest)
+	if err != nil {
+		t.Fatalf("生成执行计划失败: %v", err)
+	}
+	if !plan.Empty() {
+		t.Errorf("同步后执行计划应为空: %+v", plan.Actions)
+	}
+}
+
+func TestVersionService_ApplySync_ContinuesAfterFailure(t *testing.T) {
+	server := newFakeReleaseServer(t, []string{"1.22.7"})
+	defer server.Close()
+	service, versionRepo := newTestVersionService(t, server.URL)
+
+	plan := &model.SyncPlan{Actions: []model.SyncAction{
+		{Type: model.SyncInstall, Version: "1.99.0", Release: "1.99.0"},
+		{Type: model.SyncInstall, Version: "1.22.7", Release: "1.22.7", Tags: []string{"team"}},
+	}}
+	results := service.ApplySync(context.Background(), plan, &model.InstallOptions{SkipVerification: true, NoFailover: true}, nil)
+	if results[0].Success() || !results[1].Success() {
+		t.Errorf("期望第一个操作失败、第二个成功: %+v, %+v", results[0], results[1])
+	}
+	if installed, err := versionRepo.FindByVersion("1.22.7"); err != nil || !installed.HasTag("team") {
+		t.Errorf("失败的操作不应影响后续操作: %v", err)
+	}
+}
+
+func TestVersionService_Sync_ReleaseDrift(t *testing.T) {
+	server := newFakeReleaseServer(t, []string{"1.22.7"})
+	defer server.Close()
+	service, versionRepo := newTestVersionService(t, server.URL)
+	versionRepo.Save(&model.GoVersion{Version: "legacy", Release: "1.21.13", Path: t.TempDir(), Tags: []string{"old"}})
+
+	// 名称相同但发布版本不同，不能视为已一致
+	manifest := &model.SyncManifest{Toolchains: []model.SyncToolchain{{Version: "1.22.7", Name: "legacy", Tags: []string{"team"}}}}
+	plan, err := service.PlanSync(manifest)
+	if err != nil {
+		t.Fatalf("生成执行计划失败: %v", err)
+	}
+	if len(plan.Actions) != 1 || len(plan.Unchanged) != 0 {
+		t.Fatalf("执行计划 = %+v, 期望重新安装 legacy", plan)
+	}
+	action := plan.Actions[0]
+	if action.Type != model.SyncReinstall || action.Installed != "1.21.13" || action.Release != "1.22.7" {
+		t.Errorf("操作 = %+v, 期望从 go1.21.13 重新安装为 go1.22.7", action)
+	}
+	if !strings.Contains(action.String(), "go1.21.13") || strings.Join(action.Tags, ",") != "old,team" {
+		t.Errorf("操作说明 = %s, 标签 = %v", action.String(), action.Tags)
+	}
+
+	results := service.ApplySync(context.Background(), plan, &model.InstallOptions{SkipVerification: true}, nil)
+	if !results[0].Success() {
+		t.Fatalf("重新安装失败: %s", results[0].Error)
+	}
+	reinstalled, err := versionRepo.FindByVersion("legacy")
+	if err != nil || reinstalled.ReleaseVersion() != "1.22.7" || !reinstalled.HasTag("old") || !reinstalled.HasTag("team") {
+		t.Errorf("legacy 应重新安装为 go1.22.7 并保留标签: %+v, %v", reinstalled, err)
+	}
+	if plan, err := service.PlanSync(manifest); err != nil || !plan.Empty() {
+		t.Errorf("重新安装后执行计划应为空: %+v, %v", plan, err)
+	}
+
+	// 当前使用的版本发生偏差时不能直接重新安装
+	versionRepo.SetActive("legacy")
+	manifest.Toolchains[0].Version = "1.23.2"
+	if _, err := service.PlanSync(manifest); err == nil || !strings.Contains(err.Error(), "当前使用") {
+		t.Errorf("期望当前使用版本与清单不一致的错误, 实际 %v", err)
+	}
+}
@@ -286,9 +286,14 @@ func (r *VersionRepositoryImpl) GetStatistics() (*model.VersionStatistics, error
 
 	for _, v := range versions {
 		// 统计安装来源
-		if v.Source == model.SourceOnline {
+		switch v.Source {
+		case model.SourceOnline:
 			stats.OnlineVersions++
-		} else {
+		case model.SourceArchive:
+			stats.ArchiveVersions++
+		case model.SourceBuild:
+			stats.BuildVersions++
+		default:
 			stats.LocalVersions++
 		}
 
@@ -0,0 +1,158 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"version-list/internal/application"
+	"version-list/internal/domain/model"
+	"version-list/internal/domain/service"
+
+	"github.com/spf13/cobra"
+)
+
+// 离线安装包命令选项变量
+var (
+	bundleVersions  []string
+	bundlePlatforms []string
+	bundleOutput    string
+	bundleMirror    string
+	bundleCatalog   string
+	bundleVersion   string
+	bundleForce     bool
+)
+
+var bundleCmd = &cobra.Command{
+	Use:   "bundle",
+	Short: "离线安装包管理",
+	Long: `创建和安装离线安装包，用于无法访问网络的机器。
+
+离线安装包是一个tar文件，包含多个版本和平台的Go压缩包，以及记录
+每个压缩包SHA-256和下载信息的清单。创建时优先使用下载缓存中的压缩包，
+安装时会先校验清单中的所有压缩包，再安装适用于当前平台的版本。
+
+示例：
+  go-version bundle create --versions 1.21.13,1.22.7 --platforms linux/amd64,linux/arm64 -o bundle.tar
+  go-version bundle install bundle.tar                  # 安装当前平台的所有版本
+  go-version bundle install bundle.tar --version 1.22.7 # 只安装指定版本`,
+}
+
+var bundleCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "创建离线安装包",
+	Long: `打包指定版本和平台的Go压缩包。
+
+选项：
+  --versions      要打包的Go版本，多个版本用逗号分隔
+  --platforms     目标平台（os/arch），多个平台用逗号分隔，默认为当前平台
+  -o, --output    离线安装包输出路径
+  --mirror        缓存未命中时使用的镜像源
+  --catalog       用于校验压缩包的官方发布目录地址
+
+每个压缩包都要与官方发布目录（无法访问时为镜像源的校验和文件）中的SHA-256一致，否则拒绝打包。`,
+	Args: cobra.NoArgs,
+	Run:  runBundleCreateCommand,
+}
+
+var bundleInstallCmd = &cobra.Command{
+	Use:   "install <bundle.tar>",
+	Short: "从离线安装包安装Go",
+	Args:  cobra.ExactArgs(1),
+	Run:   runBundleInstallCommand,
+}
+
+func init() {
+	// 添加子命令
+	bundleCmd.AddCommand(bundleCreateCmd)
+	bundleCmd.AddCommand(bundleInstallCmd)
+
+	// bundle create 命令选项
+	bundleCreateCmd.Flags().StringSliceVar(&bundleVersions, "versions", nil, "要打包的Go版本（逗号分隔）")
+	bundleCreateCmd.Flags().StringSliceVar(&bundlePlatforms, "platforms", nil, "目标平台，如 linux/amd64,darwin/arm64")
+	bundleCreateCmd.Flags().StringVarP(&bundleOutput, "output", "o", "go-bundle.tar", "离线安装包输出路径")
+	bundleCreateCmd.Flags().StringVar(&bundleMirror, "mirror", "", "缓存未命中时使用的镜像源")
+	bundleCreateCmd.Flags().StringVar(&bundleCatalog, "catalog", service.DefaultReleaseCatalogURL, "用于校验压缩包的官方发布目录地址")
+	bundleCreateCmd.MarkFlagRequired("versions")
+
+	// bundle install 命令选项
+	bundleInstallCmd.Flags().StringVar(&bundleVersion, "version", "", "只安装指定版本")
+	bundleInstallCmd.Flags().BoolVar(&bundleForce, "force", false, "强制重新安装（即使版本已存在）")
+}
+
+func runBundleCreateCommand(cmd *cobra.Command, args []string) {
+	for _, platform := range bundlePlatforms {
+		if _, _, err := service.ParsePlatform(platform); err != nil {
+			PrintError(err.Error())
+			os.Exit(1)
+		}
+	}
+
+	appService, err := application.NewVersionAppService()
+	if err != nil {
+		PrintError(fmt.Sprintf("初始化应用服务失败: %s", err))
+		os.Exit(1)
+	}
+
+	options := &service.BundleCreateOptions{
+		Versions:   uniqueVersions(bundleVersions),
+		Platforms:  bundlePlatforms,
+		Mirror:     bundleMirror,
+		CatalogURL: bundleCatalog,
+	}
+
+	PrintInfo(fmt.Sprintf("正在创建离线安装包: %s", bundleOutput))
+	ctx, stop := newInterruptContext()
+	defer stop()
+	manifest, err := appService.CreateBundle(ctx, bundleOutput, options, func(entry *model.BundleEntry, message string) {
+		PrintInfo(fmt.Sprintf("  [%s %s] %s", entry.Version, entry.Platform(), message))
+	})
+	if err != nil {
+		PrintError(fmt.Sprintf("创建离线安装包失败: %s", err))
+		exitIfInterrupted(ctx, err)
+		os.Exit(1)
+	}
+
+	var total int64
+	for _, entry := range manifest.Entries {
+		total += entry.Size
+	}
+
+	PrintSuccess(fmt.Sprintf("离线安装包已创建: %s", bundleOutput))
+	PrintInfo(fmt.Sprintf("包含 %d 个压缩包，共 %s", len(manifest.Entries), formatBytes(total)))
+	for _, entry := range manifest.Entries {
+		PrintInfo(fmt.Sprintf("  %s  %s", entry.SHA256[:12], entry.Filename))
+	}
+}
+
+func runBundleInstallCommand(cmd *cobra.Command, args []string) {
+	bundlePath := args[0]
+
+	appService, err := application.NewVersionAppService()
+	if err != nil {
+		PrintError(fmt.Sprintf("初始化应用服务失败: %s", err))
+		os.Exit(1)
+	}
+
+	options := &model.InstallOptions{
+		Force: bundleForce,
+	}
+
+	PrintInfo(fmt.Sprintf("正在校验离线安装包: %s", bundlePath))
+	ctx, stop := newInterruptContext()
+	defer stop()
+	results, err := appService.InstallBundle(ctx, bundlePath, strings.TrimPrefix(bundleVersion, "go"), options, func(entry *model.BundleEntry, message string) {
+		PrintInfo(fmt.Sprintf("  [%s] %s", entry.Version, message))
+	})
+	if err != nil {
+		PrintError(fmt.Sprintf("安装失败: %s", err))
+		exitIfInterrupted(ctx, err)
+		os.Exit(1)
+	}
+
+	if failed := displayBatchInstallResults(results); failed > 0 {
+		PrintError(fmt.Sprintf("%d/%d 个版本安装失败", failed, len(results)))
+		os.Exit(1)
+	}
+	PrintSuccess(fmt.Sprintf("全部 %d 个版本安装成功", len(results)))
+}
@@ -0,0 +1,210 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"version-list/internal/domain/service"
+
+	"github.com/spf13/cobra"
+)
+
+// 缓存命令选项变量
+var (
+	cacheShowDetails bool
+	cacheOlderThan   string
+)
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "下载缓存管理",
+	Long: `管理已下载的Go安装包缓存。
+
+缓存以文件内容的SHA-256为键，相同的安装包无论来自哪个镜像都只保存一份。
+读取缓存时会校验文件内容，超出容量上限时自动淘汰最久未使用的条目。
+
+示例：
+  go-version cache list                     # 列出缓存的安装包
+  go-version cache size                     # 查看缓存占用空间
+  go-version cache verify                   # 校验所有缓存文件
+  go-version cache clean                    # 清空缓存
+  go-version cache clean --older-than 30d   # 清理30天未使用的缓存`,
+}
+
+var cacheListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "列出缓存的安装包",
+	Args:  cobra.NoArgs,
+	Run:   runCacheListCommand,
+}
+
+var cacheSizeCmd = &cobra.Command{
+	Use:   "size",
+	Short: "查看缓存占用空间",
+	Args:  cobra.NoArgs,
+	Run:   runCacheSizeCommand,
+}
+
+var cacheVerifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "校验所有缓存文件",
+	Long:  `重新计算所有缓存文件的SHA-256，损坏的缓存条目会被移除。`,
+	Args:  cobra.NoArgs,
+	Run:   runCacheVerifyCommand,
+}
+
+var cacheCleanCmd = &cobra.Command{
+	Use:   "clean",
+	Short: "清理缓存",
+	Long: `清理下载缓存。
+
+选项：
+  --older-than    只清理超过指定时长未使用的缓存，支持 30d、12h、90m 等格式`,
+	Args: cobra.NoArgs,
+	Run:  runCacheCleanCommand,
+}
+
+func init() {
+	// 添加子命令
+	cacheCmd.AddCommand(cacheListCmd)
+	cacheCmd.AddCommand(cacheSizeCmd)
+	cacheCmd.AddCommand(cacheVerifyCmd)
+	cacheCmd.AddCommand(cacheCleanCmd)
+
+	// cache list 命令选项
+	cacheListCmd.Flags().BoolVar(&cacheShowDetails, "details", false, "显示完整哈希及来源URL")
+
+	// cache clean 命令选项
+	cacheCleanCmd.Flags().StringVar(&cacheOlderThan, "older-than", "", "只清理超过指定时长未使用的缓存（如 30d、12h）")
+}
+
+// newDownloadCache 创建与下载服务共用的缓存实例
+func newDownloadCache() *service.DownloadCache {
+	return service.NewDownloadCache(service.DefaultCacheDir(), service.DefaultMaxCacheSize)
+}
+
+func runCacheListCommand(cmd *cobra.Command, args []string) {
+	entries, err := newDownloadCache().List()
+	if err != nil {
+		PrintError(fmt.Sprintf("读取缓存失败: %s", err))
+		os.Exit(1)
+	}
+
+	if len(entries) == 0 {
+		PrintInfo("缓存为空")
+		return
+	}
+
+	if cacheShowDetails {
+		for i, entry := range entries {
+			PrintInfo(fmt.Sprintf("%d. %s", i+1, entry.Filename))
+			PrintInfo(fmt.Sprintf("   SHA-256: %s", entry.SHA256))
+			PrintInfo(fmt.Sprintf("   大小: %s", formatBytes(entry.Size)))
+			PrintInfo(fmt.Sprintf("   缓存时间: %s", entry.CreatedAt.Format("2006-01-02 15:04")))
+			PrintInfo(fmt.Sprintf("   最近使用: %s", entry.LastAccessed.Format("2006-01-02 15:04")))
+			for _, url := range entry.URLs {
+				PrintInfo(fmt.Sprintf("   来源: %s", url))
+			}
+			PrintInfo("")
+		}
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, Colorize("SHA-256	文件名	大小	最近使用", ColorBold))
+	for _, entry := range entries {
+		fmt.Fprintf(w, "%s	%s	%s	%s\n", entry.SHA256[:12], entry.Filename, formatBytes(entry.Size), entry.LastAccessed.Format("2006-01-02 15:04"))
+	}
+	w.Flush()
+}
+
+func runCacheSizeCommand(cmd *cobra.Command, args []string) {
+	cache := newDownloadCache()
+	total, count, err := cache.Size()
+	if err != nil {
+		PrintError(fmt.Sprintf("读取缓存失败: %s", err))
+		os.Exit(1)
+	}
+
+	PrintInfo(fmt.Sprintf("缓存目录: %s", cache.Dir()))
+	PrintInfo(fmt.Sprintf("缓存条目: %d", count))
+	if cache.MaxSize() > 0 {
+		PrintInfo(fmt.Sprintf("占用空间: %s / %s", formatBytes(total), formatBytes(cache.MaxSize())))
+	} else {
+		PrintInfo(fmt.Sprintf("占用空间: %s", formatBytes(total)))
+	}
+}
+
+func runCacheVerifyCommand(cmd *cobra.Command, args []string) {
+	results, err := newDownloadCache().Verify()
+	if err != nil {
+		PrintError(fmt.Sprintf("校验缓存失败: %s", err))
+		os.Exit(1)
+	}
+
+	if len(results) == 0 {
+		PrintInfo("缓存为空")
+		return
+	}
+
+	corrupted := 0
+	for _, result := range results {
+		if result.Valid {
+			PrintSuccess(fmt.Sprintf("  ✅ %s (%s)", result.Entry.Filename, result.Entry.SHA256[:12]))
+		} else {
+			corrupted++
+			PrintError(fmt.Sprintf("  ❌ %s (%s): %s", result.Entry.Filename, result.Entry.SHA256[:12], result.Error))
+		}
+	}
+
+	PrintInfo("")
+	if corrupted > 0 {
+		PrintWarning(fmt.Sprintf("发现 %d 个损坏的缓存条目，已从缓存中移除", corrupted))
+		os.Exit(1)
+	}
+	PrintSuccess(fmt.Sprintf("全部 %d 个缓存条目校验通过", len(results)))
+}
+
+func runCacheCleanCommand(cmd *cobra.Command, args []string) {
+	var olderThan time.Duration
+	if cacheOlderThan != "" {
+		duration, err := parseAge(cacheOlderThan)
+		if err != nil {
+			PrintError(fmt.Sprintf("无效的时长 '%s': %s", cacheOlderThan, err))
+			os.Exit(1)
+		}
+		olderThan = duration
+	}
+
+	removed, freed, err := newDownloadCache().Clean(olderThan)
+	if err != nil {
+		PrintError(fmt.Sprintf("清理缓存失败: %s", err))
+		os.Exit(1)
+	}
+
+	PrintSuccess(fmt.Sprintf("已清理 %d 个缓存条目，释放 %s", removed, formatBytes(freed)))
+}
+
+// parseAge 解析时长，在 time.ParseDuration 的基础上支持以天为单位（如 30d）
+func parseAge(value string) (time.Duration, error) {
+	if strings.HasSuffix(value, "d") {
+		days, err := strconv.Atoi(strings.TrimSuffix(value, "d"))
+		if err != nil || days <= 0 {
+			return 0, fmt.Errorf("天数必须为正整数")
+		}
+		return time.Duration(days) * 24 * time.Hour, nil
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, err
+	}
+	if duration <= 0 {
+		return 0, fmt.Errorf("时长必须大于0")
+	}
+	return duration, nil
+}
@@ -3,6 +3,8 @@ package cli
 import (
 	"fmt"
 	"os"
+	"strings"
+	"unicode"
 )
 
 type Color string
@@ -42,3 +44,83 @@ func PrintWarning(text string) {
 func PrintHeader(text string) {
 	fmt.Println(Colorize(text, ColorBold))
 }
+
+// printTable 输出按列对齐的表格：先按纯文本的显示宽度计算列宽并补齐空格，再给单元格着色，
+// 避免颜色控制字符被计入列宽，中文等宽字符按两列计算。表头以粗体显示，color 返回各单元格的颜色（为空时不着色）
+func printTable(header []string, rows [][]string, color func(row, col int) Color) {
+	widths := make([]int, len(header))
+	for _, row := range append([][]string{header}, rows...) {
+		for col, cell := range row {
+			if width := displayWidth(cell); col < len(widths) && width > widths[col] {
+				widths[col] = width
+			}
+		}
+	}
+
+	format := func(row []string, cellColor func(col int) Color) string {
+		cells := make([]string, len(row))
+		for col, cell := range row {
+			if col < len(row)-1 {
+				cell += strings.Repeat(" ", widths[col]-displayWidth(cell))
+			}
+			if c := cellColor(col); c != "" {
+				cell = Colorize(cell, c)
+			}
+			cells[col] = cell
+		}
+		return strings.Join(cells, "  ")
+	}
+
+	fmt.Println(format(header, func(int) Color { return ColorBold }))
+	for i, row := range rows {
+		fmt.Println(format(row, func(col int) Color {
+			if color == nil {
+				return ""
+			}
+			return color(i, col)
+		}))
+	}
+}
+
+// wideRanges 终端中占两列的东亚宽字符和全角字符（East Asian Width 为 W 或 F）以及常用表情符号
+var wideRanges = [][2]rune{
+	{0x1100, 0x115F},   // 谚文字母
+	{0x2E80, 0x303E},   // CJK部首、标点符号
+	{0x3041, 0x33FF},   // 假名、注音、CJK兼容字符
+	{0x3400, 0x4DBF},   // CJK扩展A
+	{0x4E00, 0x9FFF},   // CJK统一汉字
+	{0xA000, 0xA4CF},   // 彝文
+	{0xAC00, 0xD7A3},   // 谚文音节
+	{0xF900, 0xFAFF},   // CJK兼容汉字
+	{0xFE30, 0xFE4F},   // CJK兼容形式
+	{0xFF00, 0xFF60},   // 全角字符
+	{0xFFE0, 0xFFE6},   // 全角符号
+	{0x1F300, 0x1F64F}, // 符号和表情
+	{0x1F900, 0x1F9FF}, // 补充符号和表情
+	{0x20000, 0x3FFFD}, // CJK扩展B及以后
+}
+
+// displayWidth 获取文本在终端中的显示宽度：宽字符占两列，组合字符和零宽字符不占列
+func displayWidth(text string) int {
+	width := 0
+	for _, r := range text {
+		switch {
+		case unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf):
+		case isWideRune(r):
+			width += 2
+		default:
+			width++
+		}
+	}
+	return width
+}
+
+// isWideRune 检查字符是否占两列
+func isWideRune(r rune) bool {
+	for _, wide := range wideRanges {
+		if r >= wide[0] && r <= wide[1] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,318 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"version-list/internal/application"
+	"version-list/internal/domain/model"
+	"version-list/internal/domain/service"
+
+	"github.com/spf13/cobra"
+)
+
+// 环境变量命令选项变量
+var (
+	envVersion string
+	envProject bool
+	envShell   string
+	hookShell  string
+)
+
+var envCmd = &cobra.Command{
+	Use:   "env",
+	Short: "输出生效的环境变量，管理自定义环境变量",
+	Long: `输出当前版本（或 --version 指定的版本）生效的环境变量，供脚本使用：
+
+  eval "$(go-version env)"                              # bash/zsh
+  go-version env --shell fish | source                  # fish
+  go-version env --shell powershell | Invoke-Expression # PowerShell
+  go-version env --shell json                           # JSON
+
+输出 GOROOT、GOPATH、GOBIN、GOCACHE、GOMODCACHE（按隔离方式计算）、自定义环境变量，
+以及把 GOROOT/bin 和 GOBIN 加在前面的 PATH。不修改任何 shell 配置文件。
+输出同时记录设置的变量（GO_VERSION_SHELL_VARS）和加在 PATH 前面的目录（GO_VERSION_SHELL_PATH），
+再次执行时删除不再生效的变量和目录。
+
+使用 'go-version env hook' 在每次显示提示符前自动执行，切换版本、隔离方式或项目目录后立即生效；
+使用 'go-version exec' 在不修改当前 shell 的情况下以指定版本的环境变量运行命令；
+使用 'go-version shim install' 生成 go 命令的垫片，直接运行 go 时也应用这些环境变量。
+
+子命令 set/unset/list 管理按全局、版本和项目设置的自定义环境变量（如 GOFLAGS、GOEXPERIMENT、CGO_ENABLED、GOTOOLCHAIN）。
+
+作用范围和优先级（从低到高）：
+  全局      默认范围，保存在 ~/.go-version/environment.json
+  版本      使用 --version 指定，只对该版本生效
+  项目      使用 --project，保存在当前目录所在项目的 go-version.env.yaml 中（逐级向上查找，没有时在当前目录创建）
+
+同一个变量在多个范围中设置时，优先级高的范围生效。GOROOT 和 PATH 由 go-version 管理，不能自定义；
+PROMPT_COMMAND、BASH_ENV、LD_PRELOAD 等控制 shell 和动态链接器的变量也不能自定义。
+
+项目文件随代码仓库分发，必须先检查内容并运行 'go-version env allow' 才会生效，文件修改后需要重新允许；
+使用 --project 设置的内容自动允许。
+env 输出、'go-version exec'、shell 钩子（'go-version env hook'）、go 垫片、install/use 钩子和默认工具的 go install
+使用合并后的环境变量，项目范围按当前目录查找。
+
+示例：
+  go-version env set GOFLAGS=-mod=mod                      # 所有版本
+  go-version env set CGO_ENABLED=0 --version 1.21.13       # 只对 1.21.13 生效
+  go-version env set GOEXPERIMENT=rangefunc --project      # 只在当前项目中生效
+  go-version env unset GOFLAGS
+  go-version env list --version 1.21.13                    # 查看合并后的结果和来源`,
+	Args: cobra.NoArgs,
+	Run:  runEnvCommand,
+}
+
+var envHookCmd = &cobra.Command{
+	Use:   "hook",
+	Short: "输出在每次显示提示符前应用环境变量的 shell 钩子",
+	Long: `输出 shell 钩子脚本，在每次显示提示符前执行 'go-version env'，
+使当前版本、隔离方式（GOBIN、GOCACHE、GOMODCACHE）和当前目录所在项目的自定义环境变量在 shell 中生效，
+离开项目目录或切换版本后删除不再生效的变量。
+
+添加到 shell 配置文件中：
+  eval "$(go-version env hook)"                   # ~/.bashrc
+  eval "$(go-version env hook --shell zsh)"       # ~/.zshrc
+  go-version env hook --shell fish | source       # ~/.config/fish/config.fish`,
+	Args: cobra.NoArgs,
+	Run:  runEnvHookCommand,
+}
+
+var envAllowCmd = &cobra.Command{
+	Use:   "allow",
+	Short: "允许使用当前项目的 go-version.env.yaml",
+	Long: `检查当前目录所在项目的 go-version.env.yaml 后，允许在 env、exec、shell 钩子、install/use 钩子中使用。
+记录文件路径和内容的SHA-256（~/.go-version/env-allowed.json），文件修改后需要重新允许。`,
+	Args: cobra.NoArgs,
+	Run:  runEnvAllowCommand,
+}
+
+var envDenyCmd = &cobra.Command{
+	Use:   "deny",
+	Short: "撤销对当前项目 go-version.env.yaml 的允许",
+	Args:  cobra.NoArgs,
+	Run:   runEnvDenyCommand,
+}
+
+var envSetCmd = &cobra.Command{
+	Use:   "set <NAME=value>...",
+	Short: "设置自定义环境变量",
+	Args:  cobra.MinimumNArgs(1),
+	Run:   runEnvSetCommand,
+}
+
+var envUnsetCmd = &cobra.Command{
+	Use:   "unset <NAME>...",
+	Short: "删除自定义环境变量",
+	Args:  cobra.MinimumNArgs(1),
+	Run:   runEnvUnsetCommand,
+}
+
+var envListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "查看合并后生效的自定义环境变量",
+	Long:  `按优先级合并全局、版本（默认为当前版本）和当前项目的自定义环境变量，并显示每个变量的来源。`,
+	Args:  cobra.NoArgs,
+	Run:   runEnvListCommand,
+}
+
+func init() {
+	envCmd.AddCommand(envHookCmd)
+	envCmd.AddCommand(envAllowCmd)
+	envCmd.AddCommand(envDenyCmd)
+	envCmd.AddCommand(envSetCmd)
+	envCmd.AddCommand(envUnsetCmd)
+	envCmd.AddCommand(envListCmd)
+
+	for _, cmd := range []*cobra.Command{envSetCmd, envUnsetCmd} {
+		cmd.Flags().StringVar(&envVersion, "version", "", "只对指定版本生效")
+		cmd.Flags().BoolVar(&envProject, "project", false, "只在当前项目中生效（go-version.env.yaml）")
+	}
+	envListCmd.Flags().StringVar(&envVersion, "version", "", "查看指定版本的环境变量（默认为当前版本）")
+
+	envHookCmd.Flags().StringVar(&hookShell, "shell", "bash", "shell: bash, zsh, fish")
+	envCmd.Flags().StringVar(&envShell, "shell", "bash", "输出格式: bash, fish, powershell, json")
+	envCmd.Flags().StringVar(&envVersion, "version", "", "输出指定版本的环境变量（默认为当前版本）")
+}
+
+func runEnvCommand(cmd *cobra.Command, args []string) {
+	shell := strings.ToLower(envShell)
+	if !isShellFormat(shell) {
+		PrintError(fmt.Sprintf("不支持的 shell '%s'，支持: %s", envShell, strings.Join(service.ShellFormats, ", ")))
+		os.Exit(1)
+	}
+
+	appService, err := application.NewVersionAppService()
+	if err != nil {
+		PrintError(fmt.Sprintf("初始化应用服务失败: %s", err))
+		os.Exit(1)
+	}
+
+	dir, _ := os.Getwd()
+	warnUnallowedProjectEnv(dir)
+	env, err := appService.EffectiveEnvironment(envVersion, dir)
+	if err != nil {
+		PrintError(err.Error())
+		os.Exit(1)
+	}
+	state := model.ShellState{
+		Path:      os.Getenv("PATH"),
+		AddedPath: os.Getenv(model.ShellPathVar),
+		Vars:      os.Getenv(model.ShellVarsVar),
+	}
+	output, err := service.FormatShellEnvironment(env, shell, state)
+	if err != nil {
+		PrintError(err.Error())
+		os.Exit(1)
+	}
+	fmt.Print(output)
+}
+
+func runEnvHookCommand(cmd *cobra.Command, args []string) {
+	executable, err := os.Executable()
+	if err != nil {
+		executable = "go-version"
+	}
+	output, err := service.FormatShellHook(strings.ToLower(hookShell), executable)
+	if err != nil {
+		PrintError(err.Error())
+		os.Exit(1)
+	}
+	fmt.Print(output)
+}
+
+// warnUnallowedProjectEnv 当前项目的环境变量文件未被允许时在标准错误中提示，不影响标准输出中的设置语句
+func warnUnallowedProjectEnv(dir string) {
+	path, allowed, err := service.ProjectEnvStatus(dir)
+	if err != nil || path == "" || allowed {
+		return
+	}
+	fmt.Fprintln(os.Stderr, Colorize(fmt.Sprintf("%s 未被允许，其中的环境变量不会生效，检查内容后运行 'go-version env allow'", path), ColorYellow))
+}
+
+func runEnvAllowCommand(cmd *cobra.Command, args []string) {
+	dir, err := os.Getwd()
+	if err != nil {
+		PrintError(fmt.Sprintf("获取当前目录失败: %s", err))
+		os.Exit(1)
+	}
+	path, err := service.AllowProjectEnv(dir)
+	if err != nil {
+		PrintError(err.Error())
+		os.Exit(1)
+	}
+	PrintSuccess(fmt.Sprintf("已允许 %s", path))
+}
+
+func runEnvDenyCommand(cmd *cobra.Command, args []string) {
+	dir, err := os.Getwd()
+	if err != nil {
+		PrintError(fmt.Sprintf("获取当前目录失败: %s", err))
+		os.Exit(1)
+	}
+	path, err := service.RevokeProjectEnv(dir)
+	if err != nil {
+		PrintError(err.Error())
+		os.Exit(1)
+	}
+	PrintSuccess(fmt.Sprintf("已撤销对 %s 的允许", path))
+}
+
+// isShellFormat 检查是否为支持的输出格式
+func isShellFormat(shell string) bool {
+	for _, format := range service.ShellFormats {
+		if shell == format {
+			return true
+		}
+	}
+	return false
+}
+
+// envScope 根据命令选项获取环境变量范围和目标（版本名称或项目目录）
+func envScope() (model.EnvScope, string) {
+	if envVersion != "" && envProject {
+		PrintError("--version 和 --project 不能同时使用")
+		os.Exit(1)
+	}
+	switch {
+	case envVersion != "":
+		return model.EnvScopeVersion, envVersion
+	case envProject:
+		dir, err := os.Getwd()
+		if err != nil {
+			PrintError(fmt.Sprintf("获取当前目录失败: %s", err))
+			os.Exit(1)
+		}
+		return model.EnvScopeProject, dir
+	default:
+		return model.EnvScopeGlobal, ""
+	}
+}
+
+func runEnvSetCommand(cmd *cobra.Command, args []string) {
+	scope, target := envScope()
+	appService, err := application.NewVersionAppService()
+	if err != nil {
+		PrintError(fmt.Sprintf("初始化应用服务失败: %s", err))
+		os.Exit(1)
+	}
+
+	for _, arg := range args {
+		name, value, ok := strings.Cut(arg, "=")
+		if !ok {
+			PrintError(fmt.Sprintf("无效的参数 '%s'，格式应为 NAME=value", arg))
+			os.Exit(1)
+		}
+		if err := appService.SetEnvVar(scope, target, name, value); err != nil {
+			PrintError(err.Error())
+			os.Exit(1)
+		}
+		PrintSuccess(fmt.Sprintf("已设置 %s=%s (%s)", name, value, scope))
+	}
+}
+
+func runEnvUnsetCommand(cmd *cobra.Command, args []string) {
+	scope, target := envScope()
+	appService, err := application.NewVersionAppService()
+	if err != nil {
+		PrintError(fmt.Sprintf("初始化应用服务失败: %s", err))
+		os.Exit(1)
+	}
+
+	for _, name := range args {
+		if err := appService.UnsetEnvVar(scope, target, name); err != nil {
+			PrintError(err.Error())
+			os.Exit(1)
+		}
+		PrintSuccess(fmt.Sprintf("已删除 %s (%s)", name, scope))
+	}
+}
+
+func runEnvListCommand(cmd *cobra.Command, args []string) {
+	appService, err := application.NewVersionAppService()
+	if err != nil {
+		PrintError(fmt.Sprintf("初始化应用服务失败: %s", err))
+		os.Exit(1)
+	}
+
+	dir, _ := os.Getwd()
+	warnUnallowedProjectEnv(dir)
+	vars, err := appService.ResolveEnvVars(envVersion, dir)
+	if err != nil {
+		PrintError(err.Error())
+		os.Exit(1)
+	}
+	if len(vars) == 0 {
+		PrintInfo("未设置自定义环境变量，使用 'go-version env set NAME=value' 添加")
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, Colorize("变量	值	来源", ColorBold))
+	for _, v := range vars {
+		fmt.Fprintf(w, "%s	%s	%s\n", v.Name, v.Value, v.Source)
+	}
+	w.Flush()
+}
@@ -0,0 +1,88 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+
+	"version-list/internal/application"
+	"version-list/internal/domain/model"
+	"version-list/internal/domain/service"
+
+	"github.com/spf13/cobra"
+)
+
+// exec 命令选项变量
+var execVersion string
+
+var execCmd = &cobra.Command{
+	Use:   "exec [--version <版本>] <命令> [参数...]",
+	Short: "使用指定版本生效的环境变量运行命令",
+	Long: `使用当前版本（或 --version 指定的版本）生效的环境变量运行命令，不修改当前 shell 和符号链接。
+
+设置的环境变量与 'go-version env' 输出的相同：GOROOT、按隔离方式计算的 GOBIN、GOCACHE、GOMODCACHE，
+合并后的自定义环境变量（全局、版本、当前目录所在的项目），以及把 GOROOT/bin 和 GOBIN 加在前面的 PATH。
+命令的退出状态码作为 go-version 的退出状态码。
+
+示例：
+  go-version exec go version                        # 使用当前版本
+  go-version exec --version 1.21.13 go test ./...   # 使用 1.21.13 及其隔离目录和环境变量
+  go-version exec --version 1.22.7 -- make build    # 使用 -- 分隔命令`,
+	Args: cobra.MinimumNArgs(1),
+	Run:  runExecCommand,
+}
+
+func init() {
+	// 命令之后的选项属于要运行的命令
+	execCmd.Flags().SetInterspersed(false)
+	execCmd.Flags().StringVar(&execVersion, "version", "", "使用指定版本（默认为当前版本）")
+}
+
+func runExecCommand(cmd *cobra.Command, args []string) {
+	appService, err := application.NewVersionAppService()
+	if err != nil {
+		PrintError(fmt.Sprintf("初始化应用服务失败: %s", err))
+		os.Exit(1)
+	}
+
+	dir, _ := os.Getwd()
+	warnUnallowedProjectEnv(dir)
+	env, err := appService.EffectiveEnvironment(strings.TrimPrefix(execVersion, "go"), dir)
+	if err != nil {
+		PrintError(err.Error())
+		os.Exit(1)
+	}
+
+	runWithEnvironment(env, args[0], args)
+}
+
+// runWithEnvironment 应用环境变量后运行命令，命令的退出状态码作为 go-version 的退出状态码；
+// name 为可执行文件名或路径，args[0] 用于错误提示
+func runWithEnvironment(env *model.EffectiveEnvironment, name string, args []string) {
+	// 在当前进程中应用环境变量，使命令按新的 PATH 查找
+	environ := service.ProcessEnvironment(env, os.Environ())
+	os.Clearenv()
+	for _, kv := range environ {
+		key, value, _ := strings.Cut(kv, "=")
+		os.Setenv(key, value)
+	}
+
+	command := exec.Command(name, args[1:]...)
+	command.Stdin = os.Stdin
+	command.Stdout = os.Stdout
+	command.Stderr = os.Stderr
+
+	// Ctrl-C 同时发送给命令，由命令决定如何退出
+	signal.Notify(make(chan os.Signal, 1), os.Interrupt)
+	if err := command.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() >= 0 {
+			os.Exit(exitErr.ExitCode())
+		}
+		PrintError(fmt.Sprintf("运行 %s 失败: %s", args[0], err))
+		os.Exit(1)
+	}
+}
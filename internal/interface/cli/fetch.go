@@ -0,0 +1,131 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+
+	"version-list/internal/application"
+	"version-list/internal/domain/service"
+	"version-list/internal/interface/ui"
+
+	"github.com/spf13/cobra"
+)
+
+// fetch 命令选项变量
+var (
+	fetchOS     string
+	fetchArch   string
+	fetchOutput string
+)
+
+var fetchCmd = &cobra.Command{
+	Use:   "fetch <version>",
+	Short: "下载任意平台的Go压缩包（不安装）",
+	Long: `下载并验证指定版本和平台的Go压缩包，保存到下载缓存或指定目录，但不安装。
+
+适用于在当前机器上为其他平台（如arm64构建机、Windows虚拟机）准备Go，
+下载的压缩包可以直接复制到目标机器后使用 install --from-archive 安装。
+压缩包默认保存到下载缓存，缓存中已有该压缩包时不会重新下载。
+
+示例：
+  go-version fetch 1.22.7                               # 下载当前平台的压缩包到缓存
+  go-version fetch 1.22.7 --os windows --arch arm64     # 下载Windows arm64的压缩包
+  go-version fetch 1.22.7 --os linux --arch arm64 -o ./dist
+  go-version fetch 1.22.7 --os darwin --arch arm64 --sha256 <校验和>`,
+	Args: cobra.ExactArgs(1),
+	Run:  runFetchCommand,
+}
+
+func init() {
+	fetchCmd.Flags().StringVar(&fetchOS, "os", "", "目标操作系统（默认为当前系统）")
+	fetchCmd.Flags().StringVar(&fetchArch, "arch", "", "目标CPU架构（默认为当前系统）")
+	fetchCmd.Flags().StringVarP(&fetchOutput, "output", "o", "", "压缩包保存目录（默认只保存到下载缓存）")
+	fetchCmd.Flags().StringVar(&mirrorName, "mirror", "", "指定镜像源 (official, goproxy-cn, aliyun, tencent, huawei)")
+	fetchCmd.Flags().StringVar(&archiveSHA256, "sha256", "", "期望的压缩包SHA-256校验和")
+	fetchCmd.Flags().BoolVar(&skipVerification, "skip-verification", false, "跳过文件完整性验证")
+	fetchCmd.Flags().BoolVar(&noFailover, "no-failover", false, "下载失败时不切换到其他镜像源")
+	fetchCmd.Flags().BoolVar(&noProgress, "no-progress", false, "不显示进度条")
+}
+
+func runFetchCommand(cmd *cobra.Command, args []string) {
+	version := args[0]
+	if !isValidVersion(version) {
+		PrintError(fmt.Sprintf("无效的版本号格式: %s", version))
+		PrintInfo("版本号格式示例: 1.21.0, 1.20.5")
+		os.Exit(1)
+	}
+
+	validateTargetPlatform(fetchOS, fetchArch)
+
+	appService, err := application.NewVersionAppService()
+	if err != nil {
+		PrintError(fmt.Sprintf("初始化应用服务失败: %s", err))
+		os.Exit(1)
+	}
+
+	options := &service.FetchOptions{
+		OS:               fetchOS,
+		Arch:             fetchArch,
+		OutputDir:        fetchOutput,
+		Mirror:           mirrorName,
+		SHA256:           archiveSHA256,
+		SkipVerification: skipVerification,
+		NoFailover:       noFailover,
+	}
+
+	var progressUI *ui.InstallProgressUI
+	if !noProgress {
+		progressUI = ui.NewInstallProgressUI()
+		progressUI.Start()
+	}
+
+	ctx, stop := newInterruptContext()
+	defer stop()
+	result, err := appService.Fetch(ctx, version, options, progressUI)
+	if progressUI != nil {
+		progressUI.Stop()
+	}
+	if err != nil {
+		PrintError(fmt.Sprintf("下载失败: %s", err))
+		exitIfInterrupted(ctx, err)
+		os.Exit(1)
+	}
+
+	platform := result.SystemInfo.OS + "/" + result.SystemInfo.Arch
+	if result.Cached {
+		PrintSuccess(fmt.Sprintf("Go %s (%s) 已在下载缓存中", version, platform))
+	} else {
+		PrintSuccess(fmt.Sprintf("Go %s (%s) 下载完成", version, platform))
+	}
+	PrintInfo(fmt.Sprintf("文件: %s", result.Path))
+	PrintInfo(fmt.Sprintf("大小: %s", formatBytes(result.DownloadInfo.Size)))
+	PrintInfo(fmt.Sprintf("SHA-256: %s", result.DownloadInfo.Checksum))
+	if len(result.DownloadInfo.AttemptedMirrors) > 1 {
+		PrintInfo(fmt.Sprintf("下载镜像: %s（已尝试 %s）", result.DownloadInfo.Mirror, strings.Join(result.DownloadInfo.AttemptedMirrors, ", ")))
+	}
+}
+
+// validateTargetPlatform 检查 --os/--arch 指定的平台是否有官方发布的压缩包，不支持时列出所有平台并退出
+func validateTargetPlatform(goos, goarch string) {
+	if goos == "" && goarch == "" {
+		return
+	}
+	if goos == "" {
+		goos = runtime.GOOS
+	}
+	if goarch == "" {
+		goarch = runtime.GOARCH
+	}
+	if _, err := service.LookupPlatform(goos, goarch); err != nil {
+		PrintError(err.Error())
+
+		var supported []string
+		for _, platform := range service.SupportedPlatforms() {
+			supported = append(supported, platform.String())
+		}
+		PrintInfo(fmt.Sprintf("支持的平台: %s", strings.Join(supported, ", ")))
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,256 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"version-list/internal/application"
+	"version-list/internal/domain/model"
+	"version-list/internal/domain/service"
+
+	"github.com/spf13/cobra"
+)
+
+var hooksCmd = &cobra.Command{
+	Use:   "hooks",
+	Short: "查看安装和切换版本时运行的钩子",
+	Long: `查看 ~/.go-version/hooks.yaml 中配置的钩子。
+
+钩子是在安装和切换版本前后通过 shell 运行的外部命令，支持的时机：
+  pre-install    安装开始前（版本目录尚未创建）
+  post-install   保存版本记录后、安装完成前
+  pre-use        切换版本前
+  post-use       切换版本后
+
+钩子运行时 GOROOT 和 PATH 指向对应的工具链，并设置以下环境变量：
+  GO_VERSION_HOOK      钩子时机
+  GO_VERSION_NAME      版本列表中的名称
+  GO_VERSION_RELEASE   Go发布版本号
+  GO_VERSION_PATH      安装路径
+
+钩子失败时只输出警告；标记为 required 的钩子失败时中止操作，post-install 钩子失败时回滚安装。
+
+配置示例：
+  hooks:
+    post-install:
+      - name: 预热构建缓存
+        command: go build std
+        timeout: 300
+    pre-use:
+      - command: test -x "$GO_VERSION_PATH/bin/go"
+        required: true
+  default_tools:
+    - golang.org/x/tools/gopls@latest
+    - honnef.co/go/tools/cmd/staticcheck@latest
+    - github.com/go-delve/delve/cmd/dlv@latest`,
+	Args: cobra.NoArgs,
+	Run:  runHooksCommand,
+}
+
+var toolsCmd = &cobra.Command{
+	Use:   "tools",
+	Short: "管理安装后自动安装的默认工具",
+	Long: `管理默认工具列表（~/.go-version/hooks.yaml 中的 default_tools）。
+
+每次安装后使用新工具链运行 go install <包路径>@<版本>，安装成功的工具记录在版本信息中，
+安装失败时只输出警告，不影响安装结果。
+
+示例：
+  go-version tools list                                   # 查看默认工具列表
+  go-version tools list 1.22.7                            # 查看指定版本已安装的工具
+  go-version tools add golang.org/x/tools/gopls@latest    # 添加默认工具
+  go-version tools remove golang.org/x/tools/gopls        # 移除默认工具
+  go-version tools install 1.22.7                         # 为已安装的版本安装默认工具`,
+}
+
+var toolsListCmd = &cobra.Command{
+	Use:   "list [version]",
+	Short: "查看默认工具列表或指定版本已安装的工具",
+	Args:  cobra.MaximumNArgs(1),
+	Run:   runToolsListCommand,
+}
+
+var toolsAddCmd = &cobra.Command{
+	Use:   "add <package[@version]>...",
+	Short: "添加默认工具（未指定版本时使用 latest）",
+	Args:  cobra.MinimumNArgs(1),
+	Run:   runToolsAddCommand,
+}
+
+var toolsRemoveCmd = &cobra.Command{
+	Use:   "remove <package>...",
+	Short: "移除默认工具",
+	Args:  cobra.MinimumNArgs(1),
+	Run:   runToolsRemoveCommand,
+}
+
+var toolsInstallCmd = &cobra.Command{
+	Use:   "install <version>",
+	Short: "为已安装的版本安装默认工具",
+	Args:  cobra.ExactArgs(1),
+	Run:   runToolsInstallCommand,
+}
+
+func init() {
+	toolsCmd.AddCommand(toolsListCmd)
+	toolsCmd.AddCommand(toolsAddCmd)
+	toolsCmd.AddCommand(toolsRemoveCmd)
+	toolsCmd.AddCommand(toolsInstallCmd)
+}
+
+// loadHooksConfig 读取钩子配置，失败时退出
+func loadHooksConfig() *model.HooksConfig {
+	config, err := service.LoadHooksConfig(service.HooksConfigPath())
+	if err != nil {
+		PrintError(err.Error())
+		os.Exit(1)
+	}
+	return config
+}
+
+// saveHooksConfig 保存钩子配置，失败时退出
+func saveHooksConfig(config *model.HooksConfig) {
+	if err := service.SaveHooksConfig(service.HooksConfigPath(), config); err != nil {
+		PrintError(fmt.Sprintf("保存配置失败: %s", err))
+		os.Exit(1)
+	}
+}
+
+func runHooksCommand(cmd *cobra.Command, args []string) {
+	config := loadHooksConfig()
+
+	PrintInfo(fmt.Sprintf("配置文件: %s", service.HooksConfigPath()))
+	count := 0
+	for _, hooks := range config.Hooks {
+		count += len(hooks)
+	}
+	if count == 0 {
+		PrintInfo("未配置钩子")
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, Colorize("时机	名称	必需	超时	命令", ColorBold))
+	for _, event := range model.HookEvents {
+		for _, hook := range config.Hooks[event] {
+			required := "否"
+			if hook.Required {
+				required = "是"
+			}
+			timeout := "-"
+			if hook.Timeout > 0 {
+				timeout = fmt.Sprintf("%d秒", hook.Timeout)
+			}
+			fmt.Fprintf(w, "%s	%s	%s	%s	%s\n", event, hook.Name, required, timeout, hook.Command)
+		}
+	}
+	w.Flush()
+}
+
+func runToolsListCommand(cmd *cobra.Command, args []string) {
+	if len(args) == 1 {
+		appService, err := application.NewVersionAppService()
+		if err != nil {
+			PrintError(fmt.Sprintf("初始化应用服务失败: %s", err))
+			os.Exit(1)
+		}
+		tools, err := appService.InstalledTools(args[0])
+		if err != nil {
+			PrintError(err.Error())
+			os.Exit(1)
+		}
+		if len(tools) == 0 {
+			PrintInfo(fmt.Sprintf("Go %s 没有安装默认工具", args[0]))
+			return
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, Colorize("工具	版本	安装时间", ColorBold))
+		for _, tool := range tools {
+			fmt.Fprintf(w, "%s	%s	%s\n", tool.Package, tool.Version, tool.InstalledAt.Format("2006-01-02 15:04:05"))
+		}
+		w.Flush()
+		return
+	}
+
+	config := loadHooksConfig()
+	if len(config.DefaultTools) == 0 {
+		PrintInfo("未配置默认工具，使用 'go-version tools add <包路径>@<版本>' 添加")
+		return
+	}
+	for _, tool := range config.DefaultTools {
+		fmt.Println(tool)
+	}
+}
+
+func runToolsAddCommand(cmd *cobra.Command, args []string) {
+	config := loadHooksConfig()
+	for _, arg := range args {
+		tool, err := model.ParseToolSpec(arg)
+		if err != nil {
+			PrintError(err.Error())
+			os.Exit(1)
+		}
+		config.DefaultTools = removeDefaultTool(config.DefaultTools, tool.Package)
+		config.DefaultTools = append(config.DefaultTools, tool.String())
+		PrintSuccess(fmt.Sprintf("已添加默认工具 %s", tool))
+	}
+	saveHooksConfig(config)
+}
+
+func runToolsRemoveCommand(cmd *cobra.Command, args []string) {
+	config := loadHooksConfig()
+	for _, arg := range args {
+		remaining := removeDefaultTool(config.DefaultTools, arg)
+		if len(remaining) == len(config.DefaultTools) {
+			PrintError(fmt.Sprintf("默认工具中没有 %s", arg))
+			os.Exit(1)
+		}
+		config.DefaultTools = remaining
+		PrintSuccess(fmt.Sprintf("已移除默认工具 %s", arg))
+	}
+	saveHooksConfig(config)
+}
+
+// removeDefaultTool 从默认工具列表中移除指定包（忽略版本）
+func removeDefaultTool(tools []string, pkg string) []string {
+	if parsed, err := model.ParseToolSpec(pkg); err == nil {
+		pkg = parsed.Package
+	}
+	remaining := make([]string, 0, len(tools))
+	for _, spec := range tools {
+		if tool, err := model.ParseToolSpec(spec); err == nil && tool.Package == pkg {
+			continue
+		}
+		remaining = append(remaining, spec)
+	}
+	return remaining
+}
+
+func runToolsInstallCommand(cmd *cobra.Command, args []string) {
+	appService, err := application.NewVersionAppService()
+	if err != nil {
+		PrintError(fmt.Sprintf("初始化应用服务失败: %s", err))
+		os.Exit(1)
+	}
+
+	ctx, stop := newInterruptContext()
+	defer stop()
+
+	PrintInfo(fmt.Sprintf("正在为 Go %s 安装默认工具...", args[0]))
+	tools, warnings, err := appService.InstallDefaultTools(ctx, args[0])
+	exitIfInterrupted(ctx, err)
+	if err != nil {
+		PrintError(err.Error())
+		os.Exit(1)
+	}
+	printWarnings(warnings)
+	if len(tools) == 0 {
+		PrintWarning("没有安装任何工具")
+		if len(warnings) > 0 {
+			os.Exit(1)
+		}
+		return
+	}
+	PrintSuccess(fmt.Sprintf("已安装: %s", formatInstalledTools(tools)))
+}
@@ -9,11 +9,17 @@ import (
 	"github.com/spf13/cobra"
 )
 
+// importName 导入时使用的自定义工具链名称
+var importName string
+
 var importCmd = &cobra.Command{
 	Use:   "import [path]",
 	Short: "导入本地已安装的Go版本",
-	Long:  `导入本地已安装的Go版本，例如: go-version import "C:\Go"`,
-	Args:  cobra.ExactArgs(1),
+	Long: `导入本地已安装的Go版本，例如: go-version import "C:\Go"
+
+使用 --name 为工具链指定自定义名称，用于保存同一发布版本的不同构建，例如:
+  go-version import /opt/go-fips --name 1.22.7-fips`,
+	Args: cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		path := args[0]
 
@@ -24,7 +30,7 @@ var importCmd = &cobra.Command{
 		}
 
 		PrintInfo(fmt.Sprintf("正在导入本地Go版本从路径: %s", path))
-		version, err := appService.ImportLocal(path)
+		version, err := appService.ImportLocal(path, importName)
 		if err != nil {
 			PrintError(fmt.Sprintf("导入失败: %s", err))
 			os.Exit(1)
@@ -33,3 +39,7 @@ var importCmd = &cobra.Command{
 		PrintSuccess(fmt.Sprintf("成功导入Go版本: %s", version))
 	},
 }
+
+func init() {
+	importCmd.Flags().StringVar(&importName, "name", "", "自定义工具链名称（如 1.22.7-fips），默认使用检测到的版本号")
+}
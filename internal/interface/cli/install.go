@@ -3,9 +3,14 @@ package cli
 import (
 	"fmt"
 	"os"
+	"path"
+	"runtime"
+	"strings"
+	"time"
 
 	"version-list/internal/application"
 	"version-list/internal/domain/model"
+	"version-list/internal/domain/service"
 	"version-list/internal/interface/ui"
 
 	"github.com/spf13/cobra"
@@ -23,21 +28,42 @@ var (
 	mirrorName       string
 	autoMirror       bool
 	listMirrors      bool
+	parallelInstalls int
+	fromArchive      string
+	archiveSHA256    string
+	noFailover       bool
+	installOS        string
+	installArch      string
+	fromSource       string
+	bootstrapVersion string
+	installName      string
+	installLocked    bool
+	lockFilePath     string
+	noHooks          bool
+	noTools          bool
 )
 
 var installCmd = &cobra.Command{
-	Use:   "install [version]",
+	Use:   "install [version...]",
 	Short: "安装指定版本的Go",
 	Long: `安装指定版本的Go。
 
-支持两种安装模式：
+一次指定多个版本时，将并行下载和解压（使用 --parallel 限制并发数），
+并在结束后输出每个版本的安装结果，任一版本失败时以非零状态退出。
+
+支持四种安装模式：
 1. 在线安装（默认）：从Go官网或镜像源自动下载并安装指定版本
 2. 本地安装：仅注册已存在的Go版本到版本管理器
+3. 压缩包安装：使用 --from-archive 从本地Go压缩包安装，适用于无法访问网络的环境
+   （版本号从文件名或压缩包中的VERSION文件自动识别）
+4. 源码编译安装：使用 --from-source 下载源码包（或使用本地Go源码目录）并运行 make.bash 编译，
+   使用已安装的版本作为引导工具链，可用 --bootstrap 指定
 
 镜像源支持：
 - 使用 --mirror 指定镜像源（official, goproxy-cn, aliyun, tencent, huawei）
 - 使用 --auto-mirror 自动选择最快的镜像源
 - 使用 --list-mirrors 查看所有可用的镜像源
+- 下载失败时自动切换到其他镜像继续下载，使用 --no-failover 禁用
 
 示例：
   go-version install 1.21.0                           # 在线安装Go 1.21.0（使用官方源）
@@ -46,8 +72,24 @@ var installCmd = &cobra.Command{
   go-version install 1.21.0 --path /custom           # 安装到自定义路径
   go-version install 1.21.0 --force                  # 强制重新安装
   go-version install 1.21.0 --no-progress            # 不显示进度条
-  go-version install --list-mirrors                  # 查看可用镜像源`,
-	Args: cobra.RangeArgs(0, 1),
+  go-version install 1.21.13 1.22.7 1.23.2           # 并行安装多个版本
+  go-version install 1.21.13 1.22.7 --parallel 2     # 最多同时安装2个版本
+  go-version install --list-mirrors                  # 查看可用镜像源
+  go-version install --from-archive ./go1.22.7.linux-amd64.tar.gz            # 从本地压缩包安装
+  go-version install --from-archive ./go.tar.gz --sha256 <校验和>           # 校验后从本地压缩包安装
+  go-version install --from-source 1.22.7                                    # 从源码编译安装Go 1.22.7
+  go-version install --from-source ~/src/go --bootstrap 1.22.7              # 使用指定引导工具链编译本地源码
+  go-version install --from-archive ./go-fips.tar.gz --name 1.22.7-fips      # 以自定义名称安装定制构建
+  go-version install --locked                                                # 按 go-version.lock 安装所有锁定的版本
+  go-version install 1.22.7 --os windows --arch amd64 --path /mnt/vm/go     # 安装其他平台的Go到指定路径
+
+使用 --os/--arch 安装其他平台的Go时必须指定 --path，安装结果不加入版本列表。
+使用 --name 为安装的工具链指定自定义名称（如 1.22.7-fips），以便与同一发布版本的其他构建共存。
+安装前后运行 ~/.go-version/hooks.yaml 中配置的 pre-install 和 post-install 钩子，并使用新工具链
+go install 配置的默认工具（见 go-version tools），可用 --no-hooks 和 --no-tools 跳过。
+安装过程中按 Ctrl-C 时会取消安装并清理未完成的文件，以状态码 130 退出；
+下载超过 --timeout 秒没有进度时切换到其他镜像，所有镜像都停滞时取消并清理，以状态码 124 退出。`,
+	Args: cobra.ArbitraryArgs,
 	Run:  runInstallCommand,
 }
 
@@ -56,15 +98,28 @@ func init() {
 	installCmd.Flags().StringVar(&installPath, "path", "", "自定义安装路径")
 	installCmd.Flags().BoolVar(&forceInstall, "force", false, "强制重新安装（即使版本已存在）")
 	installCmd.Flags().BoolVar(&skipVerification, "skip-verification", false, "跳过文件完整性验证")
-	installCmd.Flags().IntVar(&installTimeout, "timeout", 300, "安装超时时间（秒）")
+	installCmd.Flags().IntVar(&installTimeout, "timeout", 300, "下载停滞超时时间（秒），超过该时间没有下载进度时切换镜像或取消并清理，0 表示不限制")
 	installCmd.Flags().IntVar(&maxRetries, "max-retries", 3, "最大重试次数")
 	installCmd.Flags().BoolVar(&noProgress, "no-progress", false, "不显示进度条")
 	installCmd.Flags().BoolVar(&onlineInstall, "online", true, "在线安装模式（默认）")
+	installCmd.Flags().IntVar(&parallelInstalls, "parallel", 3, "同时安装多个版本时的最大并发数")
+	installCmd.Flags().StringVar(&fromArchive, "from-archive", "", "从本地Go压缩包安装（.tar.gz 或 .zip）")
+	installCmd.Flags().StringVar(&archiveSHA256, "sha256", "", "期望的压缩包SHA-256校验和")
+	installCmd.Flags().StringVar(&installOS, "os", "", "目标操作系统（安装其他平台的Go时使用，需指定 --path）")
+	installCmd.Flags().StringVar(&installArch, "arch", "", "目标CPU架构（安装其他平台的Go时使用，需指定 --path）")
+	installCmd.Flags().StringVar(&fromSource, "from-source", "", "从源码编译安装（版本号或本地Go源码目录）")
+	installCmd.Flags().BoolVar(&installLocked, "locked", false, "按锁文件安装（校验和必须与锁文件一致），未指定版本时安装锁文件中的所有版本")
+	installCmd.Flags().StringVar(&lockFilePath, "lockfile", service.DefaultLockFileName, "--locked 使用的锁文件路径")
+	installCmd.Flags().StringVar(&installName, "name", "", "自定义工具链名称（如 1.22.7-fips），默认使用版本号")
+	installCmd.Flags().StringVar(&bootstrapVersion, "bootstrap", "", "从源码编译时使用的引导工具链版本（默认自动选择已安装的版本）")
+	installCmd.Flags().BoolVar(&noHooks, "no-hooks", false, "不运行 pre-install/post-install 钩子")
+	installCmd.Flags().BoolVar(&noTools, "no-tools", false, "不安装默认工具")
 
 	// 镜像相关选项
 	installCmd.Flags().StringVar(&mirrorName, "mirror", "", "指定镜像源 (official, goproxy-cn, aliyun, tencent, huawei)")
 	installCmd.Flags().BoolVar(&autoMirror, "auto-mirror", false, "自动选择最快的镜像源")
 	installCmd.Flags().BoolVar(&listMirrors, "list-mirrors", false, "显示所有可用的镜像源")
+	installCmd.Flags().BoolVar(&noFailover, "no-failover", false, "下载失败时不切换到其他镜像源")
 }
 
 func runInstallCommand(cmd *cobra.Command, args []string) {
@@ -74,6 +129,33 @@ func runInstallCommand(cmd *cobra.Command, args []string) {
 		return
 	}
 
+	if fromArchive != "" && fromSource != "" {
+		PrintError("--from-archive 和 --from-source 不能同时使用")
+		os.Exit(1)
+	}
+	if installLocked && (fromArchive != "" || fromSource != "") {
+		PrintError("--locked 不能与 --from-archive、--from-source 同时使用")
+		os.Exit(1)
+	}
+
+	// 处理 --from-archive 选项
+	if fromArchive != "" {
+		runArchiveInstall(args)
+		return
+	}
+
+	// 处理 --from-source 选项
+	if fromSource != "" {
+		runSourceInstall(args)
+		return
+	}
+
+	// 处理 --locked 选项
+	if installLocked {
+		runLockedInstall(args)
+		return
+	}
+
 	// 检查是否提供了版本号
 	if len(args) == 0 {
 		PrintError("请指定要安装的Go版本号")
@@ -82,15 +164,19 @@ func runInstallCommand(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
-	version := args[0]
+	versions := uniqueVersions(args)
 
 	// 验证版本号格式
-	if !isValidVersion(version) {
-		PrintError(fmt.Sprintf("无效的版本号格式: %s", version))
-		PrintInfo("版本号格式示例: 1.21.0, 1.20.5")
-		os.Exit(1)
+	for _, version := range versions {
+		if !isValidVersion(version) {
+			PrintError(fmt.Sprintf("无效的版本号格式: %s", version))
+			PrintInfo("版本号格式示例: 1.21.0, 1.20.5")
+			os.Exit(1)
+		}
 	}
 
+	validateTargetPlatform(installOS, installArch)
+
 	// 验证镜像选项
 	if err := validateMirrorOptions(); err != nil {
 		PrintError(err.Error())
@@ -104,18 +190,36 @@ func runInstallCommand(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
+	if len(versions) > 1 {
+		if !onlineInstall || installPath != "" || archiveSHA256 != "" || installOS != "" || installArch != "" || installName != "" {
+			PrintError("同时安装多个版本时不支持 --path、--sha256、--os、--arch、--name 和本地安装模式")
+			os.Exit(1)
+		}
+		runBatchInstall(appService, versions)
+		return
+	}
+
 	if onlineInstall {
-		runOnlineInstall(appService, version)
+		runOnlineInstall(appService, versions[0])
 	} else {
-		runLocalInstall(appService, version)
+		runLocalInstall(appService, versions[0])
 	}
 }
 
-func runOnlineInstall(appService *application.VersionAppService, version string) {
-	PrintInfo(fmt.Sprintf("开始在线安装Go %s...", version))
+// buildInstallOptions 根据命令行选项创建安装选项
+func buildInstallOptions() *model.InstallOptions {
+	var targetSystem *model.SystemInfo
+	if installOS != "" || installArch != "" {
+		targetSystem = &model.SystemInfo{OS: installOS, Arch: installArch}
+		if targetSystem.OS == "" {
+			targetSystem.OS = runtime.GOOS
+		}
+		if targetSystem.Arch == "" {
+			targetSystem.Arch = runtime.GOARCH
+		}
+	}
 
-	// 创建安装选项
-	options := &model.InstallOptions{
+	return &model.InstallOptions{
 		Force:            forceInstall,
 		CustomPath:       installPath,
 		SkipVerification: skipVerification,
@@ -123,7 +227,21 @@ func runOnlineInstall(appService *application.VersionAppService, version string)
 		MaxRetries:       maxRetries,
 		Mirror:           mirrorName,
 		AutoMirror:       autoMirror,
+		SHA256:           archiveSHA256,
+		NoFailover:       noFailover,
+		TargetSystem:     targetSystem,
+		BootstrapVersion: bootstrapVersion,
+		Name:             installName,
+		NoHooks:          noHooks,
+		NoTools:          noTools,
 	}
+}
+
+func runOnlineInstall(appService *application.VersionAppService, version string) {
+	PrintInfo(fmt.Sprintf("开始在线安装Go %s...", version))
+
+	// 创建安装选项
+	options := buildInstallOptions()
 
 	// 创建进度UI
 	var progressUI *ui.InstallProgressUI
@@ -133,14 +251,18 @@ func runOnlineInstall(appService *application.VersionAppService, version string)
 		defer progressUI.Stop()
 	}
 
-	// 执行在线安装
-	result, err := appService.InstallOnline(version, options, progressUI)
+	// 执行在线安装，收到 Ctrl-C 时取消并清理
+	ctx, stop := newInterruptContext()
+	defer stop()
+	result, err := appService.InstallOnline(ctx, version, options, progressUI)
 	if err != nil {
 		if progressUI != nil {
 			progressUI.PrintError(fmt.Sprintf("安装失败: %s", err))
+			progressUI.Stop()
 		} else {
 			PrintError(fmt.Sprintf("安装失败: %s", err))
 		}
+		exitIfInterrupted(ctx, err)
 		os.Exit(1)
 	}
 
@@ -148,6 +270,169 @@ func runOnlineInstall(appService *application.VersionAppService, version string)
 	displayInstallResult(result, progressUI)
 }
 
+// runBatchInstall 并行安装多个版本并输出汇总结果
+func runBatchInstall(appService *application.VersionAppService, versions []string) {
+	PrintInfo(fmt.Sprintf("开始并行安装 %d 个Go版本（最大并发数: %d）...", len(versions), parallelInstalls))
+
+	// 创建多行进度UI
+	var progressUI *ui.MultiInstallProgressUI
+	if !noProgress {
+		progressUI = ui.NewMultiInstallProgressUI(versions)
+		progressUI.Start()
+	}
+
+	ctx, stop := newInterruptContext()
+	defer stop()
+	results := appService.InstallOnlineBatch(ctx, versions, buildInstallOptions(), parallelInstalls, progressUI)
+
+	if progressUI != nil {
+		progressUI.Stop()
+	}
+	if ctx.Err() != nil {
+		displayBatchInstallResults(results)
+		exitIfInterrupted(ctx, nil)
+	}
+
+	if failed := displayBatchInstallResults(results); failed > 0 {
+		PrintError(fmt.Sprintf("%d/%d 个版本安装失败", failed, len(results)))
+		os.Exit(1)
+	}
+
+	PrintSuccess(fmt.Sprintf("全部 %d 个版本安装成功", len(results)))
+}
+
+// displayBatchInstallResults 输出批量安装结果汇总，返回失败数量
+func displayBatchInstallResults(results []*model.InstallationResult) int {
+	failed := 0
+
+	PrintInfo("")
+	rows := make([][]string, len(results))
+	for i, result := range results {
+		if result.Success {
+			rows[i] = []string{result.Version, "成功", result.Duration.Truncate(time.Millisecond).String(), result.Path}
+		} else {
+			failed++
+			rows[i] = []string{result.Version, "失败", "-", result.Error}
+		}
+	}
+	printTable([]string{"版本", "状态", "耗时", "详情"}, rows, func(row, col int) Color {
+		if col != 1 {
+			return ""
+		}
+		if results[row].Success {
+			return ColorGreen
+		}
+		return ColorRed
+	})
+
+	for _, result := range results {
+		for _, warning := range result.Warnings {
+			PrintWarning(fmt.Sprintf("警告: Go %s %s", result.Version, warning))
+		}
+	}
+
+	return failed
+}
+
+// uniqueVersions 去除重复的版本号，保持原有顺序
+func uniqueVersions(versions []string) []string {
+	seen := make(map[string]bool, len(versions))
+	result := make([]string, 0, len(versions))
+	for _, version := range versions {
+		if !seen[version] {
+			seen[version] = true
+			result = append(result, version)
+		}
+	}
+	return result
+}
+
+// runArchiveInstall 从本地压缩包安装，版本号由压缩包自动识别
+func runArchiveInstall(args []string) {
+	if len(args) > 0 {
+		PrintError("使用 --from-archive 时无需指定版本号，版本将从压缩包中自动识别")
+		os.Exit(1)
+	}
+
+	appService, err := application.NewVersionAppService()
+	if err != nil {
+		PrintError(fmt.Sprintf("初始化应用服务失败: %s", err))
+		os.Exit(1)
+	}
+
+	PrintInfo(fmt.Sprintf("开始从压缩包安装Go: %s", fromArchive))
+
+	var progressUI *ui.InstallProgressUI
+	if !noProgress {
+		progressUI = ui.NewInstallProgressUI()
+		progressUI.Start()
+		defer progressUI.Stop()
+	}
+
+	ctx, stop := newInterruptContext()
+	defer stop()
+	result, err := appService.InstallFromArchive(ctx, fromArchive, buildInstallOptions(), progressUI)
+	if err != nil {
+		if progressUI != nil {
+			progressUI.PrintError(fmt.Sprintf("安装失败: %s", err))
+		} else {
+			PrintError(fmt.Sprintf("安装失败: %s", err))
+		}
+		exitIfInterrupted(ctx, err)
+		os.Exit(1)
+	}
+
+	displayInstallResult(result, progressUI)
+}
+
+// runSourceInstall 从源码编译安装
+func runSourceInstall(args []string) {
+	if len(args) > 0 {
+		PrintError("使用 --from-source 时无需指定版本号，请将版本号或源码目录作为 --from-source 的参数")
+		os.Exit(1)
+	}
+	if installOS != "" || installArch != "" {
+		PrintError("--from-source 不能与 --os、--arch 同时使用")
+		os.Exit(1)
+	}
+
+	// 验证镜像选项
+	if err := validateMirrorOptions(); err != nil {
+		PrintError(err.Error())
+		os.Exit(1)
+	}
+
+	appService, err := application.NewVersionAppService()
+	if err != nil {
+		PrintError(fmt.Sprintf("初始化应用服务失败: %s", err))
+		os.Exit(1)
+	}
+
+	PrintInfo(fmt.Sprintf("开始从源码编译安装Go: %s", fromSource))
+
+	var progressUI *ui.InstallProgressUI
+	if !noProgress {
+		progressUI = ui.NewInstallProgressUI()
+		progressUI.Start()
+		defer progressUI.Stop()
+	}
+
+	ctx, stop := newInterruptContext()
+	defer stop()
+	result, err := appService.InstallFromSource(ctx, fromSource, buildInstallOptions(), progressUI)
+	if err != nil {
+		if progressUI != nil {
+			progressUI.PrintError(fmt.Sprintf("安装失败: %s", err))
+		} else {
+			PrintError(fmt.Sprintf("安装失败: %s", err))
+		}
+		exitIfInterrupted(ctx, err)
+		os.Exit(1)
+	}
+
+	displayInstallResult(result, progressUI)
+}
+
 func runLocalInstall(appService *application.VersionAppService, version string) {
 	PrintInfo(fmt.Sprintf("正在注册本地Go版本 %s...", version))
 
@@ -157,7 +442,7 @@ func runLocalInstall(appService *application.VersionAppService, version string)
 	}
 
 	// 执行本地导入
-	importedVersion, err := appService.ImportLocal(installPath)
+	importedVersion, err := appService.ImportLocal(installPath, installName)
 	if err != nil {
 		PrintError(fmt.Sprintf("导入本地版本失败: %s", err))
 		os.Exit(1)
@@ -184,13 +469,24 @@ func displayInstallResult(result *model.InstallationResult, progressUI *ui.Insta
 			downloadSize := formatBytes(result.DownloadInfo.Size)
 			downloadSpeed := formatBytes(int64(result.DownloadInfo.Speed)) + "/s"
 			PrintInfo(fmt.Sprintf("下载大小: %s (平均速度: %s)", downloadSize, downloadSpeed))
+			if len(result.DownloadInfo.AttemptedMirrors) > 1 {
+				PrintInfo(fmt.Sprintf("下载镜像: %s（已尝试 %s）", result.DownloadInfo.Mirror, strings.Join(result.DownloadInfo.AttemptedMirrors, ", ")))
+			}
 		}
 
 		if result.ExtractInfo != nil {
 			PrintInfo(fmt.Sprintf("解压文件数: %d", result.ExtractInfo.FileCount))
 		}
+		if len(result.Tools) > 0 {
+			PrintInfo(fmt.Sprintf("默认工具: %s", formatInstalledTools(result.Tools)))
+		}
+		printWarnings(result.Warnings)
 
-		PrintInfo("使用 'go-version use " + result.Version + "' 来切换到此版本")
+		if options := buildInstallOptions(); options.TargetSystem != nil && (options.TargetSystem.OS != runtime.GOOS || options.TargetSystem.Arch != runtime.GOARCH) {
+			PrintInfo("其他平台的Go不会加入版本列表，请将安装目录复制到目标机器后使用")
+		} else {
+			PrintInfo("使用 'go-version use " + result.Version + "' 来切换到此版本")
+		}
 	} else {
 		message := fmt.Sprintf("Go %s 安装失败: %s", result.Version, result.Error)
 		if progressUI != nil {
@@ -201,6 +497,22 @@ func displayInstallResult(result *model.InstallationResult, progressUI *ui.Insta
 	}
 }
 
+// formatInstalledTools 格式化工具列表，如 gopls@latest, dlv@v1.23.0
+func formatInstalledTools(tools []model.InstalledTool) string {
+	names := make([]string, len(tools))
+	for i, tool := range tools {
+		names[i] = path.Base(tool.Package) + "@" + tool.Version
+	}
+	return strings.Join(names, ", ")
+}
+
+// printWarnings 输出钩子和默认工具失败的警告
+func printWarnings(warnings []string) {
+	for _, warning := range warnings {
+		PrintWarning("警告: " + warning)
+	}
+}
+
 func isValidVersion(version string) bool {
 	// 简单的版本号验证
 	if len(version) == 0 {
@@ -0,0 +1,136 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"version-list/internal/application"
+	"version-list/internal/domain/model"
+	"version-list/internal/domain/service"
+
+	"github.com/spf13/cobra"
+)
+
+// 隔离命令选项变量
+var (
+	isolateModCache bool
+	gcDryRun        bool
+)
+
+var isolateCmd = &cobra.Command{
+	Use:   "isolate [global|version]",
+	Short: "设置 GOBIN、GOCACHE 是否按版本隔离",
+	Long: `查看或设置 GOBIN、GOCACHE 的隔离方式。
+
+隔离方式：
+  global     所有版本共用（默认），GOBIN 为当前版本的 GOROOT/bin，GOCACHE 使用 go 的默认值
+  version    每个版本使用 ~/.go-version/isolated/<版本> 下独立的 bin 和 cache 目录，
+             使用 --modcache 时 GOMODCACHE 也按版本隔离
+
+切换版本、运行钩子、安装默认工具、'go-version exec'、shell 钩子（'go-version env hook'）和 go 垫片使用对应版本的目录，
+已移除版本的目录使用 'go-version gc' 清理。
+
+示例：
+  go-version isolate                      # 查看当前的隔离方式
+  go-version isolate version              # 按版本隔离 GOBIN 和 GOCACHE
+  go-version isolate version --modcache   # 同时隔离 GOMODCACHE
+  go-version isolate global               # 恢复为所有版本共用`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  runIsolateCommand,
+}
+
+var gcCmd = &cobra.Command{
+	Use:   "gc",
+	Short: "清理已移除版本的隔离目录",
+	Long: `删除 ~/.go-version/isolated 中不属于任何已安装版本的目录（GOBIN、GOCACHE 和 GOMODCACHE）。
+
+示例：
+  go-version gc              # 清理已移除版本的目录
+  go-version gc --dry-run    # 只列出将被清理的目录`,
+	Args: cobra.NoArgs,
+	Run:  runGCCommand,
+}
+
+func init() {
+	isolateCmd.Flags().BoolVar(&isolateModCache, "modcache", false, "按版本隔离时同时隔离 GOMODCACHE")
+	gcCmd.Flags().BoolVar(&gcDryRun, "dry-run", false, "只列出将被清理的目录，不删除")
+}
+
+func runIsolateCommand(cmd *cobra.Command, args []string) {
+	appService, err := application.NewVersionAppService()
+	if err != nil {
+		PrintError(fmt.Sprintf("初始化应用服务失败: %s", err))
+		os.Exit(1)
+	}
+
+	var env *model.Environment
+	if len(args) == 0 {
+		env, err = appService.Environment()
+	} else {
+		mode, parseErr := model.ParseIsolationMode(args[0])
+		if parseErr != nil {
+			PrintError(parseErr.Error())
+			os.Exit(1)
+		}
+		if isolateModCache && mode != model.IsolationVersion {
+			PrintError("--modcache 只能与 version 隔离方式一起使用")
+			os.Exit(1)
+		}
+		env, err = appService.SetIsolation(mode, isolateModCache)
+		if err == nil {
+			PrintSuccess(fmt.Sprintf("隔离方式已设置为 %s", mode))
+		}
+	}
+	if err != nil {
+		PrintError(err.Error())
+		os.Exit(1)
+	}
+
+	mode := env.Isolation
+	if mode == "" {
+		mode = model.IsolationGlobal
+	}
+	PrintInfo(fmt.Sprintf("隔离方式: %s", mode))
+	if env.IsVersionIsolated() {
+		PrintInfo(fmt.Sprintf("隔离目录: %s", service.IsolationDir()))
+		PrintInfo(fmt.Sprintf("隔离 GOMODCACHE: %t", env.IsolateModCache))
+	}
+	if env.GOBIN != "" {
+		PrintInfo(fmt.Sprintf("GOBIN: %s", env.GOBIN))
+	}
+	if env.GOCACHE != "" {
+		PrintInfo(fmt.Sprintf("GOCACHE: %s", env.GOCACHE))
+	}
+	if env.GOMODCACHE != "" {
+		PrintInfo(fmt.Sprintf("GOMODCACHE: %s", env.GOMODCACHE))
+	}
+}
+
+func runGCCommand(cmd *cobra.Command, args []string) {
+	appService, err := application.NewVersionAppService()
+	if err != nil {
+		PrintError(fmt.Sprintf("初始化应用服务失败: %s", err))
+		os.Exit(1)
+	}
+
+	orphaned, err := appService.CollectGarbage(gcDryRun)
+	if err != nil {
+		PrintError(fmt.Sprintf("清理失败: %s", err))
+		os.Exit(1)
+	}
+	if len(orphaned) == 0 {
+		PrintInfo("没有需要清理的目录")
+		return
+	}
+
+	var freed int64
+	for _, env := range orphaned {
+		freed += env.Size
+		PrintInfo(fmt.Sprintf("  %s  %s (%s)", env.Version, env.Path, formatBytes(env.Size)))
+	}
+	if gcDryRun {
+		PrintInfo(fmt.Sprintf("将清理 %d 个目录，释放 %s", len(orphaned), formatBytes(freed)))
+		return
+	}
+	PrintSuccess(fmt.Sprintf("已清理 %d 个目录，释放 %s", len(orphaned), formatBytes(freed)))
+}
@@ -13,7 +13,8 @@ import (
 var listCmd = &cobra.Command{
 	Use:   "list",
 	Short: "列出所有已安装的Go版本",
-	Long:  `列出所有已安装的Go版本，并标记当前使用的版本`,
+	Long: `列出所有已安装的Go版本，并标记当前使用的版本。
+版本按Go发布版本排序，自定义名称的工具链会显示其Go发布版本。`,
 	Run: func(cmd *cobra.Command, args []string) {
 		appService, err := application.NewVersionAppService()
 		if err != nil {
@@ -40,7 +41,11 @@ var listCmd = &cobra.Command{
 			if v.IsActive {
 				status = Colorize("当前使用", ColorGreen)
 			}
-			fmt.Fprintf(w, "%s	%s	%s \r\n", v.Version, v.Path, status)
+			name := v.Version
+			if v.Release != "" && v.Release != v.Version {
+				name = fmt.Sprintf("%s (go%s)", v.Version, v.Release)
+			}
+			fmt.Fprintf(w, "%s	%s	%s \r\n", name, v.Path, status)
 		}
 		w.Flush()
 	},
@@ -0,0 +1,241 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"version-list/internal/application"
+	"version-list/internal/domain/model"
+	"version-list/internal/domain/service"
+	"version-list/internal/interface/ui"
+
+	"github.com/spf13/cobra"
+)
+
+// lock 命令选项变量
+var (
+	lockOutput     string
+	lockPlatforms  []string
+	lockCatalog    string
+	lockAllowCache bool
+)
+
+var lockCmd = &cobra.Command{
+	Use:   "lock [version...]",
+	Short: "生成锁定Go版本、平台和校验和的团队锁文件",
+	Long: `生成 go-version.lock，锁定Go版本及各平台压缩包的SHA-256和来源地址，
+提交到代码仓库后，团队成员和CI可以使用 install --locked 安装完全相同的工具链。
+
+校验和取自官方发布目录。发布目录中没有或无法访问时，只有指定 --allow-cache 才使用
+下载缓存中压缩包的校验和，这些条目在锁文件中标记为 unverified（未经官方确认）。
+未指定版本时沿用已有锁文件中的版本（没有锁文件时使用已安装的版本），
+未指定 --platforms 时沿用已有锁文件中的平台（没有锁文件时使用当前平台）。
+
+示例：
+  go-version lock 1.22.7 --platforms linux/amd64,darwin/arm64,windows/amd64
+  go-version lock                                       # 刷新已有的锁文件
+  go-version install --locked                           # 安装锁文件中的所有版本`,
+	Args: cobra.ArbitraryArgs,
+	Run:  runLockCommand,
+}
+
+func init() {
+	lockCmd.Flags().StringVarP(&lockOutput, "output", "o", service.DefaultLockFileName, "锁文件路径")
+	lockCmd.Flags().StringSliceVar(&lockPlatforms, "platforms", nil, "锁定的平台，如 linux/amd64,darwin/arm64")
+	lockCmd.Flags().StringVar(&lockCatalog, "catalog", service.DefaultReleaseCatalogURL, "官方发布目录地址")
+	lockCmd.Flags().BoolVar(&lockAllowCache, "allow-cache", false, "发布目录中没有时使用下载缓存中压缩包的校验和（未经官方确认）")
+}
+
+func runLockCommand(cmd *cobra.Command, args []string) {
+	versions := make([]string, 0, len(args))
+	for _, version := range uniqueVersions(args) {
+		version = strings.TrimPrefix(version, "go")
+		if !isValidVersion(version) {
+			PrintError(fmt.Sprintf("无效的版本号格式: %s", version))
+			os.Exit(1)
+		}
+		versions = append(versions, version)
+	}
+	for _, platform := range lockPlatforms {
+		if _, _, err := service.ParsePlatform(platform); err != nil {
+			PrintError(err.Error())
+			os.Exit(1)
+		}
+	}
+
+	// 沿用已有锁文件中的版本和平台
+	existing, err := service.LoadLockFile(lockOutput)
+	switch {
+	case err == nil:
+		if len(versions) == 0 {
+			versions = existing.Versions()
+		}
+		if len(lockPlatforms) == 0 {
+			lockPlatforms = existing.Platforms()
+		}
+	case !errors.Is(err, os.ErrNotExist):
+		PrintError(err.Error())
+		os.Exit(1)
+	}
+
+	appService, err := application.NewVersionAppService()
+	if err != nil {
+		PrintError(fmt.Sprintf("初始化应用服务失败: %s", err))
+		os.Exit(1)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	PrintInfo("正在生成锁文件...")
+	lock, err := appService.GenerateLock(ctx, &service.LockOptions{
+		Versions:   versions,
+		Platforms:  lockPlatforms,
+		CatalogURL: lockCatalog,
+		AllowCache: lockAllowCache,
+	})
+	if err != nil {
+		PrintError(fmt.Sprintf("生成锁文件失败: %s", err))
+		os.Exit(1)
+	}
+
+	if err := service.SaveLockFile(lockOutput, lock); err != nil {
+		PrintError(err.Error())
+		os.Exit(1)
+	}
+
+	PrintSuccess(fmt.Sprintf("锁文件已保存: %s（%d 个版本，%d 个压缩包）", lockOutput, len(lock.Versions()), len(lock.Entries)))
+	for _, entry := range lock.Entries {
+		if entry.Unverified {
+			PrintWarning(fmt.Sprintf("  %-10s %-16s %s（取自下载缓存，未经官方确认）", entry.Version, entry.Platform(), shortChecksum(entry.SHA256)))
+			continue
+		}
+		PrintInfo(fmt.Sprintf("  %-10s %-16s %s", entry.Version, entry.Platform(), shortChecksum(entry.SHA256)))
+	}
+}
+
+// runLockedInstall 按锁文件安装，未指定版本时安装锁文件中的所有版本，完成后报告与锁文件的差异
+func runLockedInstall(args []string) {
+	if skipVerification {
+		PrintError("--locked 不能与 --skip-verification 同时使用")
+		os.Exit(1)
+	}
+	if installName != "" {
+		PrintError("--locked 不能与 --name 同时使用")
+		os.Exit(1)
+	}
+	validateTargetPlatform(installOS, installArch)
+
+	lock, err := service.LoadLockFile(lockFilePath)
+	if err != nil {
+		PrintError(err.Error())
+		PrintInfo("使用 'go-version lock <版本>' 生成锁文件")
+		os.Exit(1)
+	}
+
+	appService, err := application.NewVersionAppService()
+	if err != nil {
+		PrintError(fmt.Sprintf("初始化应用服务失败: %s", err))
+		os.Exit(1)
+	}
+
+	// 未指定版本时安装锁文件中的所有版本，已安装的版本由差异报告检查
+	versions := uniqueVersions(args)
+	skipInstalled := len(versions) == 0
+	if skipInstalled {
+		versions = lock.Versions()
+	}
+	installed := make(map[string]bool)
+	if skipInstalled && !forceInstall {
+		list, err := appService.List()
+		if err != nil {
+			PrintError(fmt.Sprintf("获取版本列表失败: %s", err))
+			os.Exit(1)
+		}
+		for _, version := range list {
+			installed[version.Version] = true
+		}
+	}
+
+	ctx, stop := newInterruptContext()
+	defer stop()
+	failed := 0
+	for _, version := range versions {
+		if installed[version] {
+			PrintInfo(fmt.Sprintf("Go %s 已安装，跳过", version))
+			continue
+		}
+		PrintInfo(fmt.Sprintf("按锁文件安装Go %s...", version))
+
+		var progressUI *ui.InstallProgressUI
+		if !noProgress {
+			progressUI = ui.NewInstallProgressUI()
+			progressUI.Start()
+		}
+		result, err := appService.InstallLocked(ctx, version, lock, buildInstallOptions(), progressUI)
+		if err != nil {
+			if progressUI != nil {
+				progressUI.PrintError(fmt.Sprintf("安装失败: %s", err))
+				progressUI.Stop()
+			} else {
+				PrintError(fmt.Sprintf("安装失败: %s", err))
+			}
+			if ctx.Err() != nil {
+				exitIfInterrupted(ctx, err)
+			}
+			failed++
+			continue
+		}
+		displayInstallResult(result, progressUI)
+		if progressUI != nil {
+			progressUI.Stop()
+		}
+	}
+
+	if displayLockDrift(appService, lock) {
+		failed++
+	}
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// displayLockDrift 输出已安装版本与锁文件的差异，存在校验和不一致时返回 true
+func displayLockDrift(appService *application.VersionAppService, lock *model.LockFile) bool {
+	drifts, err := appService.CheckLockDrift(lock)
+	if err != nil {
+		PrintError(fmt.Sprintf("检查锁文件差异失败: %s", err))
+		return true
+	}
+	if len(drifts) == 0 {
+		PrintSuccess("已安装的版本与锁文件一致")
+		return false
+	}
+
+	mismatch := false
+	PrintWarning(fmt.Sprintf("发现 %d 处与锁文件的差异:", len(drifts)))
+	for _, drift := range drifts {
+		if drift.Actual != "" {
+			mismatch = true
+			PrintError(fmt.Sprintf("  Go %s (%s): %s（锁文件 %s，已安装 %s）", drift.Version, drift.Platform, drift.Reason, shortChecksum(drift.Expected), shortChecksum(drift.Actual)))
+			continue
+		}
+		PrintWarning(fmt.Sprintf("  Go %s (%s): %s", drift.Version, drift.Platform, drift.Reason))
+	}
+	if mismatch {
+		PrintInfo("校验和不一致的版本可以使用 'go-version install <版本> --locked --force' 重新安装")
+	}
+	return mismatch
+}
+
+// shortChecksum 获取校验和的前12位用于显示
+func shortChecksum(checksum string) string {
+	if len(checksum) > 12 {
+		return checksum[:12]
+	}
+	return checksum
+}
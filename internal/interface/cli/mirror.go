@@ -4,7 +4,10 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"runtime"
 	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"version-list/internal/domain/service"
@@ -23,6 +26,19 @@ var (
 	mirrorDescription string
 	mirrorRegion      string
 	mirrorPriority    int
+	mirrorCredential  string
+	mirrorURLTemplate string
+	mirrorChecksumURL string
+	// mirror audit 命令选项
+	mirrorAuditReleases  int
+	mirrorAuditPlatforms []string
+	mirrorAuditChecksum  bool
+	mirrorAuditDemote    bool
+	mirrorAuditCatalog   string
+	// mirror export/import 命令选项
+	mirrorExportOutput          string
+	mirrorImportReplace         bool
+	mirrorImportKeepCredentials bool
 )
 
 var mirrorCmd = &cobra.Command{
@@ -42,7 +58,16 @@ var mirrorCmd = &cobra.Command{
   go-version mirror test                    # 测试所有镜像源速度
   go-version mirror test --name goproxy-cn # 测试指定镜像源
   go-version mirror fastest                # 选择最快的镜像源
-  go-version mirror add                     # 添加自定义镜像源
+  go-version mirror audit                  # 检查镜像是否落后或文件不一致
+  go-version mirror export -o team.json    # 导出自定义镜像配置
+  go-version mirror import team.json       # 导入团队共享的镜像配置
+  go-version mirror disable aliyun         # 禁用镜像源（不删除）
+  go-version mirror set-priority corp 0    # 调整镜像源优先级
+
+组织镜像配置：
+  /etc/go-version/mirrors.json（Windows 为 %ProgramData%\go-version\mirrors.json，
+  可通过 GO_VERSION_ORG_MIRRORS 环境变量指定）中的镜像和覆盖项作为底层配置只读加载，
+  用户配置中的同名镜像和覆盖项优先。  go-version mirror add                     # 添加自定义镜像源
   go-version mirror remove --name custom   # 移除自定义镜像源`,
 }
 
@@ -62,7 +87,9 @@ var mirrorTestCmd = &cobra.Command{
 	Short: "测试镜像源的连接速度和可用性",
 	Long: `测试镜像源的连接速度和可用性。
 
-可以测试所有镜像源或指定的镜像源。
+可以测试所有镜像源或指定的镜像源。测试时下载镜像上Go压缩包的前2MB，
+记录首字节延迟和吞吐量，并根据最近10次测试的成功率、吞吐量和延迟计算评分。
+测试记录保存在镜像配置文件中，--auto-mirror 和 mirror fastest 按评分选择镜像。
 
 选项：
   --name       指定要测试的镜像源名称
@@ -74,7 +101,9 @@ var mirrorTestCmd = &cobra.Command{
 var mirrorFastestCmd = &cobra.Command{
 	Use:   "fastest",
 	Short: "自动选择最快的镜像源",
-	Long: `自动测试所有镜像源并选择响应最快的一个。
+	Long: `自动测试所有镜像源的下载吞吐量，并选择评分最高的一个。
+
+评分综合最近10次测试的成功率、吞吐量和延迟，5分钟内测试过的镜像直接使用上次结果。
 
 选项：
   --timeout    测试超时时间（秒）
@@ -93,9 +122,19 @@ var mirrorAddCmd = &cobra.Command{
   --description 镜像源描述
   --region      镜像源地区
   --priority    镜像源优先级（数字越小优先级越高）
+  --credential  引用 ~/.go-version/credentials.json 中的凭据名称（凭据不会写入镜像配置）
+  --url-template           非标准目录结构的下载地址模板
+  --checksum-url-template  SHA-256校验和文件的地址模板
+
+URL模板支持的占位符：{version} {os} {arch} {ext} {filename}，
+模板必须包含 {version} 或 {filename}。
 
 示例：
-  go-version mirror add --name mycompany --url https://mirrors.mycompany.com/golang/ --description "公司内部镜像" --region "内网" --priority 1`,
+  go-version mirror add --name mycompany --url https://mirrors.mycompany.com/golang/ --description "公司内部镜像" --region "内网" --priority 1
+  go-version mirror add --name internal --url https://artifacts.corp/golang/ --description "内部制品库" --region "内网" --credential corp
+  go-version mirror add --name layout --url https://repo.corp/go/ --description "按平台分目录" --region "内网" \
+    --url-template "https://repo.corp/go/{version}/{os}/{arch}/go.{ext}?download=1" \
+    --checksum-url-template "https://repo.corp/go/{version}/{os}/{arch}/go.{ext}.sha256"`,
 	Run: runMirrorAddCommand,
 }
 
@@ -111,6 +150,95 @@ var mirrorRemoveCmd = &cobra.Command{
 	Run: runMirrorRemoveCommand,
 }
 
+var mirrorAuditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "检查镜像源是否落后或文件不一致",
+	Long: `对照官方发布目录检查镜像源：
+- 是否提供最新的若干个稳定版本在指定平台上的压缩包（缺少时视为落后）
+- 文件大小是否与官方一致；使用 --checksum 时完整下载文件并校验SHA-256
+
+发现落后或不一致的镜像时以非零状态退出，使用 --demote 将这些镜像的优先级
+调整到所有镜像之后，安装和下载失败切换时将最后使用它们。
+
+选项：
+  --name        只检查指定的镜像源
+  --releases    检查的最新稳定版本数量
+  --platforms   检查的平台（os/arch），多个平台用逗号分隔，默认为当前平台
+  --checksum    完整下载文件并校验SHA-256（耗时较长）
+  --demote      降低落后或不一致镜像的优先级并保存配置
+  --timeout     检查超时时间（秒）
+
+示例：
+  go-version mirror audit
+  go-version mirror audit --releases 5 --platforms linux/amd64,darwin/arm64
+  go-version mirror audit --name aliyun --checksum
+  go-version mirror audit --demote`,
+	Run: runMirrorAuditCommand,
+}
+
+var mirrorExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "导出自定义镜像配置",
+	Long: `导出用户配置中的自定义镜像以及启用状态、优先级覆盖项，不包括测速记录。
+
+导出的文件可以通过 mirror import 导入，也可以直接作为组织镜像配置文件使用。
+凭据只导出引用名称，每个成员需要在自己的凭据文件中配置对应的凭据。
+
+示例：
+  go-version mirror export                 # 输出到标准输出
+  go-version mirror export -o team.json    # 保存到文件`,
+	Args: cobra.NoArgs,
+	Run:  runMirrorExportCommand,
+}
+
+var mirrorImportCmd = &cobra.Command{
+	Use:   "import <file|url>",
+	Short: "导入镜像配置",
+	Long: `从本地文件或 http(s) 地址导入镜像配置。
+
+默认按名称合并：导入的镜像和覆盖项取代同名的现有配置，其余配置保持不变。
+使用 --replace 时替换所有自定义镜像和覆盖项。
+
+从 http(s) 地址导入时默认移除镜像的凭据引用（credential_ref），避免远程配置把本地凭据发送到其他地址；
+确认来源可信后使用 --keep-credentials 保留。
+
+示例：
+  go-version mirror import team.json
+  go-version mirror import https://intranet.corp/go-version/mirrors.json --replace
+  go-version mirror import https://intranet.corp/go-version/mirrors.json --keep-credentials`,
+	Args: cobra.ExactArgs(1),
+	Run:  runMirrorImportCommand,
+}
+
+var mirrorEnableCmd = &cobra.Command{
+	Use:   "enable <name>",
+	Short: "启用镜像源",
+	Args:  cobra.ExactArgs(1),
+	Run:   runMirrorEnableCommand,
+}
+
+var mirrorDisableCmd = &cobra.Command{
+	Use:   "disable <name>",
+	Short: "禁用镜像源",
+	Long: `禁用镜像源而不删除它，预设镜像和组织镜像同样可以禁用。
+
+禁用的镜像不参与自动选择和下载失败时的切换，使用 --mirror 指定时将报错。`,
+	Args: cobra.ExactArgs(1),
+	Run:  runMirrorDisableCommand,
+}
+
+var mirrorSetPriorityCmd = &cobra.Command{
+	Use:   "set-priority <name> <priority>",
+	Short: "设置镜像源优先级",
+	Long: `设置镜像源的优先级（数字越小优先级越高），预设镜像和组织镜像同样可以设置。
+
+示例：
+  go-version mirror set-priority corp 0
+  go-version mirror set-priority official 10`,
+	Args: cobra.ExactArgs(2),
+	Run:  runMirrorSetPriorityCommand,
+}
+
 var mirrorValidateCmd = &cobra.Command{
 	Use:   "validate",
 	Short: "验证镜像源的可用性",
@@ -129,10 +257,18 @@ func init() {
 	mirrorCmd.AddCommand(mirrorAddCmd)
 	mirrorCmd.AddCommand(mirrorRemoveCmd)
 	mirrorCmd.AddCommand(mirrorValidateCmd)
+	mirrorCmd.AddCommand(mirrorAuditCmd)
+	mirrorCmd.AddCommand(mirrorExportCmd)
+	mirrorCmd.AddCommand(mirrorImportCmd)
+	mirrorCmd.AddCommand(mirrorEnableCmd)
+	mirrorCmd.AddCommand(mirrorDisableCmd)
+	mirrorCmd.AddCommand(mirrorSetPriorityCmd)
+
+	// 所有镜像子命令共用的配置文件路径，默认为 ~/.go-version/mirrors.json
+	mirrorCmd.PersistentFlags().StringVar(&mirrorConfigPath, "config", "", "指定镜像配置文件路径")
 
 	// mirror list 命令选项
 	mirrorListCmd.Flags().BoolVar(&mirrorShowDetails, "details", false, "显示详细信息")
-	mirrorListCmd.Flags().StringVar(&mirrorConfigPath, "config", "", "指定配置文件路径")
 
 	// mirror test 命令选项
 	mirrorTestCmd.Flags().StringVar(&mirrorName, "name", "", "指定要测试的镜像源名称")
@@ -149,6 +285,9 @@ func init() {
 	mirrorAddCmd.Flags().StringVar(&mirrorDescription, "description", "", "镜像源描述（必需）")
 	mirrorAddCmd.Flags().StringVar(&mirrorRegion, "region", "", "镜像源地区（必需）")
 	mirrorAddCmd.Flags().IntVar(&mirrorPriority, "priority", 100, "镜像源优先级")
+	mirrorAddCmd.Flags().StringVar(&mirrorCredential, "credential", "", "镜像凭据引用名称")
+	mirrorAddCmd.Flags().StringVar(&mirrorURLTemplate, "url-template", "", "下载地址模板（如 https://mirror/{version}/{os}/{arch}/go.{ext}）")
+	mirrorAddCmd.Flags().StringVar(&mirrorChecksumURL, "checksum-url-template", "", "SHA-256校验和文件地址模板")
 	mirrorAddCmd.MarkFlagRequired("name")
 	mirrorAddCmd.MarkFlagRequired("url")
 	mirrorAddCmd.MarkFlagRequired("description")
@@ -161,25 +300,28 @@ func init() {
 	// mirror validate 命令选项
 	mirrorValidateCmd.Flags().StringVar(&mirrorName, "name", "", "要验证的镜像源名称（必需）")
 	mirrorValidateCmd.MarkFlagRequired("name")
+
+	// mirror audit 命令选项
+	mirrorAuditCmd.Flags().StringVar(&mirrorName, "name", "", "只检查指定的镜像源")
+	mirrorAuditCmd.Flags().IntVar(&mirrorAuditReleases, "releases", 3, "检查的最新稳定版本数量")
+	mirrorAuditCmd.Flags().StringSliceVar(&mirrorAuditPlatforms, "platforms", nil, "检查的平台，如 linux/amd64,darwin/arm64")
+	mirrorAuditCmd.Flags().BoolVar(&mirrorAuditChecksum, "checksum", false, "完整下载文件并校验SHA-256")
+	mirrorAuditCmd.Flags().BoolVar(&mirrorAuditDemote, "demote", false, "降低落后或不一致镜像的优先级")
+	mirrorAuditCmd.Flags().StringVar(&mirrorAuditCatalog, "catalog", service.DefaultReleaseCatalogURL, "官方发布目录地址")
+	mirrorAuditCmd.Flags().IntVar(&mirrorTestTimeout, "timeout", 300, "检查超时时间（秒）")
+
+	// mirror export/import 命令选项
+	mirrorExportCmd.Flags().StringVarP(&mirrorExportOutput, "output", "o", "", "输出文件路径（默认输出到标准输出）")
+	mirrorImportCmd.Flags().BoolVar(&mirrorImportReplace, "replace", false, "替换所有自定义镜像和覆盖项，而不是合并")
+	mirrorImportCmd.Flags().BoolVar(&mirrorImportKeepCredentials, "keep-credentials", false, "从 http(s) 地址导入时保留镜像的凭据引用（确认来源可信后使用）")
 }
 
 func runMirrorListCommand(cmd *cobra.Command, args []string) {
 	// 创建镜像服务
-	var mirrorService service.MirrorService
-	var err error
+	mirrorService, _ := loadMirrorService()
 
-	if mirrorConfigPath != "" {
-		mirrorService, err = service.NewMirrorServiceWithConfig(mirrorConfigPath)
-		if err != nil {
-			PrintError(fmt.Sprintf("加载镜像配置失败: %s", err))
-			os.Exit(1)
-		}
-	} else {
-		mirrorService = service.NewMirrorService()
-	}
-
-	// 获取所有镜像
-	mirrors := mirrorService.GetAvailableMirrors()
+	// 获取所有镜像（包括已禁用的镜像）
+	mirrors := mirrorService.ListMirrors()
 
 	if len(mirrors) == 0 {
 		PrintInfo("没有可用的镜像源")
@@ -191,14 +333,24 @@ func runMirrorListCommand(cmd *cobra.Command, args []string) {
 
 	for i, mirror := range mirrors {
 		if mirrorShowDetails {
-			PrintInfo(fmt.Sprintf("%d. %s", i+1, mirror.Name))
+			PrintInfo(fmt.Sprintf("%d. %s%s", i+1, mirror.Name, mirrorStatusSuffix(mirror)))
 			PrintInfo(fmt.Sprintf("   描述: %s", mirror.Description))
 			PrintInfo(fmt.Sprintf("   地区: %s", mirror.Region))
 			PrintInfo(fmt.Sprintf("   URL: %s", mirror.BaseURL))
 			PrintInfo(fmt.Sprintf("   优先级: %d", mirror.Priority))
+			if mirror.CredentialRef != "" {
+				PrintInfo(fmt.Sprintf("   凭据: %s", mirror.CredentialRef))
+			}
+			if mirror.URLTemplate != "" {
+				PrintInfo(fmt.Sprintf("   URL模板: %s", mirror.URLTemplate))
+			}
+			if mirror.ChecksumURLTemplate != "" {
+				PrintInfo(fmt.Sprintf("   校验和模板: %s", mirror.ChecksumURLTemplate))
+			}
+			PrintInfo(fmt.Sprintf("   评分: %s", formatMirrorHealth(mirrorService, mirror.Name)))
 			PrintInfo("")
 		} else {
-			PrintInfo(fmt.Sprintf("  %-12s %s (%s)", mirror.Name, mirror.Description, mirror.Region))
+			PrintInfo(fmt.Sprintf("  %-12s %s (%s)%s", mirror.Name, mirror.Description, mirror.Region, mirrorStatusSuffix(mirror)))
 			PrintInfo(fmt.Sprintf("               %s", mirror.BaseURL))
 			PrintInfo("")
 		}
@@ -213,7 +365,7 @@ func runMirrorListCommand(cmd *cobra.Command, args []string) {
 
 func runMirrorTestCommand(cmd *cobra.Command, args []string) {
 	// 创建镜像服务
-	mirrorService := service.NewMirrorService()
+	mirrorService, configPath := loadMirrorService()
 	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(mirrorTestTimeout)*time.Second)
 	defer cancel()
 
@@ -240,12 +392,16 @@ func runMirrorTestCommand(cmd *cobra.Command, args []string) {
 	PrintInfo("正在测试镜像源...")
 	PrintInfo("")
 
-	// 测试镜像
+	// 测试镜像，未指定 --force 时使用5分钟内的测试结果
+	maxAge := service.DefaultBenchmarkCacheAge
+	if mirrorForceTest {
+		maxAge = 0
+	}
 	results := make([]*service.MirrorTestResult, 0, len(mirrors))
 	for _, mirror := range mirrors {
 		PrintInfo(fmt.Sprintf("测试 %s (%s)...", mirror.Name, mirror.Description))
 
-		result, err := mirrorService.TestMirrorSpeed(ctx, mirror)
+		result, err := mirrorService.BenchmarkMirror(ctx, mirror, maxAge)
 		if err != nil {
 			PrintError(fmt.Sprintf("  测试失败: %s", err))
 			continue
@@ -254,38 +410,38 @@ func runMirrorTestCommand(cmd *cobra.Command, args []string) {
 		results = append(results, result)
 
 		if result.Available {
-			PrintSuccess(fmt.Sprintf("  ✅ 可用 (响应时间: %v)", result.ResponseTime))
+			PrintSuccess(fmt.Sprintf("  ✅ 可用 (延迟: %v, 吞吐量: %s/s, 评分: %.1f)",
+				result.ResponseTime.Round(time.Millisecond), formatBytes(int64(result.Throughput)), result.Score))
 		} else {
 			errorMsg := "未知错误"
-			if result.Error != nil {
-				errorMsg = result.Error.Error()
+			if result.ErrorMessage != "" {
+				errorMsg = result.ErrorMessage
 			}
 			PrintError(fmt.Sprintf("  ❌ 不可用 (%s)", errorMsg))
 		}
 	}
 
+	// 保存测试记录用于镜像评分
+	if err := mirrorService.SaveConfig(configPath); err != nil {
+		PrintWarning(fmt.Sprintf("保存测试记录失败: %s", err))
+	}
+
 	// 显示测试总结
 	if len(results) > 1 {
 		PrintInfo("")
 		PrintInfo("测试结果总结:")
 
-		// 按响应时间排序
-		sort.Slice(results, func(i, j int) bool {
-			if !results[i].Available && results[j].Available {
-				return false
+		// 可用的镜像按评分排序
+		sort.SliceStable(results, func(i, j int) bool {
+			if results[i].Available != results[j].Available {
+				return results[i].Available
 			}
-			if results[i].Available && !results[j].Available {
-				return true
-			}
-			if results[i].Available && results[j].Available {
-				return results[i].ResponseTime < results[j].ResponseTime
-			}
-			return false
+			return results[i].Score > results[j].Score
 		})
 
 		for i, result := range results {
 			if result.Available {
-				PrintInfo(fmt.Sprintf("  %d. %s - %v", i+1, result.Mirror.Name, result.ResponseTime))
+				PrintInfo(fmt.Sprintf("  %d. %s - 评分 %.1f (%s/s)", i+1, result.Mirror.Name, result.Score, formatBytes(int64(result.Throughput))))
 			} else {
 				PrintInfo(fmt.Sprintf("  %d. %s - 不可用", i+1, result.Mirror.Name))
 			}
@@ -295,7 +451,7 @@ func runMirrorTestCommand(cmd *cobra.Command, args []string) {
 
 func runMirrorFastestCommand(cmd *cobra.Command, args []string) {
 	// 创建镜像服务
-	mirrorService := service.NewMirrorService()
+	mirrorService, configPath := loadMirrorService()
 	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(mirrorTestTimeout)*time.Second)
 	defer cancel()
 
@@ -318,13 +474,23 @@ func runMirrorFastestCommand(cmd *cobra.Command, args []string) {
 		PrintInfo("")
 	}
 
-	// 选择最快镜像
+	// 选择评分最高的镜像
 	fastest, err := mirrorService.SelectFastestMirror(ctx, mirrors)
+	if saveErr := mirrorService.SaveConfig(configPath); saveErr != nil {
+		PrintWarning(fmt.Sprintf("保存测试记录失败: %s", saveErr))
+	}
 	if err != nil {
 		PrintError(fmt.Sprintf("选择最快镜像失败: %s", err))
 		os.Exit(1)
 	}
 
+	if mirrorShowDetails {
+		for _, mirror := range mirrors {
+			PrintInfo(fmt.Sprintf("  %-12s %s", mirror.Name, formatMirrorHealth(mirrorService, mirror.Name)))
+		}
+		PrintInfo("")
+	}
+
 	PrintSuccess(fmt.Sprintf("最快的镜像源: %s", fastest.Name))
 	PrintInfo(fmt.Sprintf("描述: %s", fastest.Description))
 	PrintInfo(fmt.Sprintf("地区: %s", fastest.Region))
@@ -341,19 +507,23 @@ func runMirrorAddCommand(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
-	// 创建镜像服务（需要配置路径来保存自定义镜像）
-	configPath := mirrorConfigPath
-	if configPath == "" {
-		// 使用默认配置路径
-		configPath = getDefaultMirrorConfigPath()
+	// 验证URL模板
+	if mirrorURLTemplate != "" {
+		if err := service.ValidateURLTemplate(mirrorURLTemplate); err != nil {
+			PrintError(fmt.Sprintf("--url-template 无效: %s", err))
+			os.Exit(1)
+		}
 	}
-
-	mirrorService, err := service.NewMirrorServiceWithConfig(configPath)
-	if err != nil {
-		// 如果配置文件不存在，创建新的服务
-		mirrorService = service.NewMirrorService()
+	if mirrorChecksumURL != "" {
+		if err := service.ValidateURLTemplate(mirrorChecksumURL); err != nil {
+			PrintError(fmt.Sprintf("--checksum-url-template 无效: %s", err))
+			os.Exit(1)
+		}
 	}
 
+	// 创建镜像服务（需要配置路径来保存自定义镜像）
+	mirrorService, configPath := loadMirrorService()
+
 	// 检查镜像名称是否已存在
 	existing, err := mirrorService.GetMirrorByName(mirrorName)
 	if err == nil && existing != nil {
@@ -368,6 +538,10 @@ func runMirrorAddCommand(cmd *cobra.Command, args []string) {
 		Description: mirrorDescription,
 		Region:      mirrorRegion,
 		Priority:    mirrorPriority,
+		// 只保存凭据引用，凭据内容保存在独立的凭据文件中
+		CredentialRef:       mirrorCredential,
+		URLTemplate:         mirrorURLTemplate,
+		ChecksumURLTemplate: mirrorChecksumURL,
 	}
 
 	// 验证镜像可用性
@@ -411,20 +585,20 @@ func runMirrorAddCommand(cmd *cobra.Command, args []string) {
 	PrintInfo(fmt.Sprintf("描述: %s", newMirror.Description))
 	PrintInfo(fmt.Sprintf("地区: %s", newMirror.Region))
 	PrintInfo(fmt.Sprintf("优先级: %d", newMirror.Priority))
+	if newMirror.CredentialRef != "" {
+		PrintInfo(fmt.Sprintf("凭据: %s", newMirror.CredentialRef))
+	}
+	if newMirror.URLTemplate != "" {
+		PrintInfo(fmt.Sprintf("URL模板: %s", newMirror.URLTemplate))
+	}
+	if newMirror.ChecksumURLTemplate != "" {
+		PrintInfo(fmt.Sprintf("校验和模板: %s", newMirror.ChecksumURLTemplate))
+	}
 }
 
 func runMirrorRemoveCommand(cmd *cobra.Command, args []string) {
 	// 创建镜像服务
-	configPath := mirrorConfigPath
-	if configPath == "" {
-		configPath = getDefaultMirrorConfigPath()
-	}
-
-	mirrorService, err := service.NewMirrorServiceWithConfig(configPath)
-	if err != nil {
-		PrintError(fmt.Sprintf("加载镜像配置失败: %s", err))
-		os.Exit(1)
-	}
+	mirrorService, configPath := loadMirrorService()
 
 	// 检查镜像是否存在
 	mirror, err := mirrorService.GetMirrorByName(mirrorName)
@@ -472,7 +646,7 @@ func runMirrorRemoveCommand(cmd *cobra.Command, args []string) {
 
 func runMirrorValidateCommand(cmd *cobra.Command, args []string) {
 	// 创建镜像服务
-	mirrorService := service.NewMirrorService()
+	mirrorService, _ := loadMirrorService()
 
 	// 获取指定镜像
 	mirror, err := mirrorService.GetMirrorByName(mirrorName)
@@ -493,7 +667,228 @@ func runMirrorValidateCommand(cmd *cobra.Command, args []string) {
 	}
 
 	PrintSuccess(fmt.Sprintf("镜像源 '%s' 验证通过，可正常使用", mirror.Name))
-	PrintInfo(fmt.Sprintf("URL: %s", mirror.BaseURL))
+	PrintInfo(fmt.Sprintf("URL: %s", mirror.URLPattern()))
+}
+
+func runMirrorAuditCommand(cmd *cobra.Command, args []string) {
+	platforms := mirrorAuditPlatforms
+	if len(platforms) == 0 {
+		platforms = []string{runtime.GOOS + "/" + runtime.GOARCH}
+	}
+	for _, platform := range platforms {
+		if _, _, err := service.ParsePlatform(platform); err != nil {
+			PrintError(err.Error())
+			os.Exit(1)
+		}
+	}
+
+	mirrorService, configPath := loadMirrorService()
+	var mirrors []service.Mirror
+	if mirrorName != "" {
+		mirror, err := mirrorService.GetMirrorByName(mirrorName)
+		if err != nil {
+			PrintError(fmt.Sprintf("找不到镜像源 '%s': %s", mirrorName, err))
+			os.Exit(1)
+		}
+		mirrors = []service.Mirror{*mirror}
+	} else {
+		mirrors = mirrorService.GetAvailableMirrors()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(mirrorTestTimeout)*time.Second)
+	defer cancel()
+
+	PrintInfo("正在获取官方发布目录...")
+	catalog, err := service.FetchReleaseCatalog(ctx, service.DefaultHTTPClientFactory().Client(time.Minute), mirrorAuditCatalog)
+	if err != nil {
+		PrintError(err.Error())
+		os.Exit(1)
+	}
+	releases := service.LatestStableReleases(catalog, mirrorAuditReleases)
+	if len(releases) == 0 {
+		PrintError("发布目录中没有稳定版本")
+		os.Exit(1)
+	}
+
+	versions := make([]string, len(releases))
+	for i, release := range releases {
+		versions[i] = release.VersionNumber()
+	}
+	if unknown := releases[0].UnknownPlatforms(); len(unknown) > 0 {
+		PrintWarning(fmt.Sprintf("发布目录中有未收录的平台: %s", strings.Join(unknown, ", ")))
+	}
+	PrintInfo(fmt.Sprintf("检查版本: %s，平台: %s", strings.Join(versions, ", "), strings.Join(platforms, ", ")))
+	PrintInfo("")
+
+	options := &service.MirrorAuditOptions{
+		Releases:       releases,
+		Platforms:      platforms,
+		VerifyChecksum: mirrorAuditChecksum,
+	}
+
+	var problems []string
+	for _, mirror := range mirrors {
+		PrintInfo(fmt.Sprintf("检查 %s (%s)...", mirror.Name, mirror.Description))
+		report := mirrorService.AuditMirror(ctx, mirror, options)
+
+		switch {
+		case len(report.Issues) == 0:
+			PrintSuccess(fmt.Sprintf("  ✅ 正常（已检查 %d 个文件）", report.Checked))
+		case report.Stale() || report.Inconsistent():
+			problems = append(problems, mirror.Name)
+			PrintError(fmt.Sprintf("  ❌ %s", describeAuditReport(report)))
+		default:
+			PrintWarning(fmt.Sprintf("  ⚠️  部分文件无法检查（已检查 %d 个文件）", report.Checked))
+		}
+		for _, issue := range report.Issues {
+			PrintInfo(fmt.Sprintf("     %s %s: %s", issue.Version, issue.Platform, issue.Detail))
+		}
+	}
+
+	PrintInfo("")
+	if len(problems) == 0 {
+		PrintSuccess("所有镜像源均与官方发布一致")
+		return
+	}
+
+	PrintWarning(fmt.Sprintf("发现 %d 个落后或不一致的镜像源: %s", len(problems), strings.Join(problems, ", ")))
+	if mirrorAuditDemote {
+		for _, name := range problems {
+			priority, err := mirrorService.DemoteMirror(name)
+			if err != nil {
+				PrintError(fmt.Sprintf("调整镜像源 '%s' 的优先级失败: %s", name, err))
+				continue
+			}
+			PrintInfo(fmt.Sprintf("已将镜像源 '%s' 的优先级调整为 %d", name, priority))
+		}
+		if err := mirrorService.SaveConfig(configPath); err != nil {
+			PrintError(fmt.Sprintf("保存配置失败: %s", err))
+		}
+	} else {
+		PrintInfo("使用 --demote 降低这些镜像源的优先级")
+	}
+	os.Exit(1)
+}
+
+func runMirrorExportCommand(cmd *cobra.Command, args []string) {
+	mirrorService, _ := loadMirrorService()
+
+	data, err := mirrorService.ExportMirrors()
+	if err != nil {
+		PrintError(fmt.Sprintf("导出镜像配置失败: %s", err))
+		os.Exit(1)
+	}
+
+	if mirrorExportOutput == "" {
+		fmt.Println(string(data))
+		return
+	}
+	if err := os.WriteFile(mirrorExportOutput, append(data, '\n'), 0644); err != nil {
+		PrintError(fmt.Sprintf("写入文件失败: %s", err))
+		os.Exit(1)
+	}
+	PrintSuccess(fmt.Sprintf("镜像配置已导出到: %s", mirrorExportOutput))
+}
+
+func runMirrorImportCommand(cmd *cobra.Command, args []string) {
+	mirrorService, configPath := loadMirrorService()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	result, err := mirrorService.ImportMirrors(ctx, args[0], mirrorImportReplace, mirrorImportKeepCredentials)
+	if err != nil {
+		PrintError(fmt.Sprintf("导入镜像配置失败: %s", err))
+		os.Exit(1)
+	}
+	if err := mirrorService.SaveConfig(configPath); err != nil {
+		PrintError(fmt.Sprintf("保存配置失败: %s", err))
+		os.Exit(1)
+	}
+
+	mode := "合并"
+	if mirrorImportReplace {
+		mode = "替换"
+	}
+	PrintSuccess(fmt.Sprintf("已%s导入 %d 个镜像源", mode, result.Imported))
+	if len(result.DroppedCredentials) > 0 {
+		PrintWarning(fmt.Sprintf("已移除远程配置中镜像源的凭据引用: %s", strings.Join(result.DroppedCredentials, ", ")))
+		PrintInfo("确认来源可信后使用 --keep-credentials 重新导入")
+	}
+}
+
+func runMirrorEnableCommand(cmd *cobra.Command, args []string) {
+	setMirrorEnabled(args[0], true)
+}
+
+func runMirrorDisableCommand(cmd *cobra.Command, args []string) {
+	setMirrorEnabled(args[0], false)
+}
+
+// setMirrorEnabled 启用或禁用镜像源并保存配置
+func setMirrorEnabled(name string, enabled bool) {
+	mirrorService, configPath := loadMirrorService()
+
+	if err := mirrorService.SetMirrorEnabled(name, enabled); err != nil {
+		PrintError(err.Error())
+		os.Exit(1)
+	}
+	if err := mirrorService.SaveConfig(configPath); err != nil {
+		PrintError(fmt.Sprintf("保存配置失败: %s", err))
+		os.Exit(1)
+	}
+
+	if enabled {
+		PrintSuccess(fmt.Sprintf("已启用镜像源: %s", name))
+	} else {
+		PrintSuccess(fmt.Sprintf("已禁用镜像源: %s", name))
+	}
+}
+
+func runMirrorSetPriorityCommand(cmd *cobra.Command, args []string) {
+	priority, err := strconv.Atoi(args[1])
+	if err != nil {
+		PrintError(fmt.Sprintf("无效的优先级: %s", args[1]))
+		os.Exit(1)
+	}
+
+	mirrorService, configPath := loadMirrorService()
+	if err := mirrorService.SetMirrorPriority(args[0], priority); err != nil {
+		PrintError(err.Error())
+		os.Exit(1)
+	}
+	if err := mirrorService.SaveConfig(configPath); err != nil {
+		PrintError(fmt.Sprintf("保存配置失败: %s", err))
+		os.Exit(1)
+	}
+	PrintSuccess(fmt.Sprintf("已将镜像源 '%s' 的优先级设置为 %d", args[0], priority))
+}
+
+// mirrorStatusSuffix 获取镜像来源和启用状态的标注
+func mirrorStatusSuffix(mirror service.Mirror) string {
+	var labels []string
+	if mirror.Source == service.MirrorSourceOrg {
+		labels = append(labels, "组织配置")
+	}
+	if mirror.Disabled {
+		labels = append(labels, "已禁用")
+	}
+	if len(labels) == 0 {
+		return ""
+	}
+	return " [" + strings.Join(labels, ", ") + "]"
+}
+
+// describeAuditReport 概括审计报告中的问题
+func describeAuditReport(report *service.MirrorAuditReport) string {
+	var parts []string
+	if report.Stale() {
+		parts = append(parts, "落后（缺少最新版本）")
+	}
+	if report.Inconsistent() {
+		parts = append(parts, "文件与官方不一致")
+	}
+	return fmt.Sprintf("%s（已检查 %d 个文件，%d 个问题）", strings.Join(parts, "，"), report.Checked, len(report.Issues))
 }
 
 // 辅助函数
@@ -502,7 +897,32 @@ func startsWith(s, prefix string) bool {
 }
 
 func getDefaultMirrorConfigPath() string {
-	// 返回默认的镜像配置文件路径
-	// 这里简化处理，实际应该根据操作系统确定合适的配置目录
-	return "mirrors.json"
+	// 与安装时加载的镜像配置保持一致
+	return service.DefaultMirrorConfigPath()
+}
+
+// formatMirrorHealth 格式化镜像的评分和历史测速统计
+func formatMirrorHealth(mirrorService service.MirrorService, name string) string {
+	health, exists := mirrorService.GetMirrorHealth(name)
+	if !exists {
+		return "尚未测速（使用 'go-version mirror test' 测速）"
+	}
+	return fmt.Sprintf("%.1f（最近 %d 次: 成功率 %.0f%%, 平均延迟 %v, 平均吞吐量 %s/s）",
+		health.Score(), len(health.Samples), health.SuccessRate()*100,
+		health.AverageLatency().Round(time.Millisecond), formatBytes(int64(health.AverageThroughput())))
+}
+
+// loadMirrorService 加载包含自定义镜像的镜像服务，返回服务及配置文件路径
+func loadMirrorService() (service.MirrorService, string) {
+	configPath := mirrorConfigPath
+	if configPath == "" {
+		configPath = getDefaultMirrorConfigPath()
+	}
+
+	mirrorService, err := service.NewMirrorServiceWithConfig(configPath)
+	if err != nil {
+		PrintError(fmt.Sprintf("加载镜像配置失败: %s", err))
+		os.Exit(1)
+	}
+	return mirrorService, configPath
 }
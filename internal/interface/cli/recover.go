@@ -0,0 +1,158 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"version-list/internal/application"
+	"version-list/internal/domain/model"
+	"version-list/internal/interface/ui"
+
+	"github.com/spf13/cobra"
+)
+
+// recover 命令选项变量
+var (
+	recoverResume bool
+	recoverList   bool
+)
+
+var recoverCmd = &cobra.Command{
+	Use:   "recover",
+	Short: "回滚或继续异常中断的安装",
+	Long: `安装过程中的每个步骤（临时目录、压缩包、版本目录、移动的文件、版本记录）都会先写入
+~/.go-version/journal 中的安装日志。安装失败或被取消时自动回滚；进程异常退出（如断电、被强制结束）时
+日志保留在磁盘上，可以使用此命令回滚或继续这些安装。
+
+再次安装同一版本时会自动回滚该版本未完成的安装。
+
+示例：
+  go-version recover --list       # 列出未完成的安装
+  go-version recover              # 回滚所有未完成的安装
+  go-version recover --resume     # 继续未完成的安装（在线安装保留已下载的部分）`,
+	Args: cobra.NoArgs,
+	Run:  runRecoverCommand,
+}
+
+func init() {
+	recoverCmd.Flags().BoolVar(&recoverResume, "resume", false, "继续未完成的安装，而不是回滚")
+	recoverCmd.Flags().BoolVar(&recoverList, "list", false, "只列出未完成的安装")
+}
+
+func runRecoverCommand(cmd *cobra.Command, args []string) {
+	appService, err := application.NewVersionAppService()
+	if err != nil {
+		PrintError(fmt.Sprintf("初始化应用服务失败: %s", err))
+		os.Exit(1)
+	}
+
+	journals, err := appService.PendingInstallJournals()
+	if err != nil {
+		PrintError(fmt.Sprintf("读取安装日志失败: %s", err))
+		os.Exit(1)
+	}
+	if len(journals) == 0 {
+		PrintSuccess("没有未完成的安装")
+		return
+	}
+
+	displayInstallJournals(journals)
+	if recoverList {
+		return
+	}
+
+	ctx, stop := newInterruptContext()
+	defer stop()
+	failed := 0
+	for _, journal := range journals {
+		name := journalDisplayName(journal)
+		if !recoverResume {
+			if err := appService.RollbackInstallJournal(journal); err != nil {
+				PrintError(err.Error())
+				failed++
+				continue
+			}
+			PrintSuccess(fmt.Sprintf("已回滚 %s", name))
+			continue
+		}
+
+		if !journal.Resumable() {
+			PrintWarning(fmt.Sprintf("%s 无法继续，请运行 'go-version recover' 回滚", name))
+			failed++
+			continue
+		}
+		PrintInfo(fmt.Sprintf("继续安装 %s...", name))
+		var progressUI *ui.InstallProgressUI
+		if !noProgress {
+			progressUI = ui.NewInstallProgressUI()
+			progressUI.Start()
+		}
+		result, err := appService.ResumeInstallJournal(ctx, journal, progressUI)
+		if err != nil {
+			if progressUI != nil {
+				progressUI.PrintError(fmt.Sprintf("安装失败: %s", err))
+				progressUI.Stop()
+			} else {
+				PrintError(fmt.Sprintf("安装失败: %s", err))
+			}
+			exitIfInterrupted(ctx, err)
+			failed++
+			continue
+		}
+		displayInstallResult(result, progressUI)
+		if progressUI != nil {
+			progressUI.Stop()
+		}
+	}
+
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// displayInstallJournals 输出未完成的安装
+func displayInstallJournals(journals []*model.InstallJournal) {
+	PrintInfo(fmt.Sprintf("发现 %d 个未完成的安装:", len(journals)))
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, Colorize("安装\t来源\t中断阶段\t开始时间\t步骤数", ColorBold))
+	for _, journal := range journals {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\n",
+			journalDisplayName(journal),
+			journalSourceName(journal.Source),
+			journalStageName(journal.Status),
+			journal.StartedAt.Format("2006-01-02 15:04:05"),
+			len(journal.Steps))
+	}
+	w.Flush()
+}
+
+// journalDisplayName 获取未完成安装的显示名称，压缩包安装在识别版本前使用压缩包路径
+func journalDisplayName(journal *model.InstallJournal) string {
+	if journal.Version == "" {
+		return journal.Archive
+	}
+	return "Go " + journal.ToolchainName()
+}
+
+// journalSourceName 获取安装来源的中文名称
+func journalSourceName(source model.InstallSource) string {
+	if source == model.SourceArchive {
+		return "压缩包安装"
+	}
+	return "在线安装"
+}
+
+// journalStageName 获取安装阶段的中文名称
+func journalStageName(status model.InstallStatus) string {
+	switch status {
+	case model.StatusDownloading:
+		return "下载"
+	case model.StatusExtracting:
+		return "解压"
+	case model.StatusConfiguring:
+		return "配置"
+	default:
+		return "准备"
+	}
+}
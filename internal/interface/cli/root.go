@@ -30,4 +30,17 @@ func init() {
 	rootCmd.AddCommand(removeCmd)
 	rootCmd.AddCommand(importCmd)
 	rootCmd.AddCommand(mirrorCmd)
+	rootCmd.AddCommand(cacheCmd)
+	rootCmd.AddCommand(bundleCmd)
+	rootCmd.AddCommand(fetchCmd)
+	rootCmd.AddCommand(lockCmd)
+	rootCmd.AddCommand(syncCmd)
+	rootCmd.AddCommand(recoverCmd)
+	rootCmd.AddCommand(hooksCmd)
+	rootCmd.AddCommand(toolsCmd)
+	rootCmd.AddCommand(isolateCmd)
+	rootCmd.AddCommand(gcCmd)
+	rootCmd.AddCommand(envCmd)
+	rootCmd.AddCommand(execCmd)
+	rootCmd.AddCommand(shimCmd)
 }
@@ -0,0 +1,137 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"version-list/internal/application"
+	"version-list/internal/domain/service"
+
+	"github.com/spf13/cobra"
+)
+
+var shimCmd = &cobra.Command{
+	Use:   "shim",
+	Short: "管理 go、gofmt 命令的垫片",
+	Long: `查看、生成或删除 ~/.go-version/shims 中 go、gofmt 命令的垫片。
+
+把垫片目录加在 PATH 最前面后，在没有应用 shell 钩子的 shell、IDE 和脚本中直接运行 go 时，
+垫片在运行前按当前目录合并环境变量，与 'go-version exec go' 相同：使用当前版本的 GOROOT、
+按隔离方式计算的 GOBIN、GOCACHE、GOMODCACHE，以及全局、版本和已允许的项目环境变量。
+
+示例：
+  go-version shim                 # 查看垫片目录和已生成的垫片
+  go-version shim install         # 生成垫片
+  go-version shim remove          # 删除垫片`,
+	Args: cobra.NoArgs,
+	Run:  runShimCommand,
+}
+
+var shimInstallCmd = &cobra.Command{
+	Use:   "install",
+	Short: "生成 go、gofmt 命令的垫片",
+	Long: `在 ~/.go-version/shims 中生成 go、gofmt 命令的垫片，已有的垫片会被覆盖。
+垫片记录 go-version 可执行文件的路径，移动 go-version 后需要重新运行此命令。`,
+	Args: cobra.NoArgs,
+	Run:  runShimInstallCommand,
+}
+
+var shimRemoveCmd = &cobra.Command{
+	Use:   "remove",
+	Short: "删除垫片目录",
+	Args:  cobra.NoArgs,
+	Run:   runShimRemoveCommand,
+}
+
+var shimRunCmd = &cobra.Command{
+	Use:    "run <命令> [参数...]",
+	Short:  "使用合并后的环境变量运行当前版本中的命令（由垫片调用）",
+	Args:   cobra.MinimumNArgs(1),
+	Hidden: true,
+	// 参数原样传给命令，包括 -h、--version 等选项
+	DisableFlagParsing: true,
+	Run:                runShimRunCommand,
+}
+
+func init() {
+	shimCmd.AddCommand(shimInstallCmd)
+	shimCmd.AddCommand(shimRemoveCmd)
+	shimCmd.AddCommand(shimRunCmd)
+}
+
+func runShimCommand(cmd *cobra.Command, args []string) {
+	PrintInfo(fmt.Sprintf("垫片目录: %s", service.ShimsDir()))
+	installed := service.InstalledShims()
+	if len(installed) == 0 {
+		PrintInfo("尚未生成垫片，使用 'go-version shim install' 生成")
+		return
+	}
+	for _, path := range installed {
+		PrintInfo("  " + path)
+	}
+	if !shimsInPath() {
+		PrintWarning("垫片目录不在 PATH 中，请把它加在 PATH 最前面")
+	}
+}
+
+func runShimInstallCommand(cmd *cobra.Command, args []string) {
+	executable, err := os.Executable()
+	if err != nil {
+		PrintError(fmt.Sprintf("获取 go-version 可执行文件路径失败: %s", err))
+		os.Exit(1)
+	}
+	paths, err := service.InstallShims(executable)
+	if err != nil {
+		PrintError(err.Error())
+		os.Exit(1)
+	}
+	for _, path := range paths {
+		PrintSuccess(fmt.Sprintf("已生成垫片 %s", path))
+	}
+	if !shimsInPath() {
+		PrintInfo("把垫片目录加在 PATH 最前面后生效，例如在 ~/.bashrc 中添加：")
+		PrintInfo(fmt.Sprintf("  export PATH=\"%s:$PATH\"", service.ShimsDir()))
+	}
+}
+
+func runShimRemoveCommand(cmd *cobra.Command, args []string) {
+	if err := service.RemoveShims(); err != nil {
+		PrintError(err.Error())
+		os.Exit(1)
+	}
+	PrintSuccess(fmt.Sprintf("已删除垫片目录 %s", service.ShimsDir()))
+}
+
+func runShimRunCommand(cmd *cobra.Command, args []string) {
+	appService, err := application.NewVersionAppService()
+	if err != nil {
+		PrintError(fmt.Sprintf("初始化应用服务失败: %s", err))
+		os.Exit(1)
+	}
+
+	dir, _ := os.Getwd()
+	warnUnallowedProjectEnv(dir)
+	env, err := appService.EffectiveEnvironment("", dir)
+	if err != nil {
+		PrintError(err.Error())
+		os.Exit(1)
+	}
+	target, err := service.ShimTarget(env, args[0])
+	if err != nil {
+		PrintError(err.Error())
+		os.Exit(1)
+	}
+
+	runWithEnvironment(env, target, args)
+}
+
+// shimsInPath 检查垫片目录是否在 PATH 中
+func shimsInPath() bool {
+	for _, dir := range strings.Split(os.Getenv("PATH"), string(os.PathListSeparator)) {
+		if dir == service.ShimsDir() {
+			return true
+		}
+	}
+	return false
+}
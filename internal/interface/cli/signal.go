@@ -0,0 +1,40 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"version-list/internal/domain/service"
+)
+
+// 安装被中断时的退出状态码
+const (
+	exitCodeTimeout     = 124 // 下载超过 --timeout 指定的时间没有进度
+	exitCodeInterrupted = 130 // 收到 Ctrl-C (SIGINT) 或 SIGTERM
+)
+
+// newInterruptContext 创建在收到 SIGINT/SIGTERM 时取消的上下文
+// 收到第一个信号后恢复默认的信号处理，再次按下 Ctrl-C 将立即退出
+func newInterruptContext() (context.Context, context.CancelFunc) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+	return ctx, stop
+}
+
+// exitIfInterrupted 安装因信号中断或下载停滞时输出提示并以对应的状态码退出
+func exitIfInterrupted(ctx context.Context, err error) {
+	if ctx.Err() != nil {
+		PrintWarning("安装已被中断，未完成的安装已清理")
+		os.Exit(exitCodeInterrupted)
+	}
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, service.ErrDownloadStalled) {
+		PrintWarning("下载长时间没有进度，未完成的安装已清理，可使用 --timeout 延长停滞超时时间")
+		os.Exit(exitCodeTimeout)
+	}
+}
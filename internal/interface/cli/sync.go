@@ -0,0 +1,167 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"version-list/internal/application"
+	"version-list/internal/domain/model"
+	"version-list/internal/domain/service"
+	"version-list/internal/interface/ui"
+
+	"github.com/spf13/cobra"
+)
+
+// sync 命令选项变量
+var (
+	syncManifestPath string
+	syncPlanOnly     bool
+)
+
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "按工具链清单安装、移除Go版本并设置默认版本",
+	Long: `读取工具链清单（toolchains.yaml 或 toolchains.json），对比已安装的版本后输出执行计划并执行：
+安装缺少的工具链、重新安装发布版本与清单不一致的工具链、为已安装的工具链添加缺少的标签、
+切换默认版本、移除清单中要求移除的版本。
+已与清单一致时不做任何操作，可以重复执行。
+
+清单示例（toolchains.yaml）：
+  default: 1.22.7
+  toolchains:
+    - version: 1.22.7
+      tags: [team]
+    - version: 1.21.13
+    - version: 1.22.7
+      name: 1.22.7-fips
+  remove:
+    - 1.20.14
+
+示例：
+  go-version sync --plan                     # 只输出执行计划
+  go-version sync                            # 执行同步
+  go-version sync -f ci/toolchains.json`,
+	Args: cobra.NoArgs,
+	Run:  runSyncCommand,
+}
+
+func init() {
+	syncCmd.Flags().StringVarP(&syncManifestPath, "file", "f", service.DefaultSyncManifestName, "工具链清单路径（.json 按JSON解析，其他按YAML解析）")
+	syncCmd.Flags().BoolVar(&syncPlanOnly, "plan", false, "只输出执行计划，不做任何修改")
+}
+
+func runSyncCommand(cmd *cobra.Command, args []string) {
+	path := syncManifestPath
+	if !cmd.Flags().Changed("file") {
+		path = findSyncManifest()
+	}
+
+	manifest, err := service.LoadSyncManifest(path)
+	if err != nil {
+		PrintError(err.Error())
+		if errors.Is(err, os.ErrNotExist) {
+			PrintInfo("在当前目录创建 toolchains.yaml 或使用 --file 指定清单路径")
+		}
+		os.Exit(1)
+	}
+
+	appService, err := application.NewVersionAppService()
+	if err != nil {
+		PrintError(fmt.Sprintf("初始化应用服务失败: %s", err))
+		os.Exit(1)
+	}
+
+	plan, err := appService.PlanSync(manifest)
+	if err != nil {
+		PrintError(fmt.Sprintf("生成执行计划失败: %s", err))
+		os.Exit(1)
+	}
+
+	if plan.Empty() {
+		PrintSuccess(fmt.Sprintf("已与清单 %s 一致，无需操作", path))
+		return
+	}
+
+	PrintInfo(fmt.Sprintf("执行计划（%s）:", path))
+	for i := range plan.Actions {
+		PrintInfo(fmt.Sprintf("  %d. %s", i+1, plan.Actions[i].String()))
+	}
+	if len(plan.Unchanged) > 0 {
+		PrintInfo(fmt.Sprintf("  已一致: %s", strings.Join(plan.Unchanged, ", ")))
+	}
+	if syncPlanOnly {
+		return
+	}
+
+	ctx, stop := newInterruptContext()
+	defer stop()
+	results := appService.ApplySync(ctx, plan, buildInstallOptions(), &syncProgress{})
+	if displaySyncSummary(results) > 0 || ctx.Err() != nil {
+		exitIfInterrupted(ctx, nil)
+		os.Exit(1)
+	}
+}
+
+// findSyncManifest 在当前目录查找工具链清单，依次尝试 toolchains.yaml、toolchains.yml 和 toolchains.json
+func findSyncManifest() string {
+	for _, name := range []string{service.DefaultSyncManifestName, "toolchains.yml", "toolchains.json"} {
+		if _, err := os.Stat(name); err == nil {
+			return name
+		}
+	}
+	return service.DefaultSyncManifestName
+}
+
+// syncProgress 同步进度报告，安装操作显示进度条，每个操作完成后输出结果
+type syncProgress struct {
+	progressUI *ui.InstallProgressUI
+}
+
+// ReporterFor 为安装操作创建进度条
+func (p *syncProgress) ReporterFor(action *model.SyncAction) service.ProgressReporter {
+	PrintInfo(action.String() + "...")
+	if noProgress {
+		return nil
+	}
+	p.progressUI = ui.NewInstallProgressUI()
+	p.progressUI.Start()
+	return p.progressUI
+}
+
+// Complete 输出单个操作的结果
+func (p *syncProgress) Complete(result *model.SyncResult) {
+	if p.progressUI != nil {
+		p.progressUI.Stop()
+		p.progressUI = nil
+	}
+	if result.Success() {
+		PrintSuccess(result.Action.String())
+		return
+	}
+	PrintError(fmt.Sprintf("%s 失败: %s", result.Action.String(), result.Error))
+}
+
+// displaySyncSummary 输出按操作类型统计的同步结果，返回失败的操作数量
+func displaySyncSummary(results []*model.SyncResult) int {
+	succeeded := make(map[model.SyncActionType]int)
+	failed := 0
+	for _, result := range results {
+		if result.Success() {
+			succeeded[result.Action.Type]++
+		} else {
+			failed++
+		}
+	}
+
+	summary := fmt.Sprintf("同步完成: 安装 %d 个，重新安装 %d 个，添加标签 %d 个，切换默认版本 %d 次，移除 %d 个",
+		succeeded[model.SyncInstall], succeeded[model.SyncReinstall], succeeded[model.SyncTag], succeeded[model.SyncUse], succeeded[model.SyncRemove])
+	if failed > 0 {
+		PrintWarning(fmt.Sprintf("%s，失败 %d 个", summary, failed))
+		PrintInfo("修复问题后重新运行 'go-version sync' 即可继续")
+		return failed
+	}
+	PrintSuccess(summary)
+	return 0
+}
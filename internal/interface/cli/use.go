@@ -12,8 +12,11 @@ import (
 var useCmd = &cobra.Command{
 	Use:   "use [version]",
 	Short: "切换到指定版本的Go",
-	Long:  `切换到指定版本的Go，例如: go-version use 1.21.0`,
-	Args:  cobra.ExactArgs(1),
+	Long: `切换到指定版本的Go，例如: go-version use 1.21.0
+
+切换前后分别运行 ~/.go-version/hooks.yaml 中配置的 pre-use 和 post-use 钩子，
+标记为 required 的 pre-use 钩子失败时不切换版本。`,
+	Args: cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		version := args[0]
 
@@ -24,7 +27,8 @@ var useCmd = &cobra.Command{
 		}
 
 		PrintInfo(fmt.Sprintf("正在切换到Go %s...", version))
-		err = appService.Use(version)
+		warnings, err := appService.Use(version)
+		printWarnings(warnings)
 		if err != nil {
 			PrintError(fmt.Sprintf("切换失败: %s", err))
 			os.Exit(1)
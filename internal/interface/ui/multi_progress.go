@@ -0,0 +1,184 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"version-list/internal/domain/model"
+	"version-list/internal/domain/service"
+)
+
+// MultiInstallProgressUI 多版本并行安装进度UI，每个版本显示一行
+type MultiInstallProgressUI struct {
+	mu             sync.Mutex
+	consoleUI      *ConsoleUI
+	versions       []string
+	trackers       map[string]*ProgressTracker
+	results        map[string]*model.InstallationResult
+	updateInterval time.Duration
+	width          int
+	lastLines      int
+	stopChan       chan struct{}
+	doneChan       chan struct{}
+}
+
+// NewMultiInstallProgressUI 创建多版本安装进度UI
+func NewMultiInstallProgressUI(versions []string) *MultiInstallProgressUI {
+	trackers := make(map[string]*ProgressTracker, len(versions))
+	for _, version := range versions {
+		trackers[version] = NewProgressTracker([]string{
+			"检测系统",
+			"下载文件",
+			"验证文件",
+			"解压文件",
+			"配置安装",
+			"完成安装",
+		})
+	}
+
+	return &MultiInstallProgressUI{
+		consoleUI: NewConsoleUI(&ConsoleUIOptions{
+			UpdateInterval: 100 * time.Millisecond,
+			ColorEnabled:   true,
+			Width:          20,
+		}),
+		versions:       versions,
+		trackers:       trackers,
+		results:        make(map[string]*model.InstallationResult),
+		updateInterval: 100 * time.Millisecond,
+		width:          20,
+	}
+}
+
+// ReporterFor 获取指定版本的进度报告器
+func (m *MultiInstallProgressUI) ReporterFor(version string) service.ProgressReporter {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	tracker, exists := m.trackers[version]
+	if !exists {
+		tracker = NewProgressTracker(nil)
+		m.trackers[version] = tracker
+		m.versions = append(m.versions, version)
+	}
+	tracker.Start()
+	return tracker
+}
+
+// Complete 标记指定版本安装完成
+func (m *MultiInstallProgressUI) Complete(result *model.InstallationResult) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.results[result.Version] = result
+	if tracker, exists := m.trackers[result.Version]; exists {
+		tracker.Stop()
+	}
+}
+
+// Start 开始显示进度
+func (m *MultiInstallProgressUI) Start() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.stopChan != nil {
+		return
+	}
+
+	m.stopChan = make(chan struct{})
+	m.doneChan = make(chan struct{})
+	go m.updateLoop(m.stopChan, m.doneChan)
+}
+
+// Stop 停止显示进度，并输出最终状态
+func (m *MultiInstallProgressUI) Stop() {
+	m.mu.Lock()
+	stopChan, doneChan := m.stopChan, m.doneChan
+	m.stopChan = nil
+	m.mu.Unlock()
+
+	if stopChan == nil {
+		return
+	}
+
+	close(stopChan)
+	<-doneChan
+	m.render()
+}
+
+// updateLoop 更新循环
+func (m *MultiInstallProgressUI) updateLoop(stopChan, doneChan chan struct{}) {
+	defer close(doneChan)
+
+	ticker := time.NewTicker(m.updateInterval)
+	defer ticker.Stop()
+
+	for {
+		m.render()
+
+		select {
+		case <-stopChan:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// render 重新绘制所有版本的进度行
+func (m *MultiInstallProgressUI) render() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i := 0; i < m.lastLines; i++ {
+		fmt.Print("\033[1A\033[2K") // 向上移动一行并清除
+	}
+
+	lines := m.buildLines()
+	for _, line := range lines {
+		fmt.Println(line)
+	}
+	m.lastLines = len(lines)
+}
+
+// buildLines 构建每个版本的进度行
+func (m *MultiInstallProgressUI) buildLines() []string {
+	lines := make([]string, 0, len(m.versions))
+	for _, version := range m.versions {
+		lines = append(lines, m.buildLine(version))
+	}
+	return lines
+}
+
+// buildLine 构建单个版本的进度行
+func (m *MultiInstallProgressUI) buildLine(version string) string {
+	label := fmt.Sprintf("Go %-10s", version)
+
+	if result, done := m.results[version]; done {
+		if result.Success {
+			return m.consoleUI.colorize(fmt.Sprintf("✅ %s 安装成功 (%v)", label, result.Duration.Truncate(time.Millisecond)), ColorGreen)
+		}
+		return m.consoleUI.colorize(fmt.Sprintf("❌ %s 安装失败: %s", label, result.Error), ColorRed)
+	}
+
+	tracker := m.trackers[version]
+	info := tracker.GetProgress()
+	if !tracker.IsActive() {
+		return m.consoleUI.colorize(fmt.Sprintf("⏸️  %s 等待中", label), ColorCyan)
+	}
+
+	parts := []string{
+		tracker.RenderSpinner(),
+		label,
+		m.consoleUI.colorize(tracker.RenderProgressBar(m.width), ColorGreen),
+	}
+	if info.Stage != "" {
+		parts = append(parts, m.consoleUI.colorize(info.Stage, ColorBlue))
+	}
+	if info.Message != "" {
+		parts = append(parts, info.Message)
+	}
+
+	return strings.Join(parts, " ")
+}
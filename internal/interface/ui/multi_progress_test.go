@@ -0,0 +1,61 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"version-list/internal/domain/model"
+)
+
+func TestMultiInstallProgressUI_Lines(t *testing.T) {
+	ui := NewMultiInstallProgressUI([]string{"1.21.13", "1.22.7", "1.23.2"})
+	ui.consoleUI.colorEnabled = false
+
+	// 第一个版本正在下载
+	reporter := ui.ReporterFor("1.21.13")
+	reporter.SetStage("下载文件")
+	reporter.SetProgress(45)
+	reporter.SetMessage("下载中... 50.0%")
+
+	// 第二个版本安装失败
+	ui.ReporterFor("1.22.7")
+	ui.Complete(&model.InstallationResult{Version: "1.22.7", Success: false, Error: "HTTP响应错误: 404"})
+
+	lines := ui.buildLines()
+	if len(lines) != 3 {
+		t.Fatalf("行数 = %d, 期望 3", len(lines))
+	}
+
+	if !strings.Contains(lines[0], "1.21.13") || !strings.Contains(lines[0], "45.0%") || !strings.Contains(lines[0], "下载文件") {
+		t.Errorf("进行中的版本行不正确: %s", lines[0])
+	}
+
+	if !strings.Contains(lines[1], "安装失败") || !strings.Contains(lines[1], "404") {
+		t.Errorf("失败的版本行不正确: %s", lines[1])
+	}
+
+	if !strings.Contains(lines[2], "等待中") {
+		t.Errorf("未开始的版本行不正确: %s", lines[2])
+	}
+
+	ui.Complete(&model.InstallationResult{Version: "1.23.2", Success: true, Duration: 2 * time.Second})
+	if line := ui.buildLine("1.23.2"); !strings.Contains(line, "安装成功") {
+		t.Errorf("成功的版本行不正确: %s", line)
+	}
+}
+
+func TestMultiInstallProgressUI_StartStop(t *testing.T) {
+	ui := NewMultiInstallProgressUI([]string{"1.21.13"})
+	ui.updateInterval = 5 * time.Millisecond
+
+	ui.Start()
+	ui.Start() // 重复启动应该无副作用
+	time.Sleep(20 * time.Millisecond)
+	ui.Stop()
+	ui.Stop() // 重复停止应该无副作用
+
+	if ui.lastLines != 1 {
+		t.Errorf("lastLines = %d, 期望 1", ui.lastLines)
+	}
+}